@@ -56,8 +56,12 @@ func main() {
 		Logs: logger.Options{
 			Plugins: []logger.PluginOptions{
 				{
-					File: &logger.FilePluginOptions{
-						Path: "/home/ubuntu/.darknode/darknode.out",
+					RotatingFile: &logger.RotatingFilePluginOptions{
+						Path:       "/home/ubuntu/.darknode/darknode.out",
+						MaxSize:    100 * 1024 * 1024,
+						MaxAge:     24 * 60 * 60,
+						MaxBackups: 7,
+						Compress:   true,
 					},
 				},
 			},
@@ -6,22 +6,36 @@ import (
 
 	"github.com/republicprotocol/republic-go/contract"
 	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/discovery"
+	"github.com/republicprotocol/republic-go/grpc"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/ome"
 )
 
 type Config struct {
-	Keystore crypto.Keystore `json:"keystore"`
-	Ethereum contract.Config `json:"ethereum"` // TODO: Darknode package should not be dependent on blockchain/ethereum
-	Logs     logger.Options  `json:"logs"`
+	Keystore  crypto.Keystore   `json:"keystore"`
+	Ethereum  contract.Config   `json:"ethereum"` // TODO: Darknode package should not be dependent on blockchain/ethereum
+	Logs      logger.Options    `json:"logs"`
+	Discovery discovery.Options `json:"discovery"`
+	TLS       grpc.TLSOptions   `json:"tls"`
 
 	Address                 identity.Address        `json:"address"`
 	OracleAddress           identity.Address        `json:"oracleAddress"`
 	BootstrapMultiAddresses identity.MultiAddresses `json:"bootstrapMultiAddresses"`
 	SentryDSN               string                  `json:"sentry,omitempty"`
+	TraceFile               string                  `json:"traceFile,omitempty"`
 	Host                    string                  `json:"host"`
 	Port                    string                  `json:"port"`
 	Alpha                   int                     `json:"alpha"`
+
+	// MatchingPolicy orders the computations of the darknode. All darknodes
+	// in a pod must use the same MatchingPolicy.
+	MatchingPolicy ome.PolicyType `json:"matchingPolicy,omitempty"`
+
+	// ComputationQueue bounds the number of computations that are waiting to
+	// be resolved.
+	ComputationQueue ome.QueueOptions `json:"computationQueue"`
 }
 
 func NewConfigFromJSONFile(filename string) (Config, error) {
@@ -38,6 +52,9 @@ func NewConfigFromJSONFile(filename string) (Config, error) {
 	if conf.Alpha == 0 {
 		conf.Alpha = 8
 	}
+	if conf.MatchingPolicy == "" {
+		conf.MatchingPolicy = ome.PolicyPrioritySum
+	}
 
 	return conf, nil
 }
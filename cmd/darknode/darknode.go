@@ -10,9 +10,8 @@ import (
 	"net"
 	netHttp "net/http"
 	"os"
-	"os/exec"
 	"path"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -20,6 +19,7 @@ import (
 	"github.com/republicprotocol/republic-go/cmd/darknode/config"
 	"github.com/republicprotocol/republic-go/contract"
 	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/discovery"
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/grpc"
 	"github.com/republicprotocol/republic-go/http"
@@ -28,11 +28,13 @@ import (
 	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/ome"
+	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/orderbook"
 	"github.com/republicprotocol/republic-go/registry"
 	"github.com/republicprotocol/republic-go/smpc"
 	"github.com/republicprotocol/republic-go/status"
 	"github.com/republicprotocol/republic-go/swarm"
+	"github.com/republicprotocol/republic-go/trace"
 	"golang.org/x/time/rate"
 )
 
@@ -53,6 +55,30 @@ func main() {
 		log.Fatalf("cannot load config: %v", err)
 	}
 
+	// Configure the logger plugins, logging to stdout if no plugins are
+	// configured
+	if len(config.Logs.Plugins) > 0 {
+		if config.Logs.FilterLevel == 0 {
+			config.Logs.FilterLevel = logger.LevelDebugLow
+		}
+		logs, err := logger.NewLogger(config.Logs)
+		if err != nil {
+			log.Fatalf("cannot configure logger: %v", err)
+		}
+		logger.SetDefaultLogger(logs)
+		defer logger.ResetDefaultLogger()
+	}
+
+	// Record computation traces to a local trace file
+	if config.TraceFile != "" {
+		exporter, err := trace.NewFileExporter(config.TraceFile)
+		if err != nil {
+			log.Fatalf("cannot open trace file: %v", err)
+		}
+		defer exporter.Close()
+		trace.SetDefaultTracer(trace.NewTracer(config.Address.String(), exporter))
+	}
+
 	// Configure Sentry and log an initial event
 	if config.SentryDSN != "" {
 		raven.SetDSN(config.SentryDSN)
@@ -62,18 +88,24 @@ func main() {
 		raven.CaptureErrorAndWait(errors.New("darknode restarting"), nil)
 	}
 
-	// Get IP-address
-	ipAddr, err := getIPAddress()
+	// Discover the public endpoint of the darknode
+	port, err := strconv.Atoi(config.Port)
 	if err != nil {
-		log.Fatalf("cannot get ip-address: %v", err)
+		log.Fatalf("cannot parse port %v: %v", config.Port, err)
 	}
+	observer := discovery.NewObserver(config.Discovery.ObserverQuorum, 2*time.Hour)
+	sources, err := discovery.NewSources(config.Discovery, observer)
+	if err != nil {
+		log.Fatalf("cannot configure discovery: %v", err)
+	}
+	discoverer := discovery.NewDiscoverer(sources...)
 
 	// Get multi-address
-	multiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/%s/tcp/18514/republic/%s", ipAddr, config.Address))
+	multiAddr, err := discoverMultiAddress(discoverer, port, config.Address)
 	if err != nil {
 		log.Fatalf("cannot get multiaddress: %v", err)
 	}
-	log.Printf("address %v", multiAddr)
+	logger.Network(logger.LevelInfo, fmt.Sprintf("address %v", multiAddr))
 
 	// Connect to Ethereum
 	conn, err := contract.Connect(config.Ethereum)
@@ -89,6 +121,26 @@ func main() {
 		log.Fatalf("cannot get ethereum bindings: %v", err)
 	}
 
+	// Load the tokens supported by the network
+	tokenRegistry, err := contractBinder.TokenRegistry()
+	if err != nil {
+		logger.Contract(logger.LevelWarn, fmt.Sprintf("cannot load token registry, using default tokens: %v", err))
+		tokenRegistry = order.NewDefaultTokenRegistry()
+	}
+	for _, tokens := range tokenRegistry.Pairs() {
+		if tokens.PriorityToken() != order.TokenETH && tokens.NonPriorityToken() != order.TokenETH {
+			logger.Contract(logger.LevelWarn, fmt.Sprintf("orders for tokens = %v cannot be valued in ETH and will not be settled", uint64(tokens)))
+		}
+	}
+	order.SetDefaultTokenRegistry(tokenRegistry)
+
+	// Load the policy for ordering computations, which must be the same for
+	// all darknodes in a pod
+	policy, err := ome.NewPolicy(config.MatchingPolicy)
+	if err != nil {
+		log.Fatalf("cannot configure matching policy %v: %v", config.MatchingPolicy, err)
+	}
+
 	// New database for persistent storage
 	store, err := leveldb.NewStore(*dataParam, time.Hour)
 	if err != nil {
@@ -119,24 +171,38 @@ func main() {
 			logger.Network(logger.LevelError, fmt.Sprintf("error retrieving own multiAddress from store: %v", err))
 		}
 	} else {
-		// Update own multiAddress if the ip address has been changed.
-		oldIP, err := oldMulti.ValueForProtocol(identity.IP4Code)
-		if err != nil {
-			log.Fatalf("cannot read own ip address from storer: %v", err)
-		}
-		if oldIP != ipAddr {
+		// Update own multiAddress if the address has been changed. The nonce
+		// must be higher than the nonce of the old multiAddress otherwise
+		// other darknodes will ignore the change.
+		if oldMulti.String() != multiAddr.String() {
+			multiAddr.Nonce = oldMulti.Nonce + 1
 			updateOwnAddress()
+		} else {
+			multiAddr = oldMulti
 		}
 	}
 
+	// Secure all gRPC servers and clients using certificates that are bound
+	// to the darknode identity
+	if config.TLS.Enabled {
+		creds, err := grpc.NewCredentials(&config.Keystore.EcdsaKey, config.Address, &crypter, config.TLS.RequireClientCertificates)
+		if err != nil {
+			log.Fatalf("cannot create tls credentials: %v", err)
+		}
+		grpc.SetDefaultCredentials(creds)
+	}
+
 	// New gRPC components
 	unaryLimiter := grpc.NewRateLimiter(rate.NewLimiter(40, 100), 8, 20)
 	streamLimiter := grpc.NewRateLimiter(rate.NewLimiter(40, 100), 8, 20)
 	server := grpc.NewServerwithLimiter(unaryLimiter, streamLimiter)
 
 	swarmClient := grpc.NewSwarmClient(store.SwarmMultiAddressStore(), multiAddr.Address())
-	swarmer := swarm.NewSwarmer(swarmClient, store.SwarmMultiAddressStore(), config.Alpha, &crypter)
-	swarmService := grpc.NewSwarmService(swarm.NewServer(swarmer, store.SwarmMultiAddressStore(), config.Alpha, &crypter))
+	routingTable := swarm.NewRoutingTable(multiAddr.Address(), swarm.BucketSize)
+	peerTracker := swarm.NewPeerTracker()
+	guard := swarm.NewGuard(swarm.DefaultNonceWindow, swarm.DefaultRelayLimit, swarm.DefaultRelayBurst)
+	swarmer := swarm.NewSwarmer(swarmClient, store.SwarmMultiAddressStore(), routingTable, peerTracker, config.Alpha, &crypter)
+	swarmService := grpc.NewSwarmServiceWithObserver(swarm.NewServer(swarmer, store.SwarmMultiAddressStore(), routingTable, peerTracker, guard, config.Alpha, &crypter), observer)
 	swarmService.Register(server)
 
 	// oracleClient := grpc.NewOracleClient(multiAddr.Address(), store.SwarmMultiAddressStore())
@@ -144,8 +210,9 @@ func main() {
 	// oracleService := grpc.NewOracleService(oracle.NewServer(oracler, config.OracleAddress, store.SwarmMultiAddressStore(), midpointPriceStorer, config.Alpha), time.Millisecond)
 	// oracleService.Register(server)
 
+	broadcaster := orderbook.NewBroadcaster(&config.Keystore.EcdsaKey)
 	orderbook := orderbook.NewOrderbook(config.Address, config.Keystore.RsaKey, store.OrderbookPointerStore(), store.OrderbookOrderStore(), store.OrderbookOrderFragmentStore(), &contractBinder, 5*time.Second, 32)
-	orderbookService := grpc.NewOrderbookService(orderbook)
+	orderbookService := grpc.NewOrderbookService(orderbook, ome.NewQuerier(orderbook, store.SomerComputationStore()), broadcaster)
 	orderbookService.Register(server)
 
 	connectorListener := grpc.NewConnectorListener(config.Address, &crypter, &crypter)
@@ -160,7 +227,7 @@ func main() {
 	}
 
 	// Populate status information
-	statusProvider := status.NewProvider(swarmer)
+	statusProvider := status.NewProvider(swarmer, peerTracker)
 	statusProvider.WriteNetwork(string(conn.Config.Network))
 	statusProvider.WriteMultiAddress(multiAddr)
 	statusProvider.WriteEthereumNetwork(ethNetwork)
@@ -168,13 +235,26 @@ func main() {
 	statusProvider.WriteDarknodeRegistryAddress(conn.Config.DarknodeRegistryAddress)
 	statusProvider.WriteRewardVaultAddress(conn.Config.DarknodeRewardVaultAddress)
 	statusProvider.WriteInfuraURL(conn.Config.URI)
-	var tokens map[string]string
-	if len(conn.Config.Tokens) == 0 {
-		tokens = contract.TokenAddresses(conn.Config.Network)
-	} else {
-		tokens = conn.Config.Tokens
+	tokens := tokenRegistry.Addresses()
+	if len(tokens) == 0 {
+		if len(conn.Config.Tokens) == 0 {
+			tokens = contract.TokenAddresses(conn.Config.Network)
+		} else {
+			tokens = conn.Config.Tokens
+		}
 	}
 	statusProvider.WriteTokens(tokens)
+	tokenPairs := []string{}
+	for _, pair := range tokenRegistry.Pairs() {
+		tokenPairs = append(tokenPairs, pair.String())
+	}
+	statusProvider.WriteTokenPairs(tokenPairs)
+	fragmentVersions := []uint32{}
+	for _, version := range order.SupportedFragmentVersions() {
+		fragmentVersions = append(fragmentVersions, uint32(version))
+	}
+	statusProvider.WriteFragmentVersions(fragmentVersions)
+	statusProvider.WriteMatchingPolicy(string(policy.Type()))
 
 	pk, err := crypto.BytesFromRsaPublicKey(&config.Keystore.RsaKey.PublicKey)
 	if err != nil {
@@ -186,7 +266,7 @@ func main() {
 	go func() {
 		bindParam := "0.0.0.0"
 		portParam := "18515"
-		log.Printf("HTTP listening on %v:%v...", bindParam, portParam)
+		logger.Network(logger.LevelInfo, fmt.Sprintf("HTTP listening on %v:%v...", bindParam, portParam))
 
 		statusAdapter := adapter.NewStatusAdapter(statusProvider)
 		if err := netHttp.ListenAndServe(fmt.Sprintf("%v:%v", bindParam, portParam), http.NewStatusServer(statusAdapter)); err != nil {
@@ -227,42 +307,37 @@ func main() {
 					logger.Network(logger.LevelError, fmt.Sprintf("cannot get bootstrap multi-address from store: %v", err))
 				}
 			} else {
-				// Update bootstrap multiAddress if the ip address has been changed.
-				oldBootstrapIP, err := oldBootstrapAddr.ValueForProtocol(identity.IP4Code)
-				if err != nil {
-					log.Printf("cannot read own ip address from storer: %v", err)
-					continue
-				}
-				newBootstrapIP, err := bootstrapMulti.ValueForProtocol(identity.IP4Code)
-				if err != nil {
-					log.Printf("cannot read own ip address from storer: %v", err)
-					continue
-				}
-				if oldBootstrapIP != newBootstrapIP {
+				// Update bootstrap multiAddress if the address has been changed.
+				if oldBootstrapAddr.String() != bootstrapMulti.String() {
 					if err := store.SwarmMultiAddressStore().InsertMultiAddress(bootstrapMulti); err != nil {
 						logger.Network(logger.LevelError, fmt.Sprintf("cannot store bootstrap multiaddress in store: %v", err))
 					}
 				}
 			}
 		}
-		log.Printf(fmtStr)
+		logger.Network(logger.LevelInfo, fmtStr)
 		if err := pingNetwork(swarmer); err != nil {
 			log.Fatalf("[error] (bootstrap) cannot ping network: %v", err)
 		}
 
 		// New secure multi-party computer
-		smpcer := smpc.NewSmpcer(connectorListener, swarmer)
+		smpcer := smpc.NewSmpcer(connectorListener, swarmer, peerTracker)
 
 		// New OME
 		epoch, err := contractBinder.PreviousEpoch()
 		if err != nil {
 			logger.Error(fmt.Sprintf("cannot get previous epoch: %v", err))
 		}
-		gen := ome.NewComputationGenerator(config.Address, store.SomerOrderFragmentStore())
+		if currEpoch, err := contractBinder.Epoch(); err != nil {
+			logger.Error(fmt.Sprintf("cannot get current epoch: %v", err))
+		} else {
+			guard.OnChangeEpoch(currEpoch)
+		}
+		gen := ome.NewComputationGenerator(config.Address, store.SomerOrderFragmentStore(), policy, config.ComputationQueue)
 		matcher := ome.NewMatcher(store.SomerComputationStore(), store.SomerOrderFragmentStore(), smpcer)
 		confirmer := ome.NewConfirmer(store.SomerComputationStore(), store.SomerOrderFragmentStore(), &contractBinder, 5*time.Second, 6)
 		settler := ome.NewSettler(store.SomerComputationStore(), smpcer, &contractBinder, 1e12)
-		ome := ome.NewOme(config.Address, gen, matcher, confirmer, settler, orderbook, smpcer, epoch)
+		ome := ome.NewOme(config.Address, gen, matcher, confirmer, settler, orderbook, broadcaster, smpcer, epoch)
 
 		dispatch.CoBegin(func() {
 			// Synchronizing the OME
@@ -270,6 +345,41 @@ func main() {
 			for err := range errs {
 				logger.Error(fmt.Sprintf("error in running the ome: %v", err))
 			}
+		}, func() {
+			// Preprocess random values for secure comparisons while the
+			// darknode is idle
+			smpcer.Preprocess(done, 64, 5*time.Second)
+		}, func() {
+			// Periodically report the depths of the preprocessing pools and
+			// the computation queues
+			for {
+				time.Sleep(10 * time.Second)
+
+				poolDepths := []status.PoolDepth{}
+				for networkID, depths := range smpcer.PoolDepths() {
+					for predicate, depth := range depths {
+						poolDepths = append(poolDepths, status.PoolDepth{
+							Network:   networkID.String(),
+							Predicate: predicate.String(),
+							Dealt:     depth.Dealt,
+							Received:  depth.Received,
+						})
+					}
+				}
+				statusProvider.WritePoolDepths(poolDepths)
+
+				queueDepths := []status.QueueDepth{}
+				for _, depth := range gen.QueueDepths() {
+					queueDepths = append(queueDepths, status.QueueDepth{
+						Epoch:       smpc.NetworkID(depth.Epoch).String(),
+						Depth:       depth.Depth,
+						Capacity:    depth.Capacity,
+						Evicted:     depth.Evicted,
+						Regenerated: depth.Regenerated,
+					})
+				}
+				statusProvider.WriteQueueDepths(queueDepths)
+			}
 		}, func() {
 			// Periodically sync the next ξ
 			for {
@@ -289,8 +399,57 @@ func main() {
 				epoch = nextEpoch
 				logger.Epoch(epoch.Hash)
 
-				// Notify the Ome
+				// Notify the Ome and the Guard
 				ome.OnChangeEpoch(epoch)
+				guard.OnChangeEpoch(epoch)
+			}
+		}, func() {
+			// Periodically rediscover the public endpoint, refreshing port
+			// mappings on the gateway and updating the network when the
+			// endpoint has changed
+			for {
+				time.Sleep(discovery.MappingLifetime / 4)
+
+				nextMultiAddr, err := discoverMultiAddress(discoverer, port, config.Address)
+				if err != nil {
+					logger.Swarm(logger.LevelError, fmt.Sprintf("discovery: cannot get multiaddress: %v", err))
+					continue
+				}
+				currMultiAddr, err := store.SwarmMultiAddressStore().MultiAddress(config.Address)
+				if err != nil {
+					logger.Swarm(logger.LevelError, fmt.Sprintf("discovery: cannot get own multiaddress: %v", err))
+					continue
+				}
+				if nextMultiAddr.String() == currMultiAddr.String() {
+					continue
+				}
+				logger.Swarm(logger.LevelInfo, fmt.Sprintf("discovery: address changed to %v", nextMultiAddr))
+
+				// Pinging the network will increment the nonce, and sign,
+				// the new multiAddress
+				nextMultiAddr.Nonce = currMultiAddr.Nonce
+				if err := store.SwarmMultiAddressStore().InsertMultiAddress(nextMultiAddr); err != nil {
+					logger.Swarm(logger.LevelError, fmt.Sprintf("discovery: cannot store own multiaddress: %v", err))
+					continue
+				}
+				if err := pingNetwork(swarmer); err != nil {
+					logger.Swarm(logger.LevelError, fmt.Sprintf("discovery: cannot ping network: %v", err))
+				}
+				statusProvider.WriteMultiAddress(nextMultiAddr)
+			}
+		}, func() {
+			// Periodically refresh the buckets of the routing table that
+			// have not been used by a query
+			for {
+				time.Sleep(swarm.RefreshInterval)
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				swarm.Refresh(ctx, swarmer, routingTable, swarm.RefreshInterval)
+				cancel()
+
+				// Forget the health of peers that are no longer in the
+				// routing table
+				peerTracker.Prune(routingTable.MultiAddresses())
 			}
 		}, func() {
 			// Prune the database every hour and update the network with the
@@ -300,11 +459,11 @@ func main() {
 				time.Sleep(time.Duration(sleepTime) * time.Minute)
 
 				if err := pingNetwork(swarmer); err != nil {
-					log.Printf("[error] (prune) cannot ping network: %v", err)
+					logger.Swarm(logger.LevelError, fmt.Sprintf("prune: cannot ping network: %v", err))
 					continue
 				}
 				if err := store.Prune(); err != nil {
-					log.Printf("[error] (prune) cannot prune the storer: %v", err)
+					logger.Sync(logger.LevelError, fmt.Sprintf("prune: cannot prune the storer: %v", err))
 					continue
 				}
 			}
@@ -312,7 +471,7 @@ func main() {
 	}()
 
 	// Start gRPC server and run until the server is stopped
-	log.Printf("gRPC listening on %v:%v...", config.Host, config.Port)
+	logger.Network(logger.LevelInfo, fmt.Sprintf("gRPC listening on %v:%v...", config.Host, config.Port))
 	lis, err := net.Listen("tcp", fmt.Sprintf("%v:%v", config.Host, config.Port))
 	if err != nil {
 		log.Fatalf("cannot listen on %v:%v: %v", config.Host, config.Port, err)
@@ -322,12 +481,17 @@ func main() {
 	}
 }
 
-func getIPAddress() (string, error) {
-	out, err := exec.Command("dig", "+short", "myip.opendns.com", "@resolver1.opendns.com").Output()
+// discoverMultiAddress returns the multiAddress of the darknode at its
+// discovered public endpoint.
+func discoverMultiAddress(discoverer discovery.Source, port int, addr identity.Address) (identity.MultiAddress, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	endpoint, err := discoverer.Discover(ctx, port)
 	if err != nil {
-		return "", err
+		return identity.MultiAddress{}, err
 	}
-	return strings.TrimSpace(string(out)), nil
+	return discovery.NewMultiAddress(endpoint, addr)
 }
 
 // pingNetwork start ping the entire network with a new multiAddress with an
@@ -343,7 +507,7 @@ func pingNetwork(swarmer swarm.Swarmer) error {
 	if err != nil {
 		return err
 	}
-	log.Printf("[info] connected to %v peers", len(peers)-1)
+	logger.Network(logger.LevelInfo, fmt.Sprintf("connected to %v peers", len(peers)-1))
 
 	return nil
 }
@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"math"
 	"math/big"
 	"net/http"
@@ -24,6 +23,7 @@ import (
 	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/registry"
 	"github.com/republicprotocol/republic-go/stackint"
@@ -61,8 +61,9 @@ type Binder struct {
 	darknodeSlasher  *bindings.DarknodeSlasher
 	orderbook        *bindings.Orderbook
 
-	settlementRegistry *bindings.SettlementRegistry
-	renExSettlement    *bindings.Settlement
+	settlementRegistry     *bindings.SettlementRegistry
+	renExSettlement        *bindings.Settlement
+	renExSettlementAddress common.Address
 }
 
 // NewBinder returns a Binder to communicate with contracts
@@ -78,43 +79,43 @@ func NewBinder(auth *bind.TransactOpts, conn Conn) (Binder, error) {
 
 	darknodeRegistry, err := bindings.NewDarknodeRegistry(common.HexToAddress(conn.Config.DarknodeRegistryAddress), bind.ContractBackend(conn.Client))
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot bind to DarknodeRegistry: %v", err))
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot bind to DarknodeRegistry: %v", err))
 		return Binder{}, err
 	}
 
 	republicToken, err := bindings.NewRepublicToken(common.HexToAddress(conn.Config.RepublicTokenAddress), bind.ContractBackend(conn.Client))
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot bind to RepublicToken: %v", err))
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot bind to RepublicToken: %v", err))
 		return Binder{}, err
 	}
 
 	orderbook, err := bindings.NewOrderbook(common.HexToAddress(conn.Config.OrderbookAddress), bind.ContractBackend(conn.Client))
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot bind to Orderbook: %v", err))
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot bind to Orderbook: %v", err))
 		return Binder{}, err
 	}
 
 	settlementRegistry, err := bindings.NewSettlementRegistry(common.HexToAddress(conn.Config.SettlementRegistryAddress), bind.ContractBackend(conn.Client))
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot bind to SettlementRegistry: %v", err))
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot bind to SettlementRegistry: %v", err))
 		return Binder{}, err
 	}
 
 	renExSettlementAddress, err := settlementRegistry.SettlementContract(&bind.CallOpts{}, uint64(order.SettlementRenEx))
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot bind to RenExSettlementAddress: %v", err))
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot bind to RenExSettlementAddress: %v", err))
 		return Binder{}, err
 	}
 
 	renExSettlement, err := bindings.NewSettlement(renExSettlementAddress, bind.ContractBackend(conn.Client))
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot bind to RenExSettlement: %v", err))
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot bind to RenExSettlement: %v", err))
 		return Binder{}, err
 	}
 
 	darknodeSlasher, err := bindings.NewDarknodeSlasher(common.HexToAddress(conn.Config.DarknodeSlasherAddress), bind.ContractBackend(conn.Client))
 	if err != nil {
-		fmt.Println(fmt.Errorf("cannot bind to DarknodeSlasher: %v", err))
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot bind to DarknodeSlasher: %v", err))
 		return Binder{}, err
 	}
 
@@ -130,8 +131,9 @@ func NewBinder(auth *bind.TransactOpts, conn Conn) (Binder, error) {
 		darknodeSlasher:  darknodeSlasher,
 		orderbook:        orderbook,
 
-		settlementRegistry: settlementRegistry,
-		renExSettlement:    renExSettlement,
+		settlementRegistry:     settlementRegistry,
+		renExSettlement:        renExSettlement,
+		renExSettlementAddress: renExSettlementAddress,
 	}
 
 	go func() {
@@ -143,13 +145,13 @@ func NewBinder(auth *bind.TransactOpts, conn Conn) (Binder, error) {
 			client := &http.Client{}
 			response, err := client.Do(request)
 			if err != nil {
-				log.Printf("cannot connect to ethGasStationAPI: %v", err)
+				logger.Contract(logger.LevelWarn, fmt.Sprintf("cannot connect to ethGasStationAPI: %v", err))
 				time.Sleep(3 * time.Minute)
 				continue
 			}
 
 			if response.StatusCode != http.StatusOK {
-				log.Printf("received status code %v from ethGasStation", response.StatusCode)
+				logger.Contract(logger.LevelWarn, fmt.Sprintf("received status code %v from ethGasStation", response.StatusCode))
 				time.Sleep(3 * time.Minute)
 				continue
 			}
@@ -162,7 +164,7 @@ func NewBinder(auth *bind.TransactOpts, conn Conn) (Binder, error) {
 
 			err = json.NewDecoder(response.Body).Decode(&data)
 			if err != nil {
-				log.Printf("cannot decode json response from ethGasStation: %v", err)
+				logger.Contract(logger.LevelWarn, fmt.Sprintf("cannot decode json response from ethGasStation: %v", err))
 				time.Sleep(3 * time.Minute)
 				continue
 			}
@@ -195,12 +197,12 @@ func (binder *Binder) sendTx(f func() (*types.Transaction, error)) (*types.Trans
 		return tx, nil
 	}
 	if err == core.ErrNonceTooLow || err == core.ErrReplaceUnderpriced || strings.Contains(err.Error(), "nonce is too low") {
-		log.Printf("nonce too low, %v", err)
+		logger.Contract(logger.LevelWarn, fmt.Sprintf("nonce too low, %v", err))
 		binder.transactOpts.Nonce.Add(binder.transactOpts.Nonce, big.NewInt(1))
 		return binder.sendTx(f)
 	}
 	if err == core.ErrNonceTooHigh {
-		log.Printf("nonce too high, %v", err)
+		logger.Contract(logger.LevelWarn, fmt.Sprintf("nonce too high, %v", err))
 		binder.transactOpts.Nonce.Sub(binder.transactOpts.Nonce, big.NewInt(1))
 		return binder.sendTx(f)
 	}
@@ -239,6 +241,117 @@ func (binder *Binder) settlementStatus(id order.ID) (uint8, error) {
 	return binder.renExSettlement.OrderStatus(binder.callOpts, id)
 }
 
+// TokenRegistry returns the tokens, and pairs of tokens, supported by the
+// network. If the Config defines a TokenRegistry, it is used. Otherwise, the
+// tokens are loaded from the RenExTokens contract used by the RenEx
+// settlement contract, and each token is paired with ETH. The symbols of the
+// tokens are found using the Tokens in the Config.
+func (binder *Binder) TokenRegistry() (*order.TokenRegistry, error) {
+	if binder.conn.Config.TokenRegistry != nil {
+		return order.NewTokenRegistryFromOptions(*binder.conn.Config.TokenRegistry)
+	}
+
+	binder.mu.RLock()
+	defer binder.mu.RUnlock()
+
+	renExSettlement, err := bindings.NewRenExSettlement(binder.renExSettlementAddress, bind.ContractBackend(binder.conn.Client))
+	if err != nil {
+		return nil, fmt.Errorf("cannot bind to RenExSettlement: %v", err)
+	}
+	renExTokensAddress, err := renExSettlement.RenExTokensContract(binder.callOpts)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get RenExTokens address: %v", err)
+	}
+	renExTokens, err := bindings.NewRenExTokens(renExTokensAddress, bind.ContractBackend(binder.conn.Client))
+	if err != nil {
+		return nil, fmt.Errorf("cannot bind to RenExTokens: %v", err)
+	}
+
+	// Find all tokens that have ever been registered
+	iter, err := renExTokens.FilterLogTokenRegistered(&bind.FilterOpts{Start: 0})
+	if err != nil {
+		return nil, fmt.Errorf("cannot filter registered tokens: %v", err)
+	}
+	codes := map[uint32]struct{}{}
+	for iter.Next() {
+		codes[iter.Event.TokenCode] = struct{}{}
+	}
+	if err := iter.Error(); err != nil {
+		iter.Close()
+		return nil, fmt.Errorf("cannot filter registered tokens: %v", err)
+	}
+	iter.Close()
+
+	symbols := binder.tokenSymbols()
+	defaults := order.NewDefaultTokenRegistry()
+	registry := order.NewTokenRegistry()
+	for code := range codes {
+		details, err := renExTokens.Tokens(binder.callOpts, code)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get token %v: %v", code, err)
+		}
+		if !details.Registered {
+			continue
+		}
+		token := order.Token(code)
+		symbol, ok := symbols[strings.ToLower(details.Addr.Hex())]
+		if !ok {
+			if defaultDetails, err := defaults.Token(token); err == nil {
+				symbol = defaultDetails.Symbol
+			} else {
+				symbol = fmt.Sprintf("%d", code)
+			}
+		}
+		address := ""
+		if details.Addr != (common.Address{}) {
+			address = details.Addr.Hex()
+		}
+		registry.RegisterToken(order.TokenDetails{
+			Token:    token,
+			Symbol:   symbol,
+			Address:  address,
+			Decimals: details.Decimals,
+		})
+	}
+
+	// BTC and ETH are not ERC20 tokens and are always supported
+	for _, token := range []order.Token{order.TokenBTC, order.TokenETH} {
+		if _, err := registry.Token(token); err != nil {
+			details, _ := defaults.Token(token)
+			registry.RegisterToken(details)
+		}
+	}
+	for _, details := range registry.Tokens() {
+		var tokens order.Tokens
+		switch details.Token {
+		case order.TokenETH:
+			continue
+		case order.TokenBTC:
+			tokens = order.NewTokens(order.TokenBTC, order.TokenETH)
+		default:
+			tokens = order.NewTokens(order.TokenETH, details.Token)
+		}
+		if err := registry.RegisterPair(tokens); err != nil {
+			return nil, fmt.Errorf("cannot register pair %v: %v", uint64(tokens), err)
+		}
+	}
+	return registry, nil
+}
+
+// tokenSymbols returns a map from the lowercase address of each token in the
+// Config to its symbol.
+func (binder *Binder) tokenSymbols() map[string]string {
+	tokens := binder.conn.Config.Tokens
+	if len(tokens) == 0 && (binder.network == NetworkMainnet || binder.network == NetworkTestnet) {
+		tokens = TokenAddresses(binder.network)
+	}
+	symbols := make(map[string]string, len(tokens))
+	for symbol, address := range tokens {
+		symbols[strings.ToLower(address)] = symbol
+	}
+	return symbols
+}
+
 // SubmitOrder to the RenEx accounts
 func (binder *Binder) SubmitOrder(ord order.Order) error {
 	if binder.conn.Config.SentryDSN != "" {
@@ -271,10 +384,10 @@ func (binder *Binder) submitOrder(ord order.Order) (*types.Transaction, error) {
 			binder.transactOpts.GasPrice = lastGasPrice
 		}()
 	} else {
-		log.Printf("[error] cannot get submission gas price limit,%v ", err)
+		logger.Contract(logger.LevelError, fmt.Sprintf("cannot get submission gas price limit,%v", err))
 	}
 
-	log.Printf("[info] (submit order) order = %v { %v, %v, %v, %v, %v, %v, %v, %v }",
+	logger.Contract(logger.LevelInfo, fmt.Sprintf("submit order = %v { %v, %v, %v, %v, %v, %v, %v, %v }",
 		ord.ID,
 		ord.Parity,
 		ord.Type,
@@ -284,7 +397,7 @@ func (binder *Binder) submitOrder(ord order.Order) (*types.Transaction, error) {
 		ord.Tokens,
 		ord.Price,
 		ord.Volume,
-		ord.MinimumVolume)
+		ord.MinimumVolume))
 
 	tokens := uint64(ord.Tokens)
 	if ord.Parity == order.ParitySell {
@@ -308,7 +421,7 @@ func (binder *Binder) SubmitMatch(buy, sell order.ID) error {
 }
 
 func (binder *Binder) submitMatch(buy, sell order.ID) (*types.Transaction, error) {
-	log.Printf("[info] (submit match) buy = %v, sell = %v", buy, sell)
+	logger.Contract(logger.LevelInfo, fmt.Sprintf("submit match: buy = %v, sell = %v", buy, sell))
 	return binder.renExSettlement.Settle(binder.transactOpts, buy, sell)
 }
 
@@ -325,7 +438,7 @@ func (binder *Binder) Settle(buy order.Order, sell order.Order) error {
 	for time.Since(start) < time.Duration(5*time.Minute) {
 		err = binder.SettleOrders(buy, sell)
 		if err != nil {
-			log.Printf("[debug] cannot submit match buy = %v, sell = %v, err = %v", buy.ID, sell.ID, err)
+			logger.Contract(logger.LevelDebug, fmt.Sprintf("cannot submit match buy = %v, sell = %v, err = %v", buy.ID, sell.ID, err))
 			time.Sleep(30 * time.Second)
 			continue
 		}
@@ -354,13 +467,13 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 			})
 	}()
 	if buyErr != nil {
-		log.Printf("[error] (settle) cannot get settlement status of buy order [%v]: %v", buy.ID, buyErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot get settlement status of buy order [%v]: %v", buy.ID, buyErr))
 	}
 	if sellErr != nil {
-		log.Printf("[error] (settle) cannot get settlement status of sell order [%v]: %v", sell.ID, sellErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot get settlement status of sell order [%v]: %v", sell.ID, sellErr))
 	}
 	if buyStatus == 2 || sellStatus == 2 {
-		log.Printf("[info] (settle) already settled buy = %v, sell = %v", buy.ID, sell.ID)
+		logger.Contract(logger.LevelInfo, fmt.Sprintf("settle: already settled buy = %v, sell = %v", buy.ID, sell.ID))
 		return nil
 	}
 
@@ -375,7 +488,7 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 				return binder.submitOrder(buy)
 			})
 		} else {
-			log.Printf("[info] (settle) skipping submission of buy = %v", buy.ID)
+			logger.Contract(logger.LevelInfo, fmt.Sprintf("settle: skipping submission of buy = %v", buy.ID))
 			time.Sleep(2 * time.Minute)
 		}
 		if sellStatus == 0 {
@@ -383,26 +496,26 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 				return binder.submitOrder(sell)
 			})
 		} else {
-			log.Printf("[info] (settle) skipping submission of sell = %v", sell.ID)
+			logger.Contract(logger.LevelInfo, fmt.Sprintf("settle: skipping submission of sell = %v", sell.ID))
 			time.Sleep(2 * time.Minute)
 		}
 	}()
 	if buyErr != nil {
-		log.Printf("[error] (settle) cannot submit buy = %v: %v", buy.ID, buyErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot submit buy = %v: %v", buy.ID, buyErr))
 		buyState, err := binder.orderbook.OrderState(binder.callOpts, buy.ID)
 		if err != nil {
-			log.Printf("[error] (settle) cannot get state of buy = %v", buy.ID)
+			logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot get state of buy = %v", buy.ID))
 		} else {
-			log.Printf("[debug] (settle) buy = %v state = %v", buy.ID, buyState)
+			logger.Contract(logger.LevelDebug, fmt.Sprintf("settle: buy = %v state = %v", buy.ID, buyState))
 		}
 	}
 	if sellErr != nil {
-		log.Printf("[error] (settle) cannot submit sell = %v: %v", sell.ID, sellErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot submit sell = %v: %v", sell.ID, sellErr))
 		sellState, err := binder.orderbook.OrderState(binder.callOpts, sell.ID)
 		if err != nil {
-			log.Printf("[error] (settle) cannot get state of sell = %v", sell.ID)
+			logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot get state of sell = %v", sell.ID))
 		} else {
-			log.Printf("[debug] (settle) sell = %v state = %v", sell.ID, sellState)
+			logger.Contract(logger.LevelDebug, fmt.Sprintf("settle: sell = %v state = %v", sell.ID, sellState))
 		}
 	}
 
@@ -421,10 +534,10 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 			}
 		})
 	if buyErr != nil {
-		log.Printf("[error] (settle) cannot wait for submit buy = %v: %v", buy.ID, buyErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot wait for submit buy = %v: %v", buy.ID, buyErr))
 	}
 	if sellErr != nil {
-		log.Printf("[error] (settle) cannot wait for submit sell = %v: %v", sell.ID, sellErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot wait for submit sell = %v: %v", sell.ID, sellErr))
 	}
 
 	time.Sleep(5 * time.Second)
@@ -443,13 +556,13 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 			})
 	}()
 	if buyErr != nil {
-		log.Printf("[error] (settle) cannot get settlement status of buy order [%v]: %v", buy.ID, buyErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot get settlement status of buy order [%v]: %v", buy.ID, buyErr))
 	}
 	if sellErr != nil {
-		log.Printf("[error] (settle) cannot get settlement status of sell order [%v]: %v", sell.ID, sellErr)
+		logger.Contract(logger.LevelError, fmt.Sprintf("settle: cannot get settlement status of sell order [%v]: %v", sell.ID, sellErr))
 	}
 	if buyStatus == 2 || sellStatus == 2 {
-		log.Printf("[info] (settle) already settled buy = %v, sell = %v", buy.ID, sell.ID)
+		logger.Contract(logger.LevelInfo, fmt.Sprintf("settle: already settled buy = %v, sell = %v", buy.ID, sell.ID))
 		return nil
 	}
 
@@ -468,7 +581,7 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 				}()
 			}
 
-			log.Printf("[info] (submit match) buy = %v, sell = %v", buy, sell)
+			logger.Contract(logger.LevelInfo, fmt.Sprintf("submit match: buy = %v, sell = %v", buy, sell))
 			return binder.renExSettlement.Settle(binder.transactOpts, buy.ID, sell.ID)
 		})
 	}()
@@ -484,7 +597,7 @@ func (binder *Binder) SettleOrders(buy order.Order, sell order.Order) error {
 		return fmt.Errorf("cannot wait to settle buy = %v, sell = %v: %v", buy.ID, sell.ID, matchErr)
 	}
 
-	log.Printf("[info] (settle) 💰💰💰 buy = %v, sell = %v 💰💰💰", buy.ID, sell.ID)
+	logger.Contract(logger.LevelInfo, fmt.Sprintf("settle: 💰💰💰 buy = %v, sell = %v 💰💰💰", buy.ID, sell.ID))
 	return nil
 }
 
@@ -1037,7 +1150,7 @@ func (binder *Binder) ConfirmOrder(id order.ID, match order.ID) error {
 
 		switch orderStatus {
 		case order.Nil, order.Canceled:
-			log.Printf("[debug] order =%v has unexpected status %v", id, orderStatus)
+			logger.Contract(logger.LevelDebug, fmt.Sprintf("order =%v has unexpected status %v", id, orderStatus))
 			return nil
 		case order.Open:
 			tx, err := binder.SendTx(func() (*types.Transaction, error) {
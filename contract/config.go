@@ -1,5 +1,7 @@
 package contract
 
+import "github.com/republicprotocol/republic-go/order"
+
 // Network is used to represent a Republic Protocol network.
 type Network string
 
@@ -25,6 +27,11 @@ type Config struct {
 	OrderbookAddress           string            `json:"orderbookAddress"`
 	SettlementRegistryAddress  string            `json:"settlementRegistryAddress"`
 	Tokens                     map[string]string `json:"tokens"`
+
+	// TokenRegistry defines the tokens, and pairs of tokens, supported by
+	// the network. If it is nil, they are loaded from the RenExTokens
+	// contract.
+	TokenRegistry *order.TokenRegistryOptions `json:"tokenRegistry,omitempty"`
 }
 
 // IsNil returns true if Config or any of its fields are nil.
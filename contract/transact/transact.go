@@ -2,7 +2,7 @@ package transact
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"math/big"
 	"strings"
 	"sync"
@@ -14,6 +14,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/republicprotocol/republic-go/logger"
 )
 
 // Transacter exposes functionality for sending transactions to the Ethereum
@@ -120,12 +121,12 @@ func (transacter *transacter) transact(ctx context.Context, buildTx func(context
 		return tx, nil
 	}
 	if err == core.ErrNonceTooLow || err == core.ErrReplaceUnderpriced || strings.Contains(err.Error(), "nonce is too low") {
-		log.Printf("[tx error] nonce too low = %v", err)
+		logger.Contract(logger.LevelError, fmt.Sprintf("nonce too low = %v", err))
 		transacter.transactOpts.Nonce.Add(transacter.transactOpts.Nonce, big.NewInt(1))
 		return transacter.transact(ctx, buildTx)
 	}
 	if err == core.ErrNonceTooHigh {
-		log.Printf("[tx error] nonce too high = %v", err)
+		logger.Contract(logger.LevelError, fmt.Sprintf("nonce too high = %v", err))
 		transacter.transactOpts.Nonce.Sub(transacter.transactOpts.Nonce, big.NewInt(1))
 		return transacter.transact(ctx, buildTx)
 	}
@@ -134,7 +135,7 @@ func (transacter *transacter) transact(ctx context.Context, buildTx func(context
 	// try again for up to 1 minute
 	var nonce uint64
 	for try := 0; try < 60 && strings.Contains(err.Error(), "nonce"); try++ {
-		log.Printf("[tx error] unknown = %v", err)
+		logger.Contract(logger.LevelError, fmt.Sprintf("unknown = %v", err))
 
 		// Delay for a second or until the contex is done
 		select {
@@ -213,6 +213,13 @@ func (verifier EcdsaVerifier) Verify(data []byte, signature []byte) error {
 	return nil
 }
 
+// NewEthereumVerifier returns an EcdsaVerifier that expects the signatory of
+// all signatures that it checks to equal the given hex encoded Ethereum
+// address.
+func NewEthereumVerifier(ethAddr string) EcdsaVerifier {
+	return NewEcdsaVerifier(string(ethAddressToRepublicAddress(ethAddr)))
+}
+
 // RecoverAddress used to produce a signature.
 func RecoverAddress(data []byte, signature []byte) (string, error) {
 
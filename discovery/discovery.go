@@ -0,0 +1,153 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+)
+
+// ErrAddressNotFound is returned when a Source cannot find a public address.
+var ErrAddressNotFound = errors.New("address not found")
+
+// ErrInvalidIPAddress is returned when an IP address cannot be parsed.
+var ErrInvalidIPAddress = errors.New("invalid ip address")
+
+// ErrInvalidPort is returned when a port is not in the range 1 to 65535.
+var ErrInvalidPort = errors.New("invalid port")
+
+// A Source discovers the public endpoint at which a darknode can be reached
+// by other darknodes. It is given the local port on which the darknode is
+// listening, and returns the public IP address and port. Sources that
+// configure port mappings on a gateway can return a different public port to
+// the local port.
+type Source interface {
+	Discover(ctx context.Context, port int) (*net.TCPAddr, error)
+}
+
+type discoverer struct {
+	sources []Source
+}
+
+// NewDiscoverer returns a Source that tries each Source in order, and returns
+// the first endpoint that is discovered. An error is returned when no Source
+// is able to discover an endpoint.
+func NewDiscoverer(sources ...Source) Source {
+	return &discoverer{
+		sources: sources,
+	}
+}
+
+// Discover implements the Source interface.
+func (discoverer *discoverer) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	if port <= 0 || port > 65535 {
+		return nil, ErrInvalidPort
+	}
+	errs := []string{}
+	for _, source := range discoverer.sources {
+		endpoint, err := source.Discover(ctx, port)
+		if err == nil {
+			return endpoint, nil
+		}
+		logger.Network(logger.LevelDebug, fmt.Sprintf("cannot discover address using %T: %v", source, err))
+		errs = append(errs, err.Error())
+
+		// Do not continue once the context is done
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+	}
+	if len(errs) == 0 {
+		return nil, ErrAddressNotFound
+	}
+	return nil, fmt.Errorf("%v: %v", ErrAddressNotFound, strings.Join(errs, "; "))
+}
+
+type staticSource struct {
+	ip net.IP
+}
+
+// NewStaticSource returns a Source that always returns the given IP address.
+// It is used when the public IP address of a darknode is explicitly
+// configured.
+func NewStaticSource(ip string) (Source, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, ErrInvalidIPAddress
+	}
+	return &staticSource{
+		ip: parsed,
+	}, nil
+}
+
+// Discover implements the Source interface.
+func (source *staticSource) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	return &net.TCPAddr{IP: source.ip, Port: port}, nil
+}
+
+// NewMultiAddress returns the identity.MultiAddress for an identity.Address
+// that is reachable at the endpoint. IPv4 endpoints use the /ip4 protocol and
+// IPv6 endpoints use the /ip6 protocol.
+func NewMultiAddress(endpoint *net.TCPAddr, addr identity.Address) (identity.MultiAddress, error) {
+	if endpoint == nil || endpoint.IP == nil {
+		return identity.MultiAddress{}, ErrInvalidIPAddress
+	}
+	if endpoint.Port <= 0 || endpoint.Port > 65535 {
+		return identity.MultiAddress{}, ErrInvalidPort
+	}
+	if ip4 := endpoint.IP.To4(); ip4 != nil {
+		return identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/%v/tcp/%d/republic/%v", ip4, endpoint.Port, addr))
+	}
+	return identity.NewMultiAddressFromString(fmt.Sprintf("/ip6/%v/tcp/%d/republic/%v", endpoint.IP, endpoint.Port, addr))
+}
+
+// Options for building the Sources used to discover the public endpoint of a
+// darknode.
+type Options struct {
+	// PublicIP overrides all other Sources when it is not empty.
+	PublicIP string `json:"publicIp,omitempty"`
+
+	// DisableNATPMP and DisableUPnP turn off the configuration of port
+	// mappings on the gateway.
+	DisableNATPMP bool `json:"disableNatPmp,omitempty"`
+	DisableUPnP   bool `json:"disableUpnp,omitempty"`
+
+	// ObserverQuorum is the number of distinct darknodes that must observe
+	// the same IP address before it is used. Defaults to the
+	// DefaultObserverQuorum.
+	ObserverQuorum int `json:"observerQuorum,omitempty"`
+}
+
+// NewSources returns the Sources described by the Options in the order in
+// which they should be tried. The Observer is used after all gateway and
+// interface Sources have failed, and before falling back to a private
+// interface address.
+func NewSources(options Options, observer Observer) ([]Source, error) {
+	if options.PublicIP != "" {
+		source, err := NewStaticSource(options.PublicIP)
+		if err != nil {
+			return nil, fmt.Errorf("cannot use public ip %v: %v", options.PublicIP, err)
+		}
+		return []Source{source}, nil
+	}
+
+	sources := []Source{}
+	if !options.DisableNATPMP {
+		sources = append(sources, NewNATPMPSource(""))
+	}
+	if !options.DisableUPnP {
+		sources = append(sources, NewUPnPSource(""))
+	}
+	sources = append(sources, NewInterfaceSource(false))
+	if observer != nil {
+		sources = append(sources, observer)
+	}
+	sources = append(sources, NewInterfaceSource(true))
+	return sources, nil
+}
@@ -0,0 +1,138 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/discovery"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+var _ = Describe("Discovery", func() {
+
+	Context("when using multiple sources", func() {
+
+		It("should return the endpoint from the first successful source", func() {
+			static, err := NewStaticSource("203.0.113.1")
+			Expect(err).ShouldNot(HaveOccurred())
+			discoverer := NewDiscoverer(&failingSource{}, static, &failingSource{})
+
+			endpoint, err := discoverer.Discover(context.Background(), 18514)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(endpoint.IP.String()).Should(Equal("203.0.113.1"))
+			Expect(endpoint.Port).Should(Equal(18514))
+		})
+
+		It("should return an error when all sources fail", func() {
+			discoverer := NewDiscoverer(&failingSource{}, &failingSource{})
+
+			_, err := discoverer.Discover(context.Background(), 18514)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should return an error when there are no sources", func() {
+			discoverer := NewDiscoverer()
+
+			_, err := discoverer.Discover(context.Background(), 18514)
+			Expect(err).Should(Equal(ErrAddressNotFound))
+		})
+
+		It("should return an error when the port is invalid", func() {
+			static, err := NewStaticSource("203.0.113.1")
+			Expect(err).ShouldNot(HaveOccurred())
+			discoverer := NewDiscoverer(static)
+
+			_, err = discoverer.Discover(context.Background(), 0)
+			Expect(err).Should(Equal(ErrInvalidPort))
+			_, err = discoverer.Discover(context.Background(), 65536)
+			Expect(err).Should(Equal(ErrInvalidPort))
+		})
+	})
+
+	Context("when using a static source", func() {
+
+		It("should return an error for invalid ip addresses", func() {
+			_, err := NewStaticSource("not an ip")
+			Expect(err).Should(Equal(ErrInvalidIPAddress))
+		})
+
+		It("should return ipv6 addresses", func() {
+			static, err := NewStaticSource("2001:db8::1")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			endpoint, err := static.Discover(context.Background(), 18514)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(endpoint.IP.String()).Should(Equal("2001:db8::1"))
+		})
+	})
+
+	Context("when building sources from options", func() {
+
+		It("should only use the public ip when it is configured", func() {
+			sources, err := NewSources(Options{PublicIP: "203.0.113.1"}, NewObserver(1, 0))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sources).Should(HaveLen(1))
+		})
+
+		It("should return an error when the public ip is invalid", func() {
+			_, err := NewSources(Options{PublicIP: "203.0.113"}, nil)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should not use disabled sources", func() {
+			sources, err := NewSources(Options{}, NewObserver(1, 0))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sources).Should(HaveLen(5))
+
+			sources, err = NewSources(Options{DisableNATPMP: true, DisableUPnP: true}, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sources).Should(HaveLen(2))
+		})
+	})
+
+	Context("when building multi-addresses", func() {
+
+		var addr identity.Address
+
+		BeforeEach(func() {
+			keystore, err := crypto.RandomKeystore()
+			Expect(err).ShouldNot(HaveOccurred())
+			addr = identity.Address(keystore.Address())
+		})
+
+		It("should use the ip4 protocol for ipv4 endpoints", func() {
+			multiAddr, err := NewMultiAddress(&net.TCPAddr{IP: net.ParseIP("203.0.113.1"), Port: 18514}, addr)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(multiAddr.String()).Should(Equal("/ip4/203.0.113.1/tcp/18514/republic/" + addr.String()))
+			Expect(multiAddr.Address()).Should(Equal(addr))
+		})
+
+		It("should use the ip6 protocol for ipv6 endpoints", func() {
+			multiAddr, err := NewMultiAddress(&net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 18514}, addr)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(multiAddr.String()).Should(Equal("/ip6/2001:db8::1/tcp/18514/republic/" + addr.String()))
+			ip, err := multiAddr.ValueForProtocol(identity.IP6Code)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ip).Should(Equal("2001:db8::1"))
+		})
+
+		It("should return an error for invalid endpoints", func() {
+			_, err := NewMultiAddress(nil, addr)
+			Expect(err).Should(Equal(ErrInvalidIPAddress))
+			_, err = NewMultiAddress(&net.TCPAddr{IP: net.ParseIP("203.0.113.1")}, addr)
+			Expect(err).Should(Equal(ErrInvalidPort))
+		})
+	})
+})
+
+type failingSource struct {
+}
+
+func (source *failingSource) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	return nil, errors.New("failing source")
+}
@@ -0,0 +1,102 @@
+package discovery
+
+import (
+	"context"
+	"net"
+)
+
+type interfaceSource struct {
+	allowPrivate bool
+}
+
+// NewInterfaceSource returns a Source that enumerates the addresses of the
+// local network interfaces. Public IPv4 addresses are preferred over public
+// IPv6 addresses. Private addresses are only returned when allowPrivate is
+// true, and only when no public address is available.
+func NewInterfaceSource(allowPrivate bool) Source {
+	return &interfaceSource{
+		allowPrivate: allowPrivate,
+	}
+}
+
+// Discover implements the Source interface.
+func (source *interfaceSource) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		switch addr := addr.(type) {
+		case *net.IPNet:
+			ips = append(ips, addr.IP)
+		case *net.IPAddr:
+			ips = append(ips, addr.IP)
+		}
+	}
+	ip := SelectIP(ips, source.allowPrivate)
+	if ip == nil {
+		return nil, ErrAddressNotFound
+	}
+	return &net.TCPAddr{IP: ip, Port: port}, nil
+}
+
+// SelectIP returns the most suitable IP address for other darknodes to use
+// when connecting. Public IPv4 addresses are preferred, followed by public
+// IPv6 addresses, private IPv4 addresses and private IPv6 addresses. Private
+// addresses are ignored unless allowPrivate is true. Loopback, link-local,
+// multicast and unspecified addresses are always ignored. Nil is returned
+// when no IP address is suitable.
+func SelectIP(ips []net.IP, allowPrivate bool) net.IP {
+	var best net.IP
+	bestRank := 0
+	for _, ip := range ips {
+		if !ip.IsGlobalUnicast() {
+			continue
+		}
+		rank := 4
+		if IsPrivateIP(ip) {
+			if !allowPrivate {
+				continue
+			}
+			rank -= 2
+		}
+		if ip.To4() == nil {
+			rank--
+		}
+		if rank > bestRank {
+			best, bestRank = ip, rank
+		}
+	}
+	return best
+}
+
+var privateNetworks = func() []*net.IPNet {
+	cidrs := []string{
+		"10.0.0.0/8",     // RFC 1918
+		"172.16.0.0/12",  // RFC 1918
+		"192.168.0.0/16", // RFC 1918
+		"100.64.0.0/10",  // RFC 6598 carrier-grade NAT
+		"fc00::/7",       // RFC 4193 unique local
+	}
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}()
+
+// IsPrivateIP returns true if the IP address belongs to a private network and
+// cannot be reached from the public internet.
+func IsPrivateIP(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package discovery_test
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/discovery"
+)
+
+var _ = Describe("Interface discovery", func() {
+
+	ips := func(addrs ...string) []net.IP {
+		ips := make([]net.IP, len(addrs))
+		for i, addr := range addrs {
+			ips[i] = net.ParseIP(addr)
+		}
+		return ips
+	}
+
+	Context("when selecting an ip address", func() {
+
+		It("should prefer public ipv4 addresses", func() {
+			ip := SelectIP(ips("192.168.1.2", "2001:db8::1", "203.0.113.1"), true)
+			Expect(ip.String()).Should(Equal("203.0.113.1"))
+		})
+
+		It("should prefer public ipv6 addresses over private addresses", func() {
+			ip := SelectIP(ips("192.168.1.2", "10.0.0.1", "2001:db8::1"), true)
+			Expect(ip.String()).Should(Equal("2001:db8::1"))
+		})
+
+		It("should ignore private addresses unless they are allowed", func() {
+			Expect(SelectIP(ips("192.168.1.2", "fd00::1"), false)).Should(BeNil())
+			Expect(SelectIP(ips("fd00::1", "192.168.1.2"), true).String()).Should(Equal("192.168.1.2"))
+		})
+
+		It("should ignore loopback, link-local and unspecified addresses", func() {
+			Expect(SelectIP(ips("127.0.0.1", "::1", "fe80::1", "169.254.0.1", "0.0.0.0"), true)).Should(BeNil())
+		})
+	})
+
+	Context("when checking for private ip addresses", func() {
+
+		It("should detect private networks", func() {
+			Expect(IsPrivateIP(net.ParseIP("10.1.2.3"))).Should(BeTrue())
+			Expect(IsPrivateIP(net.ParseIP("172.16.0.1"))).Should(BeTrue())
+			Expect(IsPrivateIP(net.ParseIP("192.168.0.1"))).Should(BeTrue())
+			Expect(IsPrivateIP(net.ParseIP("100.64.0.1"))).Should(BeTrue())
+			Expect(IsPrivateIP(net.ParseIP("fd12:3456::1"))).Should(BeTrue())
+		})
+
+		It("should not detect public networks", func() {
+			Expect(IsPrivateIP(net.ParseIP("203.0.113.1"))).Should(BeFalse())
+			Expect(IsPrivateIP(net.ParseIP("172.32.0.1"))).Should(BeFalse())
+			Expect(IsPrivateIP(net.ParseIP("2001:db8::1"))).Should(BeFalse())
+		})
+	})
+
+	Context("when enumerating interfaces", func() {
+
+		It("should return an address with the given port if private addresses are allowed", func() {
+			endpoint, err := NewInterfaceSource(true).Discover(context.Background(), 18514)
+			if err != nil {
+				// The host has no suitable interfaces
+				Expect(err).Should(Equal(ErrAddressNotFound))
+				return
+			}
+			Expect(endpoint.IP.IsGlobalUnicast()).Should(BeTrue())
+			Expect(endpoint.Port).Should(Equal(18514))
+		})
+	})
+})
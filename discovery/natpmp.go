@@ -0,0 +1,161 @@
+package discovery
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrGatewayNotFound is returned when the default gateway cannot be found.
+var ErrGatewayNotFound = errors.New("gateway not found")
+
+// ErrUnexpectedResponse is returned when a gateway responds with a malformed
+// or unexpected message.
+var ErrUnexpectedResponse = errors.New("unexpected response from gateway")
+
+// NATPMPPort is the UDP port on which gateways listen for NAT-PMP requests.
+const NATPMPPort = 5351
+
+// MappingLifetime is the lifetime requested for port mappings. Port mappings
+// must be refreshed before this lifetime expires.
+const MappingLifetime = 2 * time.Hour
+
+const (
+	natpmpOpExternalAddress = 0
+	natpmpOpMapTCP          = 2
+	natpmpOpResponse        = 128
+	natpmpMaxAttempts       = 4
+	natpmpInitialTimeout    = 250 * time.Millisecond
+)
+
+type natpmpSource struct {
+	gateway string
+}
+
+// NewNATPMPSource returns a Source that uses NAT-PMP (RFC 6886) to learn the
+// external IPv4 address of the gateway and to map the local TCP port to the
+// same external port. The gateway is a "host:port" string. When it is empty,
+// the default gateway is used.
+func NewNATPMPSource(gateway string) Source {
+	return &natpmpSource{
+		gateway: gateway,
+	}
+}
+
+// Discover implements the Source interface.
+func (source *natpmpSource) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	gateway := source.gateway
+	if gateway == "" {
+		ip, err := DefaultGateway()
+		if err != nil {
+			return nil, err
+		}
+		gateway = net.JoinHostPort(ip.String(), strconv.Itoa(NATPMPPort))
+	}
+	conn, err := net.Dial("udp4", gateway)
+	if err != nil {
+		return nil, fmt.Errorf("cannot dial gateway %v: %v", gateway, err)
+	}
+	defer conn.Close()
+
+	// Request the external address
+	res, err := natpmpRoundTrip(ctx, conn, []byte{0, natpmpOpExternalAddress}, 12)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get external address: %v", err)
+	}
+	ip := net.IPv4(res[8], res[9], res[10], res[11])
+
+	// Request a mapping for the port
+	req := make([]byte, 12)
+	req[1] = natpmpOpMapTCP
+	binary.BigEndian.PutUint16(req[4:6], uint16(port))
+	binary.BigEndian.PutUint16(req[6:8], uint16(port))
+	binary.BigEndian.PutUint32(req[8:12], uint32(MappingLifetime/time.Second))
+	res, err = natpmpRoundTrip(ctx, conn, req, 16)
+	if err != nil {
+		return nil, fmt.Errorf("cannot map port %v: %v", port, err)
+	}
+	externalPort := int(binary.BigEndian.Uint16(res[10:12]))
+
+	return &net.TCPAddr{IP: ip, Port: externalPort}, nil
+}
+
+// natpmpRoundTrip sends a request and waits for a successful response of the
+// expected length. The request is retransmitted with exponential backoff, as
+// required by RFC 6886, until the context is done.
+func natpmpRoundTrip(ctx context.Context, conn net.Conn, req []byte, n int) ([]byte, error) {
+	res := make([]byte, 16)
+	timeout := natpmpInitialTimeout
+	for attempt := 0; attempt < natpmpMaxAttempts; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+		deadline := time.Now().Add(timeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		if err := conn.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+		timeout *= 2
+
+		m, err := conn.Read(res)
+		if err != nil {
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				continue
+			}
+			return nil, err
+		}
+		if m < n || res[0] != 0 || res[1] != natpmpOpResponse+req[1] {
+			return nil, ErrUnexpectedResponse
+		}
+		if code := binary.BigEndian.Uint16(res[2:4]); code != 0 {
+			return nil, fmt.Errorf("gateway returned result code %v", code)
+		}
+		return res[:n], nil
+	}
+	return nil, fmt.Errorf("gateway did not respond after %v attempts", natpmpMaxAttempts)
+}
+
+// DefaultGateway returns the IPv4 address of the default gateway by reading
+// the kernel routing table. It is only supported on Linux.
+func DefaultGateway() (net.IP, error) {
+	file, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, ErrGatewayNotFound
+	}
+	defer file.Close()
+	return parseRoutes(file)
+}
+
+func parseRoutes(r io.Reader) (net.IP, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// Iface Destination Gateway Flags ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		gateway, err := hex.DecodeString(fields[2])
+		if err != nil || len(gateway) != 4 {
+			continue
+		}
+		// The routing table stores addresses in host byte order
+		return net.IPv4(gateway[3], gateway[2], gateway[1], gateway[0]), nil
+	}
+	return nil, ErrGatewayNotFound
+}
@@ -0,0 +1,93 @@
+package discovery_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/discovery"
+)
+
+var _ = Describe("NAT-PMP discovery", func() {
+
+	var gateway *net.UDPConn
+
+	BeforeEach(func() {
+		var err error
+		gateway, err = net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		gateway.Close()
+	})
+
+	Context("when the gateway supports NAT-PMP", func() {
+
+		It("should return the external address and mapped port", func() {
+			go serveNATPMP(gateway, net.IPv4(203, 0, 113, 1), 28514, 0)
+
+			source := NewNATPMPSource(gateway.LocalAddr().String())
+			endpoint, err := source.Discover(context.Background(), 18514)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(endpoint.IP.String()).Should(Equal("203.0.113.1"))
+			Expect(endpoint.Port).Should(Equal(28514))
+		})
+
+		It("should return an error when the gateway refuses the request", func() {
+			go serveNATPMP(gateway, net.IPv4(203, 0, 113, 1), 28514, 2)
+
+			source := NewNATPMPSource(gateway.LocalAddr().String())
+			_, err := source.Discover(context.Background(), 18514)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when the gateway does not respond", func() {
+
+		It("should return an error when the context is done", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			source := NewNATPMPSource(gateway.LocalAddr().String())
+			_, err := source.Discover(ctx, 18514)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
+
+// serveNATPMP responds to NAT-PMP requests until the connection is closed.
+func serveNATPMP(conn *net.UDPConn, external net.IP, externalPort uint16, resultCode uint16) {
+	defer GinkgoRecover()
+
+	buf := make([]byte, 64)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if n < 2 {
+			continue
+		}
+		var res []byte
+		switch buf[1] {
+		case 0:
+			res = make([]byte, 12)
+			copy(res[8:12], external.To4())
+		case 2:
+			res = make([]byte, 16)
+			copy(res[8:10], buf[4:6])
+			binary.BigEndian.PutUint16(res[10:12], externalPort)
+			copy(res[12:16], buf[8:12])
+		default:
+			continue
+		}
+		res[1] = 128 + buf[1]
+		binary.BigEndian.PutUint16(res[2:4], resultCode)
+		binary.BigEndian.PutUint32(res[4:8], 1)
+		conn.WriteToUDP(res, from)
+	}
+}
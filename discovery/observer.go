@@ -0,0 +1,97 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// An Observer records the IP addresses at which other darknodes observe this
+// darknode. It is also a Source that returns the IP address observed by a
+// quorum of distinct darknodes.
+type Observer interface {
+	Source
+
+	// Observe records that the darknode at the identity.Address observed
+	// this darknode at the IP address. Only the latest observation from
+	// each darknode is kept.
+	Observe(from identity.Address, ip net.IP)
+}
+
+type observation struct {
+	ip         string
+	observedAt time.Time
+}
+
+type observer struct {
+	quorum int
+	expiry time.Duration
+
+	mu           *sync.Mutex
+	observations map[identity.Address]observation
+}
+
+// DefaultObserverQuorum is the number of distinct darknodes that must agree
+// on an IP address when no quorum is configured.
+const DefaultObserverQuorum = 3
+
+// NewObserver returns an Observer that requires a quorum of distinct
+// darknodes to agree on an IP address before it is returned. Observations
+// older than the expiry are ignored. A quorum less than one is replaced by
+// the DefaultObserverQuorum.
+func NewObserver(quorum int, expiry time.Duration) Observer {
+	if quorum < 1 {
+		quorum = DefaultObserverQuorum
+	}
+	return &observer{
+		quorum: quorum,
+		expiry: expiry,
+
+		mu:           new(sync.Mutex),
+		observations: map[identity.Address]observation{},
+	}
+}
+
+// Observe implements the Observer interface.
+func (observer *observer) Observe(from identity.Address, ip net.IP) {
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() || ip.IsMulticast() {
+		return
+	}
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+
+	observer.observations[from] = observation{
+		ip:         ip.String(),
+		observedAt: time.Now(),
+	}
+}
+
+// Discover implements the Source interface.
+func (observer *observer) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+
+	now := time.Now()
+	counts := map[string]int{}
+	for from, obs := range observer.observations {
+		if now.Sub(obs.observedAt) > observer.expiry {
+			delete(observer.observations, from)
+			continue
+		}
+		counts[obs.ip]++
+	}
+
+	best, bestCount := "", 0
+	for ip, count := range counts {
+		if count > bestCount || (count == bestCount && ip < best) {
+			best, bestCount = ip, count
+		}
+	}
+	if bestCount < observer.quorum {
+		return nil, ErrAddressNotFound
+	}
+	return &net.TCPAddr{IP: net.ParseIP(best), Port: port}, nil
+}
@@ -0,0 +1,80 @@
+package discovery_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/discovery"
+
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+var _ = Describe("Observer", func() {
+
+	addr := func(i int) identity.Address {
+		return identity.Address(fmt.Sprintf("darknode%d", i))
+	}
+
+	Context("when darknodes report observed addresses", func() {
+
+		It("should not return an address before a quorum is reached", func() {
+			observer := NewObserver(3, time.Hour)
+			observer.Observe(addr(0), net.ParseIP("203.0.113.1"))
+			observer.Observe(addr(1), net.ParseIP("203.0.113.1"))
+
+			_, err := observer.Discover(context.Background(), 18514)
+			Expect(err).Should(Equal(ErrAddressNotFound))
+		})
+
+		It("should return an address once a quorum is reached", func() {
+			observer := NewObserver(3, time.Hour)
+			for i := 0; i < 3; i++ {
+				observer.Observe(addr(i), net.ParseIP("203.0.113.1"))
+			}
+			observer.Observe(addr(3), net.ParseIP("198.51.100.1"))
+
+			endpoint, err := observer.Discover(context.Background(), 18514)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(endpoint.IP.String()).Should(Equal("203.0.113.1"))
+			Expect(endpoint.Port).Should(Equal(18514))
+		})
+
+		It("should only count the latest observation from each darknode", func() {
+			observer := NewObserver(2, time.Hour)
+			observer.Observe(addr(0), net.ParseIP("203.0.113.1"))
+			observer.Observe(addr(0), net.ParseIP("203.0.113.1"))
+
+			_, err := observer.Discover(context.Background(), 18514)
+			Expect(err).Should(Equal(ErrAddressNotFound))
+
+			observer.Observe(addr(1), net.ParseIP("2001:db8::1"))
+			observer.Observe(addr(0), net.ParseIP("2001:db8::1"))
+
+			endpoint, err := observer.Discover(context.Background(), 18514)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(endpoint.IP.String()).Should(Equal("2001:db8::1"))
+		})
+
+		It("should ignore loopback and unspecified addresses", func() {
+			observer := NewObserver(1, time.Hour)
+			observer.Observe(addr(0), net.ParseIP("127.0.0.1"))
+			observer.Observe(addr(1), net.ParseIP("::"))
+
+			_, err := observer.Discover(context.Background(), 18514)
+			Expect(err).Should(Equal(ErrAddressNotFound))
+		})
+
+		It("should ignore expired observations", func() {
+			observer := NewObserver(1, 10*time.Millisecond)
+			observer.Observe(addr(0), net.ParseIP("203.0.113.1"))
+			time.Sleep(20 * time.Millisecond)
+
+			_, err := observer.Discover(context.Background(), 18514)
+			Expect(err).Should(Equal(ErrAddressNotFound))
+		})
+	})
+})
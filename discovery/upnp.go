@@ -0,0 +1,259 @@
+package discovery
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ssdpAddress     = "239.255.255.250:1900"
+	ssdpSearchType  = "urn:schemas-upnp-org:device:InternetGatewayDevice:1"
+	ssdpWaitTimeout = 2 * time.Second
+)
+
+type upnpSource struct {
+	location string
+}
+
+// NewUPnPSource returns a Source that uses a UPnP Internet Gateway Device to
+// learn the external IP address of the gateway and to map the local TCP port
+// to the same external port. The location is the URL of the device
+// description. When it is empty, the device is found using SSDP multicast.
+func NewUPnPSource(location string) Source {
+	return &upnpSource{
+		location: location,
+	}
+}
+
+// Discover implements the Source interface.
+func (source *upnpSource) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	location := source.location
+	if location == "" {
+		var err error
+		if location, err = ssdpSearch(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	service, err := upnpFindService(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	// Request the external address
+	res, err := service.call(ctx, "GetExternalIPAddress", "")
+	if err != nil {
+		return nil, fmt.Errorf("cannot get external address: %v", err)
+	}
+	ip := net.ParseIP(strings.TrimSpace(xmlValue(res, "NewExternalIPAddress")))
+	if ip == nil {
+		return nil, ErrUnexpectedResponse
+	}
+
+	// Request a mapping for the port
+	internalIP, err := service.internalIP()
+	if err != nil {
+		return nil, err
+	}
+	args := fmt.Sprintf(
+		"<NewRemoteHost></NewRemoteHost>"+
+			"<NewExternalPort>%d</NewExternalPort>"+
+			"<NewProtocol>TCP</NewProtocol>"+
+			"<NewInternalPort>%d</NewInternalPort>"+
+			"<NewInternalClient>%v</NewInternalClient>"+
+			"<NewEnabled>1</NewEnabled>"+
+			"<NewPortMappingDescription>darknode</NewPortMappingDescription>"+
+			"<NewLeaseDuration>%d</NewLeaseDuration>",
+		port, port, internalIP, int(MappingLifetime/time.Second))
+	if _, err := service.call(ctx, "AddPortMapping", args); err != nil {
+		return nil, fmt.Errorf("cannot map port %v: %v", port, err)
+	}
+
+	return &net.TCPAddr{IP: ip, Port: port}, nil
+}
+
+// ssdpSearch multicasts an SSDP search for an Internet Gateway Device and
+// returns the location of the first device that responds.
+func ssdpSearch(ctx context.Context) (string, error) {
+	addr, err := net.ResolveUDPAddr("udp4", ssdpAddress)
+	if err != nil {
+		return "", err
+	}
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	req := "M-SEARCH * HTTP/1.1\r\n" +
+		"HOST: " + ssdpAddress + "\r\n" +
+		"ST: " + ssdpSearchType + "\r\n" +
+		"MAN: \"ssdp:discover\"\r\n" +
+		"MX: 2\r\n\r\n"
+	if _, err := conn.WriteTo([]byte(req), addr); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(ssdpWaitTimeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return "", ErrGatewayNotFound
+		}
+		res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(buf[:n])), nil)
+		if err != nil {
+			continue
+		}
+		res.Body.Close()
+		if location := res.Header.Get("Location"); location != "" {
+			return location, nil
+		}
+	}
+}
+
+type upnpDevice struct {
+	Services []upnpServiceDesc `xml:"serviceList>service"`
+	Devices  []upnpDevice      `xml:"deviceList>device"`
+}
+
+type upnpServiceDesc struct {
+	ServiceType string `xml:"serviceType"`
+	ControlURL  string `xml:"controlURL"`
+}
+
+type upnpRoot struct {
+	URLBase string     `xml:"URLBase"`
+	Device  upnpDevice `xml:"device"`
+}
+
+type upnpService struct {
+	serviceType string
+	controlURL  *url.URL
+}
+
+// upnpFindService fetches the device description at the location and returns
+// the first WANIPConnection, or WANPPPConnection, service.
+func upnpFindService(ctx context.Context, location string) (upnpService, error) {
+	req, err := http.NewRequest("GET", location, nil)
+	if err != nil {
+		return upnpService{}, err
+	}
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return upnpService{}, fmt.Errorf("cannot get device description: %v", err)
+	}
+	defer res.Body.Close()
+
+	root := upnpRoot{}
+	if err := xml.NewDecoder(res.Body).Decode(&root); err != nil {
+		return upnpService{}, fmt.Errorf("cannot decode device description: %v", err)
+	}
+	base, err := url.Parse(location)
+	if err != nil {
+		return upnpService{}, err
+	}
+	if root.URLBase != "" {
+		if base, err = url.Parse(root.URLBase); err != nil {
+			return upnpService{}, err
+		}
+	}
+
+	devices := []upnpDevice{root.Device}
+	for len(devices) > 0 {
+		device := devices[0]
+		devices = append(devices[1:], device.Devices...)
+		for _, desc := range device.Services {
+			if !strings.Contains(desc.ServiceType, ":WANIPConnection:") && !strings.Contains(desc.ServiceType, ":WANPPPConnection:") {
+				continue
+			}
+			controlURL, err := base.Parse(desc.ControlURL)
+			if err != nil {
+				return upnpService{}, err
+			}
+			return upnpService{
+				serviceType: desc.ServiceType,
+				controlURL:  controlURL,
+			}, nil
+		}
+	}
+	return upnpService{}, ErrGatewayNotFound
+}
+
+// call invokes a SOAP action on the service and returns the response body.
+func (service upnpService) call(ctx context.Context, action, args string) ([]byte, error) {
+	body := `<?xml version="1.0"?>` +
+		`<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/" s:encodingStyle="http://schemas.xmlsoap.org/soap/encoding/">` +
+		`<s:Body><u:` + action + ` xmlns:u="` + service.serviceType + `">` + args + `</u:` + action + `></s:Body>` +
+		`</s:Envelope>`
+	req, err := http.NewRequest("POST", service.controlURL.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", `text/xml; charset="utf-8"`)
+	req.Header.Set("SOAPAction", `"`+service.serviceType+"#"+action+`"`)
+
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(res.Body); err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gateway returned status %v", res.Status)
+	}
+	return buf.Bytes(), nil
+}
+
+// internalIP returns the local IP address that is used to reach the gateway.
+func (service upnpService) internalIP() (net.IP, error) {
+	host := service.controlURL.Hostname()
+	port := service.controlURL.Port()
+	if port == "" {
+		port = "80"
+	}
+	conn, err := net.Dial("udp", net.JoinHostPort(host, port))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// xmlValue returns the character data of the first element with the local
+// name, or an empty string if there is no such element.
+func xmlValue(data []byte, name string) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == name {
+			value := ""
+			if err := decoder.DecodeElement(&value, &start); err != nil {
+				return ""
+			}
+			return value
+		}
+	}
+}
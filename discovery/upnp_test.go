@@ -0,0 +1,125 @@
+package discovery_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/discovery"
+)
+
+const mockDeviceDescription = `<?xml version="1.0"?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+  <device>
+    <deviceType>urn:schemas-upnp-org:device:InternetGatewayDevice:1</deviceType>
+    <deviceList>
+      <device>
+        <deviceType>urn:schemas-upnp-org:device:WANDevice:1</deviceType>
+        <deviceList>
+          <device>
+            <deviceType>urn:schemas-upnp-org:device:WANConnectionDevice:1</deviceType>
+            <serviceList>
+              <service>
+                <serviceType>urn:schemas-upnp-org:service:WANIPConnection:1</serviceType>
+                <controlURL>/control</controlURL>
+              </service>
+            </serviceList>
+          </device>
+        </deviceList>
+      </device>
+    </deviceList>
+  </device>
+</root>`
+
+const mockExternalIPAddressResponse = `<?xml version="1.0"?>
+<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/">
+  <s:Body>
+    <u:GetExternalIPAddressResponse xmlns:u="urn:schemas-upnp-org:service:WANIPConnection:1">
+      <NewExternalIPAddress>203.0.113.1</NewExternalIPAddress>
+    </u:GetExternalIPAddressResponse>
+  </s:Body>
+</s:Envelope>`
+
+var _ = Describe("UPnP discovery", func() {
+
+	var mu *sync.Mutex
+	var mappings []string
+	var mappingFails bool
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		mu = new(sync.Mutex)
+		mappings = []string{}
+		mappingFails = false
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/description.xml", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, mockDeviceDescription)
+		})
+		mux.HandleFunc("/control", func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			action := r.Header.Get("SOAPAction")
+			switch {
+			case strings.HasSuffix(action, `#GetExternalIPAddress"`):
+				fmt.Fprint(w, mockExternalIPAddressResponse)
+			case strings.HasSuffix(action, `#AddPortMapping"`):
+				mu.Lock()
+				defer mu.Unlock()
+				if mappingFails {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				mappings = append(mappings, string(body))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		})
+		server = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when the gateway supports UPnP", func() {
+
+		It("should return the external address and map the port", func() {
+			source := NewUPnPSource(server.URL + "/description.xml")
+			endpoint, err := source.Discover(context.Background(), 18514)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(endpoint.IP.String()).Should(Equal("203.0.113.1"))
+			Expect(endpoint.Port).Should(Equal(18514))
+
+			mu.Lock()
+			defer mu.Unlock()
+			Expect(mappings).Should(HaveLen(1))
+			Expect(mappings[0]).Should(ContainSubstring("<NewExternalPort>18514</NewExternalPort>"))
+			Expect(mappings[0]).Should(ContainSubstring("<NewInternalClient>127.0.0.1</NewInternalClient>"))
+		})
+
+		It("should return an error when the port cannot be mapped", func() {
+			mu.Lock()
+			mappingFails = true
+			mu.Unlock()
+
+			source := NewUPnPSource(server.URL + "/description.xml")
+			_, err := source.Discover(context.Background(), 18514)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when the device description is invalid", func() {
+
+		It("should return an error", func() {
+			source := NewUPnPSource(server.URL + "/missing.xml")
+			_, err := source.Discover(context.Background(), 18514)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -3,10 +3,11 @@ package grpc
 import (
 	"context"
 	"fmt"
-	"log"
+	"net"
 	"time"
 
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
 	"google.golang.org/grpc"
 )
 
@@ -14,24 +15,38 @@ import (
 // used to cancel or expire the pending connection. Once this function returns,
 // the cancellation and expiration of the Context will do nothing. Users must
 // call grpc.ClientConn.Close to terminate all the pending operations after
-// this function returns.
+// this function returns. The multiaddress can use the /ip4/, /ip6/, /dns4/ or
+// /dns6/ protocols. Hostnames are resolved to an address of the respective IP
+// version whenever a connection is established. The DefaultCredentials are
+// used to secure the connection.
 func Dial(ctx context.Context, multiAddress identity.MultiAddress) (*grpc.ClientConn, error) {
+	return DialWithCredentials(ctx, multiAddress, DefaultCredentials())
+}
+
+// DialWithCredentials is the same as Dial but it uses the given Credentials to
+// secure the connection. The server must present a certificate for the
+// identity.Address of the multiaddress. If the Credentials are nil, the
+// connection is insecure.
+func DialWithCredentials(ctx context.Context, multiAddress identity.MultiAddress, creds *Credentials) (*grpc.ClientConn, error) {
 	if multiAddress.IsNil() {
 		return nil, ErrMultiAddressIsNil
 	}
-	host, err := multiAddress.ValueForProtocol(identity.IP4Code)
+	network, addr, err := multiAddress.NetworkAddress()
 	if err != nil {
 		return nil, err
 	}
-	port, err := multiAddress.ValueForProtocol(identity.TCPCode)
-	if err != nil {
-		return nil, err
+	dialer := func(addr string, timeout time.Duration) (net.Conn, error) {
+		return net.DialTimeout(network, addr, timeout)
+	}
+	security := grpc.WithInsecure()
+	if creds != nil {
+		security = creds.DialOption(multiAddress.Address())
 	}
-	clientConn, err := grpc.DialContext(ctx, fmt.Sprintf("%s:%s", host, port), grpc.WithInsecure())
+	clientConn, err := grpc.DialContext(ctx, addr, security, grpc.WithDialer(dialer))
 	if err != nil {
 		if clientConn != nil {
 			if err := clientConn.Close(); err != nil {
-				log.Printf("[error] (dial) cannot close broken connection attempt = %v", err)
+				logger.Stream(logger.LevelError, fmt.Sprintf("dial: cannot close broken connection attempt = %v", err))
 			}
 		}
 		return nil, err
@@ -16,6 +16,8 @@ import (
 
 	_ "github.com/republicprotocol/republic-go/identity" // initialise the protocol
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ = Describe("Connections", func() {
@@ -53,6 +55,59 @@ var _ = Describe("Connections", func() {
 
 		})
 
+		It("should return a connection for dns4 multiaddresses", func() {
+			ecdsaKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			multiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/dns4/localhost/tcp/3000/republic/%s", ecdsaKey.Address()))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			conn, err := Dial(context.Background(), multiAddr)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			// The server has no services, so a connected client will receive
+			// an unimplemented error
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = NewSwarmServiceClient(conn).Ping(ctx, &PingRequest{}, grpc.FailFast(false))
+			Expect(status.Code(err)).Should(Equal(codes.Unimplemented))
+		})
+
+		It("should return a connection for ip6 multiaddresses", func() {
+			server6 := grpc.NewServer()
+			listener, err := net.Listen("tcp6", "[::1]:3001")
+			if err != nil {
+				Skip(fmt.Sprintf("ipv6 is not available: %v", err))
+			}
+			go server6.Serve(listener)
+			defer server6.Stop()
+
+			ecdsaKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			multiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip6/::1/tcp/3001/republic/%s", ecdsaKey.Address()))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			conn, err := Dial(context.Background(), multiAddr)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err = NewSwarmServiceClient(conn).Ping(ctx, &PingRequest{}, grpc.FailFast(false))
+			Expect(status.Code(err)).Should(Equal(codes.Unimplemented))
+		})
+
+		It("should error for multiaddresses without a supported network", func() {
+			ecdsaKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			multiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/udp/3000/republic/%s", ecdsaKey.Address()))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			conn, err := Dial(context.Background(), multiAddr)
+			Expect(err).Should(Equal(identity.ErrUnsupportedNetwork))
+			Expect(conn).Should(BeNil())
+		})
+
 		It("should error for nil multi-addresses", func() {
 			conn, err := Dial(context.Background(), identity.MultiAddress{})
 			Expect(err).Should(HaveOccurred())
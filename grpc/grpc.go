@@ -3,10 +3,10 @@ package grpc
 import (
 	"context"
 	"fmt"
-	"log"
 	"net"
 
 	"github.com/pkg/errors"
+	"github.com/republicprotocol/republic-go/logger"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/peer"
 )
@@ -16,21 +16,29 @@ type Server struct {
 	*grpc.Server
 }
 
+// NewServer re-exports the grpc.NewServer function. The server uses the
+// DefaultCredentials, if they are set.
 func NewServer() *Server {
-	return &Server{grpc.NewServer()}
+	return &Server{grpc.NewServer(serverOptions()...)}
 }
 
-// NewServer re-exports the grpc.NewServer function.
+// NewServerwithLimiter returns a Server that limits the rate of unary and
+// stream requests from each client. The server uses the DefaultCredentials, if
+// they are set.
 func NewServerwithLimiter(unaryLimiter, streamLimiter *RateLimiter) *Server {
 	unaryInterceptor := grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		clientIP, err := addressFromContext(ctx)
 		if err != nil {
 			return nil, err
 		}
-		if unaryLimiter.Allow(clientIP) {
+		cost := 1
+		if batch, ok := req.(rateLimitedRequest); ok {
+			cost = batch.rateLimitCost()
+		}
+		if unaryLimiter.AllowN(clientIP, cost) {
 			return handler(ctx, req)
 		}
-		log.Println(clientIP, "hit the unary rate limit")
+		logger.Stream(logger.LevelWarn, fmt.Sprintf("%v hit the unary rate limit", clientIP))
 
 		return nil, errors.New("429: Too Many Requests")
 	})
@@ -43,12 +51,25 @@ func NewServerwithLimiter(unaryLimiter, streamLimiter *RateLimiter) *Server {
 		if streamLimiter.Allow(clientIP) {
 			return handler(srv, stream)
 		}
-		log.Println(clientIP, "hit the stream rate limit")
+		logger.Stream(logger.LevelWarn, fmt.Sprintf("%v hit the stream rate limit", clientIP))
 
 		return errors.New("429: Too Many Requests")
 	})
 
-	return &Server{grpc.NewServer(unaryInterceptor, streamInterceptor)}
+	return &Server{grpc.NewServer(append(serverOptions(), unaryInterceptor, streamInterceptor)...)}
+}
+
+// A rateLimitedRequest costs more than one request when rate limiting. For
+// example, a batch of requests.
+type rateLimitedRequest interface {
+	rateLimitCost() int
+}
+
+func serverOptions() []grpc.ServerOption {
+	if creds := DefaultCredentials(); creds != nil {
+		return []grpc.ServerOption{creds.ServerOption()}
+	}
+	return []grpc.ServerOption{}
 }
 
 // Start the Server listening on a TCP connection at the given binding address.
@@ -18,9 +18,17 @@ It has these top-level messages:
 	StreamMessage
 	OpenOrderRequest
 	OpenOrderResponse
+	OpenOrderBatchRequest
+	OpenOrderBatchResponse
+	OpenOrderAck
+	QueryOrderRequest
+	QueryOrderResponse
+	SubscribeOrderRequest
+	OrderEvent
 	EncryptedOrderFragment
 	EncryptedCoExpShare
 	OrderFragmentCommitment
+	PolynomialCommitment
 	CoExpCommitment
 	StatusRequest
 	StatusResponse
@@ -178,7 +186,8 @@ func (*PingResponse) ProtoMessage()               {}
 func (*PingResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 type PongRequest struct {
-	MultiAddress *MultiAddress `protobuf:"bytes,1,opt,name=multiAddress" json:"multiAddress,omitempty"`
+	MultiAddress    *MultiAddress `protobuf:"bytes,1,opt,name=multiAddress" json:"multiAddress,omitempty"`
+	ObservedAddress string        `protobuf:"bytes,2,opt,name=observedAddress" json:"observedAddress,omitempty"`
 }
 
 func (m *PongRequest) Reset()                    { *m = PongRequest{} }
@@ -193,6 +202,13 @@ func (m *PongRequest) GetMultiAddress() *MultiAddress {
 	return nil
 }
 
+func (m *PongRequest) GetObservedAddress() string {
+	if m != nil {
+		return m.ObservedAddress
+	}
+	return ""
+}
+
 type PongResponse struct {
 }
 
@@ -234,10 +250,12 @@ func (m *QueryResponse) GetMultiAddresses() []*MultiAddress {
 }
 
 type StreamMessage struct {
-	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
-	Address   string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
-	Network   []byte `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
-	Data      []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Signature    []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Address      string `protobuf:"bytes,2,opt,name=address" json:"address,omitempty"`
+	Network      []byte `protobuf:"bytes,3,opt,name=network,proto3" json:"network,omitempty"`
+	Data         []byte `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+	Version      uint32 `protobuf:"varint,5,opt,name=version" json:"version,omitempty"`
+	EphemeralKey []byte `protobuf:"bytes,6,opt,name=ephemeralKey,proto3" json:"ephemeralKey,omitempty"`
 }
 
 func (m *StreamMessage) Reset()                    { *m = StreamMessage{} }
@@ -273,6 +291,20 @@ func (m *StreamMessage) GetData() []byte {
 	return nil
 }
 
+func (m *StreamMessage) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *StreamMessage) GetEphemeralKey() []byte {
+	if m != nil {
+		return m.EphemeralKey
+	}
+	return nil
+}
+
 type OpenOrderRequest struct {
 	OrderFragment *EncryptedOrderFragment `protobuf:"bytes,1,opt,name=orderFragment" json:"orderFragment,omitempty"`
 }
@@ -297,27 +329,254 @@ func (m *OpenOrderResponse) String() string            { return proto.CompactTex
 func (*OpenOrderResponse) ProtoMessage()               {}
 func (*OpenOrderResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
+type OpenOrderBatchRequest struct {
+	OrderFragments []*EncryptedOrderFragment `protobuf:"bytes,1,rep,name=orderFragments" json:"orderFragments,omitempty"`
+}
+
+func (m *OpenOrderBatchRequest) Reset()                    { *m = OpenOrderBatchRequest{} }
+func (m *OpenOrderBatchRequest) String() string            { return proto.CompactTextString(m) }
+func (*OpenOrderBatchRequest) ProtoMessage()               {}
+func (*OpenOrderBatchRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *OpenOrderBatchRequest) GetOrderFragments() []*EncryptedOrderFragment {
+	if m != nil {
+		return m.OrderFragments
+	}
+	return nil
+}
+
+type OpenOrderBatchResponse struct {
+	Acks []*OpenOrderAck `protobuf:"bytes,1,rep,name=acks" json:"acks,omitempty"`
+}
+
+func (m *OpenOrderBatchResponse) Reset()                    { *m = OpenOrderBatchResponse{} }
+func (m *OpenOrderBatchResponse) String() string            { return proto.CompactTextString(m) }
+func (*OpenOrderBatchResponse) ProtoMessage()               {}
+func (*OpenOrderBatchResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *OpenOrderBatchResponse) GetAcks() []*OpenOrderAck {
+	if m != nil {
+		return m.Acks
+	}
+	return nil
+}
+
+type OpenOrderAck struct {
+	OrderFragmentId []byte `protobuf:"bytes,1,opt,name=orderFragmentId,proto3" json:"orderFragmentId,omitempty"`
+	Error           string `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *OpenOrderAck) Reset()                    { *m = OpenOrderAck{} }
+func (m *OpenOrderAck) String() string            { return proto.CompactTextString(m) }
+func (*OpenOrderAck) ProtoMessage()               {}
+func (*OpenOrderAck) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *OpenOrderAck) GetOrderFragmentId() []byte {
+	if m != nil {
+		return m.OrderFragmentId
+	}
+	return nil
+}
+
+func (m *OpenOrderAck) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type QueryOrderRequest struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	OrderId   []byte `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Timestamp int64  `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *QueryOrderRequest) Reset()                    { *m = QueryOrderRequest{} }
+func (m *QueryOrderRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryOrderRequest) ProtoMessage()               {}
+func (*QueryOrderRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *QueryOrderRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *QueryOrderRequest) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *QueryOrderRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type QueryOrderResponse struct {
+	OrderId     []byte  `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Trader      string  `protobuf:"bytes,2,opt,name=trader" json:"trader,omitempty"`
+	Status      uint32  `protobuf:"varint,3,opt,name=status" json:"status,omitempty"`
+	EpochDepths []int32 `protobuf:"varint,4,rep,packed,name=epochDepths" json:"epochDepths,omitempty"`
+	Matched     bool    `protobuf:"varint,5,opt,name=matched" json:"matched,omitempty"`
+	Settled     bool    `protobuf:"varint,6,opt,name=settled" json:"settled,omitempty"`
+}
+
+func (m *QueryOrderResponse) Reset()                    { *m = QueryOrderResponse{} }
+func (m *QueryOrderResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryOrderResponse) ProtoMessage()               {}
+func (*QueryOrderResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *QueryOrderResponse) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *QueryOrderResponse) GetTrader() string {
+	if m != nil {
+		return m.Trader
+	}
+	return ""
+}
+
+func (m *QueryOrderResponse) GetStatus() uint32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *QueryOrderResponse) GetEpochDepths() []int32 {
+	if m != nil {
+		return m.EpochDepths
+	}
+	return nil
+}
+
+func (m *QueryOrderResponse) GetMatched() bool {
+	if m != nil {
+		return m.Matched
+	}
+	return false
+}
+
+func (m *QueryOrderResponse) GetSettled() bool {
+	if m != nil {
+		return m.Settled
+	}
+	return false
+}
+
+type SubscribeOrderRequest struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	OrderId   []byte `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Timestamp int64  `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *SubscribeOrderRequest) Reset()                    { *m = SubscribeOrderRequest{} }
+func (m *SubscribeOrderRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubscribeOrderRequest) ProtoMessage()               {}
+func (*SubscribeOrderRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *SubscribeOrderRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *SubscribeOrderRequest) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *SubscribeOrderRequest) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type OrderEvent struct {
+	Signature []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	OrderId   []byte `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	Type      uint32 `protobuf:"varint,3,opt,name=type" json:"type,omitempty"`
+	Trader    string `protobuf:"bytes,4,opt,name=trader" json:"trader,omitempty"`
+	Timestamp int64  `protobuf:"varint,5,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *OrderEvent) Reset()                    { *m = OrderEvent{} }
+func (m *OrderEvent) String() string            { return proto.CompactTextString(m) }
+func (*OrderEvent) ProtoMessage()               {}
+func (*OrderEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *OrderEvent) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *OrderEvent) GetOrderId() []byte {
+	if m != nil {
+		return m.OrderId
+	}
+	return nil
+}
+
+func (m *OrderEvent) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *OrderEvent) GetTrader() string {
+	if m != nil {
+		return m.Trader
+	}
+	return ""
+}
+
+func (m *OrderEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
 type EncryptedOrderFragment struct {
-	OrderId         []byte                              `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
-	OrderType       OrderType                           `protobuf:"varint,2,opt,name=orderType,enum=grpc.OrderType" json:"orderType,omitempty"`
-	OrderParity     OrderParity                         `protobuf:"varint,3,opt,name=orderParity,enum=grpc.OrderParity" json:"orderParity,omitempty"`
-	OrderSettlement OrderSettlement                     `protobuf:"varint,4,opt,name=orderSettlement,enum=grpc.OrderSettlement" json:"orderSettlement,omitempty"`
-	OrderExpiry     int64                               `protobuf:"varint,5,opt,name=orderExpiry" json:"orderExpiry,omitempty"`
-	Id              []byte                              `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
-	EpochDepth      int32                               `protobuf:"varint,7,opt,name=epochDepth" json:"epochDepth,omitempty"`
-	Tokens          []byte                              `protobuf:"bytes,8,opt,name=tokens,proto3" json:"tokens,omitempty"`
-	Price           *EncryptedCoExpShare                `protobuf:"bytes,9,opt,name=price" json:"price,omitempty"`
-	Volume          *EncryptedCoExpShare                `protobuf:"bytes,10,opt,name=volume" json:"volume,omitempty"`
-	MinimumVolume   *EncryptedCoExpShare                `protobuf:"bytes,11,opt,name=minimumVolume" json:"minimumVolume,omitempty"`
-	Nonce           []byte                              `protobuf:"bytes,12,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	Blinding        []byte                              `protobuf:"bytes,13,opt,name=blinding,proto3" json:"blinding,omitempty"`
-	Commitments     map[uint64]*OrderFragmentCommitment `protobuf:"bytes,14,rep,name=commitments" json:"commitments,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	OrderId                 []byte                              `protobuf:"bytes,1,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	OrderType               OrderType                           `protobuf:"varint,2,opt,name=orderType,enum=grpc.OrderType" json:"orderType,omitempty"`
+	OrderParity             OrderParity                         `protobuf:"varint,3,opt,name=orderParity,enum=grpc.OrderParity" json:"orderParity,omitempty"`
+	OrderSettlement         OrderSettlement                     `protobuf:"varint,4,opt,name=orderSettlement,enum=grpc.OrderSettlement" json:"orderSettlement,omitempty"`
+	OrderExpiry             int64                               `protobuf:"varint,5,opt,name=orderExpiry" json:"orderExpiry,omitempty"`
+	Id                      []byte                              `protobuf:"bytes,6,opt,name=id,proto3" json:"id,omitempty"`
+	EpochDepth              int32                               `protobuf:"varint,7,opt,name=epochDepth" json:"epochDepth,omitempty"`
+	Tokens                  []byte                              `protobuf:"bytes,8,opt,name=tokens,proto3" json:"tokens,omitempty"`
+	Price                   *EncryptedCoExpShare                `protobuf:"bytes,9,opt,name=price" json:"price,omitempty"`
+	Volume                  *EncryptedCoExpShare                `protobuf:"bytes,10,opt,name=volume" json:"volume,omitempty"`
+	MinimumVolume           *EncryptedCoExpShare                `protobuf:"bytes,11,opt,name=minimumVolume" json:"minimumVolume,omitempty"`
+	Nonce                   []byte                              `protobuf:"bytes,12,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Blinding                []byte                              `protobuf:"bytes,13,opt,name=blinding,proto3" json:"blinding,omitempty"`
+	Commitments             map[uint64]*OrderFragmentCommitment `protobuf:"bytes,14,rep,name=commitments" json:"commitments,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	VerificationBlindings   []byte                              `protobuf:"bytes,15,opt,name=verificationBlindings,proto3" json:"verificationBlindings,omitempty"`
+	VerificationCommitments []*PolynomialCommitment             `protobuf:"bytes,16,rep,name=verificationCommitments" json:"verificationCommitments,omitempty"`
+	Version                 uint32                              `protobuf:"varint,17,opt,name=version" json:"version,omitempty"`
 }
 
 func (m *EncryptedOrderFragment) Reset()                    { *m = EncryptedOrderFragment{} }
 func (m *EncryptedOrderFragment) String() string            { return proto.CompactTextString(m) }
 func (*EncryptedOrderFragment) ProtoMessage()               {}
-func (*EncryptedOrderFragment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*EncryptedOrderFragment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *EncryptedOrderFragment) GetOrderId() []byte {
 	if m != nil {
@@ -417,6 +676,27 @@ func (m *EncryptedOrderFragment) GetCommitments() map[uint64]*OrderFragmentCommi
 	return nil
 }
 
+func (m *EncryptedOrderFragment) GetVerificationBlindings() []byte {
+	if m != nil {
+		return m.VerificationBlindings
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragment) GetVerificationCommitments() []*PolynomialCommitment {
+	if m != nil {
+		return m.VerificationCommitments
+	}
+	return nil
+}
+
+func (m *EncryptedOrderFragment) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type EncryptedCoExpShare struct {
 	Co  []byte `protobuf:"bytes,1,opt,name=co,proto3" json:"co,omitempty"`
 	Exp []byte `protobuf:"bytes,2,opt,name=exp,proto3" json:"exp,omitempty"`
@@ -425,7 +705,7 @@ type EncryptedCoExpShare struct {
 func (m *EncryptedCoExpShare) Reset()                    { *m = EncryptedCoExpShare{} }
 func (m *EncryptedCoExpShare) String() string            { return proto.CompactTextString(m) }
 func (*EncryptedCoExpShare) ProtoMessage()               {}
-func (*EncryptedCoExpShare) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*EncryptedCoExpShare) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *EncryptedCoExpShare) GetCo() []byte {
 	if m != nil {
@@ -453,7 +733,7 @@ type OrderFragmentCommitment struct {
 func (m *OrderFragmentCommitment) Reset()                    { *m = OrderFragmentCommitment{} }
 func (m *OrderFragmentCommitment) String() string            { return proto.CompactTextString(m) }
 func (*OrderFragmentCommitment) ProtoMessage()               {}
-func (*OrderFragmentCommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*OrderFragmentCommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *OrderFragmentCommitment) GetPriceCo() []byte {
 	if m != nil {
@@ -497,6 +777,22 @@ func (m *OrderFragmentCommitment) GetMinimumVolumeExp() []byte {
 	return nil
 }
 
+type PolynomialCommitment struct {
+	Coefficients [][]byte `protobuf:"bytes,1,rep,name=coefficients,proto3" json:"coefficients,omitempty"`
+}
+
+func (m *PolynomialCommitment) Reset()                    { *m = PolynomialCommitment{} }
+func (m *PolynomialCommitment) String() string            { return proto.CompactTextString(m) }
+func (*PolynomialCommitment) ProtoMessage()               {}
+func (*PolynomialCommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *PolynomialCommitment) GetCoefficients() [][]byte {
+	if m != nil {
+		return m.Coefficients
+	}
+	return nil
+}
+
 type CoExpCommitment struct {
 	Co  []byte `protobuf:"bytes,1,opt,name=co,proto3" json:"co,omitempty"`
 	Exp []byte `protobuf:"bytes,2,opt,name=exp,proto3" json:"exp,omitempty"`
@@ -505,7 +801,7 @@ type CoExpCommitment struct {
 func (m *CoExpCommitment) Reset()                    { *m = CoExpCommitment{} }
 func (m *CoExpCommitment) String() string            { return proto.CompactTextString(m) }
 func (*CoExpCommitment) ProtoMessage()               {}
-func (*CoExpCommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*CoExpCommitment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *CoExpCommitment) GetCo() []byte {
 	if m != nil {
@@ -527,7 +823,7 @@ type StatusRequest struct {
 func (m *StatusRequest) Reset()                    { *m = StatusRequest{} }
 func (m *StatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*StatusRequest) ProtoMessage()               {}
-func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*StatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
 
 type StatusResponse struct {
 	Address      string `protobuf:"bytes,1,opt,name=address" json:"address,omitempty"`
@@ -538,7 +834,7 @@ type StatusResponse struct {
 func (m *StatusResponse) Reset()                    { *m = StatusResponse{} }
 func (m *StatusResponse) String() string            { return proto.CompactTextString(m) }
 func (*StatusResponse) ProtoMessage()               {}
-func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*StatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 func (m *StatusResponse) GetAddress() string {
 	if m != nil {
@@ -570,7 +866,7 @@ type UpdateMidpointRequest struct {
 func (m *UpdateMidpointRequest) Reset()                    { *m = UpdateMidpointRequest{} }
 func (m *UpdateMidpointRequest) String() string            { return proto.CompactTextString(m) }
 func (*UpdateMidpointRequest) ProtoMessage()               {}
-func (*UpdateMidpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*UpdateMidpointRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
 
 func (m *UpdateMidpointRequest) GetSignature() []byte {
 	if m != nil {
@@ -599,7 +895,7 @@ type UpdateMidpointResponse struct {
 func (m *UpdateMidpointResponse) Reset()                    { *m = UpdateMidpointResponse{} }
 func (m *UpdateMidpointResponse) String() string            { return proto.CompactTextString(m) }
 func (*UpdateMidpointResponse) ProtoMessage()               {}
-func (*UpdateMidpointResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*UpdateMidpointResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
 
 func init() {
 	proto.RegisterType((*MultiAddress)(nil), "grpc.MultiAddress")
@@ -612,9 +908,17 @@ func init() {
 	proto.RegisterType((*StreamMessage)(nil), "grpc.StreamMessage")
 	proto.RegisterType((*OpenOrderRequest)(nil), "grpc.OpenOrderRequest")
 	proto.RegisterType((*OpenOrderResponse)(nil), "grpc.OpenOrderResponse")
+	proto.RegisterType((*OpenOrderBatchRequest)(nil), "grpc.OpenOrderBatchRequest")
+	proto.RegisterType((*OpenOrderBatchResponse)(nil), "grpc.OpenOrderBatchResponse")
+	proto.RegisterType((*OpenOrderAck)(nil), "grpc.OpenOrderAck")
+	proto.RegisterType((*QueryOrderRequest)(nil), "grpc.QueryOrderRequest")
+	proto.RegisterType((*QueryOrderResponse)(nil), "grpc.QueryOrderResponse")
+	proto.RegisterType((*SubscribeOrderRequest)(nil), "grpc.SubscribeOrderRequest")
+	proto.RegisterType((*OrderEvent)(nil), "grpc.OrderEvent")
 	proto.RegisterType((*EncryptedOrderFragment)(nil), "grpc.EncryptedOrderFragment")
 	proto.RegisterType((*EncryptedCoExpShare)(nil), "grpc.EncryptedCoExpShare")
 	proto.RegisterType((*OrderFragmentCommitment)(nil), "grpc.OrderFragmentCommitment")
+	proto.RegisterType((*PolynomialCommitment)(nil), "grpc.PolynomialCommitment")
 	proto.RegisterType((*CoExpCommitment)(nil), "grpc.CoExpCommitment")
 	proto.RegisterType((*StatusRequest)(nil), "grpc.StatusRequest")
 	proto.RegisterType((*StatusResponse)(nil), "grpc.StatusResponse")
@@ -863,6 +1167,9 @@ var _StreamService_serviceDesc = grpc1.ServiceDesc{
 
 type OrderbookServiceClient interface {
 	OpenOrder(ctx context.Context, in *OpenOrderRequest, opts ...grpc1.CallOption) (*OpenOrderResponse, error)
+	OpenOrderBatch(ctx context.Context, in *OpenOrderBatchRequest, opts ...grpc1.CallOption) (*OpenOrderBatchResponse, error)
+	QueryOrder(ctx context.Context, in *QueryOrderRequest, opts ...grpc1.CallOption) (*QueryOrderResponse, error)
+	SubscribeOrder(ctx context.Context, in *SubscribeOrderRequest, opts ...grpc1.CallOption) (OrderbookService_SubscribeOrderClient, error)
 }
 
 type orderbookServiceClient struct {
@@ -882,10 +1189,63 @@ func (c *orderbookServiceClient) OpenOrder(ctx context.Context, in *OpenOrderReq
 	return out, nil
 }
 
+func (c *orderbookServiceClient) OpenOrderBatch(ctx context.Context, in *OpenOrderBatchRequest, opts ...grpc1.CallOption) (*OpenOrderBatchResponse, error) {
+	out := new(OpenOrderBatchResponse)
+	err := grpc1.Invoke(ctx, "/grpc.OrderbookService/OpenOrderBatch", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) QueryOrder(ctx context.Context, in *QueryOrderRequest, opts ...grpc1.CallOption) (*QueryOrderResponse, error) {
+	out := new(QueryOrderResponse)
+	err := grpc1.Invoke(ctx, "/grpc.OrderbookService/QueryOrder", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderbookServiceClient) SubscribeOrder(ctx context.Context, in *SubscribeOrderRequest, opts ...grpc1.CallOption) (OrderbookService_SubscribeOrderClient, error) {
+	stream, err := grpc1.NewClientStream(ctx, &_OrderbookService_serviceDesc.Streams[0], c.cc, "/grpc.OrderbookService/SubscribeOrder", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &orderbookServiceSubscribeOrderClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrderbookService_SubscribeOrderClient interface {
+	Recv() (*OrderEvent, error)
+	grpc1.ClientStream
+}
+
+type orderbookServiceSubscribeOrderClient struct {
+	grpc1.ClientStream
+}
+
+func (x *orderbookServiceSubscribeOrderClient) Recv() (*OrderEvent, error) {
+	m := new(OrderEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for OrderbookService service
 
 type OrderbookServiceServer interface {
 	OpenOrder(context.Context, *OpenOrderRequest) (*OpenOrderResponse, error)
+	OpenOrderBatch(context.Context, *OpenOrderBatchRequest) (*OpenOrderBatchResponse, error)
+	QueryOrder(context.Context, *QueryOrderRequest) (*QueryOrderResponse, error)
+	SubscribeOrder(*SubscribeOrderRequest, OrderbookService_SubscribeOrderServer) error
 }
 
 func RegisterOrderbookServiceServer(s *grpc1.Server, srv OrderbookServiceServer) {
@@ -910,6 +1270,63 @@ func _OrderbookService_OpenOrder_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderbookService_OpenOrderBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenOrderBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).OpenOrderBatch(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.OrderbookService/OpenOrderBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).OpenOrderBatch(ctx, req.(*OpenOrderBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_QueryOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderbookServiceServer).QueryOrder(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/grpc.OrderbookService/QueryOrder",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderbookServiceServer).QueryOrder(ctx, req.(*QueryOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderbookService_SubscribeOrder_Handler(srv interface{}, stream grpc1.ServerStream) error {
+	m := new(SubscribeOrderRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderbookServiceServer).SubscribeOrder(m, &orderbookServiceSubscribeOrderServer{stream})
+}
+
+type OrderbookService_SubscribeOrderServer interface {
+	Send(*OrderEvent) error
+	grpc1.ServerStream
+}
+
+type orderbookServiceSubscribeOrderServer struct {
+	grpc1.ServerStream
+}
+
+func (x *orderbookServiceSubscribeOrderServer) Send(m *OrderEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _OrderbookService_serviceDesc = grpc1.ServiceDesc{
 	ServiceName: "grpc.OrderbookService",
 	HandlerType: (*OrderbookServiceServer)(nil),
@@ -918,8 +1335,22 @@ var _OrderbookService_serviceDesc = grpc1.ServiceDesc{
 			MethodName: "OpenOrder",
 			Handler:    _OrderbookService_OpenOrder_Handler,
 		},
+		{
+			MethodName: "OpenOrderBatch",
+			Handler:    _OrderbookService_OpenOrderBatch_Handler,
+		},
+		{
+			MethodName: "QueryOrder",
+			Handler:    _OrderbookService_QueryOrder_Handler,
+		},
+	},
+	Streams: []grpc1.StreamDesc{
+		{
+			StreamName:    "SubscribeOrder",
+			Handler:       _OrderbookService_SubscribeOrder_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc1.StreamDesc{},
 	Metadata: "grpc.proto",
 }
 
@@ -1054,72 +1485,95 @@ var _OracleService_serviceDesc = grpc1.ServiceDesc{
 func init() { proto.RegisterFile("grpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1059 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x6f, 0xe3, 0x44,
-	0x10, 0x3f, 0xe7, 0x5f, 0x9b, 0x89, 0x93, 0xb8, 0xd3, 0x5e, 0xcf, 0x84, 0x82, 0x22, 0xbf, 0x10,
-	0x55, 0xb4, 0x1c, 0xa9, 0x74, 0x07, 0x27, 0xa4, 0xea, 0x2e, 0x97, 0x13, 0xa8, 0xf4, 0x52, 0x36,
-	0x70, 0x4f, 0x20, 0xe4, 0xc6, 0xab, 0xd4, 0x6a, 0xec, 0x35, 0xeb, 0x4d, 0xaf, 0x79, 0xe1, 0xa3,
-	0xf0, 0x75, 0xe0, 0x5b, 0xf0, 0x55, 0xd0, 0xee, 0xda, 0xc9, 0x3a, 0x4d, 0xdb, 0x17, 0xde, 0x76,
-	0x66, 0x7e, 0xf3, 0xf3, 0xec, 0xcc, 0xec, 0x8c, 0x01, 0xa6, 0x3c, 0x99, 0x1c, 0x27, 0x9c, 0x09,
-	0x86, 0x15, 0x79, 0xf6, 0xfe, 0x04, 0xfb, 0x7c, 0x3e, 0x13, 0xe1, 0xeb, 0x20, 0xe0, 0x34, 0x4d,
-	0xf1, 0x00, 0xea, 0x69, 0x38, 0x8d, 0x7d, 0x31, 0xe7, 0xd4, 0xb5, 0xba, 0x56, 0xcf, 0x26, 0x2b,
-	0x05, 0x7a, 0x60, 0x47, 0x06, 0xda, 0x2d, 0x75, 0xad, 0x5e, 0x9d, 0x14, 0x74, 0xf8, 0x25, 0xec,
-	0x98, 0xf2, 0x7b, 0x16, 0x4f, 0xa8, 0x5b, 0xee, 0x5a, 0xbd, 0x0a, 0xb9, 0x6b, 0xf0, 0x86, 0xd0,
-	0xb8, 0x08, 0xe3, 0x29, 0xa1, 0x7f, 0xcc, 0x69, 0x2a, 0xf0, 0xc5, 0xda, 0x07, 0x64, 0x04, 0x8d,
-	0x3e, 0x1e, 0xab, 0xb8, 0xcd, 0x40, 0x8b, 0x1f, 0xf5, 0x5a, 0x60, 0x6b, 0x9a, 0x34, 0x61, 0x71,
-	0xaa, 0x69, 0xd9, 0xff, 0x43, 0xcb, 0x0c, 0xda, 0x1e, 0xd8, 0x3f, 0xcd, 0x29, 0x5f, 0xe4, 0xbc,
-	0x2e, 0x6c, 0xf9, 0x06, 0x65, 0x9d, 0xe4, 0xa2, 0x77, 0x06, 0xcd, 0x0c, 0xa9, 0x5d, 0xf1, 0x15,
-	0xb4, 0x4c, 0x6a, 0x2a, 0x3d, 0xca, 0xf7, 0x04, 0xb1, 0x86, 0xf4, 0xe6, 0xd0, 0x1c, 0x0b, 0x4e,
-	0xfd, 0xe8, 0x9c, 0xa6, 0xa9, 0x3f, 0xa5, 0x8f, 0x54, 0xc9, 0x88, 0xaa, 0x54, 0x88, 0x4a, 0x5a,
-	0x62, 0x2a, 0x3e, 0x32, 0x7e, 0xad, 0x2a, 0x62, 0x93, 0x5c, 0x44, 0x84, 0x4a, 0xe0, 0x0b, 0xdf,
-	0xad, 0x28, 0xb5, 0x3a, 0x7b, 0x1f, 0xc0, 0x19, 0x25, 0x34, 0x1e, 0xf1, 0x80, 0xf2, 0xfc, 0xc6,
-	0x6f, 0xa0, 0xc9, 0xa4, 0xfc, 0x8e, 0xfb, 0xd3, 0x88, 0xc6, 0x22, 0x4b, 0xe5, 0x81, 0xbe, 0xc5,
-	0x30, 0x9e, 0xf0, 0x45, 0x22, 0x68, 0x30, 0x32, 0x31, 0xa4, 0xe8, 0xe2, 0xed, 0xc2, 0x8e, 0xc1,
-	0x9b, 0xa5, 0xf6, 0x9f, 0x2a, 0xec, 0x6f, 0x76, 0x97, 0x51, 0x2b, 0x82, 0x1f, 0x82, 0xec, 0xae,
-	0xb9, 0x88, 0x47, 0x50, 0x57, 0xc7, 0x9f, 0x17, 0x09, 0x55, 0x77, 0x6d, 0xf5, 0xdb, 0x3a, 0x92,
-	0x51, 0xae, 0x26, 0x2b, 0x04, 0x9e, 0x40, 0x43, 0x09, 0x17, 0x3e, 0x0f, 0xc5, 0x42, 0xa5, 0xa0,
-	0xd5, 0xdf, 0x31, 0x1c, 0xb4, 0x81, 0x98, 0x28, 0x3c, 0x85, 0xb6, 0x12, 0xc7, 0x54, 0x88, 0x19,
-	0x55, 0x77, 0xae, 0x28, 0xc7, 0xa7, 0x86, 0xe3, 0xca, 0x48, 0xd6, 0xd1, 0xd8, 0xcd, 0xbe, 0x3a,
-	0xbc, 0x4d, 0x42, 0xbe, 0x70, 0xab, 0x5d, 0xab, 0x57, 0x26, 0xa6, 0x0a, 0x5b, 0x50, 0x0a, 0x03,
-	0xb7, 0xa6, 0xee, 0x56, 0x0a, 0x03, 0xfc, 0x1c, 0x80, 0x26, 0x6c, 0x72, 0xf5, 0x96, 0x26, 0xe2,
-	0xca, 0xdd, 0xea, 0x5a, 0xbd, 0x2a, 0x31, 0x34, 0xb8, 0x0f, 0x35, 0xc1, 0xae, 0x69, 0x9c, 0xba,
-	0xdb, 0xca, 0x27, 0x93, 0xf0, 0x2b, 0xa8, 0x26, 0x3c, 0x9c, 0x50, 0xb7, 0xae, 0x8a, 0xf2, 0xc9,
-	0x5a, 0x51, 0x06, 0x6c, 0x78, 0x9b, 0x8c, 0xaf, 0x7c, 0x4e, 0x89, 0xc6, 0xe1, 0xd7, 0x50, 0xbb,
-	0x61, 0xb3, 0x79, 0x44, 0x5d, 0x78, 0xcc, 0x23, 0x03, 0xe2, 0x29, 0x34, 0xa3, 0x30, 0x0e, 0xa3,
-	0x79, 0xf4, 0x41, 0x7b, 0x36, 0x1e, 0xf3, 0x2c, 0xe2, 0x71, 0x0f, 0xaa, 0xb1, 0x9a, 0x09, 0xb6,
-	0x8a, 0x5d, 0x0b, 0xd8, 0x81, 0xed, 0xcb, 0x59, 0x18, 0x07, 0x61, 0x3c, 0x75, 0x9b, 0xca, 0xb0,
-	0x94, 0x71, 0x04, 0x8d, 0x09, 0x8b, 0xa2, 0x50, 0xc8, 0x74, 0xa6, 0x6e, 0x4b, 0xbd, 0x9b, 0xa3,
-	0x87, 0x3a, 0xee, 0x78, 0xb0, 0xc2, 0x0f, 0x63, 0xc1, 0x17, 0xc4, 0x64, 0xe8, 0xfc, 0x06, 0xce,
-	0x3a, 0x00, 0x1d, 0x28, 0x5f, 0xd3, 0x85, 0x6a, 0xb0, 0x0a, 0x91, 0x47, 0x3c, 0x81, 0xea, 0x8d,
-	0x3f, 0x9b, 0xeb, 0xc6, 0x6a, 0xf4, 0x3f, 0x33, 0xca, 0x9d, 0x7f, 0x67, 0xc5, 0x42, 0x34, 0xf6,
-	0x55, 0xe9, 0x1b, 0xcb, 0x7b, 0x09, 0xbb, 0x1b, 0xf2, 0x20, 0xab, 0x3c, 0x61, 0x59, 0x07, 0x97,
-	0x26, 0x4c, 0x7e, 0x91, 0xde, 0x26, 0x8a, 0xdd, 0x26, 0xf2, 0xe8, 0xfd, 0x6b, 0xc1, 0xb3, 0x7b,
-	0xf8, 0xe5, 0x23, 0x50, 0x35, 0x1b, 0xe4, 0x14, 0xb9, 0x28, 0x53, 0xa7, 0x8e, 0xc3, 0x25, 0xd9,
-	0x52, 0x96, 0x36, 0x5d, 0xb7, 0x01, 0xcb, 0x5e, 0xfc, 0x52, 0x96, 0x43, 0x44, 0x9f, 0xa5, 0xa3,
-	0x7e, 0xf7, 0x2b, 0x05, 0xf6, 0xa0, 0x5d, 0xa8, 0xdb, 0x80, 0xa9, 0xce, 0xb5, 0xc9, 0xba, 0x1a,
-	0x0f, 0xc1, 0x29, 0xa8, 0x24, 0x9d, 0xee, 0xe5, 0x3b, 0x7a, 0xef, 0x04, 0xda, 0x2a, 0x23, 0xc6,
-	0xc5, 0x1e, 0x4f, 0x4b, 0x5b, 0x8e, 0x3f, 0x5f, 0xcc, 0xd3, 0x6c, 0x08, 0x79, 0x01, 0xb4, 0x72,
-	0x45, 0x36, 0x5d, 0xef, 0x1d, 0xc4, 0x72, 0x65, 0x5d, 0x32, 0x26, 0x52, 0xc1, 0xfd, 0x24, 0xa1,
-	0x81, 0xe2, 0xdd, 0x26, 0x05, 0x9d, 0x6c, 0xc9, 0x84, 0x52, 0x9e, 0xaa, 0x14, 0x95, 0x89, 0x16,
-	0xbc, 0xbf, 0x2d, 0x78, 0xfa, 0x4b, 0x12, 0xf8, 0x82, 0x9e, 0x87, 0x41, 0xc2, 0xc2, 0x58, 0xe4,
-	0x43, 0xf0, 0xe1, 0xf1, 0x7b, 0x0a, 0x35, 0x95, 0x7f, 0x39, 0x7d, 0x65, 0xa7, 0x7e, 0xa1, 0x1b,
-	0x67, 0x23, 0xd5, 0xf1, 0x85, 0x42, 0xea, 0x1e, 0xcd, 0xdc, 0x56, 0x2f, 0x44, 0x6f, 0x4d, 0x2d,
-	0x74, 0xbe, 0x85, 0x86, 0x01, 0xde, 0xd0, 0xaf, 0x7b, 0x66, 0xbf, 0x56, 0xcc, 0x86, 0x74, 0x61,
-	0x7f, 0xfd, 0xeb, 0x3a, 0x6f, 0x87, 0x43, 0xa8, 0x2f, 0x27, 0x25, 0xda, 0xb0, 0x9d, 0x03, 0x9c,
-	0x27, 0x58, 0x87, 0xea, 0x8f, 0x61, 0x14, 0x0a, 0xc7, 0x42, 0x07, 0xec, 0xdc, 0xf0, 0xfb, 0xbb,
-	0xd1, 0x99, 0x53, 0xc2, 0x26, 0xd4, 0x95, 0x51, 0x89, 0xe5, 0xc3, 0x2e, 0x34, 0x8c, 0xf9, 0x89,
-	0x5b, 0x50, 0x7e, 0x33, 0x5f, 0x38, 0x4f, 0x70, 0x1b, 0x2a, 0x63, 0x3a, 0x9b, 0x39, 0xd6, 0xe1,
-	0x0b, 0x68, 0xaf, 0x0d, 0x4a, 0x89, 0x7a, 0x1f, 0xce, 0xf4, 0x97, 0x08, 0x8d, 0x87, 0xb7, 0x8e,
-	0x85, 0x6d, 0x68, 0xa8, 0xe3, 0x6b, 0xc1, 0xa2, 0x70, 0xe2, 0x94, 0xfa, 0x7f, 0x59, 0x60, 0x8f,
-	0x3f, 0xfa, 0x3c, 0x1a, 0x53, 0x7e, 0x23, 0x47, 0xd6, 0x11, 0x54, 0xe4, 0xa6, 0xc7, 0x6c, 0x6c,
-	0x1b, 0x3f, 0x0f, 0x1d, 0x34, 0x55, 0x59, 0x63, 0x48, 0x38, 0x33, 0xe0, 0xec, 0x2e, 0xdc, 0x58,
-	0xf0, 0xf8, 0x1c, 0xaa, 0x6a, 0x6d, 0x63, 0x66, 0x34, 0xb7, 0x7d, 0x67, 0xb7, 0xa0, 0xd3, 0x1e,
-	0xfd, 0xef, 0xf3, 0xdd, 0x9c, 0x07, 0xf8, 0x12, 0xb6, 0x06, 0x2c, 0x8e, 0xe9, 0x44, 0x60, 0xe6,
-	0x50, 0xd8, 0xdd, 0x9d, 0x4d, 0xca, 0x9e, 0xf5, 0xdc, 0xea, 0x5f, 0x80, 0xa3, 0x52, 0x74, 0xc9,
-	0xd8, 0x75, 0x4e, 0xf6, 0x1d, 0xd4, 0x97, 0xab, 0x12, 0xf7, 0xb3, 0x09, 0xb4, 0xb6, 0x93, 0x3b,
-	0xcf, 0xee, 0xe8, 0xb3, 0xd8, 0xde, 0xe6, 0x0f, 0x27, 0xa7, 0x3b, 0x81, 0x9a, 0x56, 0xac, 0x42,
-	0x33, 0xde, 0x55, 0x67, 0xaf, 0xa8, 0xcc, 0x58, 0x7e, 0x85, 0xe6, 0x88, 0xfb, 0x93, 0x19, 0xcd,
-	0x59, 0xce, 0xa0, 0x55, 0x6c, 0x27, 0xfc, 0xf4, 0x81, 0x16, 0xef, 0x1c, 0x6c, 0x36, 0x6a, 0xf6,
-	0xcb, 0x9a, 0xfa, 0x1b, 0x3d, 0xf9, 0x2f, 0x00, 0x00, 0xff, 0xff, 0xe8, 0xd5, 0xd1, 0x24, 0x9b,
-	0x0a, 0x00, 0x00,
+	// 1434 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0xdd, 0x6e, 0x1b, 0xc5,
+	0x17, 0xef, 0x3a, 0x76, 0x12, 0x1f, 0xaf, 0xed, 0xcd, 0x34, 0x1f, 0xfb, 0xf7, 0x3f, 0x20, 0x6b,
+	0x2f, 0xc0, 0x8a, 0x68, 0x28, 0x0e, 0x6a, 0xa1, 0x42, 0x0a, 0x49, 0xea, 0x8a, 0x2a, 0xb4, 0x09,
+	0xeb, 0xd2, 0x2b, 0x2a, 0xb4, 0xde, 0x9d, 0x26, 0xa3, 0x78, 0x77, 0x96, 0xd9, 0xb1, 0x1b, 0xdf,
+	0xf0, 0x08, 0x3c, 0x02, 0x12, 0xcf, 0x00, 0xef, 0xc0, 0x63, 0xf0, 0x06, 0x3c, 0x03, 0x9a, 0xd9,
+	0x19, 0x7b, 0x76, 0xe3, 0x36, 0x08, 0x24, 0xee, 0xe6, 0xfc, 0xce, 0xe7, 0x9e, 0x39, 0x73, 0xce,
+	0x59, 0x80, 0x0b, 0x96, 0x86, 0xfb, 0x29, 0xa3, 0x9c, 0xa2, 0xaa, 0x38, 0x7b, 0x3f, 0x82, 0xfd,
+	0x6c, 0x32, 0xe6, 0xe4, 0x28, 0x8a, 0x18, 0xce, 0x32, 0xb4, 0x0b, 0xf5, 0x8c, 0x5c, 0x24, 0x01,
+	0x9f, 0x30, 0xec, 0x5a, 0x5d, 0xab, 0x67, 0xfb, 0x0b, 0x00, 0x79, 0x60, 0xc7, 0x86, 0xb4, 0x5b,
+	0xe9, 0x5a, 0xbd, 0xba, 0x5f, 0xc0, 0xd0, 0x47, 0xb0, 0x61, 0xd2, 0xcf, 0x69, 0x12, 0x62, 0x77,
+	0xa5, 0x6b, 0xf5, 0xaa, 0xfe, 0x4d, 0x86, 0x37, 0x80, 0xc6, 0x39, 0x49, 0x2e, 0x7c, 0xfc, 0xc3,
+	0x04, 0x67, 0x1c, 0x3d, 0x28, 0x39, 0x10, 0x11, 0x34, 0xfa, 0x68, 0x5f, 0xc6, 0x6d, 0x06, 0x5a,
+	0x74, 0xea, 0xb5, 0xc0, 0xce, 0xcd, 0x64, 0x29, 0x4d, 0x32, 0xec, 0x51, 0x68, 0x9c, 0xd3, 0x7f,
+	0x6d, 0x16, 0xf5, 0xa0, 0x4d, 0x47, 0x19, 0x66, 0x53, 0x1c, 0x15, 0x3f, 0xb9, 0x0c, 0xcb, 0x00,
+	0xa8, 0x11, 0x40, 0x0f, 0xec, 0x6f, 0x26, 0x98, 0xcd, 0x74, 0x04, 0x2e, 0xac, 0x05, 0x86, 0xf3,
+	0xba, 0xaf, 0x49, 0xef, 0x14, 0x9a, 0x4a, 0x32, 0x57, 0x45, 0x8f, 0xa0, 0x65, 0x06, 0x81, 0x85,
+	0xc6, 0xca, 0x5b, 0xc2, 0x2d, 0x49, 0x7a, 0xbf, 0x5a, 0xd0, 0x1c, 0x72, 0x86, 0x83, 0xf8, 0x19,
+	0xce, 0xb2, 0xe0, 0x02, 0xdf, 0x72, 0xa1, 0x46, 0x58, 0x95, 0x42, 0x58, 0x82, 0x93, 0x60, 0xfe,
+	0x86, 0xb2, 0x2b, 0x79, 0x79, 0xb6, 0xaf, 0x49, 0x84, 0xa0, 0x1a, 0x05, 0x3c, 0x70, 0xab, 0x12,
+	0x96, 0x67, 0x21, 0x3d, 0xc5, 0x2c, 0x23, 0x34, 0x71, 0x6b, 0x5d, 0xab, 0xd7, 0xf4, 0x35, 0x29,
+	0x4a, 0x06, 0xa7, 0x97, 0x38, 0xc6, 0x2c, 0x18, 0x9f, 0xe2, 0x99, 0xbb, 0x2a, 0xb5, 0x0a, 0x98,
+	0xf7, 0x12, 0x9c, 0xb3, 0x14, 0x27, 0x67, 0x2c, 0xc2, 0x4c, 0x27, 0xec, 0x18, 0x9a, 0x54, 0xd0,
+	0x4f, 0x58, 0x70, 0x11, 0xe3, 0x84, 0xab, 0x3b, 0xdb, 0xcd, 0x93, 0x30, 0x48, 0x42, 0x36, 0x4b,
+	0x39, 0x8e, 0xce, 0x4c, 0x19, 0xbf, 0xa8, 0xe2, 0xdd, 0x85, 0x0d, 0xc3, 0xae, 0xba, 0x99, 0x57,
+	0xb0, 0x35, 0x07, 0x8f, 0x03, 0x1e, 0x5e, 0x6a, 0x8f, 0x8f, 0xa1, 0x55, 0x50, 0xd7, 0x79, 0x7f,
+	0xb7, 0xcb, 0x92, 0x8e, 0xf7, 0x25, 0x6c, 0x97, 0xcd, 0xab, 0x7b, 0xfd, 0x00, 0xaa, 0x41, 0x78,
+	0x55, 0xba, 0xcd, 0xb9, 0xec, 0x51, 0x78, 0xe5, 0x4b, 0xbe, 0xf7, 0x1c, 0x6c, 0x13, 0x95, 0x45,
+	0x68, 0xfa, 0x78, 0x1a, 0xa9, 0x7b, 0x2c, 0xc3, 0x68, 0x13, 0x6a, 0x98, 0x31, 0xca, 0xd4, 0x5d,
+	0xe6, 0x84, 0x47, 0x60, 0x43, 0x16, 0x58, 0x21, 0xbd, 0xb7, 0x96, 0x85, 0xb4, 0xfd, 0x34, 0x92,
+	0xa6, 0x6c, 0x5f, 0x93, 0x42, 0x8f, 0x93, 0x18, 0x67, 0x3c, 0x88, 0x53, 0x59, 0x18, 0x2b, 0xfe,
+	0x02, 0xf0, 0x7e, 0xb3, 0x00, 0x99, 0xbe, 0xd4, 0x97, 0x1b, 0xe6, 0xac, 0xa2, 0xb9, 0x6d, 0x58,
+	0xe5, 0x2c, 0x88, 0xb0, 0x0e, 0x59, 0x51, 0x02, 0xcf, 0x78, 0xc0, 0x27, 0x99, 0xf4, 0xd1, 0xf4,
+	0x15, 0x85, 0xba, 0xd0, 0xc0, 0x29, 0x0d, 0x2f, 0x1f, 0xe3, 0x94, 0x5f, 0x66, 0x6e, 0xb5, 0xbb,
+	0xd2, 0xab, 0xf9, 0x26, 0x24, 0x7c, 0xc5, 0x22, 0xed, 0x38, 0x92, 0x95, 0xb8, 0xee, 0x6b, 0x52,
+	0x70, 0x32, 0xcc, 0xf9, 0x18, 0x47, 0xb2, 0x08, 0xd7, 0x7d, 0x4d, 0x7a, 0x31, 0x6c, 0x0d, 0x27,
+	0xa3, 0x2c, 0x64, 0x64, 0x84, 0xff, 0x83, 0x2c, 0xfd, 0x64, 0x01, 0x48, 0x37, 0x83, 0x29, 0x4e,
+	0xfe, 0xb9, 0x13, 0x04, 0x55, 0x3e, 0x4b, 0xb1, 0xca, 0x90, 0x3c, 0x1b, 0xf9, 0xac, 0x16, 0xf2,
+	0x59, 0x08, 0xa8, 0x56, 0x0e, 0xe8, 0xcf, 0x55, 0xd8, 0x5e, 0x5e, 0xde, 0xef, 0xb8, 0xba, 0x7b,
+	0x50, 0x97, 0xc7, 0x17, 0x22, 0x06, 0x11, 0x5a, 0xab, 0xdf, 0x56, 0x35, 0xad, 0x61, 0x7f, 0x21,
+	0x81, 0x0e, 0xa0, 0x21, 0x89, 0xf3, 0x80, 0x11, 0x3e, 0x93, 0x41, 0xb7, 0xfa, 0x1b, 0x86, 0x42,
+	0xce, 0xf0, 0x4d, 0x29, 0x74, 0xa8, 0x4a, 0x7f, 0x28, 0x2f, 0x4a, 0xb6, 0x81, 0xaa, 0x54, 0xdc,
+	0x32, 0x14, 0x17, 0x4c, 0xbf, 0x2c, 0x2d, 0xea, 0x45, 0x42, 0x83, 0xeb, 0x94, 0xb0, 0x99, 0xfa,
+	0x72, 0x13, 0x42, 0x2d, 0xa8, 0x90, 0x48, 0x75, 0xa5, 0x0a, 0x89, 0xd0, 0xfb, 0x00, 0x8b, 0x72,
+	0x72, 0xd7, 0xba, 0x56, 0xaf, 0xe6, 0x1b, 0x88, 0xcc, 0x30, 0xbd, 0xc2, 0x49, 0xe6, 0xae, 0x4b,
+	0x1d, 0x45, 0xa1, 0x8f, 0xa1, 0x96, 0x32, 0x12, 0x62, 0xb7, 0x2e, 0xfb, 0xd4, 0xff, 0x4a, 0x4d,
+	0xe3, 0x84, 0x0e, 0xae, 0xd3, 0xe1, 0x65, 0xc0, 0xb0, 0x9f, 0xcb, 0xa1, 0x4f, 0x60, 0x75, 0x4a,
+	0xc7, 0x93, 0x18, 0xbb, 0x70, 0x9b, 0x86, 0x12, 0x44, 0x87, 0xd0, 0x8c, 0x49, 0x42, 0xe2, 0x49,
+	0xfc, 0x32, 0xd7, 0x6c, 0xdc, 0xa6, 0x59, 0x94, 0x17, 0x0d, 0x22, 0x91, 0xf3, 0xd8, 0x96, 0xb1,
+	0xe7, 0x04, 0xea, 0xc0, 0xfa, 0x68, 0x4c, 0x92, 0x88, 0x24, 0x17, 0x6e, 0x53, 0x32, 0xe6, 0x34,
+	0x3a, 0x83, 0x46, 0x48, 0xe3, 0x98, 0xf0, 0xbc, 0x23, 0xb6, 0x64, 0xef, 0xba, 0xf7, 0xae, 0x8e,
+	0xb8, 0x7f, 0xb2, 0x90, 0x1f, 0x24, 0x9c, 0xcd, 0x7c, 0xd3, 0x02, 0xfa, 0x14, 0xb6, 0xa6, 0x98,
+	0x91, 0xd7, 0x24, 0x0c, 0x38, 0xa1, 0xc9, 0xb1, 0x72, 0x94, 0xb9, 0x6d, 0xe9, 0x79, 0x39, 0x13,
+	0xbd, 0x80, 0x1d, 0x93, 0x61, 0xb8, 0x70, 0x1d, 0x19, 0x52, 0x27, 0x0f, 0xe9, 0x9c, 0x8e, 0x67,
+	0x09, 0x8d, 0x49, 0x30, 0x5e, 0x88, 0xf8, 0x6f, 0x53, 0x35, 0xa7, 0xd6, 0x46, 0x61, 0x6a, 0x75,
+	0x5e, 0x81, 0x53, 0xfe, 0x0c, 0xe4, 0xc0, 0xca, 0x15, 0x9e, 0xc9, 0x67, 0x50, 0xf5, 0xc5, 0x11,
+	0x1d, 0x40, 0x6d, 0x1a, 0x8c, 0x27, 0x79, 0xf9, 0x37, 0xfa, 0xef, 0x19, 0x45, 0xa9, 0xb3, 0x61,
+	0x84, 0x91, 0xcb, 0x3e, 0xaa, 0x7c, 0x66, 0x79, 0x0f, 0xe1, 0xee, 0x92, 0xdb, 0x12, 0xb5, 0x18,
+	0x52, 0xf5, 0xce, 0x2a, 0x21, 0x15, 0x1e, 0xf1, 0x75, 0xaa, 0xde, 0xbd, 0x38, 0x7a, 0x7f, 0x58,
+	0xb0, 0xf3, 0x16, 0xfb, 0xe2, 0x6b, 0x64, 0x65, 0x9d, 0x68, 0x13, 0x9a, 0x14, 0x17, 0x2c, 0x8f,
+	0x83, 0xb9, 0xb1, 0x39, 0x2d, 0x78, 0x79, 0x75, 0x9d, 0x50, 0x35, 0xe8, 0xe7, 0xb4, 0xe8, 0x1a,
+	0xf9, 0x59, 0x28, 0xe6, 0xe3, 0x7e, 0x01, 0x88, 0xb9, 0x54, 0xa8, 0xae, 0x13, 0x2a, 0xdf, 0x97,
+	0xed, 0x97, 0x61, 0xb4, 0x07, 0x4e, 0x01, 0x12, 0xe6, 0xf2, 0x17, 0x77, 0x03, 0xf7, 0x1e, 0xc1,
+	0xe6, 0xb2, 0x4b, 0x14, 0x7b, 0x44, 0x48, 0xf1, 0xeb, 0xd7, 0x24, 0x24, 0xf3, 0xd9, 0x6c, 0xfb,
+	0x05, 0xcc, 0x3b, 0x80, 0xb6, 0xcc, 0xa6, 0xa1, 0x76, 0x7b, 0x4a, 0xdb, 0x62, 0x63, 0x12, 0xc3,
+	0x45, 0x35, 0x7d, 0x2f, 0x82, 0x96, 0x06, 0x16, 0xf3, 0x6b, 0xf9, 0xf2, 0x26, 0xa2, 0x1a, 0x51,
+	0xca, 0x33, 0xce, 0x82, 0x34, 0xc5, 0x79, 0x8b, 0x5e, 0xf7, 0x0b, 0x98, 0x78, 0x74, 0x29, 0xc6,
+	0x2c, 0x53, 0x83, 0x20, 0x27, 0xbc, 0xdf, 0x2d, 0xd8, 0xfa, 0x36, 0x8d, 0x02, 0x8e, 0x9f, 0x91,
+	0x28, 0xa5, 0x24, 0xe1, 0x7f, 0x6f, 0xe8, 0x1c, 0xc2, 0xaa, 0xbc, 0x3b, 0xb1, 0xb0, 0x89, 0xc2,
+	0xff, 0x30, 0x2f, 0xba, 0xa5, 0xa6, 0xf6, 0xcf, 0xa5, 0x64, 0xfe, 0x0a, 0x95, 0xda, 0xa2, 0x07,
+	0xe4, 0x3b, 0x79, 0x4e, 0x74, 0x3e, 0x87, 0x86, 0x21, 0xbc, 0xa4, 0xd6, 0x37, 0xcd, 0x5a, 0xaf,
+	0x9a, 0xc5, 0xec, 0xc2, 0x76, 0xd9, 0x7b, 0x9e, 0xb7, 0xbd, 0x01, 0xd4, 0xe7, 0xb3, 0x00, 0xd9,
+	0xb0, 0xae, 0x05, 0x9c, 0x3b, 0xa8, 0x0e, 0xb5, 0xaf, 0x49, 0x4c, 0xb8, 0x63, 0x21, 0x07, 0x6c,
+	0xcd, 0xf8, 0xfe, 0xc9, 0xd9, 0xa9, 0x53, 0x41, 0x4d, 0xa8, 0x4b, 0xa6, 0x24, 0x57, 0xf6, 0xba,
+	0xd0, 0x30, 0x26, 0x04, 0x5a, 0x83, 0x95, 0xe3, 0xc9, 0xcc, 0xb9, 0x83, 0xd6, 0xa1, 0x3a, 0xc4,
+	0xe3, 0xb1, 0x63, 0xed, 0x3d, 0x80, 0x76, 0x69, 0x14, 0x08, 0xa9, 0xe7, 0x64, 0x9c, 0x7b, 0xf2,
+	0x71, 0x32, 0xb8, 0x76, 0x2c, 0xd4, 0x86, 0x86, 0x3c, 0x1e, 0x71, 0x1a, 0x93, 0xd0, 0xa9, 0xf4,
+	0x7f, 0xb6, 0xc0, 0x1e, 0xbe, 0x09, 0x58, 0x3c, 0xc4, 0x6c, 0x2a, 0x9a, 0xf2, 0x3d, 0xa8, 0x8a,
+	0xff, 0x08, 0xa4, 0x06, 0x93, 0xf1, 0x6b, 0xd2, 0x41, 0x26, 0xa4, 0x0a, 0x43, 0x88, 0x53, 0x43,
+	0x9c, 0xde, 0x14, 0x37, 0x7e, 0x0a, 0xd0, 0x7d, 0xa8, 0xc9, 0xed, 0x08, 0x29, 0xa6, 0xf9, 0x87,
+	0xd0, 0xb9, 0x5b, 0xc0, 0x72, 0x8d, 0xfe, 0x57, 0x7a, 0x9d, 0xd7, 0x01, 0x3e, 0x84, 0xb5, 0x13,
+	0x9a, 0x24, 0x38, 0xe4, 0x48, 0x29, 0x14, 0xd6, 0xfd, 0xce, 0x32, 0xb0, 0x67, 0xdd, 0xb7, 0xfa,
+	0xbf, 0x54, 0xc0, 0x91, 0x39, 0x1a, 0x51, 0x7a, 0xa5, 0xad, 0x7d, 0x01, 0xf5, 0xf9, 0xaa, 0x89,
+	0xb6, 0x4b, 0x1b, 0xa9, 0x0e, 0x6c, 0xe7, 0x06, 0xae, 0x3e, 0xe7, 0x14, 0x5a, 0xc5, 0x55, 0x17,
+	0xfd, 0xbf, 0x24, 0x6a, 0xee, 0xd7, 0x9d, 0xdd, 0xe5, 0x4c, 0x65, 0xec, 0x10, 0x60, 0xb1, 0x39,
+	0xa2, 0x1d, 0x23, 0x19, 0x85, 0x60, 0xdc, 0x9b, 0x0c, 0x65, 0xe0, 0x08, 0x5a, 0xc5, 0x25, 0x4e,
+	0x47, 0xb3, 0x74, 0xb5, 0xeb, 0x38, 0x46, 0xb3, 0x96, 0x7b, 0xd8, 0x7d, 0xab, 0xff, 0x58, 0xb7,
+	0x02, 0x9d, 0x9f, 0x03, 0x58, 0xcd, 0x81, 0x45, 0xb2, 0x8d, 0x4e, 0xd1, 0xd9, 0x2c, 0x82, 0xea,
+	0xce, 0xbe, 0x83, 0xe6, 0x19, 0x0b, 0xc2, 0x31, 0xd6, 0x56, 0x4e, 0xa1, 0x55, 0x7c, 0x20, 0x3a,
+	0xb2, 0xa5, 0x8f, 0xb6, 0xb3, 0xbb, 0x9c, 0x99, 0x5b, 0x1f, 0xad, 0xca, 0xbf, 0xf7, 0x83, 0xbf,
+	0x06, 0x00, 0x54, 0x9b, 0x74, 0x11, 0xcb, 0x0f, 0x00, 0x00,
 }
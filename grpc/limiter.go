@@ -3,6 +3,7 @@ package grpc
 import (
 	"context"
 	"sync"
+	"time"
 
 	"golang.org/x/time/rate"
 )
@@ -33,6 +34,13 @@ func NewRateLimiter(limiter *rate.Limiter, limit float64, burst int) *RateLimite
 // Use this method if you intend to drop / skip events that exceed the rate
 // limit. Otherwise use Reserve or Wait.
 func (limiter *RateLimiter) Allow(addr string) bool {
+	return limiter.AllowN(addr, 1)
+}
+
+// AllowN reports whether n requests from given address may happen at time
+// now. It is used for requests that cost more than one request, such as
+// batches.
+func (limiter *RateLimiter) AllowN(addr string, n int) bool {
 	limiter.mu.Lock()
 	if _, ok := limiter.local[addr]; !ok {
 		limiter.local[addr] = rate.NewLimiter(limiter.limit, limiter.burst)
@@ -40,11 +48,12 @@ func (limiter *RateLimiter) Allow(addr string) bool {
 	addrLimiter := limiter.local[addr]
 	limiter.mu.Unlock()
 
-	if !addrLimiter.Allow() {
+	now := time.Now()
+	if !addrLimiter.AllowN(now, n) {
 		return false
 	}
 
-	return limiter.global.Allow()
+	return limiter.global.AllowN(now, n)
 }
 
 // Wait blocks until the limiter permits the request to happen. It returns an
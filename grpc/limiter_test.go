@@ -57,4 +57,20 @@ var _ = Describe("Rate-limiter", func() {
 			}
 		})
 	})
+
+	Context("when an address sends requests that cost more than one request", func() {
+
+		It("should block requests after their total cost has exceeded the burst", func() {
+			rateLimiter := NewRateLimiter(rate.NewLimiter(40, 100), 1, 20)
+
+			Expect(rateLimiter.AllowN(addrs[0], 10)).To(BeTrue())
+			Expect(rateLimiter.AllowN(addrs[0], 10)).To(BeTrue())
+			Expect(rateLimiter.AllowN(addrs[0], 10)).To(BeFalse())
+			Expect(rateLimiter.Allow(addrs[0])).To(BeFalse())
+
+			// Requests that cost more than the burst are never allowed
+			Expect(rateLimiter.AllowN(addrs[1], 21)).To(BeFalse())
+			Expect(rateLimiter.AllowN(addrs[1], 20)).To(BeTrue())
+		})
+	})
 })
@@ -41,12 +41,13 @@ func (client *oracleClient) UpdateMidpoint(ctx context.Context, to identity.Mult
 	if midpointPrice.IsNil() {
 		return ErrMidPointPriceIsNil
 	}
-	conn, err := Dial(ctx, to)
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, to)
 	if err != nil {
 		logger.Network(logger.LevelError, fmt.Sprintf("cannot dial %v: %v", to, err))
 		return fmt.Errorf("cannot dial %v: %v", to, err)
 	}
-	defer conn.Close()
+	defer pool.Release(conn)
 
 	// Construct a request object and send midpoint information to a given
 	// multiaddress.
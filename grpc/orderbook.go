@@ -3,21 +3,38 @@ package grpc
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"sync"
 	"time"
 
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/orderbook"
 	"github.com/republicprotocol/republic-go/shamir"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 // ErrOpenOrderRequestIsNil is returned when a gRPC request is nil or has nil
 // fields.
 var ErrOpenOrderRequestIsNil = errors.New("open order request is nil")
 
+// ErrOpenOrderBatchRequestIsNil is returned when a gRPC batch request is nil.
+var ErrOpenOrderBatchRequestIsNil = errors.New("open order batch request is nil")
+
+// ErrOpenOrderBatchTooLarge is returned when a gRPC batch request contains
+// more than MaxOpenOrderBatchSize order fragments.
+var ErrOpenOrderBatchTooLarge = errors.New("open order batch too large")
+
+// ErrOpenOrderBatchMalformedResponse is returned when a gRPC batch response
+// does not contain an acknowledgement for every order fragment.
+var ErrOpenOrderBatchMalformedResponse = errors.New("open order batch response is malformed")
+
 // ErrOrderFragmentIsNil is returned when the order fragment contains
 // nil fields.
 var ErrOrderFragmentIsNil = errors.New("order fragment is nil")
@@ -29,6 +46,41 @@ var ErrEncryptedCoExpShareIsNil = errors.New("encrypted coexp is nil")
 // is nil.
 var ErrEncryptedOrderFragmentIsNil = errors.New("encrypted order fragment is nil")
 
+// ErrQueryOrderRequestIsNil is returned when a gRPC query request is nil.
+var ErrQueryOrderRequestIsNil = errors.New("query order request is nil")
+
+// ErrSubscribeOrderRequestIsNil is returned when a gRPC subscription request
+// is nil.
+var ErrSubscribeOrderRequestIsNil = errors.New("subscribe order request is nil")
+
+// ErrMalformedOrderID is returned when an order ID is not 32 bytes long.
+var ErrMalformedOrderID = errors.New("malformed order id")
+
+// ErrTooManySubscriptions is returned when a SubscribeOrder RPC would exceed
+// the MaxSubscriptions, or the MaxSubscriptionsPerPeer.
+var ErrTooManySubscriptions = errors.New("too many subscriptions")
+
+// MaxOpenOrderBatchSize is the maximum number of order fragments that can be
+// opened by one OpenOrderBatch RPC.
+const MaxOpenOrderBatchSize = 100
+
+// MaxSubscriptions is the maximum number of SubscribeOrder streams that an
+// OrderbookService keeps open at the same time.
+const MaxSubscriptions = 1024
+
+// MaxSubscriptionsPerPeer is the maximum number of SubscribeOrder streams
+// that an OrderbookService keeps open for one IP address at the same time.
+const MaxSubscriptionsPerPeer = 16
+
+// MaxSubscriptionLifetime is the maximum duration of a SubscribeOrder stream.
+// The stream is closed once it has been open for this duration, and the
+// trader must subscribe again with a newly signed query.
+const MaxSubscriptionLifetime = 10 * time.Minute
+
+// FragmentsPerRateLimitToken is the number of order fragments in an
+// OpenOrderBatch RPC that cost the same as one RPC when rate limiting.
+const FragmentsPerRateLimitToken = 10
+
 type orderbookClient struct {
 }
 
@@ -43,11 +95,12 @@ func (client *orderbookClient) OpenOrder(ctx context.Context, multiAddr identity
 	if orderFragment.IsNil() {
 		return ErrOrderFragmentIsNil
 	}
-	conn, err := Dial(ctx, multiAddr)
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, multiAddr)
 	if err != nil {
 		return fmt.Errorf("cannot dial %v: %v", multiAddr, err)
 	}
-	defer conn.Close()
+	defer pool.Release(conn)
 
 	request := &OpenOrderRequest{
 		OrderFragment: marshalEncryptedOrderFragment(orderFragment),
@@ -58,19 +111,183 @@ func (client *orderbookClient) OpenOrder(ctx context.Context, multiAddr identity
 	})
 }
 
+// OpenOrderBatch implements the orderbook.Client interface. The
+// order.EncryptedFragments are split into batches of at most
+// MaxOpenOrderBatchSize.
+func (client *orderbookClient) OpenOrderBatch(ctx context.Context, multiAddr identity.MultiAddress, orderFragments []order.EncryptedFragment) ([]error, error) {
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, multiAddr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot dial %v: %v", multiAddr, err)
+	}
+	defer pool.Release(conn)
+
+	errs := make([]error, len(orderFragments))
+	for begin := 0; begin < len(orderFragments); begin += MaxOpenOrderBatchSize {
+		end := begin + MaxOpenOrderBatchSize
+		if end > len(orderFragments) {
+			end = len(orderFragments)
+		}
+
+		request := &OpenOrderBatchRequest{
+			OrderFragments: make([]*EncryptedOrderFragment, 0, end-begin),
+		}
+		for i := begin; i < end; i++ {
+			if orderFragments[i].IsNil() {
+				errs[i] = ErrOrderFragmentIsNil
+				continue
+			}
+			request.OrderFragments = append(request.OrderFragments, marshalEncryptedOrderFragment(orderFragments[i]))
+		}
+		if len(request.OrderFragments) == 0 {
+			continue
+		}
+
+		var response *OpenOrderBatchResponse
+		if err := Backoff(ctx, func() error {
+			response, err = NewOrderbookServiceClient(conn).OpenOrderBatch(ctx, request)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		if len(response.Acks) != len(request.OrderFragments) {
+			return nil, ErrOpenOrderBatchMalformedResponse
+		}
+
+		// Match the acknowledgements to the order fragments that were sent
+		j := 0
+		for i := begin; i < end; i++ {
+			if errs[i] != nil {
+				continue
+			}
+			if ack := response.Acks[j]; ack.Error != "" {
+				errs[i] = errors.New(ack.Error)
+			}
+			j++
+		}
+	}
+	return errs, nil
+}
+
+// QueryOrder implements the orderbook.Client interface.
+func (client *orderbookClient) QueryOrder(ctx context.Context, multiAddr identity.MultiAddress, query orderbook.Query) (orderbook.OrderState, error) {
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, multiAddr)
+	if err != nil {
+		return orderbook.OrderState{}, fmt.Errorf("cannot dial %v: %v", multiAddr, err)
+	}
+	defer pool.Release(conn)
+
+	request := &QueryOrderRequest{
+		Signature: query.Signature,
+		OrderId:   query.OrderID[:],
+		Timestamp: query.Timestamp,
+	}
+	// Queries are not retried because they expire, and because an
+	// unauthorized query will never succeed
+	response, err := NewOrderbookServiceClient(conn).QueryOrder(ctx, request, grpc.FailFast(false))
+	if err != nil {
+		return orderbook.OrderState{}, unmarshalQueryError(err)
+	}
+	return unmarshalOrderState(response)
+}
+
+// SubscribeOrder implements the orderbook.Client interface.
+func (client *orderbookClient) SubscribeOrder(ctx context.Context, multiAddr identity.MultiAddress, query orderbook.Query) (<-chan orderbook.Event, <-chan error) {
+	events := make(chan orderbook.Event)
+	errs := make(chan error)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		pool := DefaultConnPool()
+		conn, err := pool.Acquire(ctx, multiAddr)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case errs <- fmt.Errorf("cannot dial %v: %v", multiAddr, err):
+			}
+			return
+		}
+		defer pool.Release(conn)
+
+		request := &SubscribeOrderRequest{
+			Signature: query.Signature,
+			OrderId:   query.OrderID[:],
+			Timestamp: query.Timestamp,
+		}
+		stream, err := NewOrderbookServiceClient(conn).SubscribeOrder(ctx, request, grpc.FailFast(false))
+		if err != nil {
+			select {
+			case <-ctx.Done():
+			case errs <- unmarshalQueryError(err):
+			}
+			return
+		}
+
+		verifier := crypto.NewEcdsaVerifier(multiAddr.Address().String())
+		for {
+			message, err := stream.Recv()
+			if err != nil {
+				if err == io.EOF || ctx.Err() != nil {
+					return
+				}
+				select {
+				case <-ctx.Done():
+				case errs <- unmarshalQueryError(err):
+				}
+				return
+			}
+			event, err := unmarshalOrderEvent(message)
+			if err == nil && !event.OrderID.Equal(query.OrderID) {
+				err = ErrMalformedOrderID
+			}
+			if err == nil {
+				err = verifier.Verify(event.Hash(), event.Signature)
+			}
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case errs <- fmt.Errorf("cannot verify event: %v", err):
+				}
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case events <- event:
+			}
+		}
+	}()
+
+	return events, errs
+}
+
 // OrderbookService is a Service that implements the gRPC OrderbookService
 // defined in protobuf. It exposes an RPC that accepts OpenOrderRequests and
-// delegates control to an orderbook.Server.
+// delegates control to an orderbook.Server, an RPC that accepts
+// QueryOrderRequests and delegates control to an orderbook.Querier, and an
+// RPC that streams the orderbook.Events from an orderbook.Broadcaster.
 type OrderbookService struct {
-	server orderbook.Server
+	server        orderbook.Server
+	querier       orderbook.Querier
+	broadcaster   *orderbook.Broadcaster
+	subscriptions *subscriptions
 }
 
-// NewOrderbookService returns a gRPC service that unmarshals OpenOrderRequests
-// defined in protobuf, and delegates control of the RPC to an
-// orderbook.Server.
-func NewOrderbookService(server orderbook.Server) OrderbookService {
+// NewOrderbookService returns a gRPC service that unmarshals OpenOrderRequests,
+// QueryOrderRequests, and SubscribeOrderRequests defined in protobuf, and
+// delegates control of the RPCs to an orderbook.Server, an
+// orderbook.Querier, and an orderbook.Broadcaster. The orderbook.Broadcaster
+// is also notified when order fragments are received.
+func NewOrderbookService(server orderbook.Server, querier orderbook.Querier, broadcaster *orderbook.Broadcaster) OrderbookService {
 	return OrderbookService{
-		server: server,
+		server:        server,
+		querier:       querier,
+		broadcaster:   broadcaster,
+		subscriptions: newSubscriptions(MaxSubscriptions, MaxSubscriptionsPerPeer),
 	}
 }
 
@@ -98,7 +315,196 @@ func (service *OrderbookService) OpenOrder(ctx context.Context, request *OpenOrd
 	if err != nil {
 		return nil, err
 	}
-	return &OpenOrderResponse{}, service.server.OpenOrder(ctx, fragment)
+	if err := service.server.OpenOrder(ctx, fragment); err != nil {
+		return nil, err
+	}
+	service.broadcaster.Publish(orderbook.NewEvent(fragment.OrderID, orderbook.EventReceived))
+	return &OpenOrderResponse{}, nil
+}
+
+// OpenOrderBatch implements the gRPC service for receiving many
+// EncryptedOrderFragments defined in protobuf. Each EncryptedOrderFragment is
+// opened independently, and an acknowledgement is returned for each one.
+func (service *OrderbookService) OpenOrderBatch(ctx context.Context, request *OpenOrderBatchRequest) (*OpenOrderBatchResponse, error) {
+	// Check for empty or invalid request fields.
+	if request == nil {
+		return nil, ErrOpenOrderBatchRequestIsNil
+	}
+	if len(request.OrderFragments) > MaxOpenOrderBatchSize {
+		return nil, ErrOpenOrderBatchTooLarge
+	}
+
+	acks := make([]*OpenOrderAck, len(request.OrderFragments))
+	dispatch.CoForAll(request.OrderFragments, func(i int) {
+		acks[i] = &OpenOrderAck{
+			OrderFragmentId: request.OrderFragments[i].GetId(),
+		}
+		if request.OrderFragments[i] == nil {
+			acks[i].Error = ErrOrderFragmentIsNil.Error()
+			return
+		}
+		fragment, err := unmarshalEncryptedOrderFragment(request.OrderFragments[i])
+		if err == nil {
+			err = service.server.OpenOrder(ctx, fragment)
+		}
+		if err != nil {
+			acks[i].Error = err.Error()
+			return
+		}
+		service.broadcaster.Publish(orderbook.NewEvent(fragment.OrderID, orderbook.EventReceived))
+	})
+	return &OpenOrderBatchResponse{Acks: acks}, nil
+}
+
+// QueryOrder implements the gRPC service for querying the state of an order
+// defined in protobuf. The query must be signed by the trader of the order.
+func (service *OrderbookService) QueryOrder(ctx context.Context, request *QueryOrderRequest) (*QueryOrderResponse, error) {
+	// Check for empty or invalid request fields.
+	if request == nil {
+		return nil, ErrQueryOrderRequestIsNil
+	}
+	if len(request.OrderId) != 32 {
+		return nil, ErrMalformedOrderID
+	}
+
+	query := orderbook.Query{
+		Timestamp: request.Timestamp,
+		Signature: request.Signature,
+	}
+	copy(query.OrderID[:], request.OrderId)
+	state, err := service.querier.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return marshalOrderState(state), nil
+}
+
+// SubscribeOrder implements the gRPC service for streaming the events of an
+// order defined in protobuf. The subscription must be signed by the trader of
+// the order, within the orderbook.MaxQueryAge. If the order has not been
+// opened, the subscription cannot be authenticated until it is. Until then,
+// no events are streamed, and the stream is closed if the order is opened by
+// a different trader. The number of
+// subscriptions is limited, and each subscription is closed after the
+// MaxSubscriptionLifetime.
+func (service *OrderbookService) SubscribeOrder(request *SubscribeOrderRequest, stream OrderbookService_SubscribeOrderServer) error {
+	// Check for empty or invalid request fields.
+	if request == nil {
+		return ErrSubscribeOrderRequestIsNil
+	}
+	if len(request.OrderId) != 32 {
+		return ErrMalformedOrderID
+	}
+
+	query := orderbook.Query{
+		Timestamp: request.Timestamp,
+		Signature: request.Signature,
+	}
+	copy(query.OrderID[:], request.OrderId)
+	state, err := service.querier.Query(stream.Context(), query)
+	if err != nil {
+		return err
+	}
+	authenticated := state.Status != order.Nil
+	if !authenticated {
+		// The signatory cannot be verified until the order is opened, but
+		// the subscription must still be signed
+		if err := query.VerifyTimestamp(); err != nil {
+			return err
+		}
+		if _, err := crypto.RecoverAddress(query.Hash(), query.Signature); err != nil {
+			return orderbook.ErrUnauthorizedQuery
+		}
+	}
+
+	peer, err := addressFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	if !service.subscriptions.acquire(peer) {
+		return ErrTooManySubscriptions
+	}
+	defer service.subscriptions.release(peer)
+
+	ctx, cancel := context.WithTimeout(stream.Context(), MaxSubscriptionLifetime)
+	defer cancel()
+
+	events := service.broadcaster.Subscribe(ctx.Done(), query.OrderID)
+	for event := range events {
+		if !authenticated {
+			if event.Type != orderbook.EventOpen {
+				continue
+			}
+			// The subscription was signed before the order was opened, so it
+			// is authenticated against the trader that opened it
+			if err := query.VerifyTrader(event.Trader); err != nil {
+				return err
+			}
+			authenticated = true
+		}
+		if err := stream.Send(marshalOrderEvent(event)); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() == context.DeadlineExceeded && stream.Context().Err() == nil {
+		// The subscription has reached its maximum lifetime
+		return nil
+	}
+	return stream.Context().Err()
+}
+
+// subscriptions counts the SubscribeOrder streams that are open, in total and
+// for each peer.
+type subscriptions struct {
+	mu         *sync.Mutex
+	max        int
+	maxPerPeer int
+	total      int
+	peers      map[string]int
+}
+
+func newSubscriptions(max, maxPerPeer int) *subscriptions {
+	return &subscriptions{
+		mu:         new(sync.Mutex),
+		max:        max,
+		maxPerPeer: maxPerPeer,
+		peers:      map[string]int{},
+	}
+}
+
+// acquire a subscription for a peer. It returns false if there are too many
+// subscriptions.
+func (subs *subscriptions) acquire(peer string) bool {
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+
+	if subs.total >= subs.max || subs.peers[peer] >= subs.maxPerPeer {
+		return false
+	}
+	subs.total++
+	subs.peers[peer]++
+	return true
+}
+
+// release a subscription that was acquired for a peer.
+func (subs *subscriptions) release(peer string) {
+	subs.mu.Lock()
+	defer subs.mu.Unlock()
+
+	subs.total--
+	if subs.peers[peer]--; subs.peers[peer] <= 0 {
+		delete(subs.peers, peer)
+	}
+}
+
+// rateLimitCost implements the rateLimitedRequest interface. Every
+// FragmentsPerRateLimitToken order fragments cost the same as one RPC.
+func (request *OpenOrderBatchRequest) rateLimitCost() int {
+	cost := (len(request.GetOrderFragments()) + FragmentsPerRateLimitToken - 1) / FragmentsPerRateLimitToken
+	if cost < 1 {
+		return 1
+	}
+	return cost
 }
 
 func marshalEncryptedOrderFragment(orderFragmentIn order.EncryptedFragment) *EncryptedOrderFragment {
@@ -111,6 +517,7 @@ func marshalEncryptedOrderFragment(orderFragmentIn order.EncryptedFragment) *Enc
 
 		Id:            orderFragmentIn.ID[:],
 		EpochDepth:    int32(orderFragmentIn.EpochDepth),
+		Version:       uint32(orderFragmentIn.Version),
 		Tokens:        orderFragmentIn.Tokens,
 		Price:         marshalEncryptedCoExpShare(orderFragmentIn.Price),
 		Volume:        marshalEncryptedCoExpShare(orderFragmentIn.Volume),
@@ -119,6 +526,9 @@ func marshalEncryptedOrderFragment(orderFragmentIn order.EncryptedFragment) *Enc
 
 		Blinding:    []byte(orderFragmentIn.Blinding),
 		Commitments: marshalCommitments(orderFragmentIn.Commitments),
+
+		VerificationBlindings:   orderFragmentIn.Verification.Blindings,
+		VerificationCommitments: marshalPolynomialCommitments(orderFragmentIn.Verification.Commitments),
 	}
 }
 
@@ -147,6 +557,7 @@ func unmarshalEncryptedOrderFragment(orderFragmentIn *EncryptedOrderFragment) (o
 		OrderExpiry:     time.Unix(orderFragmentIn.OrderExpiry, 0),
 
 		EpochDepth:    order.FragmentEpochDepth(orderFragmentIn.EpochDepth),
+		Version:       order.FragmentVersion(orderFragmentIn.Version),
 		Tokens:        orderFragmentIn.Tokens,
 		Price:         price,
 		Volume:        volume,
@@ -155,6 +566,11 @@ func unmarshalEncryptedOrderFragment(orderFragmentIn *EncryptedOrderFragment) (o
 
 		Blinding:    orderFragmentIn.Blinding,
 		Commitments: unmarshalCommitments(orderFragmentIn.Commitments),
+
+		Verification: order.EncryptedFragmentVerification{
+			Blindings:   orderFragmentIn.VerificationBlindings,
+			Commitments: unmarshalPolynomialCommitments(orderFragmentIn.VerificationCommitments),
+		},
 	}
 	copy(orderFragment.OrderID[:], orderFragmentIn.OrderId)
 	copy(orderFragment.ID[:], orderFragmentIn.Id)
@@ -211,3 +627,102 @@ func unmarshalCommitments(values map[uint64]*OrderFragmentCommitment) order.Frag
 	}
 	return commitments
 }
+
+func marshalPolynomialCommitments(values []shamir.PolynomialCommitment) []*PolynomialCommitment {
+	commitments := make([]*PolynomialCommitment, len(values))
+	for i, value := range values {
+		commitments[i] = &PolynomialCommitment{
+			Coefficients: make([][]byte, len(value)),
+		}
+		for j, coefficient := range value {
+			if coefficient.Int == nil {
+				continue
+			}
+			commitments[i].Coefficients[j] = coefficient.Bytes()
+		}
+	}
+	return commitments
+}
+
+func unmarshalPolynomialCommitments(values []*PolynomialCommitment) []shamir.PolynomialCommitment {
+	commitments := make([]shamir.PolynomialCommitment, len(values))
+	for i, value := range values {
+		commitments[i] = make(shamir.PolynomialCommitment, len(value.GetCoefficients()))
+		for j, coefficient := range value.GetCoefficients() {
+			commitments[i][j] = shamir.Commitment{Int: big.NewInt(0).SetBytes(coefficient)}
+		}
+	}
+	return commitments
+}
+
+func marshalOrderState(state orderbook.OrderState) *QueryOrderResponse {
+	response := &QueryOrderResponse{
+		OrderId:     state.OrderID[:],
+		Trader:      state.Trader,
+		Status:      uint32(state.Status),
+		EpochDepths: make([]int32, len(state.EpochDepths)),
+		Matched:     state.Matched,
+		Settled:     state.Settled,
+	}
+	for i, depth := range state.EpochDepths {
+		response.EpochDepths[i] = int32(depth)
+	}
+	return response
+}
+
+func unmarshalOrderState(response *QueryOrderResponse) (orderbook.OrderState, error) {
+	state := orderbook.OrderState{
+		Trader:      response.Trader,
+		Status:      order.Status(response.Status),
+		EpochDepths: make([]order.FragmentEpochDepth, len(response.EpochDepths)),
+		Matched:     response.Matched,
+		Settled:     response.Settled,
+	}
+	if len(response.OrderId) != 32 {
+		return state, ErrMalformedOrderID
+	}
+	copy(state.OrderID[:], response.OrderId)
+	for i, depth := range response.EpochDepths {
+		state.EpochDepths[i] = order.FragmentEpochDepth(depth)
+	}
+	return state, nil
+}
+
+// unmarshalQueryError returns the orderbook error that was returned by the
+// orderbook.Querier, so that callers can compare it to known errors.
+func unmarshalQueryError(err error) error {
+	switch status.Convert(err).Message() {
+	case orderbook.ErrUnauthorizedQuery.Error():
+		return orderbook.ErrUnauthorizedQuery
+	case orderbook.ErrQueryExpired.Error():
+		return orderbook.ErrQueryExpired
+	case ErrTooManySubscriptions.Error():
+		return ErrTooManySubscriptions
+	default:
+		return err
+	}
+}
+
+func marshalOrderEvent(event orderbook.Event) *OrderEvent {
+	return &OrderEvent{
+		Signature: event.Signature,
+		OrderId:   event.OrderID[:],
+		Type:      uint32(event.Type),
+		Trader:    event.Trader,
+		Timestamp: event.Timestamp,
+	}
+}
+
+func unmarshalOrderEvent(message *OrderEvent) (orderbook.Event, error) {
+	event := orderbook.Event{
+		Type:      orderbook.EventType(message.Type),
+		Timestamp: message.Timestamp,
+		Signature: message.Signature,
+		Trader:    message.Trader,
+	}
+	if len(message.OrderId) != 32 {
+		return event, ErrMalformedOrderID
+	}
+	copy(event.OrderID[:], message.OrderId)
+	return event, nil
+}
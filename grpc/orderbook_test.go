@@ -2,10 +2,13 @@ package grpc_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
 	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/order"
 
@@ -15,6 +18,9 @@ import (
 	"github.com/republicprotocol/republic-go/orderbook"
 
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/testutils"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
 )
 
 var _ = Describe("Orderbook", func() {
@@ -24,20 +30,22 @@ var _ = Describe("Orderbook", func() {
 	var service OrderbookService
 	var serviceMultiAddr identity.MultiAddress
 	var client orderbook.Client
+	var broadcaster *orderbook.Broadcaster
 
 	BeforeEach(func() {
 		var err error
 
 		client = NewOrderbookClient()
 
-		serverMock = &mockOrderbookServer{}
-		server = NewServer()
-		service = NewOrderbookService(serverMock)
-		service.Register(server)
-
 		serviceEcdsaKey, err := crypto.RandomEcdsaKey()
 		Expect(err).ShouldNot(HaveOccurred())
 
+		serverMock = &mockOrderbookServer{mu: new(sync.Mutex), rejected: map[order.FragmentID]bool{}, pending: map[order.ID]bool{}, opened: map[order.FragmentID]order.EncryptedFragment{}}
+		server = NewServer()
+		broadcaster = orderbook.NewBroadcaster(&serviceEcdsaKey)
+		service = NewOrderbookService(serverMock, serverMock, broadcaster)
+		service.Register(server)
+
 		serviceMultiAddr, err = identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/0.0.0.0/tcp/18514/republic/%v", serviceEcdsaKey.Address()))
 		Expect(err).ShouldNot(HaveOccurred())
 
@@ -57,19 +65,306 @@ var _ = Describe("Orderbook", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 
+		It("should send the verification of order fragments", func() {
+			orderFragment, err := createEncryptedFragment()
+			Expect(err).ShouldNot(HaveOccurred())
+			err = client.OpenOrder(context.Background(), serviceMultiAddr, orderFragment)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serverMock.mu.Lock()
+			defer serverMock.mu.Unlock()
+			opened := serverMock.opened[orderFragment.ID]
+			Expect(opened.Verification.Blindings).Should(Equal(orderFragment.Verification.Blindings))
+			Expect(opened.Verification.Commitments).Should(HaveLen(len(orderFragment.Verification.Commitments)))
+			for i, commitment := range orderFragment.Verification.Commitments {
+				Expect(opened.Verification.Commitments[i]).Should(HaveLen(len(commitment)))
+				for j := range commitment {
+					Expect(opened.Verification.Commitments[i][j].Cmp(commitment[j].Int)).Should(Equal(0))
+				}
+			}
+		})
+
+	})
+
+	Context("when querying orders", func() {
+
+		It("should return the order state to the trader", func() {
+			orderID := order.ID(testutils.Random32Bytes())
+			query := orderbook.NewQuery(orderID)
+			Expect(query.Sign(&mockTraderKey)).ShouldNot(HaveOccurred())
+
+			state, err := client.QueryOrder(context.Background(), serviceMultiAddr, query)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(state.OrderID).Should(Equal(orderID))
+			Expect(state.Trader).Should(Equal(mockTrader))
+			Expect(state.Status).Should(Equal(order.Open))
+			Expect(state.EpochDepths).Should(Equal([]order.FragmentEpochDepth{0, 1}))
+			Expect(state.Matched).Should(BeTrue())
+			Expect(state.Settled).Should(BeFalse())
+		})
+
+		It("should not return the order state to other traders", func() {
+			otherKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			query := orderbook.NewQuery(order.ID(testutils.Random32Bytes()))
+			Expect(query.Sign(&otherKey)).ShouldNot(HaveOccurred())
+
+			_, err = client.QueryOrder(context.Background(), serviceMultiAddr, query)
+			Expect(err).Should(Equal(orderbook.ErrUnauthorizedQuery))
+		})
+	})
+
+	Context("when subscribing to orders", func() {
+
+		subscribe := func(ctx context.Context, orderID order.ID, key crypto.EcdsaKey) (<-chan orderbook.Event, <-chan error) {
+			query := orderbook.NewQuery(orderID)
+			Expect(query.Sign(&key)).ShouldNot(HaveOccurred())
+			events, errs := client.SubscribeOrder(ctx, serviceMultiAddr, query)
+			// Wait for the subscription to be registered by the service
+			Eventually(func() int {
+				return broadcaster.Subscribers(orderID)
+			}, 10*time.Second).Should(Equal(1))
+			return events, errs
+		}
+
+		It("should stream signed events to the trader", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			orderFragment, err := createEncryptedFragment()
+			Expect(err).ShouldNot(HaveOccurred())
+			events, _ := subscribe(ctx, orderFragment.OrderID, mockTraderKey)
+
+			Expect(client.OpenOrder(ctx, serviceMultiAddr, orderFragment)).ShouldNot(HaveOccurred())
+			broadcaster.Publish(orderbook.NewEvent(orderFragment.OrderID, orderbook.EventMatched))
+			broadcaster.Publish(orderbook.NewEvent(order.ID(testutils.Random32Bytes()), orderbook.EventMatched))
+			broadcaster.Publish(orderbook.NewEvent(orderFragment.OrderID, orderbook.EventSettled))
+
+			for _, ty := range []orderbook.EventType{orderbook.EventReceived, orderbook.EventMatched, orderbook.EventSettled} {
+				event := <-events
+				Expect(event.OrderID).Should(Equal(orderFragment.OrderID))
+				Expect(event.Type).Should(Equal(ty))
+			}
+		})
+
+		It("should authenticate the trader when a pending order is opened", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			orderID := order.ID(testutils.Random32Bytes())
+			serverMock.pend(orderID)
+			events, _ := subscribe(ctx, orderID, mockTraderKey)
+
+			// Events are not streamed until the subscription is authenticated
+			broadcaster.Publish(orderbook.NewEvent(orderID, orderbook.EventMatched))
+			broadcaster.Publish(orderbook.NewEvent(orderID, orderbook.EventReceived))
+			open := orderbook.NewEvent(orderID, orderbook.EventOpen)
+			open.Trader = mockTrader
+			broadcaster.Publish(open)
+			broadcaster.Publish(orderbook.NewEvent(orderID, orderbook.EventMatched))
+
+			for _, ty := range []orderbook.EventType{orderbook.EventOpen, orderbook.EventMatched} {
+				event := <-events
+				Expect(event.Type).Should(Equal(ty))
+			}
+		})
+
+		It("should close the subscription when a pending order is opened by another trader", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			orderID := order.ID(testutils.Random32Bytes())
+			serverMock.pend(orderID)
+			otherKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			events, errs := subscribe(ctx, orderID, otherKey)
+
+			open := orderbook.NewEvent(orderID, orderbook.EventOpen)
+			open.Trader = mockTrader
+			broadcaster.Publish(open)
+
+			Expect(<-errs).Should(Equal(orderbook.ErrUnauthorizedQuery))
+			Eventually(events).Should(BeClosed())
+		})
+
+		It("should not subscribe other traders to open orders", func() {
+			otherKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			query := orderbook.NewQuery(order.ID(testutils.Random32Bytes()))
+			Expect(query.Sign(&otherKey)).ShouldNot(HaveOccurred())
+			events, errs := client.SubscribeOrder(context.Background(), serviceMultiAddr, query)
+
+			Expect(<-errs).Should(Equal(orderbook.ErrUnauthorizedQuery))
+			Eventually(events).Should(BeClosed())
+		})
+
+		It("should not subscribe to pending orders without a signature", func() {
+			orderID := order.ID(testutils.Random32Bytes())
+			serverMock.pend(orderID)
+			events, errs := client.SubscribeOrder(context.Background(), serviceMultiAddr, orderbook.NewQuery(orderID))
+
+			Expect(<-errs).Should(Equal(orderbook.ErrUnauthorizedQuery))
+			Eventually(events).Should(BeClosed())
+		})
+
+		It("should limit the number of subscriptions for each peer", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			for i := 0; i < MaxSubscriptionsPerPeer; i++ {
+				orderID := order.ID(testutils.Random32Bytes())
+				serverMock.pend(orderID)
+				subscribe(ctx, orderID, mockTraderKey)
+			}
+
+			orderID := order.ID(testutils.Random32Bytes())
+			serverMock.pend(orderID)
+			query := orderbook.NewQuery(orderID)
+			Expect(query.Sign(&mockTraderKey)).ShouldNot(HaveOccurred())
+			events, errs := client.SubscribeOrder(ctx, serviceMultiAddr, query)
+
+			Expect(<-errs).Should(Equal(ErrTooManySubscriptions))
+			Eventually(events).Should(BeClosed())
+		})
+	})
+
+	Context("when opening batches of order fragments", func() {
+
+		It("should acknowledge each order fragment", func() {
+			orderFragments := make([]order.EncryptedFragment, 2*MaxOpenOrderBatchSize+5)
+			for i := range orderFragments {
+				orderFragment, err := createEncryptedFragment()
+				Expect(err).ShouldNot(HaveOccurred())
+				orderFragments[i] = orderFragment
+				if i%3 == 0 {
+					serverMock.reject(orderFragment.ID)
+				}
+			}
+			orderFragments[1] = order.EncryptedFragment{}
+
+			errs, err := client.OpenOrderBatch(context.Background(), serviceMultiAddr, orderFragments)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(errs).Should(HaveLen(len(orderFragments)))
+			for i := range errs {
+				switch {
+				case i == 1:
+					Expect(errs[i]).Should(Equal(ErrOrderFragmentIsNil))
+				case i%3 == 0:
+					Expect(errs[i]).Should(HaveOccurred())
+					Expect(errs[i].Error()).Should(Equal(errRejected.Error()))
+				default:
+					Expect(errs[i]).ShouldNot(HaveOccurred())
+				}
+			}
+			Expect(atomic.LoadInt64(&serverMock.n)).Should(Equal(int64(len(orderFragments) - 1)))
+		})
+
+		It("should rate limit batches by the number of order fragments", func() {
+			server.Stop()
+			server = NewServerwithLimiter(NewRateLimiter(rate.NewLimiter(100, 100), 0.01, 20), NewRateLimiter(rate.NewLimiter(100, 100), 0.01, 20))
+			service.Register(server)
+			go server.Start("0.0.0.0:18514")
+			time.Sleep(100 * time.Millisecond)
+
+			orderFragments := make([]order.EncryptedFragment, MaxOpenOrderBatchSize)
+			for i := range orderFragments {
+				orderFragment, err := createEncryptedFragment()
+				Expect(err).ShouldNot(HaveOccurred())
+				orderFragments[i] = orderFragment
+			}
+
+			// Each batch costs MaxOpenOrderBatchSize / FragmentsPerRateLimitToken
+			// tokens from a burst of 20
+			conn, err := Dial(context.Background(), serviceMultiAddr)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+			request := &OpenOrderBatchRequest{}
+			for i := range orderFragments {
+				request.OrderFragments = append(request.OrderFragments, &EncryptedOrderFragment{Id: orderFragments[i].ID[:]})
+			}
+			for i := 0; i < 20/(MaxOpenOrderBatchSize/FragmentsPerRateLimitToken); i++ {
+				_, err = NewOrderbookServiceClient(conn).OpenOrderBatch(context.Background(), request, grpc.FailFast(false))
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			_, err = NewOrderbookServiceClient(conn).OpenOrderBatch(context.Background(), request)
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should not open batches that are too large", func() {
+			conn, err := Dial(context.Background(), serviceMultiAddr)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			request := &OpenOrderBatchRequest{
+				OrderFragments: make([]*EncryptedOrderFragment, MaxOpenOrderBatchSize+1),
+			}
+			_, err = NewOrderbookServiceClient(conn).OpenOrderBatch(context.Background(), request, grpc.FailFast(false))
+			Expect(err).Should(HaveOccurred())
+			Expect(atomic.LoadInt64(&serverMock.n)).Should(Equal(int64(0)))
+		})
 	})
 
 })
 
+var errRejected = errors.New("rejected")
+
+// mockTraderKey is the key of the trader for all orders in the
+// mockOrderbookServer.
+var mockTraderKey, _ = crypto.RandomEcdsaKey()
+var mockTrader = ethCrypto.PubkeyToAddress(mockTraderKey.PublicKey).Hex()
+
 type mockOrderbookServer struct {
 	n int64
+
+	mu       *sync.Mutex
+	rejected map[order.FragmentID]bool
+	pending  map[order.ID]bool
+	opened   map[order.FragmentID]order.EncryptedFragment
 }
 
 func (server *mockOrderbookServer) OpenOrder(ctx context.Context, orderFragment order.EncryptedFragment) error {
 	atomic.AddInt64(&server.n, 1)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.rejected[orderFragment.ID] {
+		return errRejected
+	}
+	server.opened[orderFragment.ID] = orderFragment
 	return nil
 }
 
+func (server *mockOrderbookServer) Query(ctx context.Context, query orderbook.Query) (orderbook.OrderState, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.pending[query.OrderID] {
+		return orderbook.OrderState{OrderID: query.OrderID}, nil
+	}
+
+	if err := query.Verify(mockTrader); err != nil {
+		return orderbook.OrderState{}, err
+	}
+	return orderbook.OrderState{
+		OrderID:     query.OrderID,
+		Trader:      mockTrader,
+		Status:      order.Open,
+		EpochDepths: []order.FragmentEpochDepth{0, 1},
+		Matched:     true,
+	}, nil
+}
+
+func (server *mockOrderbookServer) pend(id order.ID) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.pending[id] = true
+}
+
+func (server *mockOrderbookServer) reject(id order.FragmentID) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.rejected[id] = true
+}
+
 func createEncryptedFragment() (order.EncryptedFragment, error) {
 	ord := order.NewOrder(order.ParityBuy, order.TypeMidpoint, time.Now().Add(time.Hour), order.SettlementRenEx, order.TokensBTCETH, 1, 1, 1, 1)
 	ordFragments, err := ord.Split(6, 4)
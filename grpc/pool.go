@@ -0,0 +1,264 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// ErrConnPoolExhausted is returned when a ConnPool has reached its maximum
+// number of connections and none of them are idle.
+var ErrConnPoolExhausted = errors.New("connection pool exhausted")
+
+// ErrConnPoolClosed is returned when acquiring a connection from a ConnPool
+// that has been closed.
+var ErrConnPoolClosed = errors.New("connection pool closed")
+
+// DefaultMaxConnections is the default maximum number of connections that a
+// ConnPool will keep open.
+const DefaultMaxConnections = 256
+
+// DefaultIdleTimeout is the default duration after which a ConnPool closes a
+// connection that has not been used.
+const DefaultIdleTimeout = 5 * time.Minute
+
+// DefaultHealthCheckInterval is the default interval at which a ConnPool
+// closes idle and unhealthy connections.
+const DefaultHealthCheckInterval = time.Minute
+
+type connPoolKey struct {
+	multiAddr string
+	creds     *Credentials
+}
+
+type connPoolEntry struct {
+	key      connPoolKey
+	conn     *grpc.ClientConn
+	refs     int
+	lastUsed time.Time
+	evicted  bool
+}
+
+// A ConnPool shares gRPC connections between all clients that call the same
+// identity.MultiAddress. A gRPC connection multiplexes concurrent calls and
+// streams, so clients acquire a connection for the duration of a call and
+// release it afterwards, instead of dialing a new connection for every call.
+// Connections that are unhealthy, or that have been idle for longer than the
+// idle timeout, are closed.
+type ConnPool struct {
+	maxConns            int
+	idleTimeout         time.Duration
+	healthCheckInterval time.Duration
+
+	mu          *sync.Mutex
+	entries     map[connPoolKey]*connPoolEntry
+	conns       map[*grpc.ClientConn]*connPoolEntry
+	lastChecked time.Time
+	closed      bool
+}
+
+// NewConnPool returns a ConnPool that keeps at most maxConns connections
+// open, and closes connections that have been idle for the idleTimeout. Idle
+// and unhealthy connections are checked at most once per healthCheckInterval.
+func NewConnPool(maxConns int, idleTimeout, healthCheckInterval time.Duration) *ConnPool {
+	return &ConnPool{
+		maxConns:            maxConns,
+		idleTimeout:         idleTimeout,
+		healthCheckInterval: healthCheckInterval,
+
+		mu:          new(sync.Mutex),
+		entries:     map[connPoolKey]*connPoolEntry{},
+		conns:       map[*grpc.ClientConn]*connPoolEntry{},
+		lastChecked: time.Now(),
+	}
+}
+
+// Acquire a connection to the identity.MultiAddress, secured by the
+// DefaultCredentials. An existing connection is returned if there is a
+// healthy one, otherwise a new connection is dialed. The connection must be
+// returned to the ConnPool by calling ConnPool.Release, and must not be
+// closed by the caller.
+func (pool *ConnPool) Acquire(ctx context.Context, multiAddr identity.MultiAddress) (*grpc.ClientConn, error) {
+	if multiAddr.IsNil() {
+		return nil, ErrMultiAddressIsNil
+	}
+	key := connPoolKey{
+		multiAddr: multiAddr.String(),
+		creds:     DefaultCredentials(),
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.closed {
+		return nil, ErrConnPoolClosed
+	}
+	now := time.Now()
+	if now.Sub(pool.lastChecked) >= pool.healthCheckInterval {
+		pool.prune(now)
+	}
+
+	if entry, ok := pool.entries[key]; ok {
+		switch entry.conn.GetState() {
+		case connectivity.Shutdown:
+			pool.evict(entry)
+		case connectivity.TransientFailure:
+			if entry.refs == 0 {
+				pool.evict(entry)
+				break
+			}
+			// The connection is being used by other clients, so reconnect
+			// immediately instead of waiting for the connection backoff
+			entry.conn.ResetConnectBackoff()
+			fallthrough
+		default:
+			entry.refs++
+			entry.lastUsed = now
+			return entry.conn, nil
+		}
+	}
+
+	if len(pool.conns) >= pool.maxConns {
+		pool.prune(now)
+	}
+	if len(pool.conns) >= pool.maxConns && !pool.evictLeastRecentlyUsed() {
+		return nil, ErrConnPoolExhausted
+	}
+
+	conn, err := DialWithCredentials(ctx, multiAddr, key.creds)
+	if err != nil {
+		return nil, err
+	}
+	entry := &connPoolEntry{
+		key:      key,
+		conn:     conn,
+		refs:     1,
+		lastUsed: now,
+	}
+	pool.entries[key] = entry
+	pool.conns[conn] = entry
+	return conn, nil
+}
+
+// Release a connection that was acquired from the ConnPool. The connection
+// stays open so that it can be acquired again, unless it has been evicted.
+func (pool *ConnPool) Release(conn *grpc.ClientConn) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	entry, ok := pool.conns[conn]
+	if !ok || entry.refs == 0 {
+		return
+	}
+	entry.refs--
+	entry.lastUsed = time.Now()
+	if entry.refs == 0 && entry.evicted {
+		pool.close(entry)
+	}
+}
+
+// Len returns the number of connections that are open in the ConnPool.
+func (pool *ConnPool) Len() int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return len(pool.conns)
+}
+
+// Close all connections in the ConnPool. Connections that are still acquired
+// are closed, and the ConnPool cannot be used again.
+func (pool *ConnPool) Close() {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, entry := range pool.conns {
+		pool.close(entry)
+	}
+	pool.closed = true
+}
+
+// prune closes connections that have been idle for longer than the idle
+// timeout, and connections that are unhealthy and not being used. The mutex
+// must be locked by the caller.
+func (pool *ConnPool) prune(now time.Time) {
+	pool.lastChecked = now
+	for _, entry := range pool.conns {
+		if entry.refs > 0 {
+			continue
+		}
+		state := entry.conn.GetState()
+		if now.Sub(entry.lastUsed) >= pool.idleTimeout || state == connectivity.TransientFailure || state == connectivity.Shutdown {
+			pool.evict(entry)
+		}
+	}
+}
+
+// evictLeastRecentlyUsed evicts the connection that has been idle for the
+// longest time. It returns false if there are no idle connections. The mutex
+// must be locked by the caller.
+func (pool *ConnPool) evictLeastRecentlyUsed() bool {
+	var lru *connPoolEntry
+	for _, entry := range pool.conns {
+		if entry.refs > 0 {
+			continue
+		}
+		if lru == nil || entry.lastUsed.Before(lru.lastUsed) {
+			lru = entry
+		}
+	}
+	if lru == nil {
+		return false
+	}
+	pool.evict(lru)
+	return true
+}
+
+// evict a connection so that it cannot be acquired again. The connection is
+// closed once it has been released by all clients. The mutex must be locked
+// by the caller.
+func (pool *ConnPool) evict(entry *connPoolEntry) {
+	if pool.entries[entry.key] == entry {
+		delete(pool.entries, entry.key)
+	}
+	entry.evicted = true
+	if entry.refs == 0 {
+		pool.close(entry)
+	}
+}
+
+// close a connection and remove it from the ConnPool. The mutex must be
+// locked by the caller.
+func (pool *ConnPool) close(entry *connPoolEntry) {
+	if pool.entries[entry.key] == entry {
+		delete(pool.entries, entry.key)
+	}
+	delete(pool.conns, entry.conn)
+	if err := entry.conn.Close(); err != nil {
+		logger.Network(logger.LevelError, fmt.Sprintf("pool: cannot close connection = %v", err))
+	}
+}
+
+var defaultConnPoolMu = new(sync.RWMutex)
+var defaultConnPool = NewConnPool(DefaultMaxConnections, DefaultIdleTimeout, DefaultHealthCheckInterval)
+
+// SetDefaultConnPool sets the ConnPool used by all gRPC clients. The previous
+// ConnPool is not closed.
+func SetDefaultConnPool(pool *ConnPool) {
+	defaultConnPoolMu.Lock()
+	defer defaultConnPoolMu.Unlock()
+	defaultConnPool = pool
+}
+
+// DefaultConnPool returns the ConnPool used by all gRPC clients.
+func DefaultConnPool() *ConnPool {
+	defaultConnPoolMu.RLock()
+	defer defaultConnPoolMu.RUnlock()
+	return defaultConnPool
+}
@@ -0,0 +1,237 @@
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/grpc"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Connection pool", func() {
+
+	var server *grpc.Server
+	var listener net.Listener
+	var multiAddrs identity.MultiAddresses
+
+	BeforeEach(func() {
+		var err error
+		server = grpc.NewServer()
+		listener, err = net.Listen("tcp", "127.0.0.1:3011")
+		Expect(err).ShouldNot(HaveOccurred())
+		go server.Serve(listener)
+
+		multiAddrs = make(identity.MultiAddresses, 3)
+		for i := range multiAddrs {
+			addr, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			multiAddrs[i], err = identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/3011/republic/%v", addr))
+			Expect(err).ShouldNot(HaveOccurred())
+		}
+	})
+
+	AfterEach(func() {
+		server.Stop()
+		// The server may be stopped before it begins serving the listener
+		listener.Close()
+	})
+
+	Context("when acquiring connections", func() {
+
+		It("should share connections to the same multi-address", func() {
+			pool := NewConnPool(DefaultMaxConnections, DefaultIdleTimeout, DefaultHealthCheckInterval)
+			defer pool.Close()
+
+			conn, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			other, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(other).Should(Equal(conn))
+			Expect(status.Code(pingConn(conn))).Should(Equal(codes.Unimplemented))
+
+			pool.Release(conn)
+			pool.Release(other)
+			other, err = pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(other).Should(Equal(conn))
+			Expect(pool.Len()).Should(Equal(1))
+		})
+
+		It("should not share connections to different multi-addresses", func() {
+			pool := NewConnPool(DefaultMaxConnections, DefaultIdleTimeout, DefaultHealthCheckInterval)
+			defer pool.Close()
+
+			conn, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			other, err := pool.Acquire(context.Background(), multiAddrs[1])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(other).ShouldNot(Equal(conn))
+			Expect(pool.Len()).Should(Equal(2))
+		})
+
+		It("should error for nil multi-addresses", func() {
+			pool := NewConnPool(DefaultMaxConnections, DefaultIdleTimeout, DefaultHealthCheckInterval)
+			defer pool.Close()
+
+			_, err := pool.Acquire(context.Background(), identity.MultiAddress{})
+			Expect(err).Should(Equal(ErrMultiAddressIsNil))
+		})
+
+		It("should error after the pool is closed", func() {
+			pool := NewConnPool(DefaultMaxConnections, DefaultIdleTimeout, DefaultHealthCheckInterval)
+			pool.Close()
+
+			_, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).Should(Equal(ErrConnPoolClosed))
+		})
+	})
+
+	Context("when the maximum number of connections is reached", func() {
+
+		It("should evict the least recently used idle connection", func() {
+			pool := NewConnPool(2, DefaultIdleTimeout, DefaultHealthCheckInterval)
+			defer pool.Close()
+
+			first, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			pool.Release(first)
+			second, err := pool.Acquire(context.Background(), multiAddrs[1])
+			Expect(err).ShouldNot(HaveOccurred())
+			pool.Release(second)
+
+			_, err = pool.Acquire(context.Background(), multiAddrs[2])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pool.Len()).Should(Equal(2))
+
+			// The first connection was evicted so a new connection is dialed
+			conn, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(conn).ShouldNot(Equal(first))
+		})
+
+		It("should error when no connections are idle", func() {
+			pool := NewConnPool(2, DefaultIdleTimeout, DefaultHealthCheckInterval)
+			defer pool.Close()
+
+			_, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = pool.Acquire(context.Background(), multiAddrs[1])
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = pool.Acquire(context.Background(), multiAddrs[2])
+			Expect(err).Should(Equal(ErrConnPoolExhausted))
+		})
+	})
+
+	Context("when connections are idle or unhealthy", func() {
+
+		It("should close idle connections after the idle timeout", func() {
+			pool := NewConnPool(DefaultMaxConnections, 10*time.Millisecond, 10*time.Millisecond)
+			defer pool.Close()
+
+			conn, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			pool.Release(conn)
+			busy, err := pool.Acquire(context.Background(), multiAddrs[1])
+			Expect(err).ShouldNot(HaveOccurred())
+
+			time.Sleep(20 * time.Millisecond)
+			other, err := pool.Acquire(context.Background(), multiAddrs[2])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(pool.Len()).Should(Equal(2))
+
+			// Connections that are being used are not closed
+			Expect(status.Code(pingConn(busy))).Should(Equal(codes.Unimplemented))
+			Expect(status.Code(pingConn(other))).Should(Equal(codes.Unimplemented))
+		})
+
+		It("should replace connections that have been shutdown", func() {
+			pool := NewConnPool(DefaultMaxConnections, DefaultIdleTimeout, DefaultHealthCheckInterval)
+			defer pool.Close()
+
+			conn, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			pool.Release(conn)
+			Expect(conn.Close()).ShouldNot(HaveOccurred())
+
+			other, err := pool.Acquire(context.Background(), multiAddrs[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(other).ShouldNot(Equal(conn))
+			Expect(status.Code(pingConn(other))).Should(Equal(codes.Unimplemented))
+		})
+	})
+})
+
+// pingConn invokes the Ping RPC over a connection and returns its error.
+func pingConn(conn *grpc.ClientConn) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := NewSwarmServiceClient(conn).Ping(ctx, &PingRequest{}, grpc.FailFast(false))
+	return err
+}
+
+func benchmarkServer(b *testing.B) (*grpc.Server, identity.MultiAddress) {
+	server := grpc.NewServer()
+	listener, err := net.Listen("tcp", "127.0.0.1:3012")
+	if err != nil {
+		b.Fatal(err)
+	}
+	go server.Serve(listener)
+
+	addr, err := testutils.RandomAddress()
+	if err != nil {
+		b.Fatal(err)
+	}
+	multiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/3012/republic/%v", addr))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return server, multiAddr
+}
+
+func BenchmarkDialPerCall(b *testing.B) {
+	server, multiAddr := benchmarkServer(b)
+	defer server.Stop()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, err := Dial(context.Background(), multiAddr)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := pingConn(conn); status.Code(err) != codes.Unimplemented {
+			b.Fatal(err)
+		}
+		conn.Close()
+	}
+}
+
+func BenchmarkConnPool(b *testing.B) {
+	server, multiAddr := benchmarkServer(b)
+	defer server.Stop()
+
+	pool := NewConnPool(DefaultMaxConnections, DefaultIdleTimeout, DefaultHealthCheckInterval)
+	defer pool.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		conn, err := pool.Acquire(context.Background(), multiAddr)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := pingConn(conn); status.Code(err) != codes.Unimplemented {
+			b.Fatal(err)
+		}
+		pool.Release(conn)
+	}
+}
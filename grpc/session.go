@@ -0,0 +1,203 @@
+package grpc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/smpc"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+// StreamVersion is the version of the handshake used to authenticate streams
+// and agree on a Session. Unversioned handshakes encrypted a secret for the
+// StreamerService and are no longer accepted.
+const StreamVersion = 1
+
+// ErrUnsupportedStreamVersion is returned when a stream handshake uses a
+// version other than the StreamVersion.
+var ErrUnsupportedStreamVersion = errors.New("unsupported stream version")
+
+// ErrMalformedEphemeralKey is returned when a stream handshake contains an
+// ephemeral key that is not a valid Curve25519 public key.
+var ErrMalformedEphemeralKey = errors.New("malformed ephemeral key")
+
+// ErrMalformedFrame is returned when a frame cannot be authenticated by a
+// Session. The frame has either been tampered with, or was not sealed by the
+// peer of the Session.
+var ErrMalformedFrame = errors.New("malformed frame")
+
+// ErrReplayedFrame is returned when a Session opens a frame that has already
+// been opened.
+var ErrReplayedFrame = errors.New("replayed frame")
+
+// ErrOutOfOrderFrame is returned when a Session opens a frame before all of
+// the frames that were sealed before it.
+var ErrOutOfOrderFrame = errors.New("out of order frame")
+
+// ErrSessionExhausted is returned when a Session has sealed the maximum number
+// of frames and a new Session must be agreed.
+var ErrSessionExhausted = errors.New("session exhausted")
+
+const frameCounterLength = 8
+
+// A Session encrypts and authenticates the frames of a stream using AES-GCM.
+// Each direction of the stream uses a different key, and every frame carries
+// a counter that must be exactly one greater than the counter of the previous
+// frame, so frames cannot be replayed, reordered, or reflected back to their
+// sender.
+type Session struct {
+	sendMu      *sync.Mutex
+	send        cipher.AEAD
+	sendCounter uint64
+
+	recvMu      *sync.Mutex
+	recv        cipher.AEAD
+	recvCounter uint64
+}
+
+// NewSession returns a Session that derives its keys from a shared secret and
+// a salt using HKDF. Both peers must use the same secret and salt, and exactly
+// one of them must be the initiator.
+func NewSession(secret, salt []byte, initiator bool) (*Session, error) {
+	initiatorKey, err := deriveSessionKey(secret, salt, "Republic Protocol: stream: initiator")
+	if err != nil {
+		return nil, err
+	}
+	responderKey, err := deriveSessionKey(secret, salt, "Republic Protocol: stream: responder")
+	if err != nil {
+		return nil, err
+	}
+	if !initiator {
+		initiatorKey, responderKey = responderKey, initiatorKey
+	}
+	return &Session{
+		sendMu: new(sync.Mutex),
+		send:   initiatorKey,
+
+		recvMu: new(sync.Mutex),
+		recv:   responderKey,
+	}, nil
+}
+
+// Seal a plain text into a frame that can only be opened, once, by the peer
+// of the Session. Sealing consumes a counter, even if the frame is never
+// sent, so a Session must be discarded if a sealed frame cannot be sent.
+func (session *Session) Seal(plainText []byte) ([]byte, error) {
+	session.sendMu.Lock()
+	defer session.sendMu.Unlock()
+
+	if session.sendCounter == math.MaxUint64 {
+		return nil, ErrSessionExhausted
+	}
+	frame := make([]byte, frameCounterLength, frameCounterLength+len(plainText)+session.send.Overhead())
+	binary.BigEndian.PutUint64(frame, session.sendCounter)
+	frame = session.send.Seal(frame, sessionNonce(session.send, session.sendCounter), plainText, frame[:frameCounterLength])
+	session.sendCounter++
+	return frame, nil
+}
+
+// Open a frame that was sealed by the peer of the Session. Frames must be
+// opened in the order that they were sealed. A frame that cannot be opened
+// does not affect the frames that can be opened after it.
+func (session *Session) Open(frame []byte) ([]byte, error) {
+	session.recvMu.Lock()
+	defer session.recvMu.Unlock()
+
+	if len(frame) < frameCounterLength+session.recv.Overhead() {
+		return nil, ErrMalformedFrame
+	}
+	counter := binary.BigEndian.Uint64(frame[:frameCounterLength])
+	if counter < session.recvCounter {
+		return nil, ErrReplayedFrame
+	}
+	if counter > session.recvCounter {
+		return nil, ErrOutOfOrderFrame
+	}
+	plainText, err := session.recv.Open(nil, sessionNonce(session.recv, counter), frame[frameCounterLength:], frame[:frameCounterLength])
+	if err != nil {
+		return nil, ErrMalformedFrame
+	}
+	session.recvCounter++
+	return plainText, nil
+}
+
+func deriveSessionKey(secret, salt []byte, info string) (cipher.AEAD, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(info)), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sessionNonce(aead cipher.AEAD, counter uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-frameCounterLength:], counter)
+	return nonce
+}
+
+// ephemeralKey is a Curve25519 key pair that is generated for a single stream
+// handshake.
+type ephemeralKey struct {
+	privateKey [32]byte
+	publicKey  [32]byte
+}
+
+func newEphemeralKey() (ephemeralKey, error) {
+	key := ephemeralKey{}
+	if _, err := io.ReadFull(rand.Reader, key.privateKey[:]); err != nil {
+		return key, ErrCannotGenerateSecret
+	}
+	curve25519.ScalarBaseMult(&key.publicKey, &key.privateKey)
+	return key, nil
+}
+
+// session agrees a Session with the peer that owns the public key. The
+// initiatorKey and responderKey are the public keys sent during the
+// handshake, and bind the Session to the handshake.
+func (key ephemeralKey) session(peerPublicKey []byte, networkID smpc.NetworkID, initiatorKey, responderKey []byte, initiator bool) (*Session, error) {
+	if len(peerPublicKey) != 32 {
+		return nil, ErrMalformedEphemeralKey
+	}
+	peer := [32]byte{}
+	copy(peer[:], peerPublicKey)
+
+	secret := [32]byte{}
+	curve25519.ScalarMult(&secret, &key.privateKey, &peer)
+	if subtle.ConstantTimeCompare(secret[:], make([]byte, 32)) == 1 {
+		// The peer used a low order point to force a known secret
+		return nil, ErrMalformedEphemeralKey
+	}
+
+	salt := crypto.Keccak256(networkID[:], initiatorKey, responderKey)
+	return NewSession(secret[:], salt, initiator)
+}
+
+// connectHash returns the hash that is signed by the initiator of a stream
+// handshake.
+func connectHash(from, to identity.Address, networkID smpc.NetworkID, initiatorKey []byte) []byte {
+	prefix := fmt.Sprintf("Republic Protocol: connect: version %d: from %v to %v on ", StreamVersion, from, to)
+	return crypto.Keccak256([]byte(prefix), networkID[:], initiatorKey)
+}
+
+// acceptHash returns the hash that is signed by the responder of a stream
+// handshake.
+func acceptHash(from, to identity.Address, networkID smpc.NetworkID, initiatorKey, responderKey []byte) []byte {
+	prefix := fmt.Sprintf("Republic Protocol: accept: version %d: from %v to %v on ", StreamVersion, from, to)
+	return crypto.Keccak256([]byte(prefix), networkID[:], initiatorKey, responderKey)
+}
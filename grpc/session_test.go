@@ -0,0 +1,255 @@
+package grpc_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/grpc"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/smpc"
+	"github.com/republicprotocol/republic-go/testutils"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Sessions", func() {
+
+	var initiator, responder *Session
+
+	BeforeEach(func() {
+		var err error
+		secret := testutils.Random32Bytes()
+		salt := testutils.Random32Bytes()
+		initiator, err = NewSession(secret[:], salt[:], true)
+		Expect(err).ShouldNot(HaveOccurred())
+		responder, err = NewSession(secret[:], salt[:], false)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when sealing and opening frames", func() {
+
+		It("should open frames sealed by the peer in both directions", func() {
+			for i := 0; i < 10; i++ {
+				frame, err := initiator.Seal([]byte(fmt.Sprintf("ping %d", i)))
+				Expect(err).ShouldNot(HaveOccurred())
+				plainText, err := responder.Open(frame)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(plainText).Should(Equal([]byte(fmt.Sprintf("ping %d", i))))
+
+				frame, err = responder.Seal([]byte(fmt.Sprintf("pong %d", i)))
+				Expect(err).ShouldNot(HaveOccurred())
+				plainText, err = initiator.Open(frame)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(plainText).Should(Equal([]byte(fmt.Sprintf("pong %d", i))))
+			}
+		})
+
+		It("should not open frames that are reflected back to their sender", func() {
+			frame, err := initiator.Seal([]byte("ping"))
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = initiator.Open(frame)
+			Expect(err).Should(Equal(ErrMalformedFrame))
+		})
+
+		It("should not open frames from a different session", func() {
+			secret := testutils.Random32Bytes()
+			other, err := NewSession(secret[:], nil, true)
+			Expect(err).ShouldNot(HaveOccurred())
+			frame, err := other.Seal([]byte("ping"))
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = responder.Open(frame)
+			Expect(err).Should(Equal(ErrMalformedFrame))
+		})
+	})
+
+	Context("when frames are tampered with", func() {
+
+		It("should not open the frame and should open the untampered frame", func() {
+			frame, err := initiator.Seal([]byte("ping"))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for i := range frame {
+				tampered := append([]byte{}, frame...)
+				tampered[i] ^= 0x01
+				_, err = responder.Open(tampered)
+				Expect(err).Should(HaveOccurred())
+			}
+			_, err = responder.Open(frame[:len(frame)-1])
+			Expect(err).Should(Equal(ErrMalformedFrame))
+			_, err = responder.Open(frame[:4])
+			Expect(err).Should(Equal(ErrMalformedFrame))
+
+			plainText, err := responder.Open(frame)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(plainText).Should(Equal([]byte("ping")))
+		})
+	})
+
+	Context("when frames are replayed or reordered", func() {
+
+		It("should not open replayed frames", func() {
+			frame, err := initiator.Seal([]byte("ping"))
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = responder.Open(frame)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = responder.Open(frame)
+			Expect(err).Should(Equal(ErrReplayedFrame))
+		})
+
+		It("should not open frames out of order", func() {
+			first, err := initiator.Seal([]byte("first"))
+			Expect(err).ShouldNot(HaveOccurred())
+			second, err := initiator.Seal([]byte("second"))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = responder.Open(second)
+			Expect(err).Should(Equal(ErrOutOfOrderFrame))
+
+			plainText, err := responder.Open(first)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(plainText).Should(Equal([]byte("first")))
+			plainText, err = responder.Open(second)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(plainText).Should(Equal([]byte("second")))
+		})
+	})
+
+	Context("when a sealed frame cannot be sent", func() {
+
+		It("should tear down the session until a new session is injected", func() {
+			stream := &failingStream{}
+			sender := NewSender(initiator, stream)
+			message := smpc.Message{
+				MessageJoin: &smpc.MessageJoin{
+					Join:      smpc.Join{},
+					NetworkID: smpc.NetworkID{1},
+				},
+				MessageType: smpc.MessageTypeJoin,
+			}
+			Expect(sender.Send(message)).Should(Equal(errSendFailed))
+			Expect(sender.Send(message)).Should(Equal(ErrStreamDisconnected))
+			Expect(stream.sends).Should(Equal(1))
+		})
+	})
+
+	Context("when streaming between darknodes", func() {
+
+		It("should deliver messages over an agreed session", func(done Done) {
+			defer close(done)
+
+			connectorListener, addr, err := newStreamer()
+			Expect(err).ShouldNot(HaveOccurred())
+			service, serviceStreamer, serviceAddr, err := newStreamerService(addr)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			server := NewServer()
+			service.Register(server)
+			go func() {
+				defer GinkgoRecover()
+				Expect(server.Start("0.0.0.0:18515")).ShouldNot(HaveOccurred())
+			}()
+			defer server.Stop()
+			time.Sleep(time.Millisecond)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			networkID := smpc.NetworkID(testutils.Random32Bytes())
+			receiver := newChanReceiver()
+			_, err = serviceStreamer.Listen(ctx, networkID, addr, receiver)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			serviceMultiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/0.0.0.0/tcp/18515/republic/%v", serviceAddr))
+			Expect(err).ShouldNot(HaveOccurred())
+			sender, err := connectorListener.Connect(ctx, networkID, serviceMultiAddr, testutils.NewSmpcReceiver())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			message := smpc.Message{
+				MessageJoin: &smpc.MessageJoin{
+					Join:      smpc.Join{},
+					NetworkID: networkID,
+				},
+				MessageType: smpc.MessageTypeJoin,
+			}
+			Expect(sender.Send(message)).ShouldNot(HaveOccurred())
+			Expect(sender.Send(message)).ShouldNot(HaveOccurred())
+
+			for i := 0; i < 2; i++ {
+				from := <-receiver.messages
+				Expect(from).Should(Equal(addr))
+			}
+		}, 60 /* 60 second timeout */)
+
+		It("should reject unversioned handshakes", func(done Done) {
+			defer close(done)
+
+			_, addr, err := newStreamer()
+			Expect(err).ShouldNot(HaveOccurred())
+			service, _, serviceAddr, err := newStreamerService(addr)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			server := NewServer()
+			service.Register(server)
+			go func() {
+				defer GinkgoRecover()
+				Expect(server.Start("0.0.0.0:18516")).ShouldNot(HaveOccurred())
+			}()
+			defer server.Stop()
+			time.Sleep(time.Millisecond)
+
+			serviceMultiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/0.0.0.0/tcp/18516/republic/%v", serviceAddr))
+			Expect(err).ShouldNot(HaveOccurred())
+			conn, err := Dial(context.Background(), serviceMultiAddr)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			stream, err := NewStreamServiceClient(conn).Connect(context.Background())
+			Expect(err).ShouldNot(HaveOccurred())
+			networkID := testutils.Random32Bytes()
+			Expect(stream.Send(&StreamMessage{
+				Signature: make([]byte, 65),
+				Address:   addr.String(),
+				Network:   networkID[:],
+				Data:      make([]byte, 16),
+			})).ShouldNot(HaveOccurred())
+			_, err = stream.Recv()
+			Expect(err).Should(HaveOccurred())
+		}, 60 /* 60 second timeout */)
+	})
+})
+
+// chanReceiver writes the sender of every smpc.Message that it receives to a
+// channel.
+type chanReceiver struct {
+	messages chan identity.Address
+}
+
+func newChanReceiver() *chanReceiver {
+	return &chanReceiver{messages: make(chan identity.Address, 16)}
+}
+
+func (receiver *chanReceiver) Receive(from identity.Address, message smpc.Message) {
+	receiver.messages <- from
+}
+
+var errSendFailed = errors.New("send failed")
+
+// failingStream is a grpc.Stream that cannot send messages.
+type failingStream struct {
+	sends int
+}
+
+func (stream *failingStream) Context() context.Context {
+	return context.Background()
+}
+
+func (stream *failingStream) SendMsg(m interface{}) error {
+	stream.sends++
+	return errSendFailed
+}
+
+func (stream *failingStream) RecvMsg(m interface{}) error {
+	return errSendFailed
+}
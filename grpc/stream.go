@@ -1,11 +1,10 @@
 package grpc
 
 import (
-	"crypto/rand"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"sync"
 	"time"
 
@@ -33,10 +32,6 @@ var ErrUnverifiedConnection = errors.New("unverified connection")
 // provided when  authenticating a connection.
 var ErrMalformedSignature = errors.New("malformed signature")
 
-// ErrMalformedEncryptionSecret is returned when a malformed encryption secret
-// is provided when authenticating a connection.
-var ErrMalformedEncryptionSecret = errors.New("malformed encryption secret")
-
 // ErrStreamDisconnected is returned when a stream.Stream is disconnected and
 // a connection cannot be re-established.
 var ErrStreamDisconnected = errors.New("stream disconnected")
@@ -45,22 +40,20 @@ var ErrStreamDisconnected = errors.New("stream disconnected")
 // for encrypting a connection.
 var ErrCannotGenerateSecret = errors.New("cannot generate secret")
 
-// ErrCannotEncryptSecret is returned when a secret cannot be encrypted for
-// secure transfer.
-var ErrCannotEncryptSecret = errors.New("cannot encrypt secret")
+// StreamListenTimeout is the duration that a StreamerService waits for a
+// stream to be listened to after accepting it from a client.
+const StreamListenTimeout = 5 * time.Second
 
 type Sender struct {
-	cipher crypto.AESCipher
-
 	streamMu *sync.Mutex
+	session  *Session
 	stream   grpc.Stream
 }
 
-func NewSender(secret []byte, stream grpc.Stream) *Sender {
+func NewSender(session *Session, stream grpc.Stream) *Sender {
 	return &Sender{
-		cipher: crypto.NewAESCipher(secret),
-
 		streamMu: new(sync.Mutex),
+		session:  session,
 		stream:   stream,
 	}
 }
@@ -73,7 +66,7 @@ func (sender *Sender) Send(message smpc.Message) error {
 	sender.streamMu.Lock()
 	defer sender.streamMu.Unlock()
 
-	if sender.stream == nil {
+	if sender.stream == nil || sender.session == nil {
 		return ErrStreamDisconnected
 	}
 
@@ -81,31 +74,51 @@ func (sender *Sender) Send(message smpc.Message) error {
 	if err != nil {
 		return err
 	}
-	data, err = sender.cipher.Encrypt(data)
+	data, err = sender.session.Seal(data)
 	if err != nil {
 		return err
 	}
 
-	return sender.stream.SendMsg(&StreamMessage{
+	if err := sender.stream.SendMsg(&StreamMessage{
 		Data: data,
-	})
+	}); err != nil {
+		// The Session has consumed a counter that the peer will never open,
+		// so every frame sealed after it would be rejected. The Session is
+		// torn down, instead of reusing the counter, until the stream is
+		// reconnected with a new Session.
+		sender.teardown()
+		return err
+	}
+	return nil
 }
 
-func (sender *Sender) inject(secret []byte, stream grpc.Stream) {
+func (sender *Sender) inject(session *Session, stream grpc.Stream) {
 	sender.streamMu.Lock()
 	defer sender.streamMu.Unlock()
 
 	if sender.stream != nil {
 		if stream, ok := sender.stream.(grpc.ClientStream); ok {
 			if err := stream.CloseSend(); err != nil {
-				log.Printf("[error] cannot release stream: %v", err)
+				logger.Stream(logger.LevelError, fmt.Sprintf("cannot release stream: %v", err))
 			}
 		}
 	}
-	sender.cipher = crypto.NewAESCipher(secret)
+	sender.session = session
 	sender.stream = stream
 }
 
+// open a frame received from the stream using the current Session.
+func (sender *Sender) open(frame []byte) ([]byte, error) {
+	sender.streamMu.Lock()
+	session := sender.session
+	sender.streamMu.Unlock()
+
+	if session == nil {
+		return nil, ErrStreamDisconnected
+	}
+	return session.Open(frame)
+}
+
 func (sender *Sender) release() {
 	sender.streamMu.Lock()
 	defer sender.streamMu.Unlock()
@@ -115,23 +128,39 @@ func (sender *Sender) release() {
 	}
 	if stream, ok := sender.stream.(grpc.ClientStream); ok {
 		if err := stream.CloseSend(); err != nil {
-			log.Printf("[error] (release) cannot close stream client = %v", err)
+			logger.Stream(logger.LevelError, fmt.Sprintf("release: cannot close stream client = %v", err))
 		}
 	}
 	sender.stream = nil
 }
 
+// teardown the Session and the stream after a frame cannot be sent. Sending
+// returns ErrStreamDisconnected, and received frames cannot be opened, until
+// a new Session is injected. The mutex must be locked by the caller.
+func (sender *Sender) teardown() {
+	if stream, ok := sender.stream.(grpc.ClientStream); ok {
+		if err := stream.CloseSend(); err != nil {
+			logger.Stream(logger.LevelError, fmt.Sprintf("teardown: cannot close stream client = %v", err))
+		}
+	}
+	sender.session = nil
+	sender.stream = nil
+}
+
 type Connector struct {
-	addr      identity.Address
-	signer    crypto.Signer
-	encrypter crypto.Encrypter
+	addr     identity.Address
+	signer   crypto.Signer
+	verifier crypto.Verifier
 }
 
-func NewConnector(addr identity.Address, signer crypto.Signer, encrypter crypto.Encrypter) *Connector {
+// NewConnector returns a Connector that signs stream handshakes using the
+// signer, and uses the verifier to verify that the StreamerService accepting
+// the stream is a registered darknode.
+func NewConnector(addr identity.Address, signer crypto.Signer, verifier crypto.Verifier) *Connector {
 	return &Connector{
-		addr:      addr,
-		signer:    signer,
-		encrypter: encrypter,
+		addr:     addr,
+		signer:   signer,
+		verifier: verifier,
 	}
 }
 
@@ -141,15 +170,16 @@ func (connector *Connector) Connect(ctx context.Context, networkID smpc.NetworkI
 	}
 
 	connCtx, connCancel := context.WithCancel(ctx)
-	secret, stream, err := connector.connect(connCtx, networkID, to)
+	session, stream, err := connector.connect(connCtx, networkID, to)
 	if err != nil {
 		connCancel()
 		return nil, err
 	}
-	if secret == nil || stream == nil {
-		return nil, fmt.Errorf("secret or stream is nil")
+	if session == nil || stream == nil {
+		connCancel()
+		return nil, fmt.Errorf("session or stream is nil")
 	}
-	sender := NewSender(secret, stream)
+	sender := NewSender(session, stream)
 
 	// This function is used to read a message from the sender defined above
 	addr := to.Address()
@@ -157,19 +187,19 @@ func (connector *Connector) Connect(ctx context.Context, networkID smpc.NetworkI
 		// Block until a message is received or an error occurs
 		rawMessage, err := stream.Recv()
 		if err != nil {
-			log.Printf("[error] cannot receive message from %v on network %v: %v", addr, networkID, err)
+			logger.Stream(logger.LevelError, fmt.Sprintf("cannot receive message from %v on network %v: %v", addr, networkID, err))
 			return err
 		}
 		// Decrypt the message
-		data, err := sender.cipher.Decrypt(rawMessage.Data)
+		data, err := sender.open(rawMessage.Data)
 		if err != nil {
-			log.Printf("[error] received malformed encryption from %v on network %v: %v", addr, networkID, err)
+			logger.Stream(logger.LevelError, fmt.Sprintf("received malformed encryption from %v on network %v: %v", addr, networkID, err))
 			return err
 		}
 		// Unmarshal the message
 		message := smpc.Message{}
 		if err := message.UnmarshalBinary(data); err != nil {
-			log.Printf("[error] received malformed message from %v on network %v: %v", addr, networkID, err)
+			logger.Stream(logger.LevelError, fmt.Sprintf("received malformed message from %v on network %v: %v", addr, networkID, err))
 			return err
 		}
 		// Notify the receiver of the message
@@ -194,11 +224,11 @@ func (connector *Connector) Connect(ctx context.Context, networkID smpc.NetworkI
 						connCtx, connCancel = context.WithCancel(ctx)
 
 						// Reconnect when an error occurs
-						secret, stream, err = connector.connect(connCtx, networkID, to)
+						session, stream, err = connector.connect(connCtx, networkID, to)
 						if err != nil {
 							return err
 						}
-						sender.inject(secret, stream)
+						sender.inject(session, stream)
 						time.Sleep(time.Second)
 
 						// The reconnection is not considered successful until
@@ -219,7 +249,7 @@ func (connector *Connector) Connect(ctx context.Context, networkID smpc.NetworkI
 				// Backoff error indicates that the stream is dead and there is
 				// no hope of reconnecting
 				if backoffErr != nil {
-					log.Printf("[error] cannot reconnect to %v on network %v: %v", to.Address(), networkID, backoffErr)
+					logger.Stream(logger.LevelError, fmt.Sprintf("cannot reconnect to %v on network %v: %v", to.Address(), networkID, backoffErr))
 					connCancel()
 					return
 				}
@@ -233,32 +263,34 @@ func (connector *Connector) Connect(ctx context.Context, networkID smpc.NetworkI
 	return sender, nil
 }
 
-func (connector *Connector) connect(ctx context.Context, networkID smpc.NetworkID, to identity.MultiAddress) ([]byte, StreamService_ConnectClient, error) {
-	// Establish a connection to the identity.MultiAddress and clean the
+func (connector *Connector) connect(ctx context.Context, networkID smpc.NetworkID, to identity.MultiAddress) (*Session, StreamService_ConnectClient, error) {
+	// Acquire a connection to the identity.MultiAddress and release the
 	// connection once the context.Context is done
-	log.Printf("[debug] (stream) dialing...")
-	conn, err := Dial(ctx, to)
+	logger.Stream(logger.LevelDebug, "dialing...")
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, to)
 	if err != nil {
 		return nil, nil, fmt.Errorf("cannot dial %v: %v", to, err)
 	}
 	go func() {
-		defer func() {
-			if err := conn.Close(); err != nil {
-				log.Printf("[error] (connect) cannot close connection = %v", err)
-			}
-		}()
+		defer pool.Release(conn)
 		<-ctx.Done()
 	}()
 
-	// Open a bidirectional stream
+	// Open a bidirectional stream and agree on a Session
+	var session *Session
 	var stream StreamService_ConnectClient
 	if err := BackoffMax(ctx, func() error {
 		// On an error backoff and retry until the context.Context is done
 		stream, err = NewStreamServiceClient(conn).Connect(ctx)
+		if err == nil {
+			logger.Stream(logger.LevelDebug, "authorising...")
+			session, err = connector.handshake(networkID, to.Address(), stream)
+		}
 		if err != nil {
 			if stream != nil {
 				if err := stream.CloseSend(); err != nil {
-					log.Printf("[error] (connect) cannot close stream client = %v", err)
+					logger.Stream(logger.LevelError, fmt.Sprintf("connect: cannot close stream client = %v", err))
 				}
 			}
 			return err
@@ -268,37 +300,51 @@ func (connector *Connector) connect(ctx context.Context, networkID smpc.NetworkI
 		return nil, nil, fmt.Errorf("cannot open stream: %v", err)
 	}
 
-	// Generate a secret
-	log.Printf("[debug] (stream) authorising...")
-	secret := make([]byte, 16)
-	if _, err := rand.Read(secret); err != nil {
-		return secret, nil, ErrCannotGenerateSecret
-	}
-	encryptedSecret, err := connector.encrypter.Encrypt(to.Address().String(), secret[:])
+	return session, stream, nil
+}
+
+// handshake sends a signed ephemeral key to the StreamerService and waits for
+// it to accept the stream with its own signed ephemeral key. The Session is
+// agreed using both ephemeral keys.
+func (connector *Connector) handshake(networkID smpc.NetworkID, to identity.Address, stream StreamService_ConnectClient) (*Session, error) {
+	key, err := newEphemeralKey()
 	if err != nil {
-		return secret, nil, fmt.Errorf("%v = %v", ErrCannotEncryptSecret, err)
+		return nil, err
 	}
 
 	// Sign an authentication message so that the StreamService can verify the
 	// identity.Address of the client
-	signature := append([]byte(fmt.Sprintf("Republic Protocol: connect: from %v to %v on ", connector.addr, to.Address())), networkID[:]...)
-	signature = crypto.Keccak256(signature)
-	signature, err = connector.signer.Sign(signature)
+	signature, err := connector.signer.Sign(connectHash(connector.addr, to, networkID, key.publicKey[:]))
 	if err != nil {
-		return secret, nil, fmt.Errorf("cannot sign stream authentication: %v", err)
+		return nil, fmt.Errorf("cannot sign stream authentication: %v", err)
 	}
-
-	// Send the authentication message
 	if err := stream.Send(&StreamMessage{
-		Signature: signature,
-		Address:   connector.addr.String(),
-		Network:   networkID[:],
-		Data:      encryptedSecret,
+		Signature:    signature,
+		Address:      connector.addr.String(),
+		Network:      networkID[:],
+		Version:      StreamVersion,
+		EphemeralKey: key.publicKey[:],
 	}); err != nil {
-		return secret, nil, fmt.Errorf("cannot send stream address: %v", err)
+		return nil, fmt.Errorf("cannot send stream authentication: %v", err)
 	}
 
-	return secret, stream, err
+	// Verify that the stream was accepted by the identity.Address that was
+	// dialed
+	message, err := stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("cannot receive stream acceptance: %v", err)
+	}
+	if message.GetVersion() != StreamVersion {
+		return nil, ErrUnsupportedStreamVersion
+	}
+	if message.GetAddress() != to.String() || !bytes.Equal(message.GetNetwork(), networkID[:]) || len(message.GetSignature()) != 65 {
+		return nil, ErrMalformedSignature
+	}
+	hash := acceptHash(to, connector.addr, networkID, key.publicKey[:], message.GetEphemeralKey())
+	if err := verifySignature(to, connector.verifier, hash, message.GetSignature()); err != nil {
+		return nil, err
+	}
+	return key.session(message.GetEphemeralKey(), networkID, key.publicKey[:], message.GetEphemeralKey(), true)
 }
 
 type Listener struct {
@@ -347,9 +393,9 @@ type ConnectorListener struct {
 	*Listener
 }
 
-func NewConnectorListener(addr identity.Address, signer crypto.Signer, encrypter crypto.Encrypter) ConnectorListener {
+func NewConnectorListener(addr identity.Address, signer crypto.Signer, verifier crypto.Verifier) ConnectorListener {
 	return ConnectorListener{
-		Connector: NewConnector(addr, signer, encrypter),
+		Connector: NewConnector(addr, signer, verifier),
 		Listener:  NewListener(),
 	}
 }
@@ -358,23 +404,25 @@ func NewConnectorListener(addr identity.Address, signer crypto.Signer, encrypter
 // a gRPC Server it will listen for requests to the StreamService.Connect RPC
 // and pass the connections to a Streamer.
 type StreamerService struct {
-	addr      identity.Address
-	verifier  crypto.Verifier
-	decrypter crypto.Decrypter
-	lis       *Listener
+	addr     identity.Address
+	signer   crypto.Signer
+	verifier crypto.Verifier
+	lis      *Listener
 
 	donesMu *sync.Mutex
 	dones   map[smpc.NetworkID]map[identity.Address](chan struct{})
 }
 
 // NewStreamerService returns an implementation of the gRPC StreamService that
-// connects stream.Streams from clients to a Streamer.
-func NewStreamerService(addr identity.Address, verifier crypto.Verifier, decrypter crypto.Decrypter, lis *Listener) StreamerService {
+// connects stream.Streams from clients to a Streamer. The signer is used to
+// accept stream handshakes, and the verifier is used to verify that clients
+// are registered darknodes.
+func NewStreamerService(addr identity.Address, signer crypto.Signer, verifier crypto.Verifier, lis *Listener) StreamerService {
 	return StreamerService{
-		addr:      addr,
-		verifier:  verifier,
-		decrypter: decrypter,
-		lis:       lis,
+		addr:     addr,
+		signer:   signer,
+		verifier: verifier,
+		lis:      lis,
 
 		donesMu: new(sync.Mutex),
 		dones:   map[smpc.NetworkID]map[identity.Address](chan struct{}){},
@@ -397,37 +445,38 @@ func (service *StreamerService) Connect(stream StreamService_ConnectServer) erro
 	// Verify the address of this connection
 	message, err := stream.Recv()
 	if err != nil {
-		log.Printf("[error] cannot receive authorisation message on network: %v", err)
+		logger.Stream(logger.LevelError, fmt.Sprintf("cannot receive authorisation message on network: %v", err))
 		return err
 	}
-	addr, networkID, secret, err := service.verifyAuthentication(message.GetSignature(), message.GetAddress(), message.GetNetwork(), message.GetData())
+	addr, networkID, err := service.verifyAuthentication(message)
 	if err != nil {
-		log.Printf("[error] cannot authorise stream on network: %v", err)
+		logger.Stream(logger.LevelError, fmt.Sprintf("cannot authorise stream on network: %v", err))
 		return err
 	}
-	ctx, receiver, sender := func() (context.Context, smpc.Receiver, *Sender) {
-		service.lis.mu.Lock()
-		defer service.lis.mu.Unlock()
-		if _, ok := service.lis.contexts[networkID]; !ok {
-			return nil, nil, nil
-		}
-		if _, ok := service.lis.receivers[networkID]; !ok {
-			return nil, nil, nil
-		}
-		if _, ok := service.lis.senders[networkID]; !ok {
-			return nil, nil, nil
-		}
-		if _, ok := service.lis.contexts[networkID][addr]; !ok {
-			return nil, nil, nil
-		}
-		if _, ok := service.lis.receivers[networkID][addr]; !ok {
-			return nil, nil, nil
+
+	// Accept the stream before injecting it into a Sender, so that the
+	// acceptance is the first message received by the client
+	session, err := service.accept(addr, networkID, message.GetEphemeralKey(), stream)
+	if err != nil {
+		logger.Stream(logger.LevelError, fmt.Sprintf("cannot accept stream from %v on network %v: %v", addr, networkID, err))
+		return err
+	}
+
+	// The client may connect before the stream is listened to, so wait for a
+	// short time before rejecting the connection
+	var ctx context.Context
+	var receiver smpc.Receiver
+	var sender *Sender
+	for begin := time.Now(); time.Since(begin) < StreamListenTimeout; time.Sleep(100 * time.Millisecond) {
+		if ctx, receiver, sender = service.listener(networkID, addr); sender != nil {
+			break
 		}
-		if _, ok := service.lis.senders[networkID][addr]; !ok {
-			return nil, nil, nil
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		default:
 		}
-		return service.lis.contexts[networkID][addr], service.lis.receivers[networkID][addr], service.lis.senders[networkID][addr]
-	}()
+	}
 	if ctx == nil || receiver == nil || sender == nil {
 		// TODO: Return a more appropriate error
 		return fmt.Errorf("not ready to accept connection")
@@ -447,8 +496,8 @@ func (service *StreamerService) Connect(stream StreamService_ConnectServer) erro
 	}()
 
 	time.Sleep(time.Second)
-	sender.inject(secret[:], stream)
-	log.Printf("[debug] (stream) accepted connection from %v", addr)
+	sender.inject(session, stream)
+	logger.Stream(logger.LevelDebug, fmt.Sprintf("accepted connection from %v", addr))
 
 	go func() {
 		for {
@@ -472,18 +521,18 @@ func (service *StreamerService) Connect(stream StreamService_ConnectServer) erro
 							return nil
 						}
 					}
-					log.Printf("[error] cannot receive message from %v on network %v: %v", addr, networkID, recvErr)
+					logger.Stream(logger.LevelError, fmt.Sprintf("cannot receive message from %v on network %v: %v", addr, networkID, recvErr))
 					return recvErr
 				}
 				// Decrypt the message
-				data, err := sender.cipher.Decrypt(rawMessage.Data)
+				data, err := sender.open(rawMessage.Data)
 				if err != nil {
-					log.Printf("[error] received malformed encryption from %v on network %v: %v", addr, networkID, err)
+					logger.Stream(logger.LevelError, fmt.Sprintf("received malformed encryption from %v on network %v: %v", addr, networkID, err))
 					return err
 				}
 				message := smpc.Message{}
 				if err := message.UnmarshalBinary(data); err != nil {
-					log.Printf("[error] received malformed message from %v on network %v: %v", addr, networkID, err)
+					logger.Stream(logger.LevelError, fmt.Sprintf("received malformed message from %v on network %v: %v", addr, networkID, err))
 					return err
 				}
 				receiver.Receive(addr, message)
@@ -502,7 +551,7 @@ func (service *StreamerService) Connect(stream StreamService_ConnectServer) erro
 			}
 
 			if backoffErr != nil {
-				log.Printf("[error] cannot relisten to %v on network %v: %v", addr, networkID, backoffErr)
+				logger.Stream(logger.LevelError, fmt.Sprintf("cannot relisten to %v on network %v: %v", addr, networkID, backoffErr))
 				return
 			}
 		}
@@ -513,43 +562,98 @@ func (service *StreamerService) Connect(stream StreamService_ConnectServer) erro
 	select {
 	case <-done:
 		// TODO: Return better error.
-		log.Printf("[debug] (stream) client reconnected to an accepted connection")
+		logger.Stream(logger.LevelDebug, "client reconnected to an accepted connection")
 		return nil
 	case <-ctx.Done():
-		log.Printf("[debug] (stream) server closed accepted connection")
+		logger.Stream(logger.LevelDebug, "server closed accepted connection")
 		return nil
 	case <-stream.Context().Done():
-		log.Printf("[debug] (stream) client closed accepted connection")
+		logger.Stream(logger.LevelDebug, "client closed accepted connection")
 		return nil
 	}
 }
 
-func (service *StreamerService) verifyAuthentication(signature []byte, addr string, networkID []byte, encryptedSecret []byte) (identity.Address, smpc.NetworkID, [16]byte, error) {
+// listener returns the context.Context, smpc.Receiver, and Sender used to
+// listen to a stream, or nils if the stream is not being listened to.
+func (service *StreamerService) listener(networkID smpc.NetworkID, addr identity.Address) (context.Context, smpc.Receiver, *Sender) {
+	service.lis.mu.Lock()
+	defer service.lis.mu.Unlock()
+	if _, ok := service.lis.contexts[networkID]; !ok {
+		return nil, nil, nil
+	}
+	if _, ok := service.lis.receivers[networkID]; !ok {
+		return nil, nil, nil
+	}
+	if _, ok := service.lis.senders[networkID]; !ok {
+		return nil, nil, nil
+	}
+	if _, ok := service.lis.contexts[networkID][addr]; !ok {
+		return nil, nil, nil
+	}
+	if _, ok := service.lis.receivers[networkID][addr]; !ok {
+		return nil, nil, nil
+	}
+	if _, ok := service.lis.senders[networkID][addr]; !ok {
+		return nil, nil, nil
+	}
+	return service.lis.contexts[networkID][addr], service.lis.receivers[networkID][addr], service.lis.senders[networkID][addr]
+}
 
+func (service *StreamerService) verifyAuthentication(message *StreamMessage) (identity.Address, smpc.NetworkID, error) {
+	if message.GetVersion() != StreamVersion {
+		return identity.Address(""), smpc.NetworkID{}, ErrUnsupportedStreamVersion
+	}
+
+	signature, addr, networkID := message.GetSignature(), message.GetAddress(), message.GetNetwork()
 	if signature == nil || len(signature) != 65 || networkID == nil || len(networkID) != 32 || addr == "" {
-		return identity.Address(""), smpc.NetworkID{}, [16]byte{}, ErrMalformedSignature
+		return identity.Address(""), smpc.NetworkID{}, ErrMalformedSignature
 	}
-	if encryptedSecret == nil {
-		return identity.Address(""), smpc.NetworkID{}, [16]byte{}, ErrMalformedEncryptionSecret
+	if len(message.GetEphemeralKey()) != 32 {
+		return identity.Address(""), smpc.NetworkID{}, ErrMalformedEphemeralKey
 	}
+	networkID32 := [32]byte{}
+	copy(networkID32[:], networkID)
 
-	message := append([]byte(fmt.Sprintf("Republic Protocol: connect: from %v to %v on ", addr, service.addr)), networkID...)
-	message = crypto.Keccak256(message)
-	if err := service.verifier.Verify(message, signature); err != nil {
-		return identity.Address(""), smpc.NetworkID{}, [16]byte{}, err
+	hash := connectHash(identity.Address(addr), service.addr, networkID32, message.GetEphemeralKey())
+	if err := verifySignature(identity.Address(addr), service.verifier, hash, signature); err != nil {
+		return identity.Address(""), smpc.NetworkID{}, err
 	}
 
-	secret, err := service.decrypter.Decrypt(encryptedSecret)
+	return identity.Address(addr), smpc.NetworkID(networkID32), nil
+}
+
+// accept a stream by sending a signed ephemeral key to the client, and agree
+// on a Session using the ephemeral key of the client.
+func (service *StreamerService) accept(addr identity.Address, networkID smpc.NetworkID, clientKey []byte, stream StreamService_ConnectServer) (*Session, error) {
+	key, err := newEphemeralKey()
 	if err != nil {
-		return identity.Address(""), smpc.NetworkID{}, [16]byte{}, err
+		return nil, err
 	}
-	if len(secret) != 16 {
-		return identity.Address(""), smpc.NetworkID{}, [16]byte{}, ErrMalformedEncryptionSecret
+	session, err := key.session(clientKey, networkID, clientKey, key.publicKey[:], false)
+	if err != nil {
+		return nil, err
 	}
-	secret16 := [16]byte{}
-	copy(secret16[:], secret)
-	networkID32 := [32]byte{}
-	copy(networkID32[:], networkID)
+	signature, err := service.signer.Sign(acceptHash(service.addr, addr, networkID, clientKey, key.publicKey[:]))
+	if err != nil {
+		return nil, fmt.Errorf("cannot sign stream acceptance: %v", err)
+	}
+	if err := stream.Send(&StreamMessage{
+		Signature:    signature,
+		Address:      service.addr.String(),
+		Network:      networkID[:],
+		Version:      StreamVersion,
+		EphemeralKey: key.publicKey[:],
+	}); err != nil {
+		return nil, fmt.Errorf("cannot send stream acceptance: %v", err)
+	}
+	return session, nil
+}
 
-	return identity.Address(addr), smpc.NetworkID(networkID32), secret16, nil
+// verifySignature verifies that the hash was signed by the identity.Address,
+// and that the signature is accepted by the verifier.
+func verifySignature(addr identity.Address, verifier crypto.Verifier, hash, signature []byte) error {
+	if err := crypto.NewEcdsaVerifier(addr.String()).Verify(hash, signature); err != nil {
+		return fmt.Errorf("%v: %v", ErrUnverifiedConnection, err)
+	}
+	return verifier.Verify(hash, signature)
 }
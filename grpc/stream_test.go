@@ -87,20 +87,26 @@ var _ = Describe("Streaming", func() {
 })
 
 func newStreamer() (ConnectorListener, identity.Address, error) {
+	streamer, _, addr, err := newStreamerWithKey()
+	return streamer, addr, err
+}
+
+func newStreamerWithKey() (ConnectorListener, crypto.EcdsaKey, identity.Address, error) {
 	ecdsaKey, err := crypto.RandomEcdsaKey()
 	if err != nil {
-		return ConnectorListener{}, identity.Address(""), err
+		return ConnectorListener{}, ecdsaKey, identity.Address(""), err
 	}
 	addr := identity.Address(ecdsaKey.Address())
-	return NewConnectorListener(addr, &ecdsaKey, testutils.NewCrypter()), addr, nil
+	return NewConnectorListener(addr, &ecdsaKey, testutils.NewCrypter()), ecdsaKey, addr, nil
 }
 
 func newStreamerService(clientAddr identity.Address) (*StreamerService, *ConnectorListener, identity.Address, error) {
 	var streamer ConnectorListener
+	var key crypto.EcdsaKey
 	var addr identity.Address
 	var err error
 	for {
-		streamer, addr, err = newStreamer()
+		streamer, key, addr, err = newStreamerWithKey()
 		if err != nil {
 			return nil, &streamer, addr, err
 		}
@@ -108,7 +114,7 @@ func newStreamerService(clientAddr identity.Address) (*StreamerService, *Connect
 			break
 		}
 	}
-	service := NewStreamerService(addr, crypto.NewEcdsaVerifier(clientAddr.String()), testutils.NewCrypter(), streamer.Listener)
+	service := NewStreamerService(addr, &key, crypto.NewEcdsaVerifier(clientAddr.String()), streamer.Listener)
 	return &service, &streamer, addr, nil
 }
 
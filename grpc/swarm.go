@@ -3,7 +3,9 @@ package grpc
 import (
 	"errors"
 	"fmt"
+	"net"
 
+	"github.com/republicprotocol/republic-go/discovery"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/swarm"
@@ -52,12 +54,13 @@ func (client *swarmClient) Ping(ctx context.Context, to identity.MultiAddress, m
 	if multiAddr.IsNil() {
 		return ErrMultiAddressIsNil
 	}
-	conn, err := Dial(ctx, to)
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, to)
 	if err != nil {
 		logger.Network(logger.LevelError, fmt.Sprintf("cannot dial %v: %v", to, err))
 		return fmt.Errorf("cannot dial %v: %v", to, err)
 	}
-	defer conn.Close()
+	defer pool.Release(conn)
 
 	request := &PingRequest{
 		MultiAddress: &MultiAddress{
@@ -74,12 +77,13 @@ func (client *swarmClient) Ping(ctx context.Context, to identity.MultiAddress, m
 }
 
 func (client *swarmClient) Pong(ctx context.Context, to identity.MultiAddress) error {
-	conn, err := Dial(ctx, to)
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, to)
 	if err != nil {
 		logger.Network(logger.LevelError, fmt.Sprintf("cannot dial %v: %v", to, err))
 		return fmt.Errorf("cannot dial %v: %v", to, err)
 	}
-	defer conn.Close()
+	defer pool.Release(conn)
 
 	multiAddr, err := client.store.MultiAddress(client.addr)
 	if err != nil {
@@ -93,6 +97,7 @@ func (client *swarmClient) Pong(ctx context.Context, to identity.MultiAddress) e
 			MultiAddress:      multiAddr.String(),
 			MultiAddressNonce: multiAddr.Nonce,
 		},
+		ObservedAddress: observedAddressFromContext(ctx),
 	}
 
 	return Backoff(ctx, func() error {
@@ -106,12 +111,13 @@ func (client *swarmClient) Query(ctx context.Context, to identity.MultiAddress,
 	if query == "" {
 		return identity.MultiAddresses{}, ErrAddressIsNil
 	}
-	conn, err := Dial(ctx, to)
+	pool := DefaultConnPool()
+	conn, err := pool.Acquire(ctx, to)
 	if err != nil {
 		logger.Network(logger.LevelError, fmt.Sprintf("cannot dial %v: %v", to, err))
 		return identity.MultiAddresses{}, fmt.Errorf("cannot dial %v: %v", to, err)
 	}
-	defer conn.Close()
+	defer pool.Release(conn)
 
 	request := &QueryRequest{
 		Address: query.String(),
@@ -153,7 +159,8 @@ func (client *swarmClient) MultiAddress() identity.MultiAddress {
 // protobuf. It delegates responsibility for handling the Ping and Query RPCs
 // to a swarm.Server.
 type SwarmService struct {
-	server swarm.Server
+	server   swarm.Server
+	observer discovery.Observer
 }
 
 // NewSwarmService returns a SwarmService that uses the swarm.Server as a
@@ -164,6 +171,18 @@ func NewSwarmService(server swarm.Server) SwarmService {
 	}
 }
 
+// NewSwarmServiceWithObserver returns a SwarmService that uses the
+// swarm.Server as a delegate. The IP addresses at which other darknodes
+// observe this darknode, reported in the PongRequest, are recorded by the
+// discovery.Observer after the PongRequest has been accepted by the
+// swarm.Server.
+func NewSwarmServiceWithObserver(server swarm.Server, observer discovery.Observer) SwarmService {
+	return SwarmService{
+		server:   server,
+		observer: observer,
+	}
+}
+
 // Register implements the Service interface.
 func (service *SwarmService) Register(server *Server) {
 	if server == nil {
@@ -195,6 +214,9 @@ func (service *SwarmService) Ping(ctx context.Context, request *PingRequest) (*P
 	from.Signature = request.GetMultiAddress().GetSignature()
 	from.Nonce = request.GetMultiAddress().GetMultiAddressNonce()
 
+	// Report the observed address of the client in the PongRequest
+	ctx = withObservedAddress(ctx)
+
 	err = service.server.Ping(ctx, from)
 	if err != nil {
 		logger.Network(logger.LevelInfo, fmt.Sprintf("cannot update store with: %v", err))
@@ -231,6 +253,14 @@ func (service *SwarmService) Pong(ctx context.Context, request *PongRequest) (*P
 		logger.Network(logger.LevelInfo, fmt.Sprintf("cannot update storer with %v: %v", request.GetMultiAddress(), err))
 		return &PongResponse{}, fmt.Errorf("cannot update storer: %v", err)
 	}
+
+	// The PongRequest has been verified so the observed address can be
+	// recorded
+	if service.observer != nil && request.GetObservedAddress() != "" {
+		if ip := net.ParseIP(request.GetObservedAddress()); ip != nil {
+			service.observer.Observe(from.Address(), ip)
+		}
+	}
 	return &PongResponse{}, nil
 }
 
@@ -266,3 +296,25 @@ func (service *SwarmService) Query(ctx context.Context, request *QueryRequest) (
 		MultiAddresses: multiAddrMsgs,
 	}, nil
 }
+
+type observedAddressKey struct{}
+
+// withObservedAddress returns a context that stores the IP address of the
+// gRPC peer in the context. If the peer is unknown, the original context is
+// returned.
+func withObservedAddress(ctx context.Context) context.Context {
+	clientIP, err := addressFromContext(ctx)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, observedAddressKey{}, clientIP)
+}
+
+// observedAddressFromContext returns the IP address stored in the context by
+// withObservedAddress, or an empty string.
+func observedAddressFromContext(ctx context.Context) string {
+	if host, ok := ctx.Value(observedAddressKey{}).(string); ok {
+		return host
+	}
+	return ""
+}
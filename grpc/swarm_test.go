@@ -2,7 +2,9 @@ package grpc_test
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo"
@@ -11,6 +13,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/discovery"
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/leveldb"
@@ -30,6 +33,8 @@ var _ = Describe("Swarming", func() {
 	var client swarm.Client
 	var clientDb swarm.MultiAddressStorer
 	var swarmer swarm.Swarmer
+	var table swarm.RoutingTable
+	var tracker swarm.PeerTracker
 	var verifier registry.Crypter
 
 	BeforeEach(func() {
@@ -41,9 +46,11 @@ var _ = Describe("Swarming", func() {
 		serviceClient, verifier, err = newSwarmClient(serviceClientDb)
 		Expect(err).ShouldNot(HaveOccurred())
 
-		swarmer = swarm.NewSwarmer(serviceClient, serviceClientDb, 10, &verifier)
+		table = swarm.NewRoutingTable(serviceClient.MultiAddress().Address(), swarm.BucketSize)
+		tracker = swarm.NewPeerTracker()
+		swarmer = swarm.NewSwarmer(serviceClient, serviceClientDb, table, tracker, 10, &verifier)
 		Expect(err).ShouldNot(HaveOccurred())
-		service = NewSwarmService(swarm.NewServer(swarmer, serviceClientDb, 10, &verifier))
+		service = NewSwarmService(swarm.NewServer(swarmer, serviceClientDb, table, tracker, swarm.NewGuard(swarm.DefaultNonceWindow, swarm.DefaultRelayLimit, swarm.DefaultRelayBurst), 10, &verifier))
 		serviceMultiAddr = serviceClient.MultiAddress()
 		server = NewServer()
 		service.Register(server)
@@ -98,6 +105,29 @@ var _ = Describe("Swarming", func() {
 			Expect(multiAddrs).Should(HaveLen(2))
 		})
 
+		It("should report the observed address of the client in the pong", func(done Done) {
+			defer close(done)
+
+			observer := newMockObserver()
+			service = NewSwarmServiceWithObserver(swarm.NewServer(swarmer, serviceClientDb, table, tracker, swarm.NewGuard(swarm.DefaultNonceWindow, swarm.DefaultRelayLimit, swarm.DefaultRelayBurst), 10, &verifier), observer)
+			server = NewServer()
+			service.Register(server)
+
+			go func() {
+				defer GinkgoRecover()
+
+				err := server.Start("0.0.0.0:18514")
+				Expect(err).ShouldNot(HaveOccurred())
+			}()
+			time.Sleep(time.Millisecond)
+
+			// The client and the service are bound to the same endpoint so the
+			// service will receive its own pong
+			err := client.Ping(context.Background(), serviceMultiAddr, client.MultiAddress())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(observer.observations).Should(HaveKeyWithValue(serviceMultiAddr.Address(), "127.0.0.1"))
+		})
+
 		It("should return an error if nil multi-address is provided", func(done Done) {
 			defer close(done)
 
@@ -150,7 +180,7 @@ var _ = Describe("Swarming", func() {
 		It("should error when too many requests are sent to the server", func(done Done) {
 			defer close(done)
 
-			service = NewSwarmService(swarm.NewServer(swarmer, serviceClientDb, 10, &verifier))
+			service = NewSwarmService(swarm.NewServer(swarmer, serviceClientDb, table, tracker, swarm.NewGuard(swarm.DefaultNonceWindow, swarm.DefaultRelayLimit, swarm.DefaultRelayBurst), 10, &verifier))
 			serviceMultiAddr = serviceClient.MultiAddress()
 			unaryLimiter := NewRateLimiter(rate.NewLimiter(20, 40), 5, 1)
 			streamLimiter := NewRateLimiter(rate.NewLimiter(40, 80), 4.0, 20)
@@ -181,6 +211,28 @@ var _ = Describe("Swarming", func() {
 	})
 })
 
+type mockObserver struct {
+	mu           *sync.Mutex
+	observations map[identity.Address]string
+}
+
+func newMockObserver() *mockObserver {
+	return &mockObserver{
+		mu:           new(sync.Mutex),
+		observations: map[identity.Address]string{},
+	}
+}
+
+func (observer *mockObserver) Observe(from identity.Address, ip net.IP) {
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	observer.observations[from] = ip.String()
+}
+
+func (observer *mockObserver) Discover(ctx context.Context, port int) (*net.TCPAddr, error) {
+	return nil, discovery.ErrAddressNotFound
+}
+
 func newSwarmClient(db swarm.MultiAddressStorer) (swarm.Client, registry.Crypter, error) {
 	key, err := crypto.RandomKeystore()
 	if err != nil {
@@ -0,0 +1,236 @@
+package grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// ErrMissingCertificate is returned when a peer does not present a
+// certificate during the TLS handshake.
+var ErrMissingCertificate = errors.New("missing certificate")
+
+// ErrMissingIdentity is returned when the certificate of a peer does not
+// contain an identity extension.
+var ErrMissingIdentity = errors.New("missing identity in certificate")
+
+// ErrInvalidIdentity is returned when the identity extension in the
+// certificate of a peer was not signed by the identity.Address that it
+// claims.
+var ErrInvalidIdentity = errors.New("invalid identity in certificate")
+
+// ErrUnexpectedIdentity is returned when the identity.Address in the
+// certificate of a peer is not the identity.Address that was dialed.
+var ErrUnexpectedIdentity = errors.New("unexpected identity in certificate")
+
+// IdentityExtension is the object identifier of the X.509 extension that binds
+// the public key of a certificate to the identity.Address of a darknode.
+var IdentityExtension = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 53595, 1, 1}
+
+// identityPrefix is prepended to the public key of a certificate before it is
+// signed, so that the signature cannot be used in any other context.
+var identityPrefix = []byte("Republic Protocol TLS identity:")
+
+// TLSOptions configure the transport security of gRPC servers and clients.
+type TLSOptions struct {
+
+	// Enabled uses TLS for all gRPC servers and clients. All darknodes in a
+	// network must agree on whether or not TLS is enabled.
+	Enabled bool `json:"enabled"`
+
+	// RequireClientCertificates rejects clients that do not present a
+	// certificate for a registered darknode. Traders cannot connect to
+	// servers that require client certificates.
+	RequireClientCertificates bool `json:"requireClientCertificates"`
+}
+
+type identityCertificate struct {
+	Address   string
+	Signature []byte
+}
+
+// Credentials for mutually authenticated TLS. The certificate is generated
+// for an ephemeral key, and is bound to the identity.Address of a darknode by
+// a signature from the darknode ECDSA key.
+type Credentials struct {
+	addr                      identity.Address
+	certificate               tls.Certificate
+	verifier                  crypto.Verifier
+	requireClientCertificates bool
+}
+
+// NewCredentials returns Credentials for the identity.Address of the signer.
+// The verifier is used to verify that the certificates of peers were signed
+// by registered darknodes, and can be a registry.Crypter.
+func NewCredentials(signer crypto.Signer, addr identity.Address, verifier crypto.Verifier, requireClientCertificates bool) (*Credentials, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(crypto.Keccak256(identityPrefix, publicKey))
+	if err != nil {
+		return nil, err
+	}
+	extension, err := asn1.Marshal(identityCertificate{
+		Address:   addr.String(),
+		Signature: signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template := x509.Certificate{
+		SerialNumber:    serialNumber,
+		Subject:         pkix.Name{CommonName: addr.String()},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		ExtraExtensions: []pkix.Extension{{Id: IdentityExtension, Value: extension}},
+	}
+	certificate, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Credentials{
+		addr: addr,
+		certificate: tls.Certificate{
+			Certificate: [][]byte{certificate},
+			PrivateKey:  key,
+		},
+		verifier:                  verifier,
+		requireClientCertificates: requireClientCertificates,
+	}, nil
+}
+
+// Address returns the identity.Address of the Credentials.
+func (creds *Credentials) Address() identity.Address {
+	return creds.addr
+}
+
+// ServerOption returns a grpc.ServerOption that uses TLS. Clients that
+// present a certificate must present a certificate for a registered darknode.
+func (creds *Credentials) ServerOption() grpc.ServerOption {
+	clientAuth := tls.RequestClientCert
+	if creds.requireClientCertificates {
+		clientAuth = tls.RequireAnyClientCert
+	}
+	return grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{creds.certificate},
+		ClientAuth:   clientAuth,
+		MinVersion:   tls.VersionTLS12,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				if creds.requireClientCertificates {
+					return ErrMissingCertificate
+				}
+				return nil
+			}
+			_, err := VerifyCertificate(rawCerts[0], creds.verifier)
+			return err
+		},
+	}))
+}
+
+// DialOption returns a grpc.DialOption that uses TLS and expects the server
+// to present a certificate for the identity.Address.
+func (creds *Credentials) DialOption(addr identity.Address) grpc.DialOption {
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{creds.certificate},
+		MinVersion:   tls.VersionTLS12,
+		// The certificate chain is not verified because certificates are
+		// self-signed, instead the identity is verified
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				return ErrMissingCertificate
+			}
+			certAddr, err := VerifyCertificate(rawCerts[0], creds.verifier)
+			if err != nil {
+				return err
+			}
+			if certAddr != addr {
+				return ErrUnexpectedIdentity
+			}
+			return nil
+		},
+	}))
+}
+
+// VerifyCertificate returns the identity.Address in an ASN.1 DER encoded
+// certificate. The identity.Address must have signed the public key of the
+// certificate, and the signature is checked using the verifier. The verifier
+// can be nil, in which case only the signature is checked.
+func VerifyCertificate(rawCert []byte, verifier crypto.Verifier) (identity.Address, error) {
+	cert, err := x509.ParseCertificate(rawCert)
+	if err != nil {
+		return "", err
+	}
+
+	var ext *pkix.Extension
+	for i := range cert.Extensions {
+		if cert.Extensions[i].Id.Equal(IdentityExtension) {
+			ext = &cert.Extensions[i]
+			break
+		}
+	}
+	if ext == nil {
+		return "", ErrMissingIdentity
+	}
+	identityCert := identityCertificate{}
+	if _, err := asn1.Unmarshal(ext.Value, &identityCert); err != nil {
+		return "", err
+	}
+
+	hash := crypto.Keccak256(identityPrefix, cert.RawSubjectPublicKeyInfo)
+	if err := crypto.NewEcdsaVerifier(identityCert.Address).Verify(hash, identityCert.Signature); err != nil {
+		return "", ErrInvalidIdentity
+	}
+	if verifier != nil {
+		if err := verifier.Verify(hash, identityCert.Signature); err != nil {
+			return "", err
+		}
+	}
+	return identity.Address(identityCert.Address), nil
+}
+
+var defaultCredentialsMu = new(sync.RWMutex)
+var defaultCredentials *Credentials
+
+// SetDefaultCredentials sets the Credentials used by Dial and by new Servers.
+// Setting nil Credentials disables TLS.
+func SetDefaultCredentials(creds *Credentials) {
+	defaultCredentialsMu.Lock()
+	defer defaultCredentialsMu.Unlock()
+	defaultCredentials = creds
+}
+
+// DefaultCredentials returns the Credentials used by Dial and by new Servers.
+// It returns nil if TLS is disabled.
+func DefaultCredentials() *Credentials {
+	defaultCredentialsMu.RLock()
+	defer defaultCredentialsMu.RUnlock()
+	return defaultCredentials
+}
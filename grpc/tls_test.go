@@ -0,0 +1,163 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/grpc"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("TLS", func() {
+
+	var registered *mockRegistry
+	var serverKey, clientKey crypto.EcdsaKey
+	var serverMultiAddr identity.MultiAddress
+	var server *grpc.Server
+
+	serve := func(requireClientCertificates bool) {
+		creds, err := NewCredentials(&serverKey, identity.Address(serverKey.Address()), registered, requireClientCertificates)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(creds.Address()).Should(Equal(identity.Address(serverKey.Address())))
+
+		server = grpc.NewServer(creds.ServerOption())
+		listener, err := net.Listen("tcp", "127.0.0.1:3010")
+		Expect(err).ShouldNot(HaveOccurred())
+		go server.Serve(listener)
+	}
+
+	ping := func(conn *grpc.ClientConn) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_, err := NewSwarmServiceClient(conn).Ping(ctx, &PingRequest{}, grpc.FailFast(false))
+		return err
+	}
+
+	BeforeEach(func() {
+		var err error
+		serverKey, err = crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		clientKey, err = crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		registered = &mockRegistry{addrs: map[string]bool{
+			serverKey.Address(): true,
+			clientKey.Address(): true,
+		}}
+		serverMultiAddr, err = identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/3010/republic/%s", serverKey.Address()))
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Stop()
+	})
+
+	Context("when the server presents a certificate for the dialed address", func() {
+
+		It("should connect clients with a certificate", func() {
+			serve(true)
+			creds, err := NewCredentials(&clientKey, identity.Address(clientKey.Address()), registered, true)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			conn, err := DialWithCredentials(context.Background(), serverMultiAddr, creds)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			// The server has no services, so a connected client will receive
+			// an unimplemented error
+			Expect(status.Code(ping(conn))).Should(Equal(codes.Unimplemented))
+		})
+
+		It("should not connect insecure clients", func() {
+			serve(false)
+
+			conn, err := DialWithCredentials(context.Background(), serverMultiAddr, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			Expect(status.Code(ping(conn))).ShouldNot(Equal(codes.Unimplemented))
+		})
+	})
+
+	Context("when the server presents a certificate for a different address", func() {
+
+		It("should not connect", func() {
+			serve(false)
+			creds, err := NewCredentials(&clientKey, identity.Address(clientKey.Address()), registered, false)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			otherMultiAddr, err := identity.NewMultiAddressFromString(fmt.Sprintf("/ip4/127.0.0.1/tcp/3010/republic/%s", clientKey.Address()))
+			Expect(err).ShouldNot(HaveOccurred())
+			conn, err := DialWithCredentials(context.Background(), otherMultiAddr, creds)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			Expect(status.Code(ping(conn))).ShouldNot(Equal(codes.Unimplemented))
+		})
+	})
+
+	Context("when the client presents a certificate for an unregistered darknode", func() {
+
+		It("should not connect", func() {
+			serve(true)
+			delete(registered.addrs, clientKey.Address())
+			creds, err := NewCredentials(&clientKey, identity.Address(clientKey.Address()), nil, true)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			conn, err := DialWithCredentials(context.Background(), serverMultiAddr, creds)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+
+			Expect(status.Code(ping(conn))).ShouldNot(Equal(codes.Unimplemented))
+		})
+	})
+
+	Context("when the client does not present a certificate", func() {
+
+		It("should not connect to a server that requires client certificates", func() {
+			serve(true)
+			creds, err := NewCredentials(&clientKey, identity.Address(clientKey.Address()), registered, true)
+			Expect(err).ShouldNot(HaveOccurred())
+			conn, err := DialWithCredentials(context.Background(), serverMultiAddr, creds)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+			Expect(status.Code(ping(conn))).Should(Equal(codes.Unimplemented))
+
+			// A certificate that is not bound to an identity is rejected
+			otherKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			forged, err := NewCredentials(&otherKey, identity.Address(clientKey.Address()), nil, true)
+			Expect(err).ShouldNot(HaveOccurred())
+			conn, err = DialWithCredentials(context.Background(), serverMultiAddr, forged)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer conn.Close()
+			Expect(status.Code(ping(conn))).ShouldNot(Equal(codes.Unimplemented))
+		})
+	})
+})
+
+// mockRegistry verifies that signatures were produced by registered
+// addresses.
+type mockRegistry struct {
+	addrs map[string]bool
+}
+
+func (registry *mockRegistry) Verify(data []byte, signature []byte) error {
+	addr, err := crypto.RecoverAddress(data, signature)
+	if err != nil {
+		return err
+	}
+	if !registry.addrs[addr] {
+		return errors.New("unregistered address")
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package adapter
 
 import (
 	"encoding/hex"
+	"time"
 
 	"github.com/republicprotocol/republic-go/status"
 )
@@ -17,7 +18,45 @@ type Status struct {
 	PublicKey               string            `json:"publicKey"`
 	InfuraURL               string            `json:"infura"`
 	Tokens                  map[string]string `json:"tokens"`
+	TokenPairs              []string          `json:"tokenPairs"`
+	FragmentVersions        []uint32          `json:"fragmentVersions"`
+	MatchingPolicy          string            `json:"matchingPolicy"`
+	PoolDepths              []PoolDepth       `json:"poolDepths"`
+	QueueDepths             []QueueDepth      `json:"queueDepths"`
 	Peers                   int               `json:"peers"`
+	PeerHealth              []PeerHealth      `json:"peerHealth"`
+}
+
+// PoolDepth defines a structure for JSON marshalling the number of
+// preprocessed random values stored for a network and predicate.
+type PoolDepth struct {
+	Network   string `json:"network"`
+	Predicate string `json:"predicate"`
+	Dealt     int    `json:"dealt"`
+	Received  int    `json:"received"`
+}
+
+// QueueDepth defines a structure for JSON marshalling the number of
+// computations that are waiting to be resolved for an epoch.
+type QueueDepth struct {
+	Epoch       string `json:"epoch"`
+	Depth       int    `json:"depth"`
+	Capacity    int    `json:"capacity"`
+	Evicted     uint64 `json:"evicted"`
+	Regenerated uint64 `json:"regenerated"`
+}
+
+// PeerHealth defines a structure for JSON marshalling the health of a peer.
+// The last seen time is a Unix timestamp in seconds, and the round trip time
+// is in milliseconds.
+type PeerHealth struct {
+	Address             string  `json:"address"`
+	LastSeen            int64   `json:"lastSeen"`
+	RTT                 int64   `json:"rtt"`
+	Successes           int     `json:"successes"`
+	Failures            int     `json:"failures"`
+	ConsecutiveFailures int     `json:"consecutiveFailures"`
+	Score               float64 `json:"score"`
 }
 
 // StatusAdapter defines a struct which has status reading capability
@@ -66,6 +105,22 @@ func (adapter *StatusAdapter) Status() (Status, error) {
 	if err != nil {
 		return Status{}, err
 	}
+	peerHealth := make([]PeerHealth, len(peers))
+	for i, peer := range peers {
+		lastSeen := int64(0)
+		if !peer.LastSeen.IsZero() {
+			lastSeen = peer.LastSeen.Unix()
+		}
+		peerHealth[i] = PeerHealth{
+			Address:             peer.Address.String(),
+			LastSeen:            lastSeen,
+			RTT:                 int64(peer.RTT / time.Millisecond),
+			Successes:           peer.Successes,
+			Failures:            peer.Failures,
+			ConsecutiveFailures: peer.ConsecutiveFailures,
+			Score:               peer.Score,
+		}
+	}
 	infuraURL, err := adapter.InfuraURL()
 	if err != nil {
 		return Status{}, err
@@ -74,6 +129,45 @@ func (adapter *StatusAdapter) Status() (Status, error) {
 	if err != nil {
 		return Status{}, err
 	}
+	tokenPairs, err := adapter.TokenPairs()
+	if err != nil {
+		return Status{}, err
+	}
+	fragmentVersions, err := adapter.FragmentVersions()
+	if err != nil {
+		return Status{}, err
+	}
+	matchingPolicy, err := adapter.MatchingPolicy()
+	if err != nil {
+		return Status{}, err
+	}
+	depths, err := adapter.PoolDepths()
+	if err != nil {
+		return Status{}, err
+	}
+	poolDepths := make([]PoolDepth, len(depths))
+	for i, depth := range depths {
+		poolDepths[i] = PoolDepth{
+			Network:   depth.Network,
+			Predicate: depth.Predicate,
+			Dealt:     depth.Dealt,
+			Received:  depth.Received,
+		}
+	}
+	qDepths, err := adapter.QueueDepths()
+	if err != nil {
+		return Status{}, err
+	}
+	queueDepths := make([]QueueDepth, len(qDepths))
+	for i, depth := range qDepths {
+		queueDepths[i] = QueueDepth{
+			Epoch:       depth.Epoch,
+			Depth:       depth.Depth,
+			Capacity:    depth.Capacity,
+			Evicted:     depth.Evicted,
+			Regenerated: depth.Regenerated,
+		}
+	}
 	pk, err := adapter.PublicKey()
 	if err != nil {
 		return Status{}, err
@@ -89,6 +183,12 @@ func (adapter *StatusAdapter) Status() (Status, error) {
 		PublicKey:               hexPk,
 		InfuraURL:               infuraURL,
 		Tokens:                  tokens,
-		Peers:                   peers,
+		TokenPairs:              tokenPairs,
+		FragmentVersions:        fragmentVersions,
+		MatchingPolicy:          matchingPolicy,
+		PoolDepths:              poolDepths,
+		QueueDepths:             queueDepths,
+		Peers:                   len(peers),
+		PeerHealth:              peerHealth,
 	}, nil
 }
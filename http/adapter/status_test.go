@@ -13,6 +13,7 @@ import (
 	"github.com/republicprotocol/republic-go/http"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/status"
+	"github.com/republicprotocol/republic-go/swarm"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -33,6 +34,9 @@ var _ = Describe("Status adapter", func() {
 		prov.WritePublicKey([]byte{byte(103)})
 		prov.WriteRewardVaultAddress("0x123456789012345678")
 		prov.WriteTokens(map[string]string{"REN": "083", "DGX": "012", "ABC": "223"})
+		prov.WriteTokenPairs([]string{"ETH-REN", "ETH-DGX", "ETH-ABC"})
+		prov.WriteFragmentVersions([]uint32{0, 1})
+		prov.WriteMatchingPolicy("prioritySum")
 	}
 
 	// assertStatus will assert that all the fields in the status match the
@@ -73,6 +77,27 @@ var _ = Describe("Status adapter", func() {
 		providerTokens, err := reader.Tokens()
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(status.Tokens).To(Equal(providerTokens))
+
+		providerTokenPairs, err := reader.TokenPairs()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.TokenPairs).To(Equal(providerTokenPairs))
+
+		providerFragmentVersions, err := reader.FragmentVersions()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.FragmentVersions).To(Equal(providerFragmentVersions))
+
+		providerMatchingPolicy, err := reader.MatchingPolicy()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.MatchingPolicy).To(Equal(providerMatchingPolicy))
+
+		providerPeers, err := reader.Peers()
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(status.Peers).To(Equal(len(providerPeers)))
+		Expect(status.PeerHealth).To(HaveLen(len(providerPeers)))
+		for i, peer := range providerPeers {
+			Expect(status.PeerHealth[i].Address).To(Equal(peer.Address.String()))
+			Expect(status.PeerHealth[i].Score).To(Equal(peer.Score))
+		}
 	}
 
 	// sendRequestAndAssertSuccess will send a GET http request to retrieve the
@@ -95,7 +120,7 @@ var _ = Describe("Status adapter", func() {
 
 	BeforeEach(func() {
 		swarmer := testutils.NewMockSwarmer()
-		prov = status.NewProvider(&swarmer)
+		prov = status.NewProvider(&swarmer, swarm.NewPeerTracker())
 		populateProvider(prov)
 	})
 
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/multiformats/go-multiaddr"
@@ -16,10 +18,17 @@ const (
 	IP4Code      = 0x0004
 	IP6Code      = 0x0029
 	TCPCode      = 0x0006
+	DNS4Code     = 0x0036
+	DNS6Code     = 0x0037
 	RepublicCode = 0x0065
 )
 
-// Add the Republic Protocol when the package is initialized.
+// ErrUnsupportedNetwork is returned when a MultiAddress does not contain a
+// supported network address and TCP port.
+var ErrUnsupportedNetwork = errors.New("unsupported network address")
+
+// Add the Republic Protocol, and the DNS protocols, when the package is
+// initialized.
 func init() {
 	republic := multiaddr.Protocol{
 		Code:       RepublicCode,
@@ -29,6 +38,26 @@ func init() {
 		Transcoder: multiaddr.NewTranscoderFromFunctions(republicStB, republicBtS, nil),
 	}
 	multiaddr.AddProtocol(republic)
+
+	dns4 := multiaddr.Protocol{
+		Code:       DNS4Code,
+		VCode:      multiaddr.CodeToVarint(DNS4Code),
+		Size:       multiaddr.LengthPrefixedVarSize,
+		Name:       "dns4",
+		Path:       false,
+		Transcoder: multiaddr.NewTranscoderFromFunctions(dnsStB, dnsBtS, nil),
+	}
+	multiaddr.AddProtocol(dns4)
+
+	dns6 := multiaddr.Protocol{
+		Code:       DNS6Code,
+		VCode:      multiaddr.CodeToVarint(DNS6Code),
+		Size:       multiaddr.LengthPrefixedVarSize,
+		Name:       "dns6",
+		Path:       false,
+		Transcoder: multiaddr.NewTranscoderFromFunctions(dnsStB, dnsBtS, nil),
+	}
+	multiaddr.AddProtocol(dns6)
 }
 
 // MultiAddress is an alias.
@@ -79,6 +108,40 @@ func (multiAddress MultiAddress) ValueForProtocol(code int) (string, error) {
 	return multiAddress.baseMultiAddress.ValueForProtocol(code)
 }
 
+// NetworkAddress returns the network, and the "host:port" address, that can
+// be used to dial the MultiAddress. The network is "tcp4" for /ip4/ and
+// /dns4/ multi-addresses, and "tcp6" for /ip6/ and /dns6/ multi-addresses.
+// Hostnames are not resolved. An ErrUnsupportedNetwork is returned when the
+// MultiAddress does not begin with one of these protocols followed by a TCP
+// port.
+func (multiAddress MultiAddress) NetworkAddress() (string, string, error) {
+	if multiAddress.baseMultiAddress == nil {
+		return "", "", ErrUnsupportedNetwork
+	}
+	protocols := multiAddress.baseMultiAddress.Protocols()
+	if len(protocols) < 2 || protocols[1].Code != TCPCode {
+		return "", "", ErrUnsupportedNetwork
+	}
+	network := ""
+	switch protocols[0].Code {
+	case IP4Code, DNS4Code:
+		network = "tcp4"
+	case IP6Code, DNS6Code:
+		network = "tcp6"
+	default:
+		return "", "", ErrUnsupportedNetwork
+	}
+	host, err := multiAddress.baseMultiAddress.ValueForProtocol(protocols[0].Code)
+	if err != nil {
+		return "", "", err
+	}
+	port, err := multiAddress.baseMultiAddress.ValueForProtocol(TCPCode)
+	if err != nil {
+		return "", "", err
+	}
+	return network, net.JoinHostPort(host, port), nil
+}
+
 // Address returns the Republic address of a MultiAddress.
 func (multiAddress MultiAddress) Address() Address {
 	return multiAddress.address
@@ -198,3 +261,19 @@ func republicBtS(b []byte) (string, error) {
 	// This uses the default Bitcoin alphabet for Base58 encoding.
 	return m.B58String(), nil
 }
+
+// dnsStB converts a hostname from a string to bytes.
+func dnsStB(s string) ([]byte, error) {
+	if len(s) == 0 || len(s) > 253 || strings.ContainsAny(s, "/ ") {
+		return nil, fmt.Errorf("failed to parse dns addr: %s", s)
+	}
+	return []byte(s), nil
+}
+
+// dnsBtS converts a hostname, encoded as bytes, to a string.
+func dnsBtS(b []byte) (string, error) {
+	if len(b) == 0 {
+		return "", errors.New("empty dns addr")
+	}
+	return string(b), nil
+}
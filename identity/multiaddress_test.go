@@ -25,6 +25,11 @@ var _ = Describe("MultiAddresses with support for Republic Protocol", func() {
 			Expect(identity.ProtocolWithCode(identity.RepublicCode).Name).Should(Equal("republic"))
 			Expect(identity.ProtocolWithCode(identity.RepublicCode).Code).Should(Equal(identity.RepublicCode))
 		})
+
+		It("should expose the dns4 and dns6 protocols", func() {
+			Expect(identity.ProtocolWithName("dns4").Code).Should(Equal(identity.DNS4Code))
+			Expect(identity.ProtocolWithName("dns6").Code).Should(Equal(identity.DNS6Code))
+		})
 	})
 
 	Context("when creating new multi-addresses", func() {
@@ -57,6 +62,63 @@ var _ = Describe("MultiAddresses with support for Republic Protocol", func() {
 			Expect(multiAddress.ValueForProtocol(identity.TCPCode)).Should(Equal(tcp))
 			Expect(multiAddress.ValueForProtocol(identity.IP4Code)).Should(Equal(ip4))
 		})
+
+		It("should give the right value of ip6 and dns protocols", func() {
+			republicAddress := "8MGfbzAMS59Gb4cSjpm34soGNYsM2f"
+			multiAddress, err := identity.NewMultiAddressFromString("/ip6/2001:db8::1/tcp/80/republic/" + republicAddress)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(multiAddress.ValueForProtocol(identity.IP6Code)).Should(Equal("2001:db8::1"))
+
+			multiAddress, err = identity.NewMultiAddressFromString("/dns4/darknode.example.com/tcp/80/republic/" + republicAddress)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(multiAddress.ValueForProtocol(identity.DNS4Code)).Should(Equal("darknode.example.com"))
+			Expect(multiAddress.ValueForProtocol(identity.RepublicCode)).Should(Equal(republicAddress))
+			Expect(multiAddress.String()).Should(Equal("/dns4/darknode.example.com/tcp/80/republic/" + republicAddress))
+
+			multiAddress, err = identity.NewMultiAddressFromString("/dns6/darknode.example.com/tcp/80/republic/" + republicAddress)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(multiAddress.ValueForProtocol(identity.DNS6Code)).Should(Equal("darknode.example.com"))
+		})
+	})
+
+	Context("when retrieving network addresses", func() {
+		republicAddress := "8MGfbzAMS59Gb4cSjpm34soGNYsM2f"
+
+		It("should return the network and address for supported protocols", func() {
+			cases := []struct {
+				multiAddress string
+				network      string
+				address      string
+			}{
+				{"/ip4/127.0.0.1/tcp/18514", "tcp4", "127.0.0.1:18514"},
+				{"/ip6/2001:db8::1/tcp/18514", "tcp6", "[2001:db8::1]:18514"},
+				{"/dns4/darknode.example.com/tcp/18514", "tcp4", "darknode.example.com:18514"},
+				{"/dns6/darknode.example.com/tcp/18514", "tcp6", "darknode.example.com:18514"},
+			}
+			for _, c := range cases {
+				multiAddress, err := identity.NewMultiAddressFromString(c.multiAddress + "/republic/" + republicAddress)
+				Expect(err).ShouldNot(HaveOccurred())
+				network, address, err := multiAddress.NetworkAddress()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(network).Should(Equal(c.network))
+				Expect(address).Should(Equal(c.address))
+			}
+		})
+
+		It("should error for unsupported protocols", func() {
+			multiAddress, err := identity.NewMultiAddressFromString("/ip4/127.0.0.1/udp/18514/republic/" + republicAddress)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, _, err = multiAddress.NetworkAddress()
+			Expect(err).Should(Equal(identity.ErrUnsupportedNetwork))
+
+			multiAddress, err = identity.NewMultiAddressFromString("/republic/" + republicAddress)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, _, err = multiAddress.NetworkAddress()
+			Expect(err).Should(Equal(identity.ErrUnsupportedNetwork))
+
+			_, _, err = identity.MultiAddress{}.NetworkAddress()
+			Expect(err).Should(Equal(identity.ErrUnsupportedNetwork))
+		})
 	})
 
 	Context("when marshaling to JSON", func() {
@@ -74,6 +136,38 @@ var _ = Describe("MultiAddresses with support for Republic Protocol", func() {
 			Expect(multi.String()).Should(Equal(newMulti.String()))
 		})
 
+		It("should encode and then decode dns and ip6 multi-addresses to the same value", func() {
+			key, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			addr := identity.Address(key.Address())
+			for _, base := range []string{"/ip6/2001:db8::1/tcp/18514", "/dns4/darknode.example.com/tcp/18514", "/dns6/darknode.example.com/tcp/18514"} {
+				multi, err := identity.NewMultiAddressFromString(base + "/republic/" + addr.String())
+				Expect(err).ShouldNot(HaveOccurred())
+				multi.Nonce = 1
+				multi.Signature, err = key.Sign(multi.Hash())
+				Expect(err).ShouldNot(HaveOccurred())
+
+				data, err := json.Marshal(multi)
+				Expect(err).ShouldNot(HaveOccurred())
+				newMulti := identity.MultiAddress{}
+				Expect(json.Unmarshal(data, &newMulti)).ShouldNot(HaveOccurred())
+				Expect(newMulti.String()).Should(Equal(multi.String()))
+				Expect(newMulti.Hash()).Should(Equal(multi.Hash()))
+			}
+		})
+
+		It("should decode bootstrap multi-addresses with hostnames from strings", func() {
+			key, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			addr := identity.Address(key.Address())
+			data := []byte(fmt.Sprintf(`["/dns4/bootstrap.example.com/tcp/18514/republic/%v"]`, addr))
+			multis := identity.MultiAddresses{}
+			Expect(json.Unmarshal(data, &multis)).ShouldNot(HaveOccurred())
+			Expect(multis).Should(HaveLen(1))
+			Expect(multis[0].Address()).Should(Equal(addr))
+			Expect(multis[0].ValueForProtocol(identity.DNS4Code)).Should(Equal("bootstrap.example.com"))
+		})
+
 		It("should return error when marshaling empty MultiAddress", func() {
 			empty := identity.MultiAddress{}
 			_, err := empty.MarshalJSON()
@@ -49,6 +49,16 @@ var (
 	SomerComputationIterEnd      = paddingBytes(0xFF, 32)
 )
 
+// Constants for use in the index of the SomerComputationTable by order ID.
+// Keys in the index have a length of 64 bytes, 32 bytes for the order ID and
+// 32 bytes for the computation ID, and so no padding is needed to ensure that
+// keys are 64 bytes.
+var (
+	SomerOrderComputationTableBegin = []byte{0x13, 0x00}
+	SomerOrderComputationIterBegin  = paddingBytes(0x00, 32)
+	SomerOrderComputationIterEnd    = paddingBytes(0xFF, 32)
+)
+
 // Constants for use in the SomerBuyOrderFragmentTable. Keys in the
 // SomerBuyOrderFragmentTable have a length of 64 bytes, 32 bytes for the
 // epoch and 32 bytes for the order ID, and so no padding is needed to ensure
@@ -273,24 +273,47 @@ func (table *OrderbookOrderFragmentTable) OrderFragments() (orderbook.OrderFragm
 	return newOrderbookOrderFragmentIterator(iter), nil
 }
 
-// Prune iterates over all orders and deletes those that have expired.
+// Prune iterates over all order fragments and deletes those that have
+// expired while their order is open, or not stored. The order of an expired
+// order fragment is also deleted, because it can no longer be matched. Order
+// fragments of orders that have been confirmed, or canceled, are left for the
+// notifications of their status to delete.
 func (table *OrderbookOrderFragmentTable) Prune() (err error) {
 	iter := table.db.NewIterator(&util.Range{Start: table.key(OrderbookOrderFragmentIterBegin), Limit: table.key(OrderbookOrderFragmentIterEnd)}, nil)
 	defer iter.Release()
 
-	// now := time.Now()
+	now := time.Now()
 	for iter.Next() {
-		// key := iter.Key()
+		key := iter.Key()
 		value := OrderbookOrderFragmentValue{}
 		if localErr := json.Unmarshal(iter.Value(), &value); localErr != nil {
 			err = localErr
 			continue
 		}
-		// if value.Timestamp.Add(table.expiry).Before(now) {
-		// 	if localErr := table.db.Delete(key, nil); localErr != nil {
-		// 		err = localErr
-		// 	}
-		// }
+		if value.OrderFragment.IsExpired(now) {
+			orderKey := append(append(OrderbookOrderTableBegin, value.OrderFragment.OrderID[:]...), OrderbookOrderTablePadding...)
+			orderData, localErr := table.db.Get(orderKey, nil)
+			if localErr != nil && localErr != leveldb.ErrNotFound {
+				err = localErr
+				continue
+			}
+			if localErr == nil {
+				orderValue := OrderbookOrderValue{}
+				if localErr := json.Unmarshal(orderData, &orderValue); localErr != nil {
+					err = localErr
+					continue
+				}
+				if orderValue.Status != order.Open {
+					continue
+				}
+			}
+			if localErr := table.db.Delete(orderKey, nil); localErr != nil {
+				err = localErr
+			}
+			if localErr := table.db.Delete(key, nil); localErr != nil {
+				err = localErr
+			}
+		}
 	}
 	return err
 }
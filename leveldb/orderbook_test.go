@@ -61,6 +61,50 @@ var _ = Describe("Orderbook storage", func() {
 
 	})
 
+	Context("when pruning data", func() {
+		It("should delete expired order fragments and their orders", func() {
+			db := newDB(dbFile)
+			orderbookOrderTable := NewOrderbookOrderTable(db)
+			orderbookOrderFragmentTable := NewOrderbookOrderFragmentTable(db)
+			putAndExpectOrders(orderbookOrderTable)
+			putAndExpectOrderFragments(orderbookOrderFragmentTable)
+
+			ord := order.NewOrder(order.ParityBuy, order.TypeMidpoint, time.Now().Add(time.Hour), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			ordFragments, err := ord.Split(3, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orderbookOrderTable.PutOrder(ord.ID, orderStatus, "", 0)).ShouldNot(HaveOccurred())
+			Expect(orderbookOrderFragmentTable.PutOrderFragment(ordFragments[0])).ShouldNot(HaveOccurred())
+
+			Expect(orderbookOrderFragmentTable.Prune()).ShouldNot(HaveOccurred())
+			expectMissingOrders(orderbookOrderTable)
+			expectMissingOrderFragments(orderbookOrderFragmentTable)
+
+			_, _, _, err = orderbookOrderTable.Order(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			_, err = orderbookOrderFragmentTable.OrderFragment(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not delete expired order fragments of orders that are not open", func() {
+			db := newDB(dbFile)
+			orderbookOrderTable := NewOrderbookOrderTable(db)
+			orderbookOrderFragmentTable := NewOrderbookOrderFragmentTable(db)
+
+			ord := order.NewOrder(order.ParityBuy, order.TypeMidpoint, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			ordFragments, err := ord.Split(3, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orderbookOrderTable.PutOrder(ord.ID, order.Confirmed, "", 0)).ShouldNot(HaveOccurred())
+			Expect(orderbookOrderFragmentTable.PutOrderFragment(ordFragments[0])).ShouldNot(HaveOccurred())
+
+			Expect(orderbookOrderFragmentTable.Prune()).ShouldNot(HaveOccurred())
+			status, _, _, err := orderbookOrderTable.Order(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status).Should(Equal(order.Confirmed))
+			_, err = orderbookOrderFragmentTable.OrderFragment(ord.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+
 	Context("when iterating through out of range data", func() {
 		It("should trigger an out of range error", func() {
 			db := newDB(dbFile)
@@ -80,7 +80,8 @@ func NewSomerComputationTable(db *leveldb.DB) *SomerComputationTable {
 	return &SomerComputationTable{db: db}
 }
 
-// PutComputation implements the ome.ComputationStorer interface.
+// PutComputation implements the ome.ComputationStorer interface. The
+// Computation is indexed by the order IDs of its buy and sell.
 func (table *SomerComputationTable) PutComputation(computation ome.Computation) error {
 	value := SomerComputationValue{
 		Timestamp:   time.Now(),
@@ -90,12 +91,25 @@ func (table *SomerComputationTable) PutComputation(computation ome.Computation)
 	if err != nil {
 		return err
 	}
-	return table.db.Put(table.key(computation.ID[:]), data, nil)
+	batch := new(leveldb.Batch)
+	batch.Put(table.key(computation.ID[:]), data)
+	batch.Put(table.orderKey(computation.Buy.OrderID, computation.ID), []byte{})
+	batch.Put(table.orderKey(computation.Sell.OrderID, computation.ID), []byte{})
+	return table.db.Write(batch, nil)
 }
 
 // DeleteComputation implements the ome.ComputationStorer interface.
 func (table *SomerComputationTable) DeleteComputation(id ome.ComputationID) error {
-	return table.db.Delete(table.key(id[:]), nil)
+	computation, err := table.Computation(id)
+	if err != nil {
+		if err == ome.ErrComputationNotFound {
+			return nil
+		}
+		return err
+	}
+	batch := new(leveldb.Batch)
+	table.deleteComputation(batch, computation)
+	return table.db.Write(batch, nil)
 }
 
 // Computation implements the ome.ComputationStorer interface.
@@ -121,6 +135,28 @@ func (table *SomerComputationTable) Computations() (ome.ComputationIterator, err
 	return newSomerComputationIterator(iter), nil
 }
 
+// OrderComputations implements the ome.ComputationStorer interface using the
+// index of Computations by order ID.
+func (table *SomerComputationTable) OrderComputations(orderID order.ID) ([]ome.Computation, error) {
+	iter := table.db.NewIterator(&util.Range{Start: table.orderKeyRange(orderID, SomerOrderComputationIterBegin), Limit: table.orderKeyRange(orderID, SomerOrderComputationIterEnd)}, nil)
+	defer iter.Release()
+
+	computations := []ome.Computation{}
+	for iter.Next() {
+		id := ome.ComputationID{}
+		copy(id[:], iter.Key()[len(SomerOrderComputationTableBegin)+32:])
+		computation, err := table.Computation(id)
+		if err != nil {
+			if err == ome.ErrComputationNotFound {
+				continue
+			}
+			return computations, err
+		}
+		computations = append(computations, computation)
+	}
+	return computations, iter.Error()
+}
+
 // Prune iterates over all computations and deletes those that have expired.
 func (table *SomerComputationTable) Prune() (err error) {
 	iter := table.db.NewIterator(&util.Range{Start: table.key(SomerComputationIterBegin), Limit: table.key(SomerComputationIterEnd)}, nil)
@@ -128,14 +164,15 @@ func (table *SomerComputationTable) Prune() (err error) {
 
 	now := time.Now()
 	for iter.Next() {
-		key := iter.Key()
 		value := SomerComputationValue{}
 		if localErr := json.Unmarshal(iter.Value(), &value); localErr != nil {
 			err = localErr
 			continue
 		}
 		if value.Timestamp.Add(table.expiry).Before(now) {
-			if localErr := table.db.Delete(key, nil); localErr != nil {
+			batch := new(leveldb.Batch)
+			table.deleteComputation(batch, value.Computation)
+			if localErr := table.db.Write(batch, nil); localErr != nil {
 				err = localErr
 			}
 		}
@@ -143,10 +180,26 @@ func (table *SomerComputationTable) Prune() (err error) {
 	return err
 }
 
+// deleteComputation adds the deletion of a Computation, and its index
+// entries, to a batch.
+func (table *SomerComputationTable) deleteComputation(batch *leveldb.Batch, computation ome.Computation) {
+	batch.Delete(table.key(computation.ID[:]))
+	batch.Delete(table.orderKey(computation.Buy.OrderID, computation.ID))
+	batch.Delete(table.orderKey(computation.Sell.OrderID, computation.ID))
+}
+
 func (table *SomerComputationTable) key(k []byte) []byte {
 	return append(append(SomerComputationTableBegin, k...), SomerComputationTablePadding...)
 }
 
+func (table *SomerComputationTable) orderKey(orderID order.ID, id ome.ComputationID) []byte {
+	return table.orderKeyRange(orderID, id[:])
+}
+
+func (table *SomerComputationTable) orderKeyRange(orderID order.ID, k []byte) []byte {
+	return append(append(append([]byte{}, SomerOrderComputationTableBegin...), orderID[:]...), k...)
+}
+
 // SomerOrderFragmentValue is the storage format for computations being stored in
 // LevelDB. It contains additional timestamping information so that LevelDB can
 // provide pruning.
@@ -360,41 +413,43 @@ func (table *SomerOrderFragmentTable) UpdateSellOrderFragmentStatus(hash [32]byt
 	return table.db.Put(table.sellKey(hash[:], id[:]), data, nil)
 }
 
-// Prune iterates over all order fragments and deletes those that have expired.
+// Prune iterates over all order fragments and deletes those that have expired
+// while their order is open. Order fragments of orders that have been
+// confirmed are kept, because they are still waiting to be settled.
 func (table *SomerOrderFragmentTable) Prune() (err error) {
 	buyIter := table.db.NewIterator(&util.Range{Start: table.buyKey(SomerBuyOrderFragmentIterBegin, SomerBuyOrderFragmentIterBegin), Limit: table.buyKey(SomerBuyOrderFragmentIterEnd, SomerBuyOrderFragmentIterEnd)}, nil)
 	defer buyIter.Release()
 
-	// now := time.Now()
+	now := time.Now()
 	for buyIter.Next() {
-		// key := buyIter.Key()
+		key := buyIter.Key()
 		value := SomerOrderFragmentValue{}
 		if localErr := json.Unmarshal(buyIter.Value(), &value); localErr != nil {
 			err = localErr
 			continue
 		}
-		// if value.Timestamp.Add(table.expiry).Before(now) {
-		// 	if localErr := table.db.Delete(key, nil); localErr != nil {
-		// 		err = localErr
-		// 	}
-		// }
+		if value.Status == order.Open && value.OrderFragment.IsExpired(now) {
+			if localErr := table.db.Delete(key, nil); localErr != nil {
+				err = localErr
+			}
+		}
 	}
 
 	sellIter := table.db.NewIterator(&util.Range{Start: table.sellKey(SomerSellOrderFragmentIterBegin, SomerSellOrderFragmentIterBegin), Limit: table.sellKey(SomerSellOrderFragmentIterEnd, SomerSellOrderFragmentIterEnd)}, nil)
 	defer sellIter.Release()
 
 	for sellIter.Next() {
-		// key := sellIter.Key()
+		key := sellIter.Key()
 		value := SomerOrderFragmentValue{}
 		if localErr := json.Unmarshal(sellIter.Value(), &value); localErr != nil {
 			err = localErr
 			continue
 		}
-		// if value.Timestamp.Add(table.expiry).Before(now) {
-		// 	if localErr := table.db.Delete(key, nil); localErr != nil {
-		// 		err = localErr
-		// 	}
-		// }
+		if value.Status == order.Open && value.OrderFragment.IsExpired(now) {
+			if localErr := table.db.Delete(key, nil); localErr != nil {
+				err = localErr
+			}
+		}
 	}
 	return err
 }
@@ -108,6 +108,81 @@ var _ = Describe("Somer storage", func() {
 		})
 	})
 
+	Context("when loading the computations of an order", func() {
+		It("should return the computations for the buy and the sell", func() {
+			db := newDB(dbFile)
+			somerComputationTable := NewSomerComputationTable(db)
+			for i := 0; i < 10; i++ {
+				Expect(somerComputationTable.PutComputation(computations[i])).ShouldNot(HaveOccurred())
+			}
+
+			coms, err := somerComputationTable.OrderComputations(computations[3].Buy.OrderID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(coms).Should(HaveLen(1))
+			Expect(coms[0].Equal(&computations[3])).Should(BeTrue())
+			coms, err = somerComputationTable.OrderComputations(computations[3].Sell.OrderID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(coms).Should(HaveLen(1))
+
+			Expect(somerComputationTable.DeleteComputation(computations[3].ID)).ShouldNot(HaveOccurred())
+			coms, err = somerComputationTable.OrderComputations(computations[3].Buy.OrderID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(coms).Should(BeEmpty())
+		})
+	})
+
+	Context("when pruning order fragments", func() {
+		It("should delete order fragments that have expired", func() {
+			db := newDB(dbFile)
+			somerOrderFragmentTable := NewSomerOrderFragmentTable(db)
+
+			buyOrd := order.NewOrder(order.ParityBuy, order.TypeMidpoint, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			buyOrdFragments, err := buyOrd.Split(3, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellOrd := order.NewOrder(order.ParitySell, order.TypeMidpoint, time.Now().Add(time.Hour), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			sellOrdFragments, err := sellOrd.Split(3, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+			expiredSellOrd := order.NewOrder(order.ParitySell, order.TypeMidpoint, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 2, 2, 2, 2)
+			expiredSellOrdFragments, err := expiredSellOrd.Split(3, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(somerOrderFragmentTable.PutBuyOrderFragment(epoch.Hash, buyOrdFragments[0], "trader1", 1, order.Open)).ShouldNot(HaveOccurred())
+			Expect(somerOrderFragmentTable.PutSellOrderFragment(epoch.Hash, sellOrdFragments[0], "trader2", 1, order.Open)).ShouldNot(HaveOccurred())
+			Expect(somerOrderFragmentTable.PutSellOrderFragment(epoch.Hash, expiredSellOrdFragments[0], "trader2", 2, order.Open)).ShouldNot(HaveOccurred())
+
+			Expect(somerOrderFragmentTable.Prune()).ShouldNot(HaveOccurred())
+			_, _, _, _, err = somerOrderFragmentTable.BuyOrderFragment(epoch.Hash, buyOrd.ID)
+			Expect(err).Should(Equal(ome.ErrOrderFragmentNotFound))
+			_, _, _, _, err = somerOrderFragmentTable.SellOrderFragment(epoch.Hash, expiredSellOrd.ID)
+			Expect(err).Should(Equal(ome.ErrOrderFragmentNotFound))
+			_, _, _, _, err = somerOrderFragmentTable.SellOrderFragment(epoch.Hash, sellOrd.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should not delete expired order fragments of orders that have been confirmed", func() {
+			db := newDB(dbFile)
+			somerOrderFragmentTable := NewSomerOrderFragmentTable(db)
+
+			buyOrd := order.NewOrder(order.ParityBuy, order.TypeMidpoint, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			buyOrdFragments, err := buyOrd.Split(3, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellOrd := order.NewOrder(order.ParitySell, order.TypeMidpoint, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			sellOrdFragments, err := sellOrd.Split(3, 2)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(somerOrderFragmentTable.PutBuyOrderFragment(epoch.Hash, buyOrdFragments[0], "trader1", 1, order.Confirmed)).ShouldNot(HaveOccurred())
+			Expect(somerOrderFragmentTable.PutSellOrderFragment(epoch.Hash, sellOrdFragments[0], "trader2", 1, order.Confirmed)).ShouldNot(HaveOccurred())
+
+			Expect(somerOrderFragmentTable.Prune()).ShouldNot(HaveOccurred())
+			_, _, _, status, err := somerOrderFragmentTable.BuyOrderFragment(epoch.Hash, buyOrd.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status).Should(Equal(order.Confirmed))
+			_, _, _, status, err = somerOrderFragmentTable.SellOrderFragment(epoch.Hash, sellOrd.ID)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(status).Should(Equal(order.Confirmed))
+		})
+	})
+
 	Context("when updating order fragment status", func() {
 		It("should return updated status", func() {
 			db := newDB(dbFile)
@@ -73,18 +73,23 @@ func (plugin *FilePlugin) Log(l Log) error {
 		return fmt.Errorf("cannot write log to file plugin: nil file")
 	}
 	if plugin.file == os.Stdout || plugin.file == os.Stderr {
-		// format the tags to a string
-		tags := make([]string, 0)
-		for key, value := range l.Tags {
-			tags = append(tags, fmt.Sprintf("%s:%s,", key, value))
-		}
-		tag := ""
-		if len(tags) > 0 {
-			tag = "{" + strings.Join(tags, ",") + "} "
-		}
-
-		_, err := plugin.file.WriteString(fmt.Sprintf("%s [%s] (%s) %s%s\n", l.Timestamp.Format("2006/01/02 15:04:05"), l.Level, l.EventType, tag, l.Event.String()))
+		_, err := plugin.file.WriteString(fmt.Sprintf("%s [%s] %s\n", l.Timestamp.Format("2006/01/02 15:04:05"), l.Level, formatLog(l)))
 		return err
 	}
 	return json.NewEncoder(plugin.file).Encode(l)
 }
+
+// formatLog returns a human readable string containing the EventType, Tags
+// and Event of a Log.
+func formatLog(l Log) string {
+	// format the tags to a string
+	tags := make([]string, 0)
+	for key, value := range l.Tags {
+		tags = append(tags, fmt.Sprintf("%s:%s,", key, value))
+	}
+	tag := ""
+	if len(tags) > 0 {
+		tag = "{" + strings.Join(tags, ",") + "} "
+	}
+	return fmt.Sprintf("(%s) %s%s", l.EventType, tag, l.Event.String())
+}
@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultHTTPBatchSize is the default number of Logs that a HTTPPlugin sends
+// in one request.
+const DefaultHTTPBatchSize = 100
+
+// DefaultHTTPFlushInterval is the default interval, in seconds, at which a
+// HTTPPlugin sends Logs that have not filled a batch.
+const DefaultHTTPFlushInterval = 5
+
+// DefaultHTTPMaxBufferSize is the default maximum number of Logs that a
+// HTTPPlugin buffers while the collector is unavailable.
+const DefaultHTTPMaxBufferSize = 10000
+
+// DefaultHTTPTimeout is the default timeout, in seconds, of requests sent by
+// a HTTPPlugin.
+const DefaultHTTPTimeout = 10
+
+// A HTTPPlugin implements the Plugin interface by sending batches of Logs, as
+// a JSON array, to a collector using HTTP POST requests. Logs are buffered
+// until a batch is full, or until the flush interval has passed. If the
+// collector is unavailable, Logs remain buffered until the buffer is full, at
+// which point the oldest Logs are dropped.
+type HTTPPlugin struct {
+	url           string
+	batchSize     int
+	maxBufferSize int
+	flushInterval time.Duration
+	client        *http.Client
+
+	mu     *sync.Mutex
+	buffer []Log
+	flush  chan struct{}
+	done   chan struct{}
+	wg     *sync.WaitGroup
+}
+
+// HTTPPluginOptions are used to Unmarshal a HTTPPlugin from JSON. The
+// FlushInterval and Timeout are in seconds. Zero values are replaced by their
+// respective defaults.
+type HTTPPluginOptions struct {
+	URL           string `json:"url"`
+	BatchSize     int    `json:"batchSize"`
+	FlushInterval int64  `json:"flushInterval"`
+	MaxBufferSize int    `json:"maxBufferSize"`
+	Timeout       int64  `json:"timeout"`
+}
+
+// NewHTTPPlugin uses the HTTPPluginOptions to create a new HTTPPlugin.
+func NewHTTPPlugin(httpPluginOptions HTTPPluginOptions) Plugin {
+	if httpPluginOptions.BatchSize <= 0 {
+		httpPluginOptions.BatchSize = DefaultHTTPBatchSize
+	}
+	if httpPluginOptions.FlushInterval <= 0 {
+		httpPluginOptions.FlushInterval = DefaultHTTPFlushInterval
+	}
+	if httpPluginOptions.MaxBufferSize <= 0 {
+		httpPluginOptions.MaxBufferSize = DefaultHTTPMaxBufferSize
+	}
+	if httpPluginOptions.Timeout <= 0 {
+		httpPluginOptions.Timeout = DefaultHTTPTimeout
+	}
+	return &HTTPPlugin{
+		url:           httpPluginOptions.URL,
+		batchSize:     httpPluginOptions.BatchSize,
+		maxBufferSize: httpPluginOptions.MaxBufferSize,
+		flushInterval: time.Duration(httpPluginOptions.FlushInterval) * time.Second,
+		client: &http.Client{
+			Timeout: time.Duration(httpPluginOptions.Timeout) * time.Second,
+		},
+
+		mu:     new(sync.Mutex),
+		buffer: []Log{},
+		wg:     new(sync.WaitGroup),
+	}
+}
+
+// Start implements the Plugin interface. It starts sending buffered Logs in
+// the background until the plugin is stopped.
+func (plugin *HTTPPlugin) Start() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.done != nil {
+		return fmt.Errorf("cannot start http plugin: already started")
+	}
+	plugin.flush = make(chan struct{}, 1)
+	plugin.done = make(chan struct{})
+
+	plugin.wg.Add(1)
+	go func(flush <-chan struct{}, done <-chan struct{}) {
+		defer plugin.wg.Done()
+
+		ticker := time.NewTicker(plugin.flushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				plugin.send()
+				return
+			case <-ticker.C:
+			case <-flush:
+			}
+			plugin.send()
+		}
+	}(plugin.flush, plugin.done)
+	return nil
+}
+
+// Stop implements the Plugin interface. It sends all buffered Logs before
+// returning.
+func (plugin *HTTPPlugin) Stop() error {
+	plugin.mu.Lock()
+	if plugin.done == nil {
+		plugin.mu.Unlock()
+		return nil
+	}
+	close(plugin.done)
+	plugin.done = nil
+	plugin.mu.Unlock()
+
+	plugin.wg.Wait()
+	return nil
+}
+
+// Log implements the Plugin interface. The Log is buffered, and sent once a
+// batch is full or the flush interval has passed.
+func (plugin *HTTPPlugin) Log(l Log) error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.done == nil {
+		return fmt.Errorf("cannot write log to http plugin: not started")
+	}
+	plugin.buffer = append(plugin.buffer, l)
+	if len(plugin.buffer) > plugin.maxBufferSize {
+		plugin.buffer = plugin.buffer[len(plugin.buffer)-plugin.maxBufferSize:]
+	}
+	if len(plugin.buffer) >= plugin.batchSize {
+		select {
+		case plugin.flush <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// send buffered Logs to the collector in batches until the buffer is empty.
+// If a batch cannot be sent, it is returned to the buffer and sending stops
+// until the next flush.
+func (plugin *HTTPPlugin) send() {
+	for {
+		plugin.mu.Lock()
+		n := len(plugin.buffer)
+		if n == 0 {
+			plugin.mu.Unlock()
+			return
+		}
+		if n > plugin.batchSize {
+			n = plugin.batchSize
+		}
+		batch := make([]Log, n)
+		copy(batch, plugin.buffer)
+		plugin.buffer = plugin.buffer[n:]
+		plugin.mu.Unlock()
+
+		if err := plugin.post(batch); err != nil {
+			log.Printf("cannot send logs to %v: %v", plugin.url, err)
+
+			plugin.mu.Lock()
+			plugin.buffer = append(batch, plugin.buffer...)
+			if len(plugin.buffer) > plugin.maxBufferSize {
+				plugin.buffer = plugin.buffer[len(plugin.buffer)-plugin.maxBufferSize:]
+			}
+			plugin.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (plugin *HTTPPlugin) post(batch []Log) error {
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	res, err := plugin.client.Post(plugin.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %v", res.Status)
+	}
+	return nil
+}
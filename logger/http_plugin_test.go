@@ -0,0 +1,124 @@
+package logger_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/logger"
+
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("HTTP plugin", func() {
+
+	var collector *testutils.MockCollector
+
+	BeforeEach(func() {
+		collector = testutils.NewMockCollector()
+	})
+
+	AfterEach(func() {
+		collector.Close()
+	})
+
+	newLog := func(message string) Log {
+		return Log{
+			Timestamp: time.Now(),
+			Level:     LevelInfo,
+			EventType: TypeGeneric,
+			Event:     GenericEvent{Message: message},
+		}
+	}
+
+	Context("when logging to a collector", func() {
+
+		It("should send logs in batches", func() {
+			plugin := NewHTTPPlugin(HTTPPluginOptions{
+				URL:           collector.URL(),
+				BatchSize:     10,
+				FlushInterval: 60,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			defer plugin.Stop()
+
+			for i := 0; i < 30; i++ {
+				Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			}
+			Eventually(collector.Logs).Should(HaveLen(30))
+			Expect(collector.Batches()).Should(BeNumerically("<=", 3))
+			for _, l := range collector.Logs() {
+				Expect(l.EventType).Should(Equal(TypeGeneric))
+				Expect(l.Event.(GenericEvent).Message).Should(Equal("Some information"))
+			}
+		})
+
+		It("should send partial batches after the flush interval", func() {
+			plugin := NewHTTPPlugin(HTTPPluginOptions{
+				URL:           collector.URL(),
+				BatchSize:     10,
+				FlushInterval: 1,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			defer plugin.Stop()
+
+			Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			Consistently(collector.Logs, 500*time.Millisecond).Should(BeEmpty())
+			Eventually(collector.Logs, 2*time.Second).Should(HaveLen(1))
+		})
+
+		It("should send buffered logs when the plugin is stopped", func() {
+			plugin := NewHTTPPlugin(HTTPPluginOptions{
+				URL:           collector.URL(),
+				BatchSize:     10,
+				FlushInterval: 60,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			for i := 0; i < 5; i++ {
+				Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			}
+			Expect(plugin.Stop()).ShouldNot(HaveOccurred())
+			Expect(collector.Logs()).Should(HaveLen(5))
+		})
+	})
+
+	Context("when the collector is unavailable", func() {
+
+		It("should retry buffered logs", func() {
+			collector.Fail(1)
+			plugin := NewHTTPPlugin(HTTPPluginOptions{
+				URL:           collector.URL(),
+				BatchSize:     5,
+				FlushInterval: 1,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			defer plugin.Stop()
+
+			for i := 0; i < 5; i++ {
+				Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			}
+			Eventually(collector.Logs, 3*time.Second).Should(HaveLen(5))
+		})
+
+		It("should drop the oldest logs when the buffer is full", func() {
+			plugin := NewHTTPPlugin(HTTPPluginOptions{
+				URL:           collector.URL(),
+				BatchSize:     100,
+				FlushInterval: 60,
+				MaxBufferSize: 10,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			for i := 0; i < 20; i++ {
+				Expect(plugin.Log(newLog(fmt.Sprintf("%d", i)))).ShouldNot(HaveOccurred())
+			}
+			Expect(plugin.Stop()).ShouldNot(HaveOccurred())
+
+			logs := collector.Logs()
+			Expect(logs).Should(HaveLen(10))
+			for i, l := range logs {
+				Expect(l.Event.(GenericEvent).Message).Should(Equal(fmt.Sprintf("%d", i+10)))
+			}
+		})
+	})
+})
@@ -36,14 +36,36 @@ const (
 	TypeOrderReceived  = EventType("orderReceived")
 	TypeNetwork        = EventType("network")
 	TypeCompute        = EventType("compute")
+	TypeSettlement     = EventType("settlement")
+	TypeSync           = EventType("sync")
+	TypeSmpc           = EventType("smpc")
+	TypeSwarm          = EventType("swarm")
+	TypeContract       = EventType("contract")
+	TypeStream         = EventType("stream")
 )
 
-// Log an Event.
+// Package names used by Logs that are produced by a specific package. They can
+// be used to override the FilterLevel for the package.
+const (
+	PackageOme       = "ome"
+	PackageSmpc      = "smpc"
+	PackageOrderbook = "orderbook"
+	PackageSwarm     = "swarm"
+	PackageContract  = "contract"
+	PackageGrpc      = "grpc"
+)
+
+// Log an Event. If the Log is produced by a package that has a level in the
+// PackageLevels, it is used instead of the FilterLevel.
 func (logger *Logger) Log(l Log) {
 	if _, ok := logger.FilterEvents[l.EventType]; !ok && len(logger.FilterEvents) > 0 {
 		return
 	}
-	if l.Level <= logger.FilterLevel {
+	filterLevel := logger.FilterLevel
+	if level, ok := logger.PackageLevels[l.Package]; ok && l.Package != "" {
+		filterLevel = level
+	}
+	if l.Level <= filterLevel {
 		l.Tags = logger.Tags
 		for _, plugin := range logger.Plugins {
 			if err := plugin.Log(l); err != nil {
@@ -78,6 +100,17 @@ func SetFilterLevel(l Level) {
 	defaultLogger.FilterLevel = l
 }
 
+// SetPackageLevel changes the logging level of a package for the
+// defaultLogger, overriding the FilterLevel for Logs produced by the package.
+func SetPackageLevel(pkg string, l Level) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	if defaultLogger.PackageLevels == nil {
+		defaultLogger.PackageLevels = map[string]Level{}
+	}
+	defaultLogger.PackageLevels[pkg] = l
+}
+
 // SetFilterEvents sets the defaultLogger to filter specific EventTypes.
 func SetFilterEvents(events []EventType) {
 	defaultLoggerMu.Lock()
@@ -198,20 +231,66 @@ func Compute(ty Level, message string) {
 	defaultLogger.Compute(ty, message)
 }
 
+// Settlement logs a SettlementEvent using the DefaultLogger.
+func Settlement(ty Level, buyID, sellID, message string) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger.Settlement(ty, buyID, sellID, message)
+}
+
+// Sync logs a SyncEvent using the DefaultLogger.
+func Sync(ty Level, message string) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger.Sync(ty, message)
+}
+
+// Smpc logs a SmpcEvent using the DefaultLogger.
+func Smpc(ty Level, message string) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger.Smpc(ty, message)
+}
+
+// Swarm logs a SwarmEvent using the DefaultLogger.
+func Swarm(ty Level, message string) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger.Swarm(ty, message)
+}
+
+// Contract logs a ContractEvent using the DefaultLogger.
+func Contract(ty Level, message string) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger.Contract(ty, message)
+}
+
+// Stream logs a StreamEvent using the DefaultLogger.
+func Stream(ty Level, message string) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger.Stream(ty, message)
+}
+
 // Logger handles distributing logs to plugins registered with it
 type Logger struct {
-	Plugins      []Plugin
-	Tags         map[string]string
-	FilterLevel  Level
-	FilterEvents map[EventType]struct{}
+	Plugins       []Plugin
+	Tags          map[string]string
+	FilterLevel   Level
+	FilterEvents  map[EventType]struct{}
+	PackageLevels map[string]Level
 }
 
-// Options are used to Unmarshal a Logger from JSON.
+// Options are used to Unmarshal a Logger from JSON. The PackageLevels map
+// package names to the Level used instead of the FilterLevel for Logs
+// produced by the package.
 type Options struct {
-	Plugins      []PluginOptions   `json:"plugins"`
-	Tags         map[string]string `json:"tags"`
-	FilterLevel  Level             `json:"filterLevel"`
-	FilterEvents []EventType       `json:"filterEvents"`
+	Plugins       []PluginOptions   `json:"plugins"`
+	Tags          map[string]string `json:"tags"`
+	FilterLevel   Level             `json:"filterLevel"`
+	FilterEvents  []EventType       `json:"filterEvents"`
+	PackageLevels map[string]Level  `json:"packageLevels"`
 }
 
 // The Plugin interface describes a worker that consumes logs
@@ -223,7 +302,10 @@ type Plugin interface {
 
 // PluginOptions are used to Unmarshal plugins from JSON.
 type PluginOptions struct {
-	File *FilePluginOptions `json:"file,omitempty"`
+	File         *FilePluginOptions         `json:"file,omitempty"`
+	RotatingFile *RotatingFilePluginOptions `json:"rotatingFile,omitempty"`
+	Syslog       *SyslogPluginOptions       `json:"syslog,omitempty"`
+	HTTP         *HTTPPluginOptions         `json:"http,omitempty"`
 }
 
 func eventListToMap(events []EventType) map[EventType]struct{} {
@@ -243,11 +325,30 @@ func NewLogger(options Options) (*Logger, error) {
 		FilterLevel:  options.FilterLevel,
 		FilterEvents: eventListToMap(options.FilterEvents),
 	}
+	logger.PackageLevels = make(map[string]Level, len(options.PackageLevels))
+	for pkg, level := range options.PackageLevels {
+		logger.PackageLevels[pkg] = level
+	}
 	for i := range options.Plugins {
 		if options.Plugins[i].File != nil {
 			plugin := NewFilePlugin(*options.Plugins[i].File)
 			logger.Plugins = append(logger.Plugins, plugin)
 		}
+		if options.Plugins[i].RotatingFile != nil {
+			plugin := NewRotatingFilePlugin(*options.Plugins[i].RotatingFile)
+			logger.Plugins = append(logger.Plugins, plugin)
+		}
+		if options.Plugins[i].Syslog != nil {
+			plugin, err := NewSyslogPlugin(*options.Plugins[i].Syslog)
+			if err != nil {
+				return nil, err
+			}
+			logger.Plugins = append(logger.Plugins, plugin)
+		}
+		if options.Plugins[i].HTTP != nil {
+			plugin := NewHTTPPlugin(*options.Plugins[i].HTTP)
+			logger.Plugins = append(logger.Plugins, plugin)
+		}
 	}
 	return logger, nil
 }
@@ -438,12 +539,93 @@ func (logger *Logger) Compute(ty Level, message string) {
 		Timestamp: time.Now(),
 		Level:     ty,
 		EventType: TypeCompute,
+		Package:   PackageOme,
 		Event: ComputeEvent{
 			Message: message,
 		},
 	})
 }
 
+// Settlement logs a SettlementEvent.
+func (logger *Logger) Settlement(ty Level, buyID, sellID, message string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Level:     ty,
+		EventType: TypeSettlement,
+		Package:   PackageOme,
+		Event: SettlementEvent{
+			BuyID:   buyID,
+			SellID:  sellID,
+			Message: message,
+		},
+	})
+}
+
+// Sync logs a SyncEvent.
+func (logger *Logger) Sync(ty Level, message string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Level:     ty,
+		EventType: TypeSync,
+		Package:   PackageOrderbook,
+		Event: SyncEvent{
+			Message: message,
+		},
+	})
+}
+
+// Smpc logs a SmpcEvent.
+func (logger *Logger) Smpc(ty Level, message string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Level:     ty,
+		EventType: TypeSmpc,
+		Package:   PackageSmpc,
+		Event: SmpcEvent{
+			Message: message,
+		},
+	})
+}
+
+// Swarm logs a SwarmEvent.
+func (logger *Logger) Swarm(ty Level, message string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Level:     ty,
+		EventType: TypeSwarm,
+		Package:   PackageSwarm,
+		Event: SwarmEvent{
+			Message: message,
+		},
+	})
+}
+
+// Contract logs a ContractEvent.
+func (logger *Logger) Contract(ty Level, message string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Level:     ty,
+		EventType: TypeContract,
+		Package:   PackageContract,
+		Event: ContractEvent{
+			Message: message,
+		},
+	})
+}
+
+// Stream logs a StreamEvent.
+func (logger *Logger) Stream(ty Level, message string) {
+	logger.Log(Log{
+		Timestamp: time.Now(),
+		Level:     ty,
+		EventType: TypeStream,
+		Package:   PackageGrpc,
+		Event: StreamEvent{
+			Message: message,
+		},
+	})
+}
+
 func (level Level) String() string {
 	switch level {
 	case LevelError:
@@ -468,6 +650,7 @@ type Log struct {
 	Timestamp time.Time         `json:"timestamp"`
 	Level     Level             `json:"level"`
 	EventType EventType         `json:"eventType"`
+	Package   string            `json:"package,omitempty"`
 	Event     Event             `json:"event"`
 	Tags      map[string]string `json:"tags"`
 }
@@ -476,6 +659,7 @@ type rawLog struct {
 	Timestamp time.Time         `json:"timestamp"`
 	Level     Level             `json:"level"`
 	EventType EventType         `json:"eventType"`
+	Package   string            `json:"package,omitempty"`
 	Event     json.RawMessage   `json:"event"`
 	Tags      map[string]string `json:"tags"`
 }
@@ -535,11 +719,48 @@ func (log *Log) UnmarshalJSON(data []byte) error {
 			return err
 		}
 		log.Event = ev
+	case TypeSettlement:
+		ev := SettlementEvent{}
+		if err := json.Unmarshal(rawLog.Event, &ev); err != nil {
+			return err
+		}
+		log.Event = ev
+	case TypeSync:
+		ev := SyncEvent{}
+		if err := json.Unmarshal(rawLog.Event, &ev); err != nil {
+			return err
+		}
+		log.Event = ev
+	case TypeSmpc:
+		ev := SmpcEvent{}
+		if err := json.Unmarshal(rawLog.Event, &ev); err != nil {
+			return err
+		}
+		log.Event = ev
+	case TypeSwarm:
+		ev := SwarmEvent{}
+		if err := json.Unmarshal(rawLog.Event, &ev); err != nil {
+			return err
+		}
+		log.Event = ev
+	case TypeContract:
+		ev := ContractEvent{}
+		if err := json.Unmarshal(rawLog.Event, &ev); err != nil {
+			return err
+		}
+		log.Event = ev
+	case TypeStream:
+		ev := StreamEvent{}
+		if err := json.Unmarshal(rawLog.Event, &ev); err != nil {
+			return err
+		}
+		log.Event = ev
 	}
 
 	log.Timestamp = rawLog.Timestamp
 	log.Level = rawLog.Level
 	log.EventType = rawLog.EventType
+	log.Package = rawLog.Package
 	return nil
 }
 
@@ -631,3 +852,60 @@ type ComputeEvent struct {
 func (event ComputeEvent) String() string {
 	return event.Message
 }
+
+// SettlementEvent logs a state transition, or error, during the settlement of
+// two matched orders
+type SettlementEvent struct {
+	BuyID   string `json:"buyId"`
+	SellID  string `json:"sellId"`
+	Message string `json:"message"`
+}
+
+func (event SettlementEvent) String() string {
+	return fmt.Sprintf("buy = %s; sell = %s; %s", event.BuyID, event.SellID, event.Message)
+}
+
+// SyncEvent logs a message about synchronising the orderbook
+type SyncEvent struct {
+	Message string `json:"message"`
+}
+
+func (event SyncEvent) String() string {
+	return event.Message
+}
+
+// SmpcEvent logs a message about the secure multi-party computation network
+type SmpcEvent struct {
+	Message string `json:"message"`
+}
+
+func (event SmpcEvent) String() string {
+	return event.Message
+}
+
+// SwarmEvent logs a message about the swarm network
+type SwarmEvent struct {
+	Message string `json:"message"`
+}
+
+func (event SwarmEvent) String() string {
+	return event.Message
+}
+
+// ContractEvent logs a message about interactions with Ethereum contracts
+type ContractEvent struct {
+	Message string `json:"message"`
+}
+
+func (event ContractEvent) String() string {
+	return event.Message
+}
+
+// StreamEvent logs a message about gRPC streams
+type StreamEvent struct {
+	Message string `json:"message"`
+}
+
+func (event StreamEvent) String() string {
+	return event.Message
+}
@@ -497,6 +497,99 @@ var _ = Describe("Logger", func() {
 
 		})
 
+		Context("when we set event filter to whitelist settlement events", func() {
+			BeforeEach(func() {
+				SetFilterEvents([]EventType{TypeSettlement})
+				SetFilterLevel(6)
+			})
+
+			It("should show settlement messages", func() {
+				testSettlement(LevelInfo, true)
+			})
+
+			It("should filter non-settlement messages", func() {
+				testGenericError(false)
+				testCompute(LevelError, false)
+				testPackageEvent(Sync, LevelError, TypeSync, false)
+				testPackageEvent(Smpc, LevelError, TypeSmpc, false)
+				testPackageEvent(Swarm, LevelError, TypeSwarm, false)
+				testPackageEvent(Contract, LevelError, TypeContract, false)
+				testPackageEvent(Stream, LevelError, TypeStream, false)
+			})
+
+		})
+
+		Context("when the level of a package is set", func() {
+			BeforeEach(func() {
+				SetFilterLevel(LevelError)
+				SetPackageLevel(PackageSmpc, LevelDebug)
+				SetPackageLevel(PackageOrderbook, 0)
+			})
+
+			It("should show messages from the package below its level", func() {
+				testPackageEvent(Smpc, LevelDebug, TypeSmpc, true)
+			})
+
+			It("should filter messages from the package above its level", func() {
+				testPackageEvent(Smpc, LevelDebugLow, TypeSmpc, false)
+			})
+
+			It("should filter all messages from a package with level 0", func() {
+				testPackageEvent(Sync, LevelError, TypeSync, false)
+			})
+
+			It("should use the filter level for other packages", func() {
+				testPackageEvent(Swarm, LevelInfo, TypeSwarm, false)
+				testPackageEvent(Contract, LevelError, TypeContract, true)
+			})
+
+			It("should use the filter level for generic messages", func() {
+				testGenericInfo(false)
+			})
+
+		})
+
+	})
+
+	Context("when the package levels are configured using JSON", func() {
+
+		It("should override the filter level of each package", func() {
+			options := Options{}
+			Expect(json.Unmarshal([]byte(`{"filterLevel":1,"packageLevels":{"ome":3,"grpc":5}}`), &options)).ShouldNot(HaveOccurred())
+			logger, err := NewLogger(options)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(logger.FilterLevel).Should(Equal(LevelError))
+			Expect(logger.PackageLevels).Should(Equal(map[string]Level{
+				PackageOme:  LevelInfo,
+				PackageGrpc: LevelDebug,
+			}))
+		})
+
+	})
+
+	Context("when marshaling package events", func() {
+
+		It("should unmarshal the event and the package", func() {
+			l := Log{
+				Timestamp: time.Now(),
+				Level:     LevelWarn,
+				EventType: TypeSettlement,
+				Package:   PackageOme,
+				Event: SettlementEvent{
+					BuyID:   "buy",
+					SellID:  "sell",
+					Message: msg,
+				},
+			}
+			data, err := json.Marshal(l)
+			Expect(err).ShouldNot(HaveOccurred())
+			unmarshaled := Log{}
+			Expect(json.Unmarshal(data, &unmarshaled)).ShouldNot(HaveOccurred())
+			Expect(unmarshaled.Package).Should(Equal(PackageOme))
+			Expect(unmarshaled.Event).Should(Equal(l.Event))
+			Expect(unmarshaled.Event.String()).Should(Equal("buy = buy; sell = sell; " + msg))
+		})
+
 	})
 
 })
@@ -856,3 +949,46 @@ func testCompute(l Level, shouldLog bool) {
 	Expect(log.EventType).Should(Equal(TypeCompute))
 	Expect(log.Event.(ComputeEvent).Message).Should(Equal(msg))
 }
+
+func testSettlement(l Level, shouldLog bool) {
+	start := time.Now()
+	msg := "Some information"
+	Settlement(l, "buy", "sell", msg)
+	end := time.Now()
+
+	if !shouldLog {
+		checkNilLog()
+		return
+	}
+
+	log, err := readTmp()
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(start.Before(log.Timestamp)).Should(BeTrue())
+	Expect(log.Timestamp.Before(end)).Should(BeTrue())
+	Expect(log.Level).Should(Equal(l))
+	Expect(log.EventType).Should(Equal(TypeSettlement))
+	Expect(log.Package).Should(Equal(PackageOme))
+	Expect(log.Event.(SettlementEvent).BuyID).Should(Equal("buy"))
+	Expect(log.Event.(SettlementEvent).SellID).Should(Equal("sell"))
+	Expect(log.Event.(SettlementEvent).Message).Should(Equal(msg))
+}
+
+func testPackageEvent(logFn func(Level, string), l Level, ty EventType, shouldLog bool) {
+	start := time.Now()
+	msg := "Some information"
+	logFn(l, msg)
+	end := time.Now()
+
+	if !shouldLog {
+		checkNilLog()
+		return
+	}
+
+	log, err := readTmp()
+	Expect(err).ShouldNot(HaveOccurred())
+	Expect(start.Before(log.Timestamp)).Should(BeTrue())
+	Expect(log.Timestamp.Before(end)).Should(BeTrue())
+	Expect(log.Level).Should(Equal(l))
+	Expect(log.EventType).Should(Equal(ty))
+	Expect(log.Event.String()).Should(Equal(msg))
+}
@@ -0,0 +1,246 @@
+package logger
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedFileTimeFormat is used to name rotated files. It sorts in
+// chronological order so that the oldest rotated files can be removed.
+const rotatedFileTimeFormat = "20060102T150405.000000000"
+
+// A RotatingFilePlugin implements the Plugin interface by logging all events
+// to a File. The File is rotated when it grows larger than a maximum size, or
+// when it has been open for longer than a maximum age. Rotated files can be
+// compressed, and the oldest rotated files are removed so that logs do not
+// grow without bound.
+type RotatingFilePlugin struct {
+	mu *sync.Mutex
+
+	file     *os.File
+	fileSize int64
+	openedAt time.Time
+	filePath string
+
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	rotationsMu *sync.Mutex
+	rotations   *sync.WaitGroup
+}
+
+// RotatingFilePluginOptions are used to Unmarshal a RotatingFilePlugin from
+// JSON. The MaxSize is in bytes and the MaxAge is in seconds. A zero MaxSize,
+// or MaxAge, disables the respective rotation, and a zero MaxBackups keeps all
+// rotated files.
+type RotatingFilePluginOptions struct {
+	Path       string `json:"path"`
+	MaxSize    int64  `json:"maxSize"`
+	MaxAge     int64  `json:"maxAge"`
+	MaxBackups int    `json:"maxBackups"`
+	Compress   bool   `json:"compress"`
+}
+
+// NewRotatingFilePlugin uses the RotatingFilePluginOptions to create a new
+// RotatingFilePlugin.
+func NewRotatingFilePlugin(rotatingFilePluginOptions RotatingFilePluginOptions) Plugin {
+	return &RotatingFilePlugin{
+		mu: new(sync.Mutex),
+
+		file:     nil,
+		filePath: rotatingFilePluginOptions.Path,
+
+		maxSize:    rotatingFilePluginOptions.MaxSize,
+		maxAge:     time.Duration(rotatingFilePluginOptions.MaxAge) * time.Second,
+		maxBackups: rotatingFilePluginOptions.MaxBackups,
+		compress:   rotatingFilePluginOptions.Compress,
+
+		rotationsMu: new(sync.Mutex),
+		rotations:   new(sync.WaitGroup),
+	}
+}
+
+// Start implements the Plugin interface. It opens the log file which will be
+// opened as appendable and will be closed when the plugin is stopped.
+func (plugin *RotatingFilePlugin) Start() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	return plugin.open()
+}
+
+// Stop implements the Plugin interface. It closes the open log file and waits
+// for rotated files to be compressed.
+func (plugin *RotatingFilePlugin) Stop() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	defer plugin.rotations.Wait()
+	if plugin.file == nil {
+		return nil
+	}
+	err := plugin.file.Close()
+	plugin.file = nil
+	return err
+}
+
+// Log implements the Plugin interface. The log file is rotated before writing
+// the Log if the Log would grow the file larger than the maximum size, or if
+// the file is older than the maximum age.
+func (plugin *RotatingFilePlugin) Log(l Log) error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.file == nil {
+		return fmt.Errorf("cannot write log to rotating file plugin: nil file")
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if plugin.shouldRotate(int64(len(data))) {
+		if err := plugin.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := plugin.file.Write(data)
+	plugin.fileSize += int64(n)
+	return err
+}
+
+// shouldRotate returns true if the log file must be rotated before n bytes
+// can be written to it. Empty log files are never rotated. The mutex must be
+// locked by the caller.
+func (plugin *RotatingFilePlugin) shouldRotate(n int64) bool {
+	if plugin.fileSize == 0 {
+		return false
+	}
+	if plugin.maxSize > 0 && plugin.fileSize+n > plugin.maxSize {
+		return true
+	}
+	if plugin.maxAge > 0 && time.Since(plugin.openedAt) >= plugin.maxAge {
+		return true
+	}
+	return false
+}
+
+// open the log file as appendable. The mutex must be locked by the caller.
+func (plugin *RotatingFilePlugin) open() error {
+	file, err := os.OpenFile(plugin.filePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	plugin.file = file
+	plugin.fileSize = info.Size()
+	plugin.openedAt = time.Now()
+	return nil
+}
+
+// rotate closes the log file, renames it using the current time, and opens a
+// new log file. Compressing the rotated file, and removing the oldest rotated
+// files, happens in the background. The mutex must be locked by the caller.
+func (plugin *RotatingFilePlugin) rotate() error {
+	if err := plugin.file.Close(); err != nil {
+		return err
+	}
+	plugin.file = nil
+
+	rotatedPath := plugin.filePath + "." + time.Now().UTC().Format(rotatedFileTimeFormat)
+	if err := os.Rename(plugin.filePath, rotatedPath); err != nil {
+		return err
+	}
+	if err := plugin.open(); err != nil {
+		return err
+	}
+
+	plugin.rotations.Add(1)
+	go func() {
+		defer plugin.rotations.Done()
+
+		plugin.rotationsMu.Lock()
+		defer plugin.rotationsMu.Unlock()
+
+		if plugin.compress {
+			if err := compressFile(rotatedPath); err != nil {
+				log.Printf("cannot compress rotated log file %v: %v", rotatedPath, err)
+			}
+		}
+		if err := plugin.removeBackups(); err != nil {
+			log.Printf("cannot remove rotated log files: %v", err)
+		}
+	}()
+	return nil
+}
+
+// removeBackups removes the oldest rotated files until at most the maximum
+// number of rotated files remain.
+func (plugin *RotatingFilePlugin) removeBackups() error {
+	if plugin.maxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(plugin.filePath + ".*")
+	if err != nil {
+		return err
+	}
+	// Rotated files are named by the time of their rotation so sorting them
+	// will sort them from oldest to newest
+	sort.Slice(backups, func(i, j int) bool {
+		return strings.TrimSuffix(backups[i], ".gz") < strings.TrimSuffix(backups[j], ".gz")
+	})
+	for len(backups) > plugin.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// compressFile writes a gzip compressed copy of a file, and removes the
+// original file.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0640)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(dst)
+	if _, err := io.Copy(writer, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,158 @@
+package logger_test
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/logger"
+)
+
+var _ = Describe("Rotating file plugin", func() {
+
+	const rotatingFolder = "./tmp-rotating/"
+	const rotatingFile = rotatingFolder + "darknode.out"
+
+	BeforeEach(func() {
+		Expect(os.MkdirAll(rotatingFolder, os.ModePerm)).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rotatingFolder)).ShouldNot(HaveOccurred())
+	})
+
+	newLog := func(message string) Log {
+		return Log{
+			Timestamp: time.Now(),
+			Level:     LevelInfo,
+			EventType: TypeGeneric,
+			Event:     GenericEvent{Message: message},
+		}
+	}
+
+	backups := func() []string {
+		matches, err := filepath.Glob(rotatingFile + ".*")
+		Expect(err).ShouldNot(HaveOccurred())
+		return matches
+	}
+
+	Context("when the file grows larger than the maximum size", func() {
+
+		It("should rotate the file", func() {
+			plugin := NewRotatingFilePlugin(RotatingFilePluginOptions{
+				Path:    rotatingFile,
+				MaxSize: 512,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			for i := 0; i < 20; i++ {
+				Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			}
+			Expect(plugin.Stop()).ShouldNot(HaveOccurred())
+
+			Expect(len(backups())).Should(BeNumerically(">", 1))
+			for _, path := range append(backups(), rotatingFile) {
+				info, err := os.Stat(path)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(info.Size()).Should(BeNumerically("<=", 512))
+			}
+		})
+
+		It("should remove the oldest rotated files", func() {
+			plugin := NewRotatingFilePlugin(RotatingFilePluginOptions{
+				Path:       rotatingFile,
+				MaxSize:    512,
+				MaxBackups: 2,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			for i := 0; i < 50; i++ {
+				Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			}
+			Expect(plugin.Stop()).ShouldNot(HaveOccurred())
+
+			Expect(backups()).Should(HaveLen(2))
+		})
+
+		It("should compress rotated files", func() {
+			plugin := NewRotatingFilePlugin(RotatingFilePluginOptions{
+				Path:     rotatingFile,
+				MaxSize:  512,
+				Compress: true,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			for i := 0; i < 20; i++ {
+				Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			}
+			Expect(plugin.Stop()).ShouldNot(HaveOccurred())
+
+			Expect(backups()).ShouldNot(BeEmpty())
+			for _, path := range backups() {
+				Expect(filepath.Ext(path)).Should(Equal(".gz"))
+
+				file, err := os.Open(path)
+				Expect(err).ShouldNot(HaveOccurred())
+				reader, err := gzip.NewReader(file)
+				Expect(err).ShouldNot(HaveOccurred())
+				scanner := bufio.NewScanner(reader)
+				for scanner.Scan() {
+					l := Log{}
+					Expect(json.Unmarshal(scanner.Bytes(), &l)).ShouldNot(HaveOccurred())
+					Expect(l.Event.(GenericEvent).Message).Should(Equal("Some information"))
+				}
+				Expect(scanner.Err()).ShouldNot(HaveOccurred())
+				file.Close()
+			}
+		})
+	})
+
+	Context("when the file is older than the maximum age", func() {
+
+		It("should rotate the file", func() {
+			plugin := NewRotatingFilePlugin(RotatingFilePluginOptions{
+				Path:   rotatingFile,
+				MaxAge: 1,
+			})
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			Expect(backups()).Should(BeEmpty())
+
+			time.Sleep(time.Second)
+			Expect(plugin.Log(newLog("Some information"))).ShouldNot(HaveOccurred())
+			Expect(plugin.Stop()).ShouldNot(HaveOccurred())
+			Expect(backups()).Should(HaveLen(1))
+		})
+	})
+
+	Context("when the plugin is configured using JSON", func() {
+
+		It("should create a rotating file plugin", func() {
+			options := Options{}
+			Expect(json.Unmarshal([]byte(`{"plugins":[{"rotatingFile":{"path":"`+rotatingFile+`","maxSize":1048576,"maxAge":86400,"maxBackups":7,"compress":true}}],"filterLevel":3}`), &options)).ShouldNot(HaveOccurred())
+			Expect(*options.Plugins[0].RotatingFile).Should(Equal(RotatingFilePluginOptions{
+				Path:       rotatingFile,
+				MaxSize:    1048576,
+				MaxAge:     86400,
+				MaxBackups: 7,
+				Compress:   true,
+			}))
+
+			logger, err := NewLogger(options)
+			Expect(err).ShouldNot(HaveOccurred())
+			logger.Start()
+			logger.Info("Some information")
+			logger.Stop()
+
+			file, err := os.Open(rotatingFile)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer file.Close()
+			l := Log{}
+			Expect(json.NewDecoder(file).Decode(&l)).ShouldNot(HaveOccurred())
+			Expect(l.Event.(GenericEvent).Message).Should(Equal("Some information"))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+	"sync"
+)
+
+// A SyslogPlugin implements the Plugin interface by logging all events to a
+// syslog daemon. The syslog daemon can be local, or remote.
+type SyslogPlugin struct {
+	mu *sync.Mutex
+
+	writer   *syslog.Writer
+	network  string
+	address  string
+	tag      string
+	facility syslog.Priority
+}
+
+// SyslogPluginOptions are used to Unmarshal a SyslogPlugin from JSON. If the
+// Network is empty, the local syslog daemon will be used. The Facility is the
+// name of a syslog facility, such as "daemon" or "local0", and defaults to
+// "daemon".
+type SyslogPluginOptions struct {
+	Network  string `json:"network"`
+	Address  string `json:"address"`
+	Tag      string `json:"tag"`
+	Facility string `json:"facility"`
+}
+
+// NewSyslogPlugin uses the SyslogPluginOptions to create a new SyslogPlugin.
+// An error is returned if the Facility is not recognized.
+func NewSyslogPlugin(syslogPluginOptions SyslogPluginOptions) (Plugin, error) {
+	facility, err := syslogFacility(syslogPluginOptions.Facility)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogPlugin{
+		mu:       new(sync.Mutex),
+		writer:   nil,
+		network:  syslogPluginOptions.Network,
+		address:  syslogPluginOptions.Address,
+		tag:      syslogPluginOptions.Tag,
+		facility: facility,
+	}, nil
+}
+
+// Start implements the Plugin interface. It connects to the syslog daemon,
+// and the connection will be closed when the plugin is stopped.
+func (plugin *SyslogPlugin) Start() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	var err error
+	plugin.writer, err = syslog.Dial(plugin.network, plugin.address, plugin.facility|syslog.LOG_INFO, plugin.tag)
+	return err
+}
+
+// Stop implements the Plugin interface. It closes the connection to the
+// syslog daemon.
+func (plugin *SyslogPlugin) Stop() error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.writer == nil {
+		return nil
+	}
+	err := plugin.writer.Close()
+	plugin.writer = nil
+	return err
+}
+
+// Log implements the Plugin interface. The Level of the Log is used as the
+// syslog severity.
+func (plugin *SyslogPlugin) Log(l Log) error {
+	plugin.mu.Lock()
+	defer plugin.mu.Unlock()
+
+	if plugin.writer == nil {
+		return fmt.Errorf("cannot write log to syslog plugin: nil writer")
+	}
+	message := formatLog(l)
+	switch l.Level {
+	case LevelError:
+		return plugin.writer.Err(message)
+	case LevelWarn:
+		return plugin.writer.Warning(message)
+	case LevelInfo:
+		return plugin.writer.Info(message)
+	default:
+		return plugin.writer.Debug(message)
+	}
+}
+
+func syslogFacility(facility string) (syslog.Priority, error) {
+	switch strings.ToLower(facility) {
+	case "", "daemon":
+		return syslog.LOG_DAEMON, nil
+	case "user":
+		return syslog.LOG_USER, nil
+	case "local0":
+		return syslog.LOG_LOCAL0, nil
+	case "local1":
+		return syslog.LOG_LOCAL1, nil
+	case "local2":
+		return syslog.LOG_LOCAL2, nil
+	case "local3":
+		return syslog.LOG_LOCAL3, nil
+	case "local4":
+		return syslog.LOG_LOCAL4, nil
+	case "local5":
+		return syslog.LOG_LOCAL5, nil
+	case "local6":
+		return syslog.LOG_LOCAL6, nil
+	case "local7":
+		return syslog.LOG_LOCAL7, nil
+	default:
+		return 0, fmt.Errorf("cannot create syslog plugin: unrecognized facility %v", facility)
+	}
+}
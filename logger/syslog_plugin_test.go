@@ -0,0 +1,94 @@
+package logger_test
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/logger"
+)
+
+var _ = Describe("Syslog plugin", func() {
+
+	var conn net.PacketConn
+
+	BeforeEach(func() {
+		var err error
+		conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		conn.Close()
+	})
+
+	read := func() string {
+		buf := make([]byte, 4096)
+		Expect(conn.SetReadDeadline(time.Now().Add(5 * time.Second))).ShouldNot(HaveOccurred())
+		n, _, err := conn.ReadFrom(buf)
+		Expect(err).ShouldNot(HaveOccurred())
+		return string(buf[:n])
+	}
+
+	Context("when logging to a syslog daemon", func() {
+
+		It("should send messages with the severity of the log level", func() {
+			plugin, err := NewSyslogPlugin(SyslogPluginOptions{
+				Network:  "udp",
+				Address:  conn.LocalAddr().String(),
+				Tag:      "darknode",
+				Facility: "local0",
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(plugin.Start()).ShouldNot(HaveOccurred())
+			defer plugin.Stop()
+
+			// The priority is the facility multiplied by 8, plus the severity
+			levels := map[Level]string{
+				LevelError: "<131>",
+				LevelWarn:  "<132>",
+				LevelInfo:  "<134>",
+				LevelDebug: "<135>",
+			}
+			for level, priority := range levels {
+				Expect(plugin.Log(Log{
+					Timestamp: time.Now(),
+					Level:     level,
+					EventType: TypeNetwork,
+					Event:     NetworkEvent{Message: "Some information"},
+				})).ShouldNot(HaveOccurred())
+
+				message := read()
+				Expect(strings.HasPrefix(message, priority)).Should(BeTrue())
+				Expect(message).Should(ContainSubstring("darknode"))
+				Expect(message).Should(ContainSubstring("(network) Some information"))
+			}
+		})
+
+		It("should not log before the plugin is started", func() {
+			plugin, err := NewSyslogPlugin(SyslogPluginOptions{
+				Network: "udp",
+				Address: conn.LocalAddr().String(),
+			})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(plugin.Log(Log{Level: LevelInfo, Event: GenericEvent{}})).Should(HaveOccurred())
+		})
+	})
+
+	Context("when the facility is not recognized", func() {
+
+		It("should return an error", func() {
+			_, err := NewSyslogPlugin(SyslogPluginOptions{Facility: "kitchen"})
+			Expect(err).Should(HaveOccurred())
+
+			_, err = NewLogger(Options{
+				Plugins: []PluginOptions{
+					{Syslog: &SyslogPluginOptions{Facility: "kitchen"}},
+				},
+			})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -97,6 +97,12 @@ func NewComputation(epoch [32]byte, buy, sell order.Fragment, state ComputationS
 	return com
 }
 
+// IsExpired returns true if the order of either side of the Computation has
+// expired at the given time.
+func (com *Computation) IsExpired(now time.Time) bool {
+	return com.Buy.IsExpired(now) || com.Sell.IsExpired(now)
+}
+
 // Equal returns true when Computations are equal in value and state, and
 // returns false otherwise.
 func (com *Computation) Equal(arg *Computation) bool {
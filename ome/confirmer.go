@@ -103,6 +103,7 @@ func (confirmer *confirmer) Confirm(done <-chan struct{}, coms <-chan Computatio
 
 					// Confirm Computations on the blockchain and register them for
 					// observation (we need to wait for finality)
+					traceComputation(com, ResolveStageNil, "ome.confirm.begin", nil)
 					go func() {
 						if err := confirmer.beginConfirmation(com); err != nil {
 							// An error in confirmation should not stop the
@@ -231,6 +232,7 @@ func (confirmer *confirmer) checkOrdersForConfirmationFinality(orderParity order
 		case <-done:
 			return
 		case confirmations <- com:
+			traceComputation(com, ResolveStageNil, "ome.confirm", nil)
 			delete(confirmer.confirmingBuyOrders, com.Buy.OrderID)
 			delete(confirmer.confirmingSellOrders, com.Sell.OrderID)
 			confirmer.confirmed[com.Buy.OrderID] = time.Now()
@@ -1,25 +1,25 @@
 package ome
 
 import (
-	"log"
-	"sort"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/orderbook"
 	"github.com/republicprotocol/republic-go/registry"
 )
 
-type computationWeight struct {
-	computation Computation
-	weight      uint64
-}
-
 type ComputationGenerator interface {
 	Generate(done <-chan struct{}, notifications <-chan orderbook.Notification) (<-chan Computation, <-chan error)
 	OnChangeEpoch(epoch registry.Epoch)
+
+	// QueueDepths returns the QueueDepth of the current, and previous,
+	// epochs.
+	QueueDepths() []QueueDepth
 }
 
 type computationGenerator struct {
@@ -29,8 +29,10 @@ type computationGenerator struct {
 	addr identity.Address
 
 	matMu                *sync.Mutex
+	matCurr              *computationMatrix
 	matCurrDone          chan struct{}
 	matCurrNotifications chan orderbook.Notification
+	matPrev              *computationMatrix
 	matPrevDone          chan struct{}
 	matPrevNotifications chan orderbook.Notification
 
@@ -38,9 +40,20 @@ type computationGenerator struct {
 	broadcastErrs         chan (<-chan error)
 
 	fragmentStore OrderFragmentStorer
+	policy        Policy
+	queueOptions  QueueOptions
 }
 
-func NewComputationGenerator(addr identity.Address, orderFragmentStore OrderFragmentStorer) ComputationGenerator {
+// NewComputationGenerator returns a ComputationGenerator that orders the
+// Computations it generates using a Policy. Computations wait to be resolved
+// in bounded queues that are configured by the QueueOptions.
+func NewComputationGenerator(addr identity.Address, orderFragmentStore OrderFragmentStorer, policy Policy, queueOptions QueueOptions) ComputationGenerator {
+	if queueOptions.Capacity <= 0 {
+		queueOptions.Capacity = DefaultQueueCapacity
+	}
+	if queueOptions.RegenerationDepth >= queueOptions.Capacity {
+		queueOptions.RegenerationDepth = queueOptions.Capacity - 1
+	}
 	return &computationGenerator{
 		doneMu: new(sync.Mutex),
 		done:   nil,
@@ -48,8 +61,10 @@ func NewComputationGenerator(addr identity.Address, orderFragmentStore OrderFrag
 		addr: addr,
 
 		matMu:                new(sync.Mutex),
+		matCurr:              nil,
 		matCurrDone:          nil,
 		matCurrNotifications: nil,
+		matPrev:              nil,
 		matPrevDone:          nil,
 		matPrevNotifications: nil,
 
@@ -57,6 +72,8 @@ func NewComputationGenerator(addr identity.Address, orderFragmentStore OrderFrag
 		broadcastErrs:         make(chan (<-chan error)),
 
 		fragmentStore: orderFragmentStore,
+		policy:        policy,
+		queueOptions:  queueOptions,
 	}
 }
 
@@ -110,12 +127,14 @@ func (gen *computationGenerator) OnChangeEpoch(epoch registry.Epoch) {
 		close(gen.matPrevDone)
 		close(gen.matPrevNotifications)
 	}
+	gen.matPrev = gen.matCurr
 	gen.matPrevDone = gen.matCurrDone
 	gen.matPrevNotifications = gen.matCurrNotifications
 	gen.matCurrDone = make(chan struct{})
 	gen.matCurrNotifications = make(chan orderbook.Notification)
 
-	mat := newComputationMatrix(gen.addr, epoch, gen.fragmentStore)
+	mat := newComputationMatrix(gen.addr, epoch, gen.fragmentStore, gen.policy, gen.queueOptions)
+	gen.matCurr = mat
 	computations, errs := mat.generate(gen.matCurrDone, gen.matCurrNotifications)
 
 	go func() {
@@ -131,6 +150,21 @@ func (gen *computationGenerator) OnChangeEpoch(epoch registry.Epoch) {
 	}()
 }
 
+// QueueDepths implements the ComputationGenerator interface.
+func (gen *computationGenerator) QueueDepths() []QueueDepth {
+	gen.matMu.Lock()
+	defer gen.matMu.Unlock()
+
+	depths := []QueueDepth{}
+	if gen.matCurr != nil {
+		depths = append(depths, gen.matCurr.queueDepth())
+	}
+	if gen.matPrev != nil {
+		depths = append(depths, gen.matPrev.queueDepth())
+	}
+	return depths
+}
+
 func (gen *computationGenerator) routeNotification(notification orderbook.Notification, done <-chan struct{}) {
 	switch notification := notification.(type) {
 
@@ -189,20 +223,43 @@ type computationMatrix struct {
 	pod           *registry.Pod
 	epoch         registry.Epoch
 	fragmentStore OrderFragmentStorer
+	policy        Policy
+	queueOptions  QueueOptions
+
+	// The queue of Computations waiting to be resolved, and the state needed
+	// to generate evicted Computations again. Each Computation is owned by the
+	// order that was inserted last, and the truncated map stores the greatest
+	// key of the Computations that each order has had evicted.
+	queueMu     *sync.Mutex
+	queue       *computationQueue
+	queueSignal chan struct{}
+	orderSeqs   map[order.ID]uint64
+	nextSeq     uint64
+	truncated   map[order.ID]truncation
+	evicted     uint64
+	regenerated uint64
+}
 
-	sortedComputationsMu     *sync.Mutex
-	sortedComputations       []computationWeight
-	sortedComputationsSignal chan struct{}
+// truncation is the greatest queueKey of the Computations that an order has
+// had evicted from the queue.
+type truncation struct {
+	key    queueKey
+	parity order.Parity
 }
 
-func newComputationMatrix(addr identity.Address, epoch registry.Epoch, orderFragmentStore OrderFragmentStorer) *computationMatrix {
+func newComputationMatrix(addr identity.Address, epoch registry.Epoch, orderFragmentStore OrderFragmentStorer, policy Policy, queueOptions QueueOptions) *computationMatrix {
 	mat := &computationMatrix{
 		epoch:         epoch,
 		fragmentStore: orderFragmentStore,
-
-		sortedComputationsMu:     new(sync.Mutex),
-		sortedComputations:       []computationWeight{},
-		sortedComputationsSignal: make(chan struct{}),
+		policy:        policy,
+		queueOptions:  queueOptions,
+
+		queueMu:     new(sync.Mutex),
+		queue:       newComputationQueue(queueOptions.Capacity),
+		queueSignal: make(chan struct{}),
+		orderSeqs:   map[order.ID]uint64{},
+		nextSeq:     1,
+		truncated:   map[order.ID]truncation{},
 	}
 	pod, err := epoch.Pod(addr)
 	if err != nil {
@@ -240,28 +297,40 @@ func (mat *computationMatrix) generate(done <-chan struct{}, notifications <-cha
 					select {
 					case <-done:
 						return
-					case <-mat.sortedComputationsSignal:
-
+					case <-mat.queueSignal:
 						for {
-							mat.sortedComputationsMu.Lock()
-							if len(mat.sortedComputations) == 0 {
-								mat.sortedComputationsMu.Unlock()
+							mat.queueMu.Lock()
+							regenerating := false
+							if mat.queue.Len() <= mat.queueOptions.RegenerationDepth {
+								regenerating = mat.regenerateComputations()
+							}
+							item, ok := mat.queue.Pop()
+							mat.queueMu.Unlock()
+							if !ok {
+								// Unlock the queue between passes, so that
+								// notifications are not blocked while evicted
+								// Computations are generated again
+								if regenerating {
+									continue
+								}
 								break
 							}
 
 							select {
-							case <-mat.sortedComputationsSignal:
+							case <-mat.queueSignal:
 							default:
 							}
 
-							computationWeight := mat.sortedComputations[0]
-							mat.sortedComputations = mat.sortedComputations[1:]
-							mat.sortedComputationsMu.Unlock()
+							// Orders can expire while their computations are
+							// waiting to be resolved
+							if item.computation.IsExpired(time.Now()) {
+								continue
+							}
 
 							select {
 							case <-done:
-								break
-							case computations <- computationWeight.computation:
+								return
+							case computations <- item.computation:
 							}
 						}
 					}
@@ -284,6 +353,8 @@ func (mat *computationMatrix) handleNotification(notification orderbook.Notifica
 		mat.removeOrderFragment(notification.OrderID)
 	case orderbook.NotificationCancelOrder:
 		mat.removeOrderFragment(notification.OrderID)
+	case orderbook.NotificationExpireOrder:
+		mat.removeOrderFragment(notification.OrderID)
 	default:
 		select {
 		case <-done:
@@ -299,57 +370,104 @@ func (mat *computationMatrix) insertOrderFragment(notification orderbook.Notific
 		return
 	}
 
-	// Store the order.Fragment and get the opposing list so that computations
-	// can be generated
-	var oppositeOrderFragmentIter OrderFragmentIterator
-	var err error
+	// Expired order fragments are never stored, and never matched, so that
+	// joins are not wasted on them
+	if notification.OrderFragment.IsExpired(time.Now()) {
+		mat.removeOrderFragment(notification.OrderID)
+		return
+	}
+
+	// Order the order.Fragment after all order fragments that have already
+	// been inserted, before it can be found in storage
+	mat.queueMu.Lock()
+	if _, ok := mat.orderSeqs[notification.OrderID]; !ok {
+		mat.orderSeqs[notification.OrderID] = mat.nextSeq
+		mat.nextSeq++
+	}
+	mat.queueMu.Unlock()
 
+	// Store the order.Fragment so that computations can be generated
 	if notification.OrderFragment.OrderParity == order.ParityBuy {
 		if err := mat.fragmentStore.PutBuyOrderFragment(mat.epoch.Hash, notification.OrderFragment, notification.Trader, uint64(notification.Priority), order.Open); err != nil {
-			log.Printf("[error] (generator) cannot store buy order fragment = %v: %v", notification.OrderID, err)
-			return
-		}
-		oppositeOrderFragmentIter, err = mat.fragmentStore.SellOrderFragments(mat.epoch.Hash)
-		if err != nil {
-			log.Printf("[error] (generator) cannot load buy order fragment iterator: %v", err)
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot store buy order fragment = %v: %v", notification.OrderID, err))
 			return
 		}
-		defer oppositeOrderFragmentIter.Release()
 	} else {
 		if err := mat.fragmentStore.PutSellOrderFragment(mat.epoch.Hash, notification.OrderFragment, notification.Trader, uint64(notification.Priority), order.Open); err != nil {
-			log.Printf("[error] (generator) cannot store sell order fragment = %v: %v", notification.OrderID, err)
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot store sell order fragment = %v: %v", notification.OrderID, err))
 			return
 		}
+	}
+
+	mat.queueMu.Lock()
+	didGenerateNewComputation := mat.generateComputations(notification, nil)
+	mat.queueMu.Unlock()
+
+	if didGenerateNewComputation {
+		select {
+		case <-done:
+		case mat.queueSignal <- struct{}{}:
+		}
+	}
+}
+
+// generateComputations pushes the Computations owned by the order of a
+// notification into the queue. Computations are owned by the order that was
+// inserted last, so that each Computation is only generated once. If the
+// threshold is not nil, only Computations with a key that is not greater than
+// the threshold are generated. It returns true if a Computation was queued.
+// The queueMu must be locked by the caller.
+func (mat *computationMatrix) generateComputations(notification orderbook.NotificationOpenOrder, threshold *queueKey) bool {
+	// Get the opposing list so that computations can be generated
+	var oppositeOrderFragmentIter OrderFragmentIterator
+	var err error
+	if notification.OrderFragment.OrderParity == order.ParityBuy {
+		oppositeOrderFragmentIter, err = mat.fragmentStore.SellOrderFragments(mat.epoch.Hash)
+		if err != nil {
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot load buy order fragment iterator: %v", err))
+			return false
+		}
+	} else {
 		oppositeOrderFragmentIter, err = mat.fragmentStore.BuyOrderFragments(mat.epoch.Hash)
 		if err != nil {
-			log.Printf("[error] (generator) cannot load sell order fragment iterator: %v", err)
-			return
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot load sell order fragment iterator: %v", err))
+			return false
 		}
-		defer oppositeOrderFragmentIter.Release()
 	}
+	defer oppositeOrderFragmentIter.Release()
 
-	mat.sortedComputationsMu.Lock()
 	// Iterate through the opposing list and generate computations
+	now := time.Now()
+	seq := mat.orderSeqs[notification.OrderID]
 	didGenerateNewComputation := false
 	for oppositeOrderFragmentIter.Next() {
 		orderFragment, trader, priority, status, err := oppositeOrderFragmentIter.Cursor()
 		if err != nil {
-			log.Printf("[error] (generator) cannot load cursor: %v", err)
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot load cursor: %v", err))
 			continue
 		}
 
+		if oppositeSeq := mat.orderSeqs[orderFragment.OrderID]; oppositeSeq >= seq {
+			continue
+		}
 		if !isCompatible(notification, orderFragment, trader, priority) {
 			continue
 		}
 		if status != order.Open {
 			continue
 		}
+		if orderFragment.IsExpired(now) {
+			continue
+		}
 
 		var computation Computation
+		var comPriority ComputationPriority
 		if notification.OrderFragment.OrderParity == order.ParityBuy {
 			computation = NewComputation(mat.epoch.Hash, notification.OrderFragment, orderFragment, ComputationStateNil, false)
+			comPriority = ComputationPriority{Buy: uint64(notification.Priority), Sell: priority}
 		} else {
 			computation = NewComputation(mat.epoch.Hash, orderFragment, notification.OrderFragment, ComputationStateNil, false)
+			comPriority = ComputationPriority{Buy: priority, Sell: uint64(notification.Priority)}
 		}
 
 		// Get the priority adjustment based on the distance of our pod from
@@ -359,39 +477,113 @@ func (mat *computationMatrix) insertOrderFragment(notification orderbook.Notific
 		commonPath := buyPath.Ancestor(sellPath)
 		index, ok := commonPath.IndexOfPod(mat.pod)
 		if !ok {
-			log.Printf("[error] (generator) received orders with divergent paths")
+			logger.Compute(logger.LevelError, "received orders with divergent paths")
 			continue
 		}
-		adjustment := uint64(len(commonPath) - (index + 1))
-		comWeight := computationWeight{weight: uint64(notification.Priority) + priority + adjustment, computation: computation}
+		comPriority.Adjustment = uint64(len(commonPath) - (index + 1))
 
-		// Insert sort into the list of sorted computations
-		didGenerateNewComputation = true
-		if len(mat.sortedComputations) == 0 {
-			mat.sortedComputations = append(mat.sortedComputations, comWeight)
+		item := &queueItem{
+			key:         queueKey{weight: mat.policy.Weight(comPriority), id: computation.ID},
+			computation: computation,
+			owner:       notification.OrderID,
+			parity:      notification.OrderFragment.OrderParity,
+		}
+		if threshold != nil && threshold.less(item.key) {
+			continue
+		}
+		if mat.queue.Contains(computation.ID) {
 			continue
 		}
-		n := sort.Search(len(mat.sortedComputations), func(i int) bool {
-			return comWeight.weight >= mat.sortedComputations[i].weight
-		})
-		mat.sortedComputations = append(mat.sortedComputations[:n], append([]computationWeight{comWeight}, mat.sortedComputations[n:]...)...)
+		if threshold != nil {
+			mat.regenerated++
+		}
+
+		// Computations with the lowest weight are evicted when the queue is
+		// full, and are generated again when there is space
+		evicted := mat.queue.Push(item)
+		if evicted != item {
+			didGenerateNewComputation = true
+		}
+		if evicted != nil {
+			mat.evicted++
+			if truncated, ok := mat.truncated[evicted.owner]; !ok || truncated.key.less(evicted.key) {
+				mat.truncated[evicted.owner] = truncation{key: evicted.key, parity: evicted.parity}
+			}
+		}
 	}
-	mat.sortedComputationsMu.Unlock()
+	return didGenerateNewComputation
+}
 
-	if didGenerateNewComputation {
-		select {
-		case <-done:
-		case mat.sortedComputationsSignal <- struct{}{}:
+// regenerateComputations generates the Computations that have been evicted
+// from the queue again. Every Computation that an order has had evicted has a
+// lower key than the Computations of that order that remain in the queue, or
+// that have been resolved, so only the Computations that are not greater than
+// the greatest evicted key need to be generated. Each order requires a scan
+// of the stored order fragments, so at most MaxRegenerationsPerPass orders are
+// regenerated while the queueMu is locked. It returns true if there are
+// orders that still need to be regenerated. The queueMu must be locked by the
+// caller.
+func (mat *computationMatrix) regenerateComputations() bool {
+	for n := 0; n < MaxRegenerationsPerPass && len(mat.truncated) > 0 && mat.queue.Len() < mat.queueOptions.Capacity; n++ {
+		var orderID order.ID
+		var truncated truncation
+		for orderID, truncated = range mat.truncated {
+			break
+		}
+		delete(mat.truncated, orderID)
+
+		var orderFragment order.Fragment
+		var trader string
+		var priority uint64
+		var status order.Status
+		var err error
+		if truncated.parity == order.ParityBuy {
+			orderFragment, trader, priority, status, err = mat.fragmentStore.BuyOrderFragment(mat.epoch.Hash, orderID)
+		} else {
+			orderFragment, trader, priority, status, err = mat.fragmentStore.SellOrderFragment(mat.epoch.Hash, orderID)
 		}
+		if err != nil || status != order.Open || orderFragment.IsExpired(time.Now()) {
+			continue
+		}
+		notification := orderbook.NotificationOpenOrder{
+			OrderID:       orderID,
+			OrderFragment: orderFragment,
+			Trader:        trader,
+			Priority:      uint(priority),
+		}
+		mat.generateComputations(notification, &truncated.key)
+	}
+	return len(mat.truncated) > 0
+}
+
+func (mat *computationMatrix) queueDepth() QueueDepth {
+	mat.queueMu.Lock()
+	defer mat.queueMu.Unlock()
+
+	return QueueDepth{
+		Epoch:       mat.epoch.Hash,
+		Depth:       mat.queue.Len(),
+		Capacity:    mat.queueOptions.Capacity,
+		Evicted:     mat.evicted,
+		Regenerated: mat.regenerated,
 	}
 }
 
 func (mat *computationMatrix) removeOrderFragment(orderID order.ID) {
+	// The queue is locked until the order fragment has been deleted so that
+	// it cannot be used to generate computations again
+	mat.queueMu.Lock()
+	defer mat.queueMu.Unlock()
+
+	mat.queue.RemoveOrder(orderID)
+	delete(mat.orderSeqs, orderID)
+	delete(mat.truncated, orderID)
+
 	if err := mat.fragmentStore.DeleteBuyOrderFragment(mat.epoch.Hash, orderID); err != nil {
-		log.Printf("[error] (generator) cannot delete order fragment = %v; %v", orderID, err)
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot delete order fragment = %v; %v", orderID, err))
 	}
 	if err := mat.fragmentStore.DeleteSellOrderFragment(mat.epoch.Hash, orderID); err != nil {
-		log.Printf("[error] (generator) cannot delete order fragment = %v; %v", orderID, err)
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot delete order fragment = %v; %v", orderID, err))
 	}
 }
 
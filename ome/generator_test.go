@@ -1 +1,165 @@
 package ome_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Computation generator", func() {
+
+	var storer *leveldb.Store
+	var done chan struct{}
+	var notifications chan orderbook.Notification
+	var computations <-chan Computation
+	var generator ComputationGenerator
+	var queueOptions QueueOptions
+
+	BeforeEach(func() {
+		var err error
+		storer, err = leveldb.NewStore("./data.out", time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+		queueOptions = QueueOptions{}
+	})
+
+	JustBeforeEach(func() {
+		addr, epoch, err := testutils.RandomEpoch(0)
+		Expect(err).ShouldNot(HaveOccurred())
+		policy, err := NewPolicy(PolicyPrioritySum)
+		Expect(err).ShouldNot(HaveOccurred())
+		generator = NewComputationGenerator(addr, storer.SomerOrderFragmentStore(), policy, queueOptions)
+
+		done = make(chan struct{})
+		notifications = make(chan orderbook.Notification)
+		computations, _ = generator.Generate(done, notifications)
+		generator.OnChangeEpoch(epoch)
+	})
+
+	AfterEach(func() {
+		close(done)
+		storer.Release()
+		os.RemoveAll("./data.out")
+	})
+
+	Context("when orders expire", func() {
+
+		It("should not generate computations for expired orders", func() {
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			expiredSellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			expiredSellFragments[0].OrderExpiry = time.Now().Add(-time.Minute)
+
+			notifications <- orderbook.NotificationOpenOrder{OrderID: buyFragments[0].OrderID, OrderFragment: buyFragments[0], Trader: "buyer", Priority: 1}
+			notifications <- orderbook.NotificationOpenOrder{OrderID: expiredSellFragments[0].OrderID, OrderFragment: expiredSellFragments[0], Trader: "seller", Priority: 2}
+			notifications <- orderbook.NotificationOpenOrder{OrderID: sellFragments[0].OrderID, OrderFragment: sellFragments[0], Trader: "seller", Priority: 3}
+
+			var com Computation
+			Eventually(computations).Should(Receive(&com))
+			Expect(com.Buy.OrderID).Should(Equal(buyFragments[0].OrderID))
+			Expect(com.Sell.OrderID).Should(Equal(sellFragments[0].OrderID))
+			Consistently(computations).ShouldNot(Receive())
+
+			_, _, _, _, err = storer.SomerOrderFragmentStore().SellOrderFragment(com.Epoch, expiredSellFragments[0].OrderID)
+			Expect(err).Should(Equal(ErrOrderFragmentNotFound))
+		})
+
+		It("should remove orders when they are notified to have expired", func() {
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			notifications <- orderbook.NotificationOpenOrder{OrderID: buyFragments[0].OrderID, OrderFragment: buyFragments[0], Trader: "buyer", Priority: 1}
+			notifications <- orderbook.NotificationExpireOrder{OrderID: buyFragments[0].OrderID}
+			notifications <- orderbook.NotificationOpenOrder{OrderID: sellFragments[0].OrderID, OrderFragment: sellFragments[0], Trader: "seller", Priority: 2}
+
+			Consistently(computations).ShouldNot(Receive())
+		})
+	})
+
+	Context("when the queue of computations is full", func() {
+
+		BeforeEach(func() {
+			queueOptions = QueueOptions{Capacity: 4}
+		})
+
+		It("should evict the computations with the lowest weight and generate them again", func() {
+			numSellOrders := 64
+			for i := 1; i <= numSellOrders; i++ {
+				sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+				Expect(err).ShouldNot(HaveOccurred())
+				notifications <- orderbook.NotificationOpenOrder{OrderID: sellFragments[0].OrderID, OrderFragment: sellFragments[0], Trader: "seller", Priority: uint(i)}
+			}
+			buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			notifications <- orderbook.NotificationOpenOrder{OrderID: buyFragments[0].OrderID, OrderFragment: buyFragments[0], Trader: "buyer", Priority: 0}
+
+			// Computations are resolved in order of their weight, even when
+			// they have been evicted from the queue
+			seen := map[ComputationID]bool{}
+			prevPriority := uint64(0)
+			for i := 0; i < numSellOrders; i++ {
+				var com Computation
+				Eventually(computations).Should(Receive(&com))
+				Expect(seen[com.ID]).Should(BeFalse())
+				seen[com.ID] = true
+
+				_, _, priority, _, err := storer.SomerOrderFragmentStore().SellOrderFragment(com.Epoch, com.Sell.OrderID)
+				Expect(err).ShouldNot(HaveOccurred())
+				if i > 0 {
+					Expect(priority).Should(BeNumerically("<", prevPriority))
+				}
+				prevPriority = priority
+			}
+			Consistently(computations).ShouldNot(Receive())
+
+			depths := generator.QueueDepths()
+			Expect(depths).Should(HaveLen(1))
+			Expect(depths[0].Depth).Should(Equal(0))
+			Expect(depths[0].Capacity).Should(Equal(4))
+			Expect(depths[0].Evicted).Should(BeNumerically(">=", numSellOrders-4))
+			Expect(depths[0].Regenerated).Should(Equal(depths[0].Evicted))
+		})
+
+		It("should generate the computations evicted from many orders again", func() {
+			numOrders := 2 * MaxRegenerationsPerPass
+			for i := 1; i <= numOrders; i++ {
+				sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+				Expect(err).ShouldNot(HaveOccurred())
+				notifications <- orderbook.NotificationOpenOrder{OrderID: sellFragments[0].OrderID, OrderFragment: sellFragments[0], Trader: "seller", Priority: uint(i)}
+			}
+			for i := 1; i <= numOrders; i++ {
+				buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+				Expect(err).ShouldNot(HaveOccurred())
+				notifications <- orderbook.NotificationOpenOrder{OrderID: buyFragments[0].OrderID, OrderFragment: buyFragments[0], Trader: "buyer", Priority: uint(numOrders + i)}
+			}
+
+			// Every buy order owns more computations than can be queued, so
+			// regenerating them takes many passes
+			seen := map[ComputationID]bool{}
+			for i := 0; i < numOrders*numOrders; i++ {
+				var com Computation
+				Eventually(computations).Should(Receive(&com))
+				Expect(seen[com.ID]).Should(BeFalse())
+				seen[com.ID] = true
+			}
+			Consistently(computations).ShouldNot(Receive())
+
+			depths := generator.QueueDepths()
+			Expect(depths).Should(HaveLen(1))
+			Expect(depths[0].Depth).Should(Equal(0))
+			Expect(depths[0].Evicted).Should(BeNumerically(">", MaxRegenerationsPerPass))
+			Expect(depths[0].Regenerated).Should(Equal(depths[0].Evicted))
+		})
+	})
+})
@@ -1,20 +1,32 @@
 package ome
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"log"
+	"time"
 
+	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/smpc"
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 // ErrUnexpectedResolveStage is returned when a ResolveStage is not one of the
 // explicitly enumerated values.
 var ErrUnexpectedResolveStage = errors.New("unexpected resolve stage")
 
+// ExpiryMargin is the time before the expiry of either order at which a
+// Computation is no longer started, and the time after the expiry at which a
+// Computation that has been started is aborted. Darknodes check expiries
+// against their own clocks, so the margin makes sure that darknodes with
+// clocks that differ by less than the margin agree on which Computations are
+// resolved, instead of some darknodes aborting a Computation that the rest of
+// the pod is still resolving.
+const ExpiryMargin = time.Minute
+
 // ResolveStage defines the various stages that resolving can be in for any
 // given Computation.
 type ResolveStage byte
@@ -51,6 +63,8 @@ func (stage ResolveStage) String() string {
 		return "sellVolumeCo"
 	case ResolveStageTokens:
 		return "tokens"
+	case ResolveStageSettlement:
+		return "settlement"
 	}
 	return ""
 }
@@ -89,6 +103,8 @@ func NewMatcher(computationStore ComputationStorer, fragmentStore OrderFragmentS
 
 // Resolve implements the Matcher interface.
 func (matcher *matcher) Resolve(com Computation, callback MatchCallback) {
+	traceComputation(com, ResolveStageNil, "ome.resolve", trace.Attributes{"epoch": base64.StdEncoding.EncodeToString(com.Epoch[:])})
+
 	if com, err := matcher.computationStore.Computation(com.ID); err == nil {
 		// If computation exists in store and it is a match, trigger callback
 		if com.State == ComputationStateMatched {
@@ -101,67 +117,90 @@ func (matcher *matcher) Resolve(com Computation, callback MatchCallback) {
 			return
 		}
 	}
-	if com.Buy.OrderSettlement != com.Sell.OrderSettlement {
-		// Store the computation as a mismatch
-		com.State = ComputationStateMismatched
-		com.Match = false
-		if err := matcher.computationStore.PutComputation(com); err != nil {
-			logger.Compute(logger.LevelError, fmt.Sprintf("cannot store mismatched computation buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
-		}
-		// Trigger the callback with a mismatch
-		logger.Compute(logger.LevelDebug, fmt.Sprintf("✗ settlement => buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
-		callback(com)
+	reason := ""
+	switch {
+	case com.Buy.OrderSettlement != com.Sell.OrderSettlement:
+		reason = "orderSettlement"
+	case com.Buy.Version != com.Sell.Version:
+		// Fragments with different versions cannot be compared
+		reason = "fragmentVersion"
+	case com.IsExpired(time.Now().Add(ExpiryMargin)):
+		// Expired orders must not be settled, and orders that are about to
+		// expire are not started so that no darknode in the pod starts a
+		// computation that other darknodes consider to be expired
+		reason = "orderExpiry"
+	}
+	if reason != "" {
+		matcher.mismatch(com, callback, reason)
 		return
 	}
-	matcher.resolve(smpc.NetworkID(com.Epoch), com, callback, ResolveStagePriceExp)
+	matcher.resolve(smpc.NetworkID(com.Epoch), com, callback, resolveStageForVersion(com.Buy.Version, ResolveStagePriceExp))
 }
 
 func (matcher *matcher) resolve(networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage) {
 
-	join, joinCommitments, err := buildJoin(com, stage)
+	comparison, joinCommitments, err := buildComparison(com, stage)
 	if err != nil {
-		logger.Compute(logger.LevelError, fmt.Sprintf("cannot build %v join: %v", stage, err))
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot build %v comparison: %v", stage, err))
 		return
 	}
-	matcher.smpcer.InsertCommitments(networkID, join.ID, joinCommitments)
+	matcher.smpcer.InsertCommitments(networkID, comparison.ID, joinCommitments)
 
-	err = matcher.smpcer.Join(networkID, join, func(joinID smpc.JoinID, values []uint64) {
-		matcher.resolveValues(values, networkID, com, callback, stage)
+	traceComputation(com, stage, "ome.stage.begin", nil)
+	err = matcher.smpcer.Compare(traceContext(com, stage), networkID, comparison, func(joinID smpc.JoinID, results []bool) {
+		matcher.resolveResults(results, networkID, com, callback, stage)
 	}, stage == ResolveStageTokens /* delay messaging for the last check so that the dedicated confirmer has a head start */)
 	if err != nil {
-		logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: cannot join computation = %v: %v", stage, com.ID, err))
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: cannot compare computation = %v: %v", stage, com.ID, err))
 	}
 }
 
-func (matcher *matcher) resolveValues(values []uint64, networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage) {
-	if len(values) != 1 {
-		logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: unexpected number of values: %v", stage, len(values)))
+func (matcher *matcher) resolveResults(results []bool, networkID smpc.NetworkID, com Computation, callback MatchCallback, stage ResolveStage) {
+	if len(results) != len(stagePredicates(com.Buy.Version, stage)) {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot resolve %v: unexpected number of results: %v", stage, len(results)))
 		return
 	}
 	if matcher.orderConfirmed(com) {
 		logger.Compute(logger.LevelDebug, fmt.Sprintf("stop resolving buy=%v, sell=%v as at lease one of them gets confirmed", com.Buy.OrderID, com.Sell.OrderID))
+		traceComputation(com, stage, "ome.stage.end", trace.Attributes{"result": "confirmed"})
+		return
+	}
+	if com.IsExpired(time.Now().Add(-ExpiryMargin)) {
+		// Abort resolution once either order has clearly expired so that no
+		// more joins are spent on it
+		traceComputation(com, stage, "ome.stage.end", trace.Attributes{"result": "expired"})
+		matcher.mismatch(com, callback, "orderExpiry")
 		return
 	}
 
 	switch stage {
 	case ResolveStagePriceExp, ResolveStageBuyVolumeExp, ResolveStageSellVolumeExp:
-		if isGreaterThanZero(values[0]) {
+		if results[0] {
+			traceComputation(com, stage, "ome.stage.end", trace.Attributes{"result": "greater"})
 			matcher.resolve(networkID, com, callback, stage+2)
 			return
 		}
-		if isEqualToZero(values[0]) {
+		if results[1] {
+			traceComputation(com, stage, "ome.stage.end", trace.Attributes{"result": "equal"})
 			matcher.resolve(networkID, com, callback, stage+1)
 			return
 		}
 
 	case ResolveStagePriceCo, ResolveStageBuyVolumeCo, ResolveStageSellVolumeCo:
-		if isGreaterThanOrEqualToZero(values[0]) {
-			matcher.resolve(networkID, com, callback, stage+1)
+		if !inRange(results[1:]) {
+			// Values outside of the fixed point range cannot be compared
+			break
+		}
+		if !results[0] {
+			traceComputation(com, stage, "ome.stage.end", trace.Attributes{"result": "greaterOrEqual"})
+			matcher.resolve(networkID, com, callback, resolveStageForVersion(com.Buy.Version, stage+1))
 			return
 		}
 
 	case ResolveStageTokens:
-		if isEqualToZero(values[0]) {
+		if results[0] {
+			traceComputation(com, stage, "ome.stage.end", trace.Attributes{"result": "equal"})
+
 			// Store the computation as a match
 			com.State = ComputationStateMatched
 			com.Match = true
@@ -170,6 +209,7 @@ func (matcher *matcher) resolveValues(values []uint64, networkID smpc.NetworkID,
 			}
 
 			// Trigger the callback with a match
+			traceComputation(com, ResolveStageNil, "ome.match", nil)
 			callback(com)
 			return
 		}
@@ -186,7 +226,24 @@ func (matcher *matcher) resolveValues(values []uint64, networkID smpc.NetworkID,
 	}
 
 	// Trigger the callback with a mismatch
-	log.Printf("[debug] (%v) ✗ buy = %v, sell = %v", stage, com.Buy.OrderID, com.Sell.OrderID)
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("(%v) ✗ buy = %v, sell = %v", stage, com.Buy.OrderID, com.Sell.OrderID))
+	traceComputation(com, stage, "ome.stage.end", trace.Attributes{"result": "mismatch"})
+	traceComputation(com, ResolveStageNil, "ome.mismatch", trace.Attributes{"reason": stage.String()})
+	callback(com)
+}
+
+// mismatch stores a Computation that cannot be resolved as a mismatch, and
+// triggers the callback with it.
+func (matcher *matcher) mismatch(com Computation, callback MatchCallback, reason string) {
+	// Store the computation as a mismatch
+	com.State = ComputationStateMismatched
+	com.Match = false
+	if err := matcher.computationStore.PutComputation(com); err != nil {
+		logger.Compute(logger.LevelError, fmt.Sprintf("cannot store mismatched computation buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+	}
+	// Trigger the callback with a mismatch
+	logger.Compute(logger.LevelDebug, fmt.Sprintf("✗ %v => buy = %v, sell = %v", reason, com.Buy.OrderID, com.Sell.OrderID))
+	traceComputation(com, ResolveStageNil, "ome.mismatch", trace.Attributes{"reason": reason})
 	callback(com)
 }
 
@@ -204,13 +261,16 @@ func (matcher *matcher) orderConfirmed(com Computation) bool {
 	return false
 }
 
-func buildJoin(com Computation, stage ResolveStage) (smpc.Join, smpc.JoinCommitments, error) {
+// buildComparison returns the smpc.Comparison that resolves a ResolveStage.
+// Only the results of the stagePredicates are revealed, and not the
+// differences between the values of the orders.
+func buildComparison(com Computation, stage ResolveStage) (smpc.Comparison, smpc.JoinCommitments, error) {
 	joinCommitments := smpc.JoinCommitments{
 		LHS: map[uint64]shamir.Commitment{},
 		RHS: map[uint64]shamir.Commitment{},
 	}
 
-	var share shamir.Share
+	var share, lhs, rhs shamir.Share
 	switch stage {
 	case ResolveStagePriceExp:
 		share = com.Buy.Price.Exp.Sub(&com.Sell.Price.Exp)
@@ -218,7 +278,8 @@ func buildJoin(com Computation, stage ResolveStage) (smpc.Join, smpc.JoinCommitm
 		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].PriceExp
 
 	case ResolveStagePriceCo:
-		share = com.Buy.Price.Co.Sub(&com.Sell.Price.Co)
+		lhs, rhs = com.Buy.Price.Co, com.Sell.Price.Co
+		share = lhs.Sub(&rhs)
 		joinCommitments.LHS[share.Index] = com.Buy.Commitments[share.Index].PriceCo
 		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].PriceCo
 
@@ -228,7 +289,8 @@ func buildJoin(com Computation, stage ResolveStage) (smpc.Join, smpc.JoinCommitm
 		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].MinimumVolumeExp
 
 	case ResolveStageBuyVolumeCo:
-		share = com.Buy.Volume.Co.Sub(&com.Sell.MinimumVolume.Co)
+		lhs, rhs = com.Buy.Volume.Co, com.Sell.MinimumVolume.Co
+		share = lhs.Sub(&rhs)
 		joinCommitments.LHS[share.Index] = com.Buy.Commitments[share.Index].VolumeCo
 		joinCommitments.RHS[share.Index] = com.Sell.Commitments[share.Index].MinimumVolumeCo
 
@@ -238,7 +300,8 @@ func buildJoin(com Computation, stage ResolveStage) (smpc.Join, smpc.JoinCommitm
 		joinCommitments.RHS[share.Index] = com.Buy.Commitments[share.Index].MinimumVolumeExp
 
 	case ResolveStageSellVolumeCo:
-		share = com.Sell.Volume.Co.Sub(&com.Buy.MinimumVolume.Co)
+		lhs, rhs = com.Sell.Volume.Co, com.Buy.MinimumVolume.Co
+		share = lhs.Sub(&rhs)
 		joinCommitments.LHS[share.Index] = com.Sell.Commitments[share.Index].VolumeCo
 		joinCommitments.RHS[share.Index] = com.Buy.Commitments[share.Index].MinimumVolumeCo
 
@@ -247,7 +310,7 @@ func buildJoin(com Computation, stage ResolveStage) (smpc.Join, smpc.JoinCommitm
 		// FIXME: Tokens are not verified.
 
 	default:
-		return smpc.Join{}, smpc.JoinCommitments{}, ErrUnexpectedResolveStage
+		return smpc.Comparison{}, smpc.JoinCommitments{}, ErrUnexpectedResolveStage
 	}
 
 	// FIXME: Re-enable blindings
@@ -257,25 +320,88 @@ func buildJoin(com Computation, stage ResolveStage) (smpc.Join, smpc.JoinCommitm
 	// }
 	// blinding.Mod(blinding.Int, shamir.CommitP)
 
-	// Create the join
-	join := smpc.Join{
-		Index:     smpc.JoinIndex(share.Index),
-		Shares:    shamir.Shares{share},
-		Blindings: shamir.Blindings{},
+	// Create the comparison
+	predicates := stagePredicates(com.Buy.Version, stage)
+	comparison := smpc.Comparison{
+		Index:      smpc.JoinIndex(share.Index),
+		Shares:     make(shamir.Shares, len(predicates)),
+		Predicates: predicates,
+	}
+	for i := range comparison.Shares {
+		comparison.Shares[i] = share
+	}
+	if len(predicates) > 1 && com.Buy.Version == order.FragmentVersionFixedPoint {
+		// Fixed point values are not checked against the MaxFixedPointValue
+		// when they are shared by the trader, so the comparison also checks
+		// that both values are non-negative and not greater than the largest
+		// fixed point value
+		bound := shamir.Share{Index: share.Index, Value: order.MaxFixedPointValue - 1}
+		comparison.Shares[1] = lhs
+		comparison.Shares[2] = rhs
+		comparison.Shares[3] = bound.Sub(&lhs)
+		comparison.Shares[4] = bound.Sub(&rhs)
 	}
-	copy(join.ID[:], com.ID[:])
-	join.ID[32] = byte(stage)
-	return join, joinCommitments, nil
+	comparison.ID = joinID(com, stage)
+	return comparison, joinCommitments, nil
+}
+
+// joinID returns the smpc.JoinID used by a ResolveStage of a Computation. It
+// is bound to the polynomial commitments of both order.Fragments, so that
+// darknodes that were given different commitments by a trader never combine
+// their shares.
+func joinID(com Computation, stage ResolveStage) smpc.JoinID {
+	buyHash := com.Buy.VerificationHash()
+	sellHash := com.Sell.VerificationHash()
+	id := smpc.JoinID{}
+	copy(id[:], crypto.Keccak256(com.ID[:], buyHash[:], sellHash[:]))
+	id[32] = byte(stage)
+	return id
 }
 
-func isGreaterThanOrEqualToZero(value uint64) bool {
-	return value >= 0 && value < shamir.Prime/2
+// stagePredicates returns the smpc.Predicates that are compared in a
+// ResolveStage. The difference between the exponents is compared with zero in
+// both directions, but coefficients only need to be greater than, or equal
+// to, each other and tokens only need to be equal. Fixed point coefficients
+// are also range checked, because the difference between two values is only
+// signed correctly when both values are less than the MaxFixedPointValue.
+func stagePredicates(version order.FragmentVersion, stage ResolveStage) []smpc.Predicate {
+	switch stage {
+	case ResolveStagePriceExp, ResolveStageBuyVolumeExp, ResolveStageSellVolumeExp:
+		return []smpc.Predicate{smpc.PredicateGreaterThanZero, smpc.PredicateEqualToZero}
+	case ResolveStagePriceCo, ResolveStageBuyVolumeCo, ResolveStageSellVolumeCo:
+		if version == order.FragmentVersionFixedPoint {
+			return []smpc.Predicate{smpc.PredicateLessThanZero, smpc.PredicateLessThanZero, smpc.PredicateLessThanZero, smpc.PredicateLessThanZero, smpc.PredicateLessThanZero}
+		}
+		return []smpc.Predicate{smpc.PredicateLessThanZero}
+	case ResolveStageTokens:
+		return []smpc.Predicate{smpc.PredicateEqualToZero}
+	}
+	return []smpc.Predicate{}
 }
 
-func isGreaterThanZero(value uint64) bool {
-	return value > 0 && value < shamir.Prime/2
+// inRange returns true if none of the range checked values of a ResolveStage
+// are negative.
+func inRange(results []bool) bool {
+	for _, result := range results {
+		if result {
+			return false
+		}
+	}
+	return true
 }
 
-func isEqualToZero(value uint64) bool {
-	return value == 0 || value == shamir.Prime
+// resolveStageForVersion returns the ResolveStage, unless it is not needed to
+// resolve Computations with the order.FragmentVersion, in which case the next
+// ResolveStage is returned. Fixed point values are compared in one stage, so
+// the exponent stages are not needed.
+func resolveStageForVersion(version order.FragmentVersion, stage ResolveStage) ResolveStage {
+	if version != order.FragmentVersionFixedPoint {
+		return stage
+	}
+	switch stage {
+	case ResolveStagePriceExp, ResolveStageBuyVolumeExp, ResolveStageSellVolumeExp:
+		return stage + 1
+	default:
+		return stage
+	}
 }
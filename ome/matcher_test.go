@@ -1,6 +1,7 @@
 package ome_test
 
 import (
+	"encoding/base64"
 	"log"
 	"os"
 	"time"
@@ -11,7 +12,10 @@ import (
 
 	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
+	"github.com/republicprotocol/republic-go/smpc"
 	"github.com/republicprotocol/republic-go/testutils"
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 var _ = Describe("Matcher", func() {
@@ -98,4 +102,193 @@ var _ = Describe("Matcher", func() {
 			Expect(numMatches).Should(BeNumerically("<", numTrials))
 		})
 	})
+
+	Context("when resolving fixed point fragments near the maximum fixed point value", func() {
+
+		// fixedPointFragments returns the only fragment of a buy, and a sell,
+		// split into one share so that the share is the value itself. Each
+		// pair of orders uses new nonces, so that their computations are
+		// not already stored.
+		nonce := uint64(0)
+		fixedPointFragments := func(buyPrice, sellPrice uint64) (order.Fragment, order.Fragment) {
+			max := order.MaxFixedPointValue - 1
+			nonce++
+			buy := order.NewFixedPointOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(time.Hour), order.SettlementRenEx, order.TokensBTCETH, max, max, 1, nonce)
+			sell := order.NewFixedPointOrder(order.ParitySell, order.TypeLimit, time.Now().Add(time.Hour), order.SettlementRenEx, order.TokensBTCETH, max, max, 1, nonce)
+			buyFragments, err := buy.SplitWithVersion(1, 1, order.FragmentVersionFixedPoint)
+			Expect(err).ShouldNot(HaveOccurred())
+			sellFragments, err := sell.SplitWithVersion(1, 1, order.FragmentVersionFixedPoint)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Replace the prices in the same way as a trader that shares
+			// values without using SplitWithVersion
+			buyFragments[0].Price.Co.Value = buyPrice
+			sellFragments[0].Price.Co.Value = sellPrice
+			return buyFragments[0], sellFragments[0]
+		}
+
+		resolve := func(buyFragment, sellFragment order.Fragment) bool {
+			matcher := NewMatcher(compStore, fragmentStore, &plaintextSmpc{testutils.NewAlwaysMatchSmpc()})
+			com := NewComputation([32]byte{}, buyFragment, sellFragment, ComputationStateNil, true)
+			matched := false
+			matcher.Resolve(com, func(com Computation) {
+				matched = com.Match
+			})
+			return matched
+		}
+
+		It("should match values that are less than the maximum fixed point value", func() {
+			Expect(resolve(fixedPointFragments(order.MaxFixedPointValue-1, order.MaxFixedPointValue-2))).Should(BeTrue())
+			Expect(resolve(fixedPointFragments(order.MaxFixedPointValue-1, 0))).Should(BeTrue())
+			Expect(resolve(fixedPointFragments(order.MaxFixedPointValue-2, order.MaxFixedPointValue-1))).Should(BeFalse())
+		})
+
+		It("should mismatch values that are not less than the maximum fixed point value", func() {
+			Expect(resolve(fixedPointFragments(order.MaxFixedPointValue, order.MaxFixedPointValue-1))).Should(BeFalse())
+			Expect(resolve(fixedPointFragments(shamir.Prime/2, 1))).Should(BeFalse())
+
+			// The difference between these prices is positive, even though
+			// the sell price is greater than the buy price
+			Expect(resolve(fixedPointFragments(order.MaxFixedPointValue-1, shamir.Prime-1))).Should(BeFalse())
+		})
+	})
+
+	Context("when tracing computations", func() {
+
+		AfterEach(func() {
+			trace.SetDefaultTracer(trace.NewTracer("", nil))
+		})
+
+		It("should record an event at the beginning and end of each resolve stage", func() {
+			exporter := &mockExporter{}
+			trace.SetDefaultTracer(trace.NewTracer("node", exporter))
+
+			smpcer := testutils.NewAlwaysMatchSmpc()
+			matcher := NewMatcher(compStore, fragmentStore, smpcer)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matcher.Resolve(com, func(com Computation) {})
+
+			stages := []ResolveStage{ResolveStagePriceExp, ResolveStagePriceCo, ResolveStageBuyVolumeExp, ResolveStageBuyVolumeCo, ResolveStageSellVolumeExp, ResolveStageSellVolumeCo, ResolveStageTokens}
+			Expect(exporter.events).Should(HaveLen(2*len(stages) + 2))
+			Expect(exporter.events[0].Name).Should(Equal("ome.resolve"))
+			for i, stage := range stages {
+				begin, end := exporter.events[2*i+1], exporter.events[2*i+2]
+				Expect(begin.Name).Should(Equal("ome.stage.begin"))
+				Expect(end.Name).Should(Equal("ome.stage.end"))
+				Expect(begin.Attributes["stage"]).Should(Equal(stage.String()))
+				Expect(end.SpanID).Should(Equal(begin.SpanID))
+				Expect(end.SpanID[:32]).Should(Equal(com.ID[:]))
+				Expect(end.SpanID[32]).Should(Equal(byte(stage)))
+			}
+			Expect(exporter.events[len(exporter.events)-1].Name).Should(Equal("ome.match"))
+			for _, event := range exporter.events {
+				Expect(event.ID).Should(Equal(trace.ID(com.ID)))
+				Expect(event.Attributes["buy"]).Should(Equal(base64.StdEncoding.EncodeToString(com.Buy.OrderID[:])))
+				Expect(event.Attributes["sell"]).Should(Equal(base64.StdEncoding.EncodeToString(com.Sell.OrderID[:])))
+			}
+		})
+
+		It("should skip the exponent stages for fixed point fragments", func() {
+			exporter := &mockExporter{}
+			trace.SetDefaultTracer(trace.NewTracer("node", exporter))
+
+			buyFragment.Version = order.FragmentVersionFixedPoint
+			sellFragment.Version = order.FragmentVersionFixedPoint
+			smpcer := testutils.NewAlwaysMatchSmpc()
+			matcher := NewMatcher(compStore, fragmentStore, smpcer)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matched := false
+			matcher.Resolve(com, func(com Computation) {
+				matched = com.Match
+			})
+			Expect(matched).Should(BeTrue())
+
+			stages := []ResolveStage{ResolveStagePriceCo, ResolveStageBuyVolumeCo, ResolveStageSellVolumeCo, ResolveStageTokens}
+			Expect(exporter.events).Should(HaveLen(2*len(stages) + 2))
+			for i, stage := range stages {
+				Expect(exporter.events[2*i+1].Attributes["stage"]).Should(Equal(stage.String()))
+			}
+		})
+
+		It("should mismatch fragments with different versions", func() {
+			exporter := &mockExporter{}
+			trace.SetDefaultTracer(trace.NewTracer("node", exporter))
+
+			buyFragment.Version = order.FragmentVersionFixedPoint
+			smpcer := testutils.NewAlwaysMatchSmpc()
+			matcher := NewMatcher(compStore, fragmentStore, smpcer)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matched := true
+			matcher.Resolve(com, func(com Computation) {
+				matched = com.Match
+			})
+			Expect(matched).Should(BeFalse())
+
+			Expect(exporter.events).Should(HaveLen(2))
+			Expect(exporter.events[1].Name).Should(Equal("ome.mismatch"))
+			Expect(exporter.events[1].Attributes["reason"]).Should(Equal("fragmentVersion"))
+		})
+
+		It("should mismatch fragments that have expired", func() {
+			exporter := &mockExporter{}
+			trace.SetDefaultTracer(trace.NewTracer("node", exporter))
+
+			sellFragment.OrderExpiry = time.Now().Add(-time.Minute)
+			smpcer := testutils.NewAlwaysMatchSmpc()
+			matcher := NewMatcher(compStore, fragmentStore, smpcer)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matched := true
+			matcher.Resolve(com, func(com Computation) {
+				matched = com.Match
+			})
+			Expect(matched).Should(BeFalse())
+
+			Expect(exporter.events).Should(HaveLen(2))
+			Expect(exporter.events[1].Name).Should(Equal("ome.mismatch"))
+			Expect(exporter.events[1].Attributes["reason"]).Should(Equal("orderExpiry"))
+		})
+
+		It("should mismatch fragments that expire within the expiry margin", func() {
+			exporter := &mockExporter{}
+			trace.SetDefaultTracer(trace.NewTracer("node", exporter))
+
+			sellFragment.OrderExpiry = time.Now().Add(ExpiryMargin / 2)
+			smpcer := testutils.NewAlwaysMatchSmpc()
+			matcher := NewMatcher(compStore, fragmentStore, smpcer)
+			com := NewComputation([32]byte{byte(0)}, buyFragment, sellFragment, ComputationStateNil, true)
+			matched := true
+			matcher.Resolve(com, func(com Computation) {
+				matched = com.Match
+			})
+			Expect(matched).Should(BeFalse())
+
+			Expect(exporter.events).Should(HaveLen(2))
+			Expect(exporter.events[1].Attributes["reason"]).Should(Equal("orderExpiry"))
+		})
+	})
 })
+
+// plaintextSmpc is a mock implementation of the smpc.Smpcer interface that
+// compares the values of its shares, which are equal to the shared values
+// when they are shared with a threshold of one.
+type plaintextSmpc struct {
+	*testutils.Smpc
+}
+
+func (smpcer *plaintextSmpc) Compare(ctx trace.Context, networkID smpc.NetworkID, comparison smpc.Comparison, callback smpc.ComparisonCallback, useDelay bool) error {
+	results := make([]bool, len(comparison.Predicates))
+	for i, predicate := range comparison.Predicates {
+		results[i] = predicate.Evaluate(comparison.Shares[i].Value)
+	}
+	callback(comparison.ID, results)
+	return nil
+}
+
+type mockExporter struct {
+	events []trace.Event
+}
+
+func (exporter *mockExporter) Export(event trace.Event) error {
+	exporter.events = append(exporter.events, event)
+	return nil
+}
@@ -3,7 +3,6 @@ package ome
 import (
 	"bytes"
 	"fmt"
-	"log"
 	"sync"
 	"time"
 
@@ -32,6 +31,7 @@ type ome struct {
 	addr identity.Address
 
 	orderbook orderbook.Orderbook
+	publisher orderbook.Publisher
 	gen       ComputationGenerator
 	matcher   Matcher
 	confirmer Confirmer
@@ -46,10 +46,13 @@ type ome struct {
 // NewOme returns an Ome that uses an order.Orderbook to synchronize changes
 // from the Ethereum blockchain, and an smpc.Smpcer to run the secure
 // multi-party computations necessary for the secure order matching engine.
-func NewOme(addr identity.Address, gen ComputationGenerator, matcher Matcher, confirmer Confirmer, settler Settler, orderbook orderbook.Orderbook, smpcer smpc.Smpcer, epochPrev registry.Epoch) Ome {
+// Changes to orders are published as orderbook.Events to an
+// orderbook.Publisher.
+func NewOme(addr identity.Address, gen ComputationGenerator, matcher Matcher, confirmer Confirmer, settler Settler, orderbook orderbook.Orderbook, publisher orderbook.Publisher, smpcer smpc.Smpcer, epochPrev registry.Epoch) Ome {
 	ome := &ome{
 		addr:      addr,
 		orderbook: orderbook,
+		publisher: publisher,
 		gen:       gen,
 		matcher:   matcher,
 		confirmer: confirmer,
@@ -72,6 +75,7 @@ func (ome *ome) Run(done <-chan struct{}) <-chan error {
 
 	// Sync notifications from the orderbook
 	notifications, orderbookErrs := ome.orderbook.Sync(done)
+	notifications = ome.publishNotifications(done, notifications)
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -168,7 +172,8 @@ func (ome *ome) sendComputationToMatcher(done <-chan struct{}, computations <-ch
 					if !com.Match {
 						return
 					}
-					log.Printf("[debug] (resolve) ✔ buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID)
+					logger.Compute(logger.LevelDebug, fmt.Sprintf("resolve: ✔ buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
+					ome.publishComputation(com, orderbook.EventMatched)
 					select {
 					case <-done:
 					case matches <- com:
@@ -208,5 +213,45 @@ func (ome *ome) sendComputationToSettler(com Computation) {
 	logger.Compute(logger.LevelDebug, fmt.Sprintf("settling buy = %v, sell = %v", com.Buy.OrderID, com.Sell.OrderID))
 	if err := ome.settler.Settle(com); err != nil {
 		logger.Network(logger.LevelError, fmt.Sprintf("cannot settle: %v", err))
+		return
 	}
+	ome.publishComputation(com, orderbook.EventSettled)
+}
+
+// publishNotifications publishes an orderbook.Event for every
+// orderbook.Notification that it reads, and forwards the
+// orderbook.Notification to the returned channel.
+func (ome *ome) publishNotifications(done <-chan struct{}, notifications <-chan orderbook.Notification) <-chan orderbook.Notification {
+	forwarded := make(chan orderbook.Notification)
+
+	go func() {
+		defer close(forwarded)
+		for {
+			select {
+			case <-done:
+				return
+			case notification, ok := <-notifications:
+				if !ok {
+					return
+				}
+				if event, ok := orderbook.NewEventFromNotification(notification); ok {
+					ome.publisher.Publish(event)
+				}
+				select {
+				case <-done:
+					return
+				case forwarded <- notification:
+				}
+			}
+		}
+	}()
+
+	return forwarded
+}
+
+// publishComputation publishes an orderbook.Event for both of the orders in a
+// Computation.
+func (ome *ome) publishComputation(com Computation, ty orderbook.EventType) {
+	ome.publisher.Publish(orderbook.NewEvent(com.Buy.OrderID, ty))
+	ome.publisher.Publish(orderbook.NewEvent(com.Sell.OrderID, ty))
 }
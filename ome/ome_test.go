@@ -34,6 +34,7 @@ var _ = Describe("Ome", func() {
 		comStorer      ComputationStorer
 		fragmentStorer OrderFragmentStorer
 		book           orderbook.Orderbook
+		broadcaster    *orderbook.Broadcaster
 		smpcer         smpc.Smpcer
 		contract       ContractBinder
 
@@ -55,10 +56,15 @@ var _ = Describe("Ome", func() {
 			comStorer = store.SomerComputationStore()
 			fragmentStorer = store.SomerOrderFragmentStore()
 
-			computationsGenerator = NewComputationGenerator(addr, store.SomerOrderFragmentStore())
+			policy, err := NewPolicy(PolicyPrioritySum)
+			Expect(err).ShouldNot(HaveOccurred())
+			computationsGenerator = NewComputationGenerator(addr, store.SomerOrderFragmentStore(), policy, QueueOptions{})
 			rsaKey, err := crypto.RandomRsaKey()
 			Expect(err).ShouldNot(HaveOccurred())
 			book = testutils.NewRandOrderbook(rsaKey)
+			ecdsaKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			broadcaster = orderbook.NewBroadcaster(&ecdsaKey)
 			Expect(err).ShouldNot(HaveOccurred())
 			smpcer = testutils.NewAlwaysMatchSmpc()
 			contract = newOmeBinder()
@@ -76,7 +82,7 @@ var _ = Describe("Ome", func() {
 		It("should be able to sync with the order book ", func() {
 			done := make(chan struct{})
 
-			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, book, smpcer, epoch)
+			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, book, broadcaster, smpcer, epoch)
 			errs := ome.Run(done)
 			go func() {
 				defer GinkgoRecover()
@@ -91,7 +97,7 @@ var _ = Describe("Ome", func() {
 
 		It("should be able to listen for epoch change event", func() {
 			done := make(chan struct{})
-			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, book, smpcer, epoch)
+			ome := NewOme(addr, computationsGenerator, matcher, confirmer, settler, book, broadcaster, smpcer, epoch)
 			errs := ome.Run(done)
 
 			go func() {
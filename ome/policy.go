@@ -0,0 +1,135 @@
+package ome
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrUnexpectedPolicyType is returned when a PolicyType is not one of the
+// explicitly enumerated values.
+var ErrUnexpectedPolicyType = errors.New("unexpected policy type")
+
+// A PolicyType identifies a Policy in the configuration of a darknode.
+type PolicyType string
+
+// Values for a PolicyType.
+const (
+	PolicyPrioritySum  = PolicyType("prioritySum")
+	PolicyFIFO         = PolicyType("fifo")
+	PolicyTimePriority = PolicyType("timePriority")
+)
+
+// ComputationPriority is the public information that a Policy can use to
+// weight a Computation. Prices and volumes are secret shared, so they cannot
+// be used.
+type ComputationPriority struct {
+
+	// Buy and Sell are the priorities of the orders, assigned by the order
+	// in which they were opened on the Ethereum blockchain.
+	Buy  uint64
+	Sell uint64
+
+	// Adjustment is the distance of the Pod from the first Pod that could
+	// resolve the Computation.
+	Adjustment uint64
+}
+
+// A Policy orders the Computations generated by a ComputationGenerator.
+// Computations with a greater weight are resolved first. All darknodes in a
+// Pod must use the same Policy, otherwise they will resolve different
+// Computations at the same time and wait on each other. Darknodes publish
+// their PolicyType in their status so that a mismatch can be detected.
+type Policy interface {
+
+	// Type returns the PolicyType of the Policy.
+	Type() PolicyType
+
+	// Weight returns the weight of a Computation with the given
+	// ComputationPriority.
+	Weight(priority ComputationPriority) uint64
+}
+
+// NewPolicy returns the Policy for a PolicyType. It returns
+// ErrUnexpectedPolicyType if the PolicyType is unknown.
+func NewPolicy(ty PolicyType) (Policy, error) {
+	switch ty {
+	case PolicyPrioritySum:
+		return prioritySumPolicy{}, nil
+	case PolicyFIFO:
+		return fifoPolicy{}, nil
+	case PolicyTimePriority:
+		return timePriorityPolicy{}, nil
+	default:
+		return nil, ErrUnexpectedPolicyType
+	}
+}
+
+// adjustmentBits is the number of low bits of a weight that are reserved for
+// the Adjustment, when a Policy uses the Adjustment to break ties.
+const adjustmentBits = 16
+
+// withAdjustment returns a weight that orders Computations by a primary
+// weight, and then by their Adjustment, so that the Pods closest to the first
+// Pod that could resolve a Computation prefer it. The primary weight must be
+// less than 2^(64-adjustmentBits).
+func withAdjustment(weight, adjustment uint64) uint64 {
+	if adjustment >= 1<<adjustmentBits {
+		adjustment = 1<<adjustmentBits - 1
+	}
+	return weight<<adjustmentBits | adjustment
+}
+
+// inverse returns a primary weight that is greater for lesser priorities.
+func inverse(priority uint64) uint64 {
+	max := uint64(math.MaxUint64 >> adjustmentBits)
+	if priority > max {
+		return 0
+	}
+	return max - priority
+}
+
+// prioritySumPolicy weights a Computation by the sum of the priorities of its
+// orders, and the adjustment of the Pod. It is the default Policy.
+type prioritySumPolicy struct{}
+
+func (policy prioritySumPolicy) Type() PolicyType {
+	return PolicyPrioritySum
+}
+
+func (policy prioritySumPolicy) Weight(priority ComputationPriority) uint64 {
+	return priority.Buy + priority.Sell + priority.Adjustment
+}
+
+// fifoPolicy resolves Computations in the order that they became possible,
+// which is when the latest of its orders was opened. Ties are broken by the
+// Adjustment.
+type fifoPolicy struct{}
+
+func (policy fifoPolicy) Type() PolicyType {
+	return PolicyFIFO
+}
+
+func (policy fifoPolicy) Weight(priority ComputationPriority) uint64 {
+	latest := priority.Buy
+	if priority.Sell > latest {
+		latest = priority.Sell
+	}
+	return withAdjustment(inverse(latest), priority.Adjustment)
+}
+
+// timePriorityPolicy resolves Computations for the order that has been open
+// the longest first, so that orders resting in the orderbook are matched
+// before orders that arrive after them. Ties are broken by the Adjustment.
+type timePriorityPolicy struct{}
+
+func (policy timePriorityPolicy) Type() PolicyType {
+	return PolicyTimePriority
+}
+
+func (policy timePriorityPolicy) Weight(priority ComputationPriority) uint64 {
+	earliest := priority.Buy
+	if priority.Sell < earliest {
+		earliest = priority.Sell
+	}
+	return withAdjustment(inverse(earliest), priority.Adjustment)
+}
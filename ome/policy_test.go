@@ -0,0 +1,64 @@
+package ome_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+)
+
+var _ = Describe("Policies", func() {
+
+	newPolicy := func(ty PolicyType) Policy {
+		policy, err := NewPolicy(ty)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(policy.Type()).Should(Equal(ty))
+		return policy
+	}
+
+	Context("when weighting by the sum of priorities", func() {
+
+		It("should prefer computations with greater priorities", func() {
+			policy := newPolicy(PolicyPrioritySum)
+			Expect(policy.Weight(ComputationPriority{Buy: 1, Sell: 2, Adjustment: 3})).Should(Equal(uint64(6)))
+			Expect(policy.Weight(ComputationPriority{Buy: 4, Sell: 1})).Should(BeNumerically(">", policy.Weight(ComputationPriority{Buy: 1, Sell: 3})))
+		})
+	})
+
+	Context("when weighting computations first in, first out", func() {
+
+		It("should prefer computations that became possible first", func() {
+			policy := newPolicy(PolicyFIFO)
+			Expect(policy.Weight(ComputationPriority{Buy: 1, Sell: 5})).Should(BeNumerically(">", policy.Weight(ComputationPriority{Buy: 6, Sell: 2})))
+			Expect(policy.Weight(ComputationPriority{Buy: 5, Sell: 1})).Should(Equal(policy.Weight(ComputationPriority{Buy: 2, Sell: 5})))
+		})
+
+		It("should break ties using the adjustment", func() {
+			policy := newPolicy(PolicyFIFO)
+			Expect(policy.Weight(ComputationPriority{Buy: 5, Sell: 1, Adjustment: 2})).Should(BeNumerically(">", policy.Weight(ComputationPriority{Buy: 2, Sell: 5, Adjustment: 1})))
+			Expect(policy.Weight(ComputationPriority{Buy: 4, Sell: 1})).Should(BeNumerically(">", policy.Weight(ComputationPriority{Buy: 2, Sell: 5, Adjustment: 3})))
+		})
+	})
+
+	Context("when weighting computations by time priority", func() {
+
+		It("should prefer computations for the order that has been open the longest", func() {
+			policy := newPolicy(PolicyTimePriority)
+			Expect(policy.Weight(ComputationPriority{Buy: 6, Sell: 1})).Should(BeNumerically(">", policy.Weight(ComputationPriority{Buy: 2, Sell: 3})))
+			Expect(policy.Weight(ComputationPriority{Buy: 1, Sell: 5})).Should(Equal(policy.Weight(ComputationPriority{Buy: 9, Sell: 1})))
+		})
+
+		It("should break ties using the adjustment", func() {
+			policy := newPolicy(PolicyTimePriority)
+			Expect(policy.Weight(ComputationPriority{Buy: 1, Sell: 5, Adjustment: 1})).Should(BeNumerically(">", policy.Weight(ComputationPriority{Buy: 9, Sell: 1})))
+			Expect(policy.Weight(ComputationPriority{Buy: 2, Sell: 3})).Should(BeNumerically("<", policy.Weight(ComputationPriority{Buy: 1, Sell: 3, Adjustment: 0})))
+		})
+	})
+
+	Context("when creating unknown policies", func() {
+
+		It("should return an error", func() {
+			_, err := NewPolicy(PolicyType("proRata"))
+			Expect(err).Should(Equal(ErrUnexpectedPolicyType))
+		})
+	})
+})
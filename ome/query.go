@@ -0,0 +1,61 @@
+package ome
+
+import (
+	"context"
+
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+)
+
+type querier struct {
+	querier          orderbook.Querier
+	computationStore ComputationStorer
+}
+
+// NewQuerier returns an orderbook.Querier that extends the
+// orderbook.OrderState returned by another orderbook.Querier with the
+// Computations that have been stored for the order.Order.
+func NewQuerier(q orderbook.Querier, computationStore ComputationStorer) orderbook.Querier {
+	return &querier{
+		querier:          q,
+		computationStore: computationStore,
+	}
+}
+
+// Query implements the orderbook.Querier interface.
+func (querier *querier) Query(ctx context.Context, query orderbook.Query) (orderbook.OrderState, error) {
+	state, err := querier.querier.Query(ctx, query)
+	if err != nil {
+		return state, err
+	}
+	if state.Status == order.Nil {
+		// The order has not been opened, so the query has not been
+		// authenticated and the order cannot have been matched
+		return state, nil
+	}
+
+	coms, err := querier.computationStore.OrderComputations(query.OrderID)
+	if err != nil {
+		return state, err
+	}
+	for _, com := range coms {
+		state.EpochDepths = insertEpochDepth(state.EpochDepths, com.EpochDepth)
+		if !com.Match {
+			continue
+		}
+		state.Matched = true
+		if com.State == ComputationStateSettled {
+			state.Settled = true
+		}
+	}
+	return state, nil
+}
+
+func insertEpochDepth(depths []order.FragmentEpochDepth, depth order.FragmentEpochDepth) []order.FragmentEpochDepth {
+	for _, d := range depths {
+		if d == depth {
+			return depths
+		}
+	}
+	return append(depths, depth)
+}
@@ -0,0 +1,84 @@
+package ome_test
+
+import (
+	"context"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/ome"
+
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/orderbook"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Querier", func() {
+
+	var store *leveldb.Store
+	var buyFragment, sellFragment order.Fragment
+
+	BeforeEach(func() {
+		var err error
+		store, err = leveldb.NewStore("./data.out", time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		buyFragments, err := testutils.RandomBuyOrderFragments(6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		buyFragment = buyFragments[0]
+		sellFragments, err := testutils.RandomSellOrderFragments(6, 4)
+		Expect(err).ShouldNot(HaveOccurred())
+		sellFragment = sellFragments[0]
+	})
+
+	AfterEach(func() {
+		store.Release()
+		os.RemoveAll("./data.out")
+	})
+
+	It("should extend the order state with matches and settlements", func() {
+		com := NewComputation([32]byte{}, buyFragment, sellFragment, ComputationStateSettled, true)
+		Expect(store.SomerComputationStore().PutComputation(com)).ShouldNot(HaveOccurred())
+
+		querier := NewQuerier(&mockQuerier{status: order.Confirmed}, store.SomerComputationStore())
+		state, err := querier.Query(context.Background(), orderbook.NewQuery(sellFragment.OrderID))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(state.Matched).Should(BeTrue())
+		Expect(state.Settled).Should(BeTrue())
+		Expect(state.EpochDepths).Should(Equal([]order.FragmentEpochDepth{buyFragment.EpochDepth}))
+	})
+
+	It("should not extend the order state with mismatches", func() {
+		com := NewComputation([32]byte{}, buyFragment, sellFragment, ComputationStateMismatched, false)
+		Expect(store.SomerComputationStore().PutComputation(com)).ShouldNot(HaveOccurred())
+
+		querier := NewQuerier(&mockQuerier{status: order.Open}, store.SomerComputationStore())
+		state, err := querier.Query(context.Background(), orderbook.NewQuery(buyFragment.OrderID))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(state.Matched).Should(BeFalse())
+		Expect(state.Settled).Should(BeFalse())
+	})
+
+	It("should not extend the order state of orders that are not open", func() {
+		com := NewComputation([32]byte{}, buyFragment, sellFragment, ComputationStateSettled, true)
+		Expect(store.SomerComputationStore().PutComputation(com)).ShouldNot(HaveOccurred())
+
+		querier := NewQuerier(&mockQuerier{status: order.Nil}, store.SomerComputationStore())
+		state, err := querier.Query(context.Background(), orderbook.NewQuery(buyFragment.OrderID))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(state.Matched).Should(BeFalse())
+		Expect(state.Settled).Should(BeFalse())
+	})
+})
+
+// mockQuerier returns an orderbook.OrderState with a fixed order.Status for
+// all queries.
+type mockQuerier struct {
+	status order.Status
+}
+
+func (querier *mockQuerier) Query(ctx context.Context, query orderbook.Query) (orderbook.OrderState, error) {
+	return orderbook.OrderState{OrderID: query.OrderID, Status: querier.status}, nil
+}
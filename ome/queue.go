@@ -0,0 +1,202 @@
+package ome
+
+import (
+	"bytes"
+	"container/heap"
+
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// DefaultQueueCapacity is the number of Computations that are queued for an
+// epoch when no capacity is configured.
+const DefaultQueueCapacity = 4096
+
+// MaxRegenerationsPerPass is the maximum number of orders for which evicted
+// Computations are generated again each time the queue is locked.
+const MaxRegenerationsPerPass = 4
+
+// QueueOptions configure the bounded queues of Computations that are waiting
+// to be resolved. Each epoch has its own queue.
+type QueueOptions struct {
+
+	// Capacity is the maximum number of Computations that are queued for an
+	// epoch. When a queue is full, the Computations with the lowest weight are
+	// evicted. A zero Capacity uses the DefaultQueueCapacity.
+	Capacity int `json:"capacity"`
+
+	// RegenerationDepth is the depth at which evicted Computations are
+	// generated again. It must be less than the Capacity.
+	RegenerationDepth int `json:"regenerationDepth"`
+}
+
+// QueueDepth reports the state of the queue of Computations for an epoch.
+type QueueDepth struct {
+	Epoch    [32]byte
+	Depth    int
+	Capacity int
+
+	// Evicted is the number of Computations that have been evicted, or
+	// dropped, because the queue was full. Regenerated is the number of
+	// Computations that have been generated again after being evicted.
+	Evicted     uint64
+	Regenerated uint64
+}
+
+// queueKey totally orders the Computations in a computationQueue. The
+// ComputationID breaks ties between weights so that all darknodes in a Pod
+// resolve Computations in the same order.
+type queueKey struct {
+	weight uint64
+	id     ComputationID
+}
+
+func (key queueKey) less(other queueKey) bool {
+	if key.weight != other.weight {
+		return key.weight < other.weight
+	}
+	return bytes.Compare(key.id[:], other.id[:]) < 0
+}
+
+// queueItem is a Computation in a computationQueue. The owner is the order
+// that generated the Computation when it was inserted into the
+// computationMatrix.
+type queueItem struct {
+	key         queueKey
+	computation Computation
+	owner       order.ID
+	parity      order.Parity
+
+	maxIndex int
+	minIndex int
+}
+
+// computationQueue is a bounded double ended priority queue of Computations.
+// Computations with the greatest key are popped first, and Computations with
+// the lowest key are evicted first. It is not safe for concurrent use.
+type computationQueue struct {
+	capacity int
+	max      maxQueueHeap
+	min      minQueueHeap
+	items    map[ComputationID]*queueItem
+}
+
+func newComputationQueue(capacity int) *computationQueue {
+	return &computationQueue{
+		capacity: capacity,
+		max:      maxQueueHeap{},
+		min:      minQueueHeap{},
+		items:    map[ComputationID]*queueItem{},
+	}
+}
+
+// Len returns the number of Computations in the queue.
+func (queue *computationQueue) Len() int {
+	return len(queue.items)
+}
+
+// Contains returns true if a Computation is in the queue.
+func (queue *computationQueue) Contains(id ComputationID) bool {
+	_, ok := queue.items[id]
+	return ok
+}
+
+// Push an item into the queue. If the queue is full, the item with the lowest
+// key is evicted and returned. This can be the pushed item.
+func (queue *computationQueue) Push(item *queueItem) *queueItem {
+	if len(queue.items) >= queue.capacity {
+		if len(queue.min) == 0 || !queue.min[0].key.less(item.key) {
+			return item
+		}
+		evicted := queue.min[0]
+		queue.remove(evicted)
+		queue.push(item)
+		return evicted
+	}
+	queue.push(item)
+	return nil
+}
+
+// Pop the item with the greatest key from the queue. It returns false if the
+// queue is empty.
+func (queue *computationQueue) Pop() (*queueItem, bool) {
+	if len(queue.max) == 0 {
+		return nil, false
+	}
+	item := queue.max[0]
+	queue.remove(item)
+	return item, true
+}
+
+// RemoveOrder removes all items with a Computation for the order.ID from the
+// queue.
+func (queue *computationQueue) RemoveOrder(orderID order.ID) {
+	for _, item := range queue.items {
+		if item.computation.Buy.OrderID == orderID || item.computation.Sell.OrderID == orderID {
+			queue.remove(item)
+		}
+	}
+}
+
+func (queue *computationQueue) push(item *queueItem) {
+	queue.items[item.computation.ID] = item
+	heap.Push(&queue.max, item)
+	heap.Push(&queue.min, item)
+}
+
+func (queue *computationQueue) remove(item *queueItem) {
+	delete(queue.items, item.computation.ID)
+	heap.Remove(&queue.max, item.maxIndex)
+	heap.Remove(&queue.min, item.minIndex)
+}
+
+// maxQueueHeap implements the heap.Interface with the greatest key first.
+type maxQueueHeap []*queueItem
+
+func (h maxQueueHeap) Len() int           { return len(h) }
+func (h maxQueueHeap) Less(i, j int) bool { return h[j].key.less(h[i].key) }
+
+func (h maxQueueHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].maxIndex = i
+	h[j].maxIndex = j
+}
+
+func (h *maxQueueHeap) Push(x interface{}) {
+	item := x.(*queueItem)
+	item.maxIndex = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *maxQueueHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
+
+// minQueueHeap implements the heap.Interface with the lowest key first.
+type minQueueHeap []*queueItem
+
+func (h minQueueHeap) Len() int           { return len(h) }
+func (h minQueueHeap) Less(i, j int) bool { return h[i].key.less(h[j].key) }
+
+func (h minQueueHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].minIndex = i
+	h[j].minIndex = j
+}
+
+func (h *minQueueHeap) Push(x interface{}) {
+	item := x.(*queueItem)
+	item.minIndex = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *minQueueHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return item
+}
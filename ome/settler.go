@@ -2,13 +2,13 @@ package ome
 
 import (
 	"fmt"
-	"log"
 	"math/big"
 
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/smpc"
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 // A Settler settles Computations that have been resolved to matches and have
@@ -84,20 +84,26 @@ func (settler *settler) joinOrderMatch(networkID smpc.NetworkID, com Computation
 			com.Sell.Blinding,
 		},
 	}
-	copy(join.ID[:], com.ID[:])
-	join.ID[32] = byte(ResolveStageSettlement)
+	join.ID = joinID(com, ResolveStageSettlement)
 
-	err := settler.smpcer.Join(networkID, join, func(joinID smpc.JoinID, values []uint64) {
+	traceComputation(com, ResolveStageSettlement, "ome.settle.begin", nil)
+	err := settler.smpcer.Join(traceContext(com, ResolveStageSettlement), networkID, join, func(joinID smpc.JoinID, values []uint64) {
 		if len(values) != 16 {
 			logger.Compute(logger.LevelError, fmt.Sprintf("cannot join buy = %v, sell = %v: unexpected number of values: %v", com.Buy.OrderID, com.Sell.OrderID, len(values)))
 			return
 		}
-		buy := order.NewOrder(com.Buy.OrderParity, com.Buy.OrderType, com.Buy.OrderExpiry, com.Buy.OrderSettlement, order.Tokens(values[0]), order.PriceFromCoExp(values[1], values[2]), order.VolumeFromCoExp(values[3], values[4]), order.VolumeFromCoExp(values[5], values[6]), values[7])
-		buy.MinimumVolume = order.VolumeFromCoExp(values[5], values[6])
-
-		sell := order.NewOrder(com.Sell.OrderParity, com.Sell.OrderType, com.Sell.OrderExpiry, com.Sell.OrderSettlement, order.Tokens(values[8]), order.PriceFromCoExp(values[9], values[10]), order.VolumeFromCoExp(values[11], values[12]), order.VolumeFromCoExp(values[13], values[14]), values[15])
-		sell.MinimumVolume = order.VolumeFromCoExp(values[13], values[14])
-
+		buy, err := orderFromValues(com.Buy, values[:8])
+		if err != nil {
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot join buy = %v, sell = %v: cannot reconstruct buy: %v", com.Buy.OrderID, com.Sell.OrderID, err))
+			traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "outOfRange"})
+			return
+		}
+		sell, err := orderFromValues(com.Sell, values[8:])
+		if err != nil {
+			logger.Compute(logger.LevelError, fmt.Sprintf("cannot join buy = %v, sell = %v: cannot reconstruct sell: %v", com.Buy.OrderID, com.Sell.OrderID, err))
+			traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "outOfRange"})
+			return
+		}
 		settler.settleOrderMatch(com, buy, sell)
 	}, true /* delay message sending to ensure the round-robin */)
 	if err != nil {
@@ -112,57 +118,93 @@ func (settler *settler) settleOrderMatch(com Computation, buy, sell order.Order)
 		sell.Volume < buy.MinimumVolume ||
 		buy.Price < sell.Price {
 		if err := settler.contract.SubmitChallengeOrder(buy); err != nil {
-			log.Printf("[error] (settle) cannot submit challenge for buy order = %v: %v", buy.ID, err)
+			logger.Settlement(logger.LevelError, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot submit challenge for buy order: %v", err))
 		}
 		if err := settler.contract.SubmitChallengeOrder(sell); err != nil {
-			log.Printf("[error] (settle) cannot submit challenge for sell order = %v: %v", sell.ID, err)
+			logger.Settlement(logger.LevelError, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot submit challenge for sell order: %v", err))
 		}
 		if err := settler.contract.SubmitChallenge(buy.ID, sell.ID); err != nil {
-			log.Printf("[error] (settle) cannot submit challenge buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+			logger.Settlement(logger.LevelError, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot submit challenge: %v", err))
 		}
-		log.Printf("[info] (slash) found mismatched order confirmation")
+		logger.Settlement(logger.LevelInfo, buy.ID.String(), sell.ID.String(), "found mismatched order confirmation")
+		traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "challenged"})
+		return
+	}
+
+	// Leave the orders if the pair is not supported, because the settlement
+	// contract will not accept them.
+	if !order.DefaultTokenRegistry().IsSupported(buy.Tokens) {
+		logger.Settlement(logger.LevelWarn, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot execute settlement: unsupported tokens = %v", uint64(buy.Tokens)))
+		traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "unsupportedTokens"})
 		return
 	}
 
 	// Leave the orders if volume is too low and there is no profit for
-	// submitting such orders. Note: minimum volume is set to 1 ETH.
-	settleVolume := volumeInEth(buy, sell)
+	// submitting such orders. Note: minimum volume is set to 1 ETH. Pairs that
+	// cannot be valued in ETH are left, because their volume cannot be
+	// checked against the minimum volume.
+	settleVolume, ok := volumeInEth(buy, sell)
+	if !ok {
+		logger.Settlement(logger.LevelWarn, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot execute settlement: cannot value tokens = %v in ETH", uint64(buy.Tokens)))
+		traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "unvaluedTokens"})
+		return
+	}
 	if settleVolume < settler.minimumSettleVolume {
-		log.Printf("[info] (settle) cannot execute settlement buy = %v, sell = %v: volume = %v ETH too low", buy.ID, sell.ID, settleVolume)
+		logger.Settlement(logger.LevelInfo, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot execute settlement: volume = %v ETH too low", settleVolume))
+		traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "volumeTooLow"})
 		return
 	}
 
 	// Try settling the orders for at most 3 times.
 	err := settler.contract.Settle(buy, sell)
 	if err != nil {
-		log.Printf("[error] (settle) cannot store settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+		logger.Settlement(logger.LevelError, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot execute settlement: %v", err))
+		traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "error", "error": err.Error()})
 		return
 	}
+	traceComputation(com, ResolveStageSettlement, "ome.settle.end", trace.Attributes{"result": "settled"})
 
 	com.State = ComputationStateSettled
 	if err := settler.computationStore.PutComputation(com); err != nil {
-		log.Printf("[error] (settle) cannot store settlement buy = %v, sell = %v: %v", buy.ID, sell.ID, err)
+		logger.Settlement(logger.LevelError, buy.ID.String(), sell.ID.String(), fmt.Sprintf("cannot store settlement: %v", err))
 		return
 	}
 }
 
-func volumeInEth(buy, sell order.Order) uint64 {
-	if buy.Tokens.PriorityToken() == order.TokenETH {
-		// BTC-ETH
-		if buy.Volume >= sell.Volume {
-			return sell.Volume
-		} else {
-			return buy.Volume
-		}
-	} else {
-		// ETH-ERC20
-		var erc20Volume uint64
-		if buy.Volume >= sell.Volume {
-			erc20Volume = sell.Volume
-		} else {
-			erc20Volume = buy.Volume
+// orderFromValues returns the order.Order that was split into an
+// order.Fragment, using the values reconstructed from the shares of the
+// order.Fragment in the order that they are joined. It returns
+// order.ErrFixedPointRange if a fixed point price, or volume, is not less
+// than the order.MaxFixedPointValue.
+func orderFromValues(fragment order.Fragment, values []uint64) (order.Order, error) {
+	if fragment.Version == order.FragmentVersionFixedPoint {
+		if values[1] >= order.MaxFixedPointValue || values[3] >= order.MaxFixedPointValue || values[5] >= order.MaxFixedPointValue {
+			return order.Order{}, order.ErrFixedPointRange
 		}
+		return order.NewFixedPointOrder(fragment.OrderParity, fragment.OrderType, fragment.OrderExpiry, fragment.OrderSettlement, order.Tokens(values[0]), values[1], values[3], values[5], values[7]), nil
+	}
+	ord := order.NewOrder(fragment.OrderParity, fragment.OrderType, fragment.OrderExpiry, fragment.OrderSettlement, order.Tokens(values[0]), order.PriceFromCoExp(values[1], values[2]), order.VolumeFromCoExp(values[3], values[4]), order.VolumeFromCoExp(values[5], values[6]), values[7])
+	ord.MinimumVolume = order.VolumeFromCoExp(values[5], values[6])
+	return ord, nil
+}
 
+// volumeInEth returns the volume of a match in units of 1e-12 ETH. It returns
+// false if the volume cannot be valued in ETH, because neither Token in the
+// pair is ETH.
+func volumeInEth(buy, sell order.Order) (uint64, bool) {
+	volume := buy.Volume
+	if sell.Volume < volume {
+		volume = sell.Volume
+	}
+
+	switch {
+	case buy.Tokens.PriorityToken() == order.TokenETH:
+		// The volume is in ETH (e.g. BTC-ETH)
+		return volume, true
+
+	case buy.Tokens.NonPriorityToken() == order.TokenETH:
+		// The volume is in the priority token and the price is in ETH (e.g.
+		// ETH-ERC20)
 		x := big.NewInt(0)
 		y := big.NewInt(0)
 
@@ -173,12 +215,15 @@ func volumeInEth(buy, sell order.Order) uint64 {
 		y.SetUint64(2)
 		x.Div(x, y)
 
-		y.SetUint64(erc20Volume)
+		y.SetUint64(volume)
 		x.Mul(x, y)
 
 		y.SetUint64(1e12)
 		x.Div(x, y)
 
-		return x.Uint64()
+		return x.Uint64(), true
+
+	default:
+		return 0, false
 	}
 }
@@ -24,6 +24,10 @@ type ComputationStorer interface {
 	DeleteComputation(id ComputationID) error
 	Computation(id ComputationID) (Computation, error)
 	Computations() (ComputationIterator, error)
+
+	// OrderComputations returns the Computations for which the order.ID is
+	// the buy or the sell.
+	OrderComputations(orderID order.ID) ([]Computation, error)
 }
 
 // ComputationIterator is used to iterate over a Computation collection.
@@ -0,0 +1,38 @@
+package ome
+
+import (
+	"encoding/base64"
+
+	"github.com/republicprotocol/republic-go/trace"
+)
+
+// traceContext returns the trace.Context for a Computation. The
+// ComputationID is used as the trace.ID, and the ComputationID followed by the
+// ResolveStage is used as the trace.SpanID, so that every node in a pod
+// records the same identifiers. The ResolveStageNil returns a trace.Context
+// that is not part of any span.
+func traceContext(com Computation, stage ResolveStage) trace.Context {
+	ctx := trace.Context{
+		ID: trace.ID(com.ID),
+	}
+	if stage != ResolveStageNil {
+		copy(ctx.SpanID[:], com.ID[:])
+		ctx.SpanID[32] = byte(stage)
+	}
+	return ctx
+}
+
+// traceComputation records a trace.Event for a Computation. The complete
+// order IDs of the Computation are always recorded, so that the trace can be
+// found from either order.
+func traceComputation(com Computation, stage ResolveStage, name string, attrs trace.Attributes) {
+	if attrs == nil {
+		attrs = trace.Attributes{}
+	}
+	attrs["buy"] = base64.StdEncoding.EncodeToString(com.Buy.OrderID[:])
+	attrs["sell"] = base64.StdEncoding.EncodeToString(com.Sell.OrderID[:])
+	if stage != ResolveStageNil {
+		attrs["stage"] = stage.String()
+	}
+	trace.Record(traceContext(com, stage), name, attrs)
+}
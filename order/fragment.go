@@ -5,6 +5,7 @@ import (
 	"crypto/rsa"
 	"encoding/base64"
 	"encoding/binary"
+	"errors"
 	"math/big"
 	"time"
 
@@ -12,6 +13,75 @@ import (
 	"github.com/republicprotocol/republic-go/shamir"
 )
 
+// ErrUnverifiableFragment is returned when a Fragment does not have a
+// FragmentVerification for each of its shares.
+var ErrUnverifiableFragment = errors.New("unverifiable fragment")
+
+// ErrUnexpectedThreshold is returned when the polynomial commitments of a
+// Fragment do not commit to polynomials with the threshold of the pod that
+// received the Fragment.
+var ErrUnexpectedThreshold = errors.New("unexpected threshold")
+
+// ErrUnsupportedFragmentVersion is returned when a Fragment uses a
+// FragmentVersion that is not supported.
+var ErrUnsupportedFragmentVersion = errors.New("unsupported fragment version")
+
+// ErrFixedPointRange is returned when a price, or volume, cannot be encoded
+// using FragmentVersionFixedPoint because it is not less than the
+// MaxFixedPointValue.
+var ErrFixedPointRange = errors.New("fixed point value out of range")
+
+// ErrOrderExpired is returned when a Fragment, or EncryptedFragment, is used
+// after the expiry of its order.
+var ErrOrderExpired = errors.New("order expired")
+
+// MaxFixedPointValue is the exclusive upper bound on prices, and volumes, that
+// are encoded using FragmentVersionFixedPoint. The difference between two
+// values below this bound is always less than half of the shamir.Prime, so
+// the sign of the difference can be read from its reconstruction.
+const MaxFixedPointValue = uint64(1) << 62
+
+// A FragmentVersion defines how the price, and volumes, of an Order are
+// encoded into the CoExpShares of a Fragment. Both Fragments in a computation
+// must use the same FragmentVersion.
+type FragmentVersion uint32
+
+// FragmentVersion values.
+const (
+	// FragmentVersionCoExp encodes prices, and volumes, as a CoExp. The
+	// precision is roughly three significant digits.
+	FragmentVersionCoExp = FragmentVersion(0)
+
+	// FragmentVersionFixedPoint encodes prices, and volumes, as integers in
+	// the same units as the Order. The Co share of each CoExpShare is a share
+	// of the value, and the Exp share is a share of zero.
+	FragmentVersionFixedPoint = FragmentVersion(1)
+)
+
+// SupportedFragmentVersions returns all FragmentVersions that are supported,
+// in ascending order.
+func SupportedFragmentVersions() []FragmentVersion {
+	return []FragmentVersion{FragmentVersionCoExp, FragmentVersionFixedPoint}
+}
+
+// IsSupported returns true if the FragmentVersion is supported. Otherwise, it
+// returns false.
+func (version FragmentVersion) IsSupported() bool {
+	return version <= FragmentVersionFixedPoint
+}
+
+// String returns a human-readable representation of the FragmentVersion.
+func (version FragmentVersion) String() string {
+	switch version {
+	case FragmentVersionCoExp:
+		return "coExp"
+	case FragmentVersionFixedPoint:
+		return "fixedPoint"
+	default:
+		return "unexpected fragment version"
+	}
+}
+
 // An FragmentID is the Keccak256 hash of a Fragment.
 type FragmentID [32]byte
 
@@ -39,6 +109,7 @@ type Fragment struct {
 	OrderExpiry     time.Time          `json:"orderExpiry"`
 	ID              FragmentID         `json:"id"`
 	EpochDepth      FragmentEpochDepth `json:"epochDepth"`
+	Version         FragmentVersion    `json:"version"`
 
 	Tokens        shamir.Share `json:"tokens"`
 	Price         CoExpShare   `json:"price"`
@@ -52,6 +123,10 @@ type Fragment struct {
 	// CommitmentSet for different fragment indices, other than the index of
 	// this fragment
 	Commitments FragmentCommitments `json:"commitments"`
+
+	// Verification of the shares in this fragment against the shares in all
+	// other fragments of the same order
+	Verification FragmentVerification `json:"verification"`
 }
 
 // NewFragment returns a new Fragment and computes the FragmentID.
@@ -112,6 +187,9 @@ func (fragment *Fragment) Bytes() ([]byte, error) {
 	if err := binary.Write(buf, binary.BigEndian, fragment.OrderExpiry.Unix()); err != nil {
 		return nil, err
 	}
+	if err := binary.Write(buf, binary.BigEndian, fragment.Version); err != nil {
+		return nil, err
+	}
 	if err := binary.Write(buf, binary.BigEndian, fragment.Tokens); err != nil {
 		return nil, err
 	}
@@ -130,6 +208,75 @@ func (fragment *Fragment) Bytes() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Shares returns the shares in a Fragment, in the order that they are
+// verified by a FragmentVerification.
+func (fragment *Fragment) Shares() shamir.Shares {
+	return shamir.Shares{
+		fragment.Tokens,
+		fragment.Price.Co,
+		fragment.Price.Exp,
+		fragment.Volume.Co,
+		fragment.Volume.Exp,
+		fragment.MinimumVolume.Co,
+		fragment.MinimumVolume.Exp,
+		fragment.Nonce,
+	}
+}
+
+// Verify that the shares in a Fragment are consistent with the polynomial
+// commitments published by the trader that created the Fragment, and that the
+// commitments are for polynomials with k coefficients, where k is the
+// threshold of the pod. It returns ErrUnverifiableFragment if the Fragment
+// has no FragmentVerification, ErrUnexpectedThreshold if any commitment does
+// not have k coefficients, and shamir.ErrInconsistentShare if any share is
+// inconsistent.
+func (fragment *Fragment) Verify(k int64) error {
+	shares := fragment.Shares()
+	if len(fragment.Verification.Blindings) != len(shares) || len(fragment.Verification.Commitments) != len(shares) {
+		return ErrUnverifiableFragment
+	}
+	for _, commitment := range fragment.Verification.Commitments {
+		if int64(len(commitment)) != k {
+			return ErrUnexpectedThreshold
+		}
+	}
+	for i, share := range shares {
+		if err := fragment.Verification.Commitments[i].Verify(share, fragment.Verification.Blindings[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerificationHash returns the Keccak256 hash of the polynomial commitments
+// in the FragmentVerification. All fragments of an order have the same hash,
+// unless the trader published different commitments to different darknodes.
+// Darknodes bind computations to this hash, so that fragments are only
+// combined with fragments that were verified against the same commitments.
+func (fragment *Fragment) VerificationHash() [32]byte {
+	buf := new(bytes.Buffer)
+	for _, commitment := range fragment.Verification.Commitments {
+		binary.Write(buf, binary.BigEndian, uint32(len(commitment)))
+		for _, c := range commitment {
+			var data []byte
+			if c.Int != nil {
+				data = c.Int.Bytes()
+			}
+			binary.Write(buf, binary.BigEndian, uint32(len(data)))
+			buf.Write(data)
+		}
+	}
+	hash32 := [32]byte{}
+	copy(hash32[:], crypto.Keccak256(buf.Bytes()))
+	return hash32
+}
+
+// IsExpired returns true if the order of the Fragment has expired at the
+// given time.
+func (fragment *Fragment) IsExpired(now time.Time) bool {
+	return !now.Before(fragment.OrderExpiry)
+}
+
 // Equal returns an equality check between two Orders.
 func (fragment *Fragment) Equal(other *Fragment) bool {
 	return bytes.Equal(fragment.OrderID[:], other.OrderID[:]) &&
@@ -137,6 +284,7 @@ func (fragment *Fragment) Equal(other *Fragment) bool {
 		fragment.OrderParity == other.OrderParity &&
 		fragment.OrderExpiry.Equal(other.OrderExpiry) &&
 		bytes.Equal(fragment.ID[:], other.ID[:]) &&
+		fragment.Version == other.Version &&
 		fragment.Tokens.Equal(&other.Tokens) &&
 		fragment.Price.Equal(&other.Price) &&
 		fragment.Volume.Equal(&other.Volume) &&
@@ -155,6 +303,7 @@ func (fragment *Fragment) Encrypt(pubKey rsa.PublicKey) (EncryptedFragment, erro
 		OrderExpiry:     fragment.OrderExpiry,
 		ID:              fragment.ID,
 		EpochDepth:      fragment.EpochDepth,
+		Version:         fragment.Version,
 	}
 	encryptedFragment.Tokens, err = fragment.Tokens.Encrypt(pubKey)
 	if err != nil {
@@ -181,6 +330,13 @@ func (fragment *Fragment) Encrypt(pubKey rsa.PublicKey) (EncryptedFragment, erro
 		return encryptedFragment, err
 	}
 	encryptedFragment.Commitments = fragment.Commitments
+	if len(fragment.Verification.Blindings) > 0 {
+		encryptedFragment.Verification.Blindings, err = fragment.Verification.Blindings.Encrypt(pubKey)
+		if err != nil {
+			return encryptedFragment, err
+		}
+	}
+	encryptedFragment.Verification.Commitments = fragment.Verification.Commitments
 	return encryptedFragment, nil
 }
 
@@ -194,6 +350,7 @@ type EncryptedFragment struct {
 	OrderExpiry     time.Time           `json:"orderExpiry"`
 	ID              FragmentID          `json:"id"`
 	EpochDepth      FragmentEpochDepth  `json:"epochDepth"`
+	Version         FragmentVersion     `json:"version"`
 	Tokens          []byte              `json:"tokens"`
 	Price           EncryptedCoExpShare `json:"price"`
 	Volume          EncryptedCoExpShare `json:"volume"`
@@ -202,6 +359,8 @@ type EncryptedFragment struct {
 
 	Blinding    []byte              `json:"blinding"`
 	Commitments FragmentCommitments `json:"commitments"`
+
+	Verification EncryptedFragmentVerification `json:"verification"`
 }
 
 // Decrypt an EncryptedFragment using an rsa.PrivateKey.
@@ -215,6 +374,7 @@ func (fragment *EncryptedFragment) Decrypt(privKey *rsa.PrivateKey) (Fragment, e
 		OrderExpiry:     fragment.OrderExpiry,
 		ID:              fragment.ID,
 		EpochDepth:      fragment.EpochDepth,
+		Version:         fragment.Version,
 	}
 	if err := decryptedFragment.Tokens.Decrypt(privKey, fragment.Tokens); err != nil {
 		return decryptedFragment, err
@@ -242,9 +402,21 @@ func (fragment *EncryptedFragment) Decrypt(privKey *rsa.PrivateKey) (Fragment, e
 		return decryptedFragment, err
 	}
 	decryptedFragment.Commitments = fragment.Commitments
+	if len(fragment.Verification.Blindings) > 0 {
+		if err := decryptedFragment.Verification.Blindings.Decrypt(privKey, fragment.Verification.Blindings); err != nil {
+			return decryptedFragment, err
+		}
+	}
+	decryptedFragment.Verification.Commitments = fragment.Verification.Commitments
 	return decryptedFragment, nil
 }
 
+// IsExpired returns true if the order of the EncryptedFragment has expired at
+// the given time.
+func (fragment *EncryptedFragment) IsExpired(now time.Time) bool {
+	return !now.Before(fragment.OrderExpiry)
+}
+
 // IsNil checks if an EncryptedFragment is null.
 func (fragment *EncryptedFragment) IsNil() bool {
 	return fragment == nil || fragment.ID == (FragmentID{}) || fragment.ID == [32]byte{} || fragment.OrderID == [32]byte{}
@@ -260,3 +432,19 @@ type FragmentCommitment struct {
 }
 
 type FragmentCommitments map[uint64]FragmentCommitment
+
+// A FragmentVerification stores a blinding share, and a polynomial commitment,
+// for each share returned by Fragment.Shares. The polynomial commitments are
+// the same for all fragments of an order, but the blinding shares must be kept
+// as secret as the shares.
+type FragmentVerification struct {
+	Blindings   shamir.Shares                 `json:"blindings"`
+	Commitments []shamir.PolynomialCommitment `json:"commitments"`
+}
+
+// An EncryptedFragmentVerification is a FragmentVerification with blinding
+// shares that have been encrypted by an RSA public key.
+type EncryptedFragmentVerification struct {
+	Blindings   []byte                        `json:"blindings"`
+	Commitments []shamir.PolynomialCommitment `json:"commitments"`
+}
@@ -113,6 +113,99 @@ var _ = Describe("Order fragments", func() {
 		})
 	})
 
+	Context("when checking for expiry", func() {
+
+		It("should be expired at, and after, the expiry of the order", func() {
+			copy(orderID[:], "orderID")
+			expiry := time.Now()
+			fragment, err := NewFragment(orderID, TypeLimit, ParityBuy, SettlementRenEx, expiry, tokens, price, maxVolume, minVolume, nonce)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(fragment.IsExpired(expiry.Add(-time.Second))).Should(BeFalse())
+			Expect(fragment.IsExpired(expiry)).Should(BeTrue())
+			Expect(fragment.IsExpired(expiry.Add(time.Second))).Should(BeTrue())
+
+			encryptedFragment := EncryptedFragment{OrderExpiry: expiry}
+			Expect(encryptedFragment.IsExpired(expiry.Add(-time.Second))).Should(BeFalse())
+			Expect(encryptedFragment.IsExpired(expiry)).Should(BeTrue())
+		})
+	})
+
+	Context("when verifying fragments", func() {
+
+		It("should verify the fragments of an order", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 1000000000000, 42)
+			fragments, err := ord.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments {
+				Expect(fragment.Verify(4)).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should not verify fragments that are inconsistent with the other fragments", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 1000000000000, 42)
+			fragments, err := ord.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			fragments[0].Price.Co.Value = (fragments[0].Price.Co.Value + 1) % shamir.Prime
+			Expect(fragments[0].Verify(4)).Should(Equal(shamir.ErrInconsistentShare))
+
+			fragments[1].Volume = fragments[2].Volume
+			Expect(fragments[1].Verify(4)).Should(Equal(shamir.ErrInconsistentShare))
+		})
+
+		It("should not verify fragments that were split with a different threshold", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 1000000000000, 42)
+
+			// Shares of a polynomial with a degree that is too high cannot be
+			// opened by the pod
+			fragments, err := ord.Split(6, 5)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fragments[0].Verify(5)).ShouldNot(HaveOccurred())
+			Expect(fragments[0].Verify(4)).Should(Equal(ErrUnexpectedThreshold))
+
+			fragments, err = ord.Split(6, 3)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fragments[0].Verify(4)).Should(Equal(ErrUnexpectedThreshold))
+		})
+
+		It("should return the same verification hash for the fragments of an order", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 1000000000000, 42)
+			fragments, err := ord.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			for _, fragment := range fragments {
+				Expect(fragment.VerificationHash()).Should(Equal(fragments[0].VerificationHash()))
+			}
+
+			// Splitting the order again produces different commitments
+			otherFragments, err := ord.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(otherFragments[0].Verify(4)).ShouldNot(HaveOccurred())
+			Expect(otherFragments[0].VerificationHash()).ShouldNot(Equal(fragments[0].VerificationHash()))
+		})
+
+		It("should not verify fragments without a verification", func() {
+			fragment, err := NewFragment(orderID, TypeLimit, ParityBuy, SettlementRenEx, time.Now(), tokens, price, maxVolume, minVolume, nonce)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fragment.Verify(4)).Should(Equal(ErrUnverifiableFragment))
+		})
+
+		It("should verify fragments after decrypting their encrypted form", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensETHREN, 1000000000000, 1000000000000, 1000000000000, 42)
+			fragments, err := ord.Split(6, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			encryptedFragment, err := fragments[0].Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			decryptedFragment, err := encryptedFragment.Decrypt(rsaKey.PrivateKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(decryptedFragment.Verification).Should(Equal(fragments[0].Verification))
+			Expect(decryptedFragment.Verify(4)).ShouldNot(HaveOccurred())
+		})
+	})
+
 	Context("when encrypting and decrypting fragments", func() {
 
 		It("should return the same fragment after decrypting its encrypted form", func() {
@@ -31,7 +31,7 @@ func (id ID) String() string {
 }
 
 // Token is a numerical representation of a token supported by Republic
-// Protocol.
+// Protocol. The supported Tokens are stored in a TokenRegistry.
 type Token uint32
 
 // Token values.
@@ -45,30 +45,17 @@ const (
 	TokenOMG  Token = 65538
 )
 
-// String returns a human-readable representation of a Token.
+// String returns the symbol of a Token in the DefaultTokenRegistry.
 func (token Token) String() string {
-	switch token {
-	case TokenBTC:
-		return "BTC"
-	case TokenETH:
-		return "ETH"
-	case TokenDGX:
-		return "DGX"
-	case TokenTUSD:
-		return "TUSD"
-	case TokenREN:
-		return "REN"
-	case TokenZRX:
-		return "ZRX"
-	case TokenOMG:
-		return "OMG"
-	default:
+	details, err := DefaultTokenRegistry().Token(token)
+	if err != nil {
 		return "unexpected token"
 	}
+	return details.Symbol
 }
 
 // Tokens are a numerical representation of the token pairings supported by
-// Republic Protocol.
+// Republic Protocol. The supported pairs are stored in a TokenRegistry.
 type Tokens uint64
 
 // Tokens values.
@@ -91,24 +78,13 @@ func (tokens Tokens) NonPriorityToken() Token {
 	return Token(tokens >> 32)
 }
 
-// String returns a human-readable representation of Tokens.
+// String returns a human-readable representation of Tokens that are
+// registered as a pair in the DefaultTokenRegistry.
 func (tokens Tokens) String() string {
-	switch tokens {
-	case TokensBTCETH:
-		return "BTC-ETH"
-	case TokensETHDGX:
-		return "ETH-DGX"
-	case TokensETHTUSD:
-		return "ETH-TUSD"
-	case TokensETHREN:
-		return "ETH-REN"
-	case TokensETHZRX:
-		return "ETH-ZRX"
-	case TokensETHOMG:
-		return "ETH-OMG"
-	default:
+	if !DefaultTokenRegistry().IsSupported(tokens) {
 		return "unexpected tokens"
 	}
+	return tokens.NonPriorityToken().String() + "-" + tokens.PriorityToken().String()
 }
 
 // A Type is a publicly bit of information that determines the type of
@@ -231,6 +207,26 @@ func NewOrder(parity Parity, ty Type, expiry time.Time, settlement Settlement, t
 	return order
 }
 
+// NewFixedPointOrder returns a new Order and computes the ID. Unlike NewOrder,
+// the price and volumes are not rounded to the precision of a CoExp, so the
+// Order must be split using FragmentVersionFixedPoint.
+func NewFixedPointOrder(parity Parity, ty Type, expiry time.Time, settlement Settlement, tokens Tokens, price, volume, minimumVolume, nonce uint64) Order {
+	order := Order{
+		Parity: parity,
+		Type:   ty,
+		Expiry: expiry,
+		Nonce:  nonce,
+
+		Settlement:    settlement,
+		Tokens:        tokens,
+		Price:         price,
+		Volume:        volume,
+		MinimumVolume: minimumVolume,
+	}
+	order.ID = ID(order.Hash())
+	return order
+}
+
 // NewOrderFromJSONFile returns an order that is unmarshaled from a JSON file.
 func NewOrderFromJSONFile(fileName string) (Order, error) {
 	order := Order{}
@@ -270,61 +266,86 @@ func WriteOrdersToJSONFile(fileName string, orders []*Order) error {
 }
 
 // Split the Order into n OrderFragments, where k OrderFragments are needed to
-// reconstruct the Order. Returns a slice of all n OrderFragments, or an error.
+// reconstruct the Order. The OrderFragments use FragmentVersionCoExp. Returns
+// a slice of all n OrderFragments, or an error.
 func (order *Order) Split(n, k int64) ([]Fragment, error) {
-	priceCoExp := PriceToCoExp(order.Price)
-	volumeCoExp := VolumeToCoExp(order.Volume)
-	minimumVolumeCoExp := VolumeToCoExp(order.MinimumVolume)
+	return order.SplitWithVersion(n, k, FragmentVersionCoExp)
+}
 
-	tokens, err := shamir.Split(n, k, uint64(order.Tokens))
-	if err != nil {
-		return nil, err
-	}
-	priceCos, err := shamir.Split(n, k, priceCoExp.Co)
-	if err != nil {
-		return nil, err
-	}
-	priceExps, err := shamir.Split(n, k, priceCoExp.Exp)
-	if err != nil {
-		return nil, err
-	}
-	volumeCos, err := shamir.Split(n, k, volumeCoExp.Co)
-	if err != nil {
-		return nil, err
-	}
-	volumeExps, err := shamir.Split(n, k, volumeCoExp.Exp)
-	if err != nil {
-		return nil, err
-	}
-	minimumVolumeCos, err := shamir.Split(n, k, minimumVolumeCoExp.Co)
-	if err != nil {
-		return nil, err
-	}
-	minimumVolumeExps, err := shamir.Split(n, k, minimumVolumeCoExp.Exp)
-	if err != nil {
-		return nil, err
-	}
-	nonces, err := shamir.Split(n, k, order.Nonce)
-	if err != nil {
-		return nil, err
+// SplitWithVersion splits the Order into n OrderFragments in the same way as
+// Split, but uses a FragmentVersion to encode the price and volumes. Returns a
+// slice of all n OrderFragments, or an error.
+func (order *Order) SplitWithVersion(n, k int64, version FragmentVersion) ([]Fragment, error) {
+	var priceCoExp, volumeCoExp, minimumVolumeCoExp CoExp
+	switch version {
+	case FragmentVersionCoExp:
+		priceCoExp = PriceToCoExp(order.Price)
+		volumeCoExp = VolumeToCoExp(order.Volume)
+		minimumVolumeCoExp = VolumeToCoExp(order.MinimumVolume)
+	case FragmentVersionFixedPoint:
+		if order.Price >= MaxFixedPointValue || order.Volume >= MaxFixedPointValue || order.MinimumVolume >= MaxFixedPointValue {
+			return nil, ErrFixedPointRange
+		}
+		priceCoExp = NewCoExp(order.Price, 0)
+		volumeCoExp = NewCoExp(order.Volume, 0)
+		minimumVolumeCoExp = NewCoExp(order.MinimumVolume, 0)
+	default:
+		return nil, ErrUnsupportedFragmentVersion
+	}
+
+	secrets := []uint64{
+		uint64(order.Tokens),
+		priceCoExp.Co,
+		priceCoExp.Exp,
+		volumeCoExp.Co,
+		volumeCoExp.Exp,
+		minimumVolumeCoExp.Co,
+		minimumVolumeCoExp.Exp,
+		order.Nonce,
+	}
+	shares := make([]shamir.Shares, len(secrets))
+	blindings := make([]shamir.Shares, len(secrets))
+	commitments := make([]shamir.PolynomialCommitment, len(secrets))
+	for i, secret := range secrets {
+		var err error
+		shares[i], blindings[i], commitments[i], err = shamir.SplitVerifiable(n, k, secret)
+		if err != nil {
+			return nil, err
+		}
 	}
+
 	fragments := make([]Fragment, n)
 	for i := range fragments {
+		var err error
 		fragments[i], err = NewFragment(
 			order.ID,
 			order.Type,
 			order.Parity,
 			order.Settlement,
 			order.Expiry,
-			tokens[i],
-			CoExpShare{Co: priceCos[i], Exp: priceExps[i]},
-			CoExpShare{Co: volumeCos[i], Exp: volumeExps[i]},
-			CoExpShare{Co: minimumVolumeCos[i], Exp: minimumVolumeExps[i]},
-			nonces[i],
+			shares[0][i],
+			CoExpShare{Co: shares[1][i], Exp: shares[2][i]},
+			CoExpShare{Co: shares[3][i], Exp: shares[4][i]},
+			CoExpShare{Co: shares[5][i], Exp: shares[6][i]},
+			shares[7][i],
 		)
 		if err != nil {
 			return nil, err
 		}
+		if version != FragmentVersionCoExp {
+			// Recompute the FragmentID so that it covers the FragmentVersion
+			fragments[i].Version = version
+			fragmentHash, err := fragments[i].Hash()
+			if err != nil {
+				return nil, err
+			}
+			fragments[i].ID = FragmentID(fragmentHash)
+		}
+		fragments[i].Verification.Blindings = make(shamir.Shares, len(secrets))
+		for j := range secrets {
+			fragments[i].Verification.Blindings[j] = blindings[j][i]
+		}
+		fragments[i].Verification.Commitments = commitments
 	}
 	return fragments, nil
 }
@@ -9,6 +9,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/shamir"
 )
 
 var _ = Describe("Orders", func() {
@@ -68,6 +69,55 @@ var _ = Describe("Orders", func() {
 				}
 			}
 		})
+
+		It("should split prices and volumes exactly when using fixed point fragments", func() {
+			ord := NewFixedPointOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensBTCETH, 123456789123456789, 987654321, 12345, 10)
+
+			fragments, err := ord.SplitWithVersion(n, k, FragmentVersionFixedPoint)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(len(fragments)).Should(Equal(int(n)))
+
+			prices := make(shamir.Shares, k)
+			volumes := make(shamir.Shares, k)
+			minimumVolumes := make(shamir.Shares, k)
+			for i := int64(0); i < k; i++ {
+				Expect(fragments[i].Version).Should(Equal(FragmentVersionFixedPoint))
+				Expect(fragments[i].Verify(k)).ShouldNot(HaveOccurred())
+				prices[i] = fragments[i].Price.Co
+				volumes[i] = fragments[i].Volume.Co
+				minimumVolumes[i] = fragments[i].MinimumVolume.Co
+			}
+			Expect(shamir.Join(prices)).Should(Equal(ord.Price))
+			Expect(shamir.Join(volumes)).Should(Equal(ord.Volume))
+			Expect(shamir.Join(minimumVolumes)).Should(Equal(ord.MinimumVolume))
+		})
+
+		It("should return different fragment IDs for different fragment versions", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensBTCETH, price, maxVolume, minVolume, 10)
+
+			fragments, err := ord.SplitWithVersion(n, k, FragmentVersionFixedPoint)
+			Expect(err).ShouldNot(HaveOccurred())
+			fragment := fragments[0]
+			fragment.Version = FragmentVersionCoExp
+			fragmentHash, err := fragment.Hash()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(FragmentID(fragmentHash)).ShouldNot(Equal(fragments[0].ID))
+		})
+
+		It("should return an error for values out of the fixed point range", func() {
+			ord := NewFixedPointOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensBTCETH, MaxFixedPointValue, maxVolume, minVolume, 10)
+
+			_, err := ord.SplitWithVersion(n, k, FragmentVersionFixedPoint)
+			Expect(err).Should(Equal(ErrFixedPointRange))
+		})
+
+		It("should return an error for unsupported fragment versions", func() {
+			ord := NewOrder(ParityBuy, TypeLimit, time.Now().Add(time.Hour), SettlementRenEx, TokensBTCETH, price, maxVolume, minVolume, 10)
+
+			_, err := ord.SplitWithVersion(n, k, FragmentVersion(2))
+			Expect(err).Should(Equal(ErrUnsupportedFragmentVersion))
+			Expect(FragmentVersion(2).IsSupported()).Should(BeFalse())
+		})
 	})
 
 	Context("when reading and writing orders from files", func() {
@@ -0,0 +1,266 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedToken is returned when a Token is not registered in a
+// TokenRegistry.
+var ErrUnsupportedToken = errors.New("unsupported token")
+
+// ErrUnsupportedTokens is returned when Tokens are not registered as a pair
+// in a TokenRegistry.
+var ErrUnsupportedTokens = errors.New("unsupported tokens")
+
+// ErrMalformedTokens is returned when a token pair cannot be parsed.
+var ErrMalformedTokens = errors.New("malformed tokens")
+
+// NewTokens returns the Tokens that pair a non-priority Token with a priority
+// Token.
+func NewTokens(nonPriority, priority Token) Tokens {
+	return Tokens((uint64(nonPriority) << 32) | uint64(priority))
+}
+
+// TokenDetails describe a Token that is supported by Republic Protocol. The
+// Address is empty for Tokens that are not ERC20 tokens.
+type TokenDetails struct {
+	Token    Token  `json:"token"`
+	Symbol   string `json:"symbol"`
+	Address  string `json:"address,omitempty"`
+	Decimals uint8  `json:"decimals"`
+}
+
+// TokenRegistryOptions are used to Unmarshal a TokenRegistry from JSON. Pairs
+// are written as the symbol of the non-priority Token and the symbol of the
+// priority Token, separated by a dash (e.g. "ETH-REN").
+type TokenRegistryOptions struct {
+	Tokens []TokenDetails `json:"tokens"`
+	Pairs  []string       `json:"pairs"`
+}
+
+// A TokenRegistry stores the Tokens, and the pairs of Tokens, that are
+// supported by Republic Protocol. It is safe for concurrent use.
+type TokenRegistry struct {
+	mu      *sync.RWMutex
+	tokens  map[Token]TokenDetails
+	symbols map[string]Token
+	pairs   map[Tokens]struct{}
+}
+
+// NewTokenRegistry returns an empty TokenRegistry.
+func NewTokenRegistry() *TokenRegistry {
+	return &TokenRegistry{
+		mu:      new(sync.RWMutex),
+		tokens:  map[Token]TokenDetails{},
+		symbols: map[string]Token{},
+		pairs:   map[Tokens]struct{}{},
+	}
+}
+
+// NewTokenRegistryFromOptions returns a TokenRegistry with the Tokens, and
+// pairs, defined by the TokenRegistryOptions. An error is returned if a pair
+// uses a Token that is not defined.
+func NewTokenRegistryFromOptions(options TokenRegistryOptions) (*TokenRegistry, error) {
+	registry := NewTokenRegistry()
+	for _, details := range options.Tokens {
+		registry.RegisterToken(details)
+	}
+	for _, pair := range options.Pairs {
+		tokens, err := registry.ParseTokens(pair)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse pair %v: %v", pair, err)
+		}
+		if err := registry.RegisterPair(tokens); err != nil {
+			return nil, fmt.Errorf("cannot register pair %v: %v", pair, err)
+		}
+	}
+	return registry, nil
+}
+
+// NewDefaultTokenRegistry returns a TokenRegistry with the Tokens, and pairs,
+// that were supported before Tokens could be registered.
+func NewDefaultTokenRegistry() *TokenRegistry {
+	registry := NewTokenRegistry()
+	registry.RegisterToken(TokenDetails{Token: TokenBTC, Symbol: "BTC", Decimals: 8})
+	registry.RegisterToken(TokenDetails{Token: TokenETH, Symbol: "ETH", Decimals: 18})
+	registry.RegisterToken(TokenDetails{Token: TokenDGX, Symbol: "DGX", Decimals: 9})
+	registry.RegisterToken(TokenDetails{Token: TokenTUSD, Symbol: "TUSD", Decimals: 18})
+	registry.RegisterToken(TokenDetails{Token: TokenREN, Symbol: "REN", Decimals: 18})
+	registry.RegisterToken(TokenDetails{Token: TokenZRX, Symbol: "ZRX", Decimals: 18})
+	registry.RegisterToken(TokenDetails{Token: TokenOMG, Symbol: "OMG", Decimals: 18})
+	for _, tokens := range []Tokens{TokensBTCETH, TokensETHDGX, TokensETHTUSD, TokensETHREN, TokensETHZRX, TokensETHOMG} {
+		if err := registry.RegisterPair(tokens); err != nil {
+			panic(fmt.Sprintf("cannot register default pair %v: %v", uint64(tokens), err))
+		}
+	}
+	return registry
+}
+
+// RegisterToken adds a Token to the TokenRegistry, replacing the
+// TokenDetails of the Token if it is already registered.
+func (registry *TokenRegistry) RegisterToken(details TokenDetails) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if previous, ok := registry.tokens[details.Token]; ok {
+		delete(registry.symbols, previous.Symbol)
+	}
+	registry.tokens[details.Token] = details
+	registry.symbols[details.Symbol] = details.Token
+}
+
+// DeregisterToken removes a Token, and all pairs that use the Token, from the
+// TokenRegistry.
+func (registry *TokenRegistry) DeregisterToken(token Token) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	details, ok := registry.tokens[token]
+	if !ok {
+		return
+	}
+	delete(registry.tokens, token)
+	delete(registry.symbols, details.Symbol)
+	for tokens := range registry.pairs {
+		if tokens.PriorityToken() == token || tokens.NonPriorityToken() == token {
+			delete(registry.pairs, tokens)
+		}
+	}
+}
+
+// RegisterPair adds a pair of Tokens to the TokenRegistry. Both Tokens must
+// already be registered.
+func (registry *TokenRegistry) RegisterPair(tokens Tokens) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.tokens[tokens.PriorityToken()]; !ok {
+		return ErrUnsupportedToken
+	}
+	if _, ok := registry.tokens[tokens.NonPriorityToken()]; !ok {
+		return ErrUnsupportedToken
+	}
+	registry.pairs[tokens] = struct{}{}
+	return nil
+}
+
+// Token returns the TokenDetails of a Token. It returns ErrUnsupportedToken if
+// the Token is not registered.
+func (registry *TokenRegistry) Token(token Token) (TokenDetails, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	details, ok := registry.tokens[token]
+	if !ok {
+		return TokenDetails{}, ErrUnsupportedToken
+	}
+	return details, nil
+}
+
+// TokenBySymbol returns the TokenDetails of the Token with a symbol. It
+// returns ErrUnsupportedToken if no such Token is registered.
+func (registry *TokenRegistry) TokenBySymbol(symbol string) (TokenDetails, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	token, ok := registry.symbols[symbol]
+	if !ok {
+		return TokenDetails{}, ErrUnsupportedToken
+	}
+	return registry.tokens[token], nil
+}
+
+// Tokens returns the TokenDetails of all registered Tokens, ordered by Token.
+func (registry *TokenRegistry) Tokens() []TokenDetails {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	tokens := make([]TokenDetails, 0, len(registry.tokens))
+	for _, details := range registry.tokens {
+		tokens = append(tokens, details)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Token < tokens[j].Token
+	})
+	return tokens
+}
+
+// Pairs returns all registered pairs of Tokens, in ascending order.
+func (registry *TokenRegistry) Pairs() []Tokens {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	pairs := make([]Tokens, 0, len(registry.pairs))
+	for tokens := range registry.pairs {
+		pairs = append(pairs, tokens)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i] < pairs[j]
+	})
+	return pairs
+}
+
+// IsSupported returns true if the Tokens are registered as a pair.
+func (registry *TokenRegistry) IsSupported(tokens Tokens) bool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	_, ok := registry.pairs[tokens]
+	return ok
+}
+
+// ParseTokens returns the Tokens written as the symbol of the non-priority
+// Token and the symbol of the priority Token, separated by a dash. The Tokens
+// do not need to be registered as a pair.
+func (registry *TokenRegistry) ParseTokens(pair string) (Tokens, error) {
+	symbols := strings.Split(pair, "-")
+	if len(symbols) != 2 {
+		return 0, ErrMalformedTokens
+	}
+	nonPriority, err := registry.TokenBySymbol(symbols[0])
+	if err != nil {
+		return 0, err
+	}
+	priority, err := registry.TokenBySymbol(symbols[1])
+	if err != nil {
+		return 0, err
+	}
+	return NewTokens(nonPriority.Token, priority.Token), nil
+}
+
+// Addresses returns a map from the symbol of each registered ERC20 token to
+// its address.
+func (registry *TokenRegistry) Addresses() map[string]string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	addresses := map[string]string{}
+	for _, details := range registry.tokens {
+		if details.Address != "" {
+			addresses[details.Symbol] = details.Address
+		}
+	}
+	return addresses
+}
+
+var defaultTokenRegistryMu = new(sync.RWMutex)
+var defaultTokenRegistry = NewDefaultTokenRegistry()
+
+// SetDefaultTokenRegistry sets the TokenRegistry used to describe Tokens. By
+// default, the TokenRegistry returned by NewDefaultTokenRegistry is used.
+func SetDefaultTokenRegistry(registry *TokenRegistry) {
+	defaultTokenRegistryMu.Lock()
+	defer defaultTokenRegistryMu.Unlock()
+	defaultTokenRegistry = registry
+}
+
+// DefaultTokenRegistry returns the TokenRegistry used to describe Tokens.
+func DefaultTokenRegistry() *TokenRegistry {
+	defaultTokenRegistryMu.RLock()
+	defer defaultTokenRegistryMu.RUnlock()
+	return defaultTokenRegistry
+}
@@ -0,0 +1,124 @@
+package order_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/order"
+)
+
+var _ = Describe("Token registry", func() {
+
+	const TokenABC = Token(65539)
+
+	Context("when using the default token registry", func() {
+
+		It("should support the default pairs", func() {
+			registry := NewDefaultTokenRegistry()
+			Expect(registry.Pairs()).Should(Equal([]Tokens{TokensBTCETH, TokensETHDGX, TokensETHTUSD, TokensETHREN, TokensETHZRX, TokensETHOMG}))
+			for _, tokens := range registry.Pairs() {
+				Expect(registry.IsSupported(tokens)).Should(BeTrue())
+			}
+			Expect(registry.IsSupported(NewTokens(TokenREN, TokenETH))).Should(BeFalse())
+		})
+
+		It("should return the details of the default tokens", func() {
+			registry := NewDefaultTokenRegistry()
+			details, err := registry.Token(TokenDGX)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(details.Symbol).Should(Equal("DGX"))
+			Expect(details.Decimals).Should(Equal(uint8(9)))
+
+			details, err = registry.TokenBySymbol("BTC")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(details.Token).Should(Equal(TokenBTC))
+
+			_, err = registry.Token(TokenABC)
+			Expect(err).Should(Equal(ErrUnsupportedToken))
+		})
+	})
+
+	Context("when registering tokens", func() {
+
+		var registry *TokenRegistry
+
+		BeforeEach(func() {
+			registry = NewDefaultTokenRegistry()
+			registry.RegisterToken(TokenDetails{Token: TokenABC, Symbol: "ABC", Address: "0xabc", Decimals: 12})
+		})
+
+		It("should support new pairs once they are registered", func() {
+			tokens := NewTokens(TokenETH, TokenABC)
+			Expect(tokens.PriorityToken()).Should(Equal(TokenABC))
+			Expect(tokens.NonPriorityToken()).Should(Equal(TokenETH))
+			Expect(registry.IsSupported(tokens)).Should(BeFalse())
+
+			Expect(registry.RegisterPair(tokens)).ShouldNot(HaveOccurred())
+			Expect(registry.IsSupported(tokens)).Should(BeTrue())
+		})
+
+		It("should not register pairs with unregistered tokens", func() {
+			Expect(registry.RegisterPair(NewTokens(TokenETH, Token(1000)))).Should(Equal(ErrUnsupportedToken))
+		})
+
+		It("should remove pairs when a token is deregistered", func() {
+			tokens := NewTokens(TokenETH, TokenABC)
+			Expect(registry.RegisterPair(tokens)).ShouldNot(HaveOccurred())
+
+			registry.DeregisterToken(TokenABC)
+			Expect(registry.IsSupported(tokens)).Should(BeFalse())
+			_, err := registry.TokenBySymbol("ABC")
+			Expect(err).Should(Equal(ErrUnsupportedToken))
+			Expect(registry.IsSupported(TokensETHREN)).Should(BeTrue())
+		})
+
+		It("should parse pairs using the symbols of the tokens", func() {
+			tokens, err := registry.ParseTokens("ETH-ABC")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(tokens).Should(Equal(NewTokens(TokenETH, TokenABC)))
+
+			_, err = registry.ParseTokens("ETH")
+			Expect(err).Should(Equal(ErrMalformedTokens))
+			_, err = registry.ParseTokens("ETH-XYZ")
+			Expect(err).Should(Equal(ErrUnsupportedToken))
+		})
+
+		It("should return the addresses of ERC20 tokens", func() {
+			Expect(registry.Addresses()).Should(Equal(map[string]string{"ABC": "0xabc"}))
+		})
+
+		It("should describe new pairs when it is the default token registry", func() {
+			tokens := NewTokens(TokenETH, TokenABC)
+			Expect(registry.RegisterPair(tokens)).ShouldNot(HaveOccurred())
+
+			defaultRegistry := DefaultTokenRegistry()
+			SetDefaultTokenRegistry(registry)
+			defer SetDefaultTokenRegistry(defaultRegistry)
+
+			Expect(TokenABC.String()).Should(Equal("ABC"))
+			Expect(tokens.String()).Should(Equal("ETH-ABC"))
+		})
+	})
+
+	Context("when the token registry is configured using JSON", func() {
+
+		It("should register the tokens and pairs", func() {
+			options := TokenRegistryOptions{}
+			Expect(json.Unmarshal([]byte(`{"tokens":[{"token":1,"symbol":"ETH","decimals":18},{"token":65539,"symbol":"ABC","address":"0xabc","decimals":12}],"pairs":["ETH-ABC"]}`), &options)).ShouldNot(HaveOccurred())
+
+			registry, err := NewTokenRegistryFromOptions(options)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(registry.Tokens()).Should(HaveLen(2))
+			Expect(registry.Pairs()).Should(Equal([]Tokens{NewTokens(TokenETH, TokenABC)}))
+		})
+
+		It("should return an error for pairs with undefined tokens", func() {
+			_, err := NewTokenRegistryFromOptions(TokenRegistryOptions{
+				Tokens: []TokenDetails{{Token: TokenETH, Symbol: "ETH", Decimals: 18}},
+				Pairs:  []string{"ETH-ABC"},
+			})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
@@ -1,9 +1,11 @@
 package orderbook
 
 import (
-	"log"
+	"fmt"
+	"time"
 
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/registry"
 )
@@ -14,11 +16,16 @@ type Aggregator interface {
 
 	// InsertOrder status into the Aggregator. Returns a Notification
 	// if the respective order fragment has already been inserted, and the
-	// order was inserted with the open status.
+	// order was inserted with the open status. A NotificationExpireOrder is
+	// returned, and the order is dropped, if the order fragment has expired.
 	InsertOrder(orderID order.ID, orderStatus order.Status, trader string, priority uint) (Notification, error)
 
 	// InsertOrderFragment into the Aggregator. Returns a Notification
 	// if the respective order is currently inserted with the open status.
+	// Order fragments that cannot be verified against the threshold of the
+	// pod are rejected with an error.
+	// Expired order fragments are dropped, and a NotificationExpireOrder is
+	// returned.
 	InsertOrderFragment(orderFragment order.Fragment) (Notification, error)
 }
 
@@ -53,10 +60,10 @@ func (agg *aggregator) InsertOrder(orderID order.ID, orderStatus order.Status, t
 	if orderStatus != order.Open {
 		// The order is no longer open
 		if err := agg.orderStore.DeleteOrder(orderID); err != nil {
-			log.Printf("[error] (sync) cannot delete order: %v", err)
+			logger.Sync(logger.LevelError, fmt.Sprintf("cannot delete order: %v", err))
 		}
 		if err := agg.orderFragmentStore.DeleteOrderFragment(orderID); err != nil {
-			log.Printf("[error] (sync) cannot delete order fragment: %v", err)
+			logger.Sync(logger.LevelError, fmt.Sprintf("cannot delete order fragment: %v", err))
 		}
 		return nil, nil
 	}
@@ -73,8 +80,11 @@ func (agg *aggregator) InsertOrder(orderID order.ID, orderStatus order.Status, t
 		}
 		return nil, err
 	}
+	if orderFragment.IsExpired(time.Now()) {
+		return agg.expireOrder(orderID), nil
+	}
 	// Produce notification
-	log.Printf("[info] (sync) order = %v", orderID)
+	logger.Sync(logger.LevelInfo, fmt.Sprintf("order = %v", orderID))
 	return NotificationOpenOrder{
 		OrderID:       orderID,
 		OrderFragment: orderFragment,
@@ -88,6 +98,16 @@ func (agg *aggregator) InsertOrderFragment(orderFragment order.Fragment) (Notifi
 	if !agg.isInPathOfEpoch(orderFragment.OrderID) {
 		return nil, nil
 	}
+	// Reject order fragments that are inconsistent with the other order
+	// fragments of the order, or that cannot be opened by the pod, before
+	// they can be used in a computation
+	if err := orderFragment.Verify(int64(agg.pod.Threshold())); err != nil {
+		logger.Stream(logger.LevelWarn, fmt.Sprintf("cannot verify order fragment %v of order %v: %v", orderFragment.ID, orderFragment.OrderID, err))
+		return nil, err
+	}
+	if orderFragment.IsExpired(time.Now()) {
+		return agg.expireOrder(orderFragment.OrderID), nil
+	}
 
 	// Store the order fragment
 	if err := agg.orderFragmentStore.PutOrderFragment(orderFragment); err != nil {
@@ -105,15 +125,15 @@ func (agg *aggregator) InsertOrderFragment(orderFragment order.Fragment) (Notifi
 	if orderStatus != order.Open {
 		// The order was found but is no longer open
 		if err := agg.orderStore.DeleteOrder(orderFragment.OrderID); err != nil {
-			log.Printf("[error] (sync) cannot delete order: %v", err)
+			logger.Sync(logger.LevelError, fmt.Sprintf("cannot delete order: %v", err))
 		}
 		if err := agg.orderFragmentStore.DeleteOrderFragment(orderFragment.OrderID); err != nil {
-			log.Printf("[error] (sync) cannot delete order fragment: %v", err)
+			logger.Sync(logger.LevelError, fmt.Sprintf("cannot delete order fragment: %v", err))
 		}
 		return nil, nil
 	}
 	// Produce notification
-	log.Printf("[sync] order = %v", orderFragment.OrderID)
+	logger.Sync(logger.LevelInfo, fmt.Sprintf("order = %v", orderFragment.OrderID))
 	return NotificationOpenOrder{
 		OrderID:       orderFragment.OrderID,
 		OrderFragment: orderFragment,
@@ -122,6 +142,19 @@ func (agg *aggregator) InsertOrderFragment(orderFragment order.Fragment) (Notifi
 	}, nil
 }
 
+// expireOrder deletes an order, and its order fragment, and returns a
+// NotificationExpireOrder for it.
+func (agg *aggregator) expireOrder(orderID order.ID) Notification {
+	logger.Sync(logger.LevelInfo, fmt.Sprintf("expired = %v", orderID))
+	if err := agg.orderStore.DeleteOrder(orderID); err != nil {
+		logger.Sync(logger.LevelError, fmt.Sprintf("cannot delete order: %v", err))
+	}
+	if err := agg.orderFragmentStore.DeleteOrderFragment(orderID); err != nil {
+		logger.Sync(logger.LevelError, fmt.Sprintf("cannot delete order fragment: %v", err))
+	}
+	return NotificationExpireOrder{OrderID: orderID}
+}
+
 func (agg *aggregator) isInPathOfEpoch(orderID order.ID) bool {
 	if agg.pod == nil || agg.epoch.Pods == nil || len(agg.epoch.Pods) == 0 {
 		return false
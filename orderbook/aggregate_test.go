@@ -1 +1,83 @@
 package orderbook_test
+
+import (
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/orderbook"
+
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Aggregator", func() {
+
+	var storer *leveldb.Store
+	var aggregator Aggregator
+
+	BeforeEach(func() {
+		var err error
+		storer, err = leveldb.NewStore("./data.out", time.Hour)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		addr, epoch, err := testutils.RandomEpoch(0)
+		Expect(err).ShouldNot(HaveOccurred())
+		aggregator = NewAggregator(addr, epoch, storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore())
+	})
+
+	AfterEach(func() {
+		storer.Release()
+		os.RemoveAll("./data.out")
+	})
+
+	Context("when inserting orders and order fragments", func() {
+
+		It("should notify the opening of the order once both have been inserted", func() {
+			ord := testutils.RandomOrder()
+			fragments, err := ord.Split(1, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			notification, err := aggregator.InsertOrder(ord.ID, order.Open, "trader", 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notification).Should(BeNil())
+
+			notification, err = aggregator.InsertOrderFragment(fragments[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notification).Should(Equal(NotificationOpenOrder{OrderID: ord.ID, OrderFragment: fragments[0], Trader: "trader", Priority: 1}))
+		})
+
+		It("should drop order fragments that have expired", func() {
+			ord := order.NewOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			fragments, err := ord.Split(1, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = aggregator.InsertOrder(ord.ID, order.Open, "trader", 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			notification, err := aggregator.InsertOrderFragment(fragments[0])
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notification).Should(Equal(NotificationExpireOrder{OrderID: ord.ID}))
+
+			_, err = storer.OrderbookOrderFragmentStore().OrderFragment(ord.ID)
+			Expect(err).Should(Equal(ErrOrderFragmentNotFound))
+			_, _, _, err = storer.OrderbookOrderStore().Order(ord.ID)
+			Expect(err).Should(Equal(ErrOrderNotFound))
+		})
+
+		It("should drop orders when the stored order fragment has expired", func() {
+			ord := order.NewOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			fragments, err := ord.Split(5, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(storer.OrderbookOrderFragmentStore().PutOrderFragment(fragments[0])).ShouldNot(HaveOccurred())
+
+			notification, err := aggregator.InsertOrder(ord.ID, order.Open, "trader", 1)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(notification).Should(Equal(NotificationExpireOrder{OrderID: ord.ID}))
+
+			_, _, _, err = storer.OrderbookOrderStore().Order(ord.ID)
+			Expect(err).Should(Equal(ErrOrderNotFound))
+		})
+	})
+})
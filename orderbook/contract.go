@@ -20,6 +20,9 @@ type ContractBinder interface {
 	// Status of an order.ID.
 	Status(orderID order.ID) (order.Status, error)
 
+	// Trader that opened an order.ID.
+	Trader(orderID order.ID) (string, error)
+
 	// MinimumEpochInterval returns the minimum number of blocks between
 	// epochs.
 	MinimumEpochInterval() (*big.Int, error)
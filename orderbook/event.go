@@ -0,0 +1,192 @@
+package orderbook
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/logger"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// EventBufferLimit is the number of Events that can be buffered for a
+// subscriber before Events are dropped.
+const EventBufferLimit = 16
+
+// An EventType describes the change to an order.Order that produced an Event.
+type EventType uint8
+
+// Values for an EventType.
+const (
+	EventNil EventType = iota
+	EventReceived
+	EventOpen
+	EventMatched
+	EventConfirmed
+	EventSettled
+	EventCanceled
+	EventExpired
+)
+
+// String implements the Stringer interface.
+func (ty EventType) String() string {
+	switch ty {
+	case EventNil:
+		return "nil"
+	case EventReceived:
+		return "received"
+	case EventOpen:
+		return "open"
+	case EventMatched:
+		return "matched"
+	case EventConfirmed:
+		return "confirmed"
+	case EventSettled:
+		return "settled"
+	case EventCanceled:
+		return "canceled"
+	case EventExpired:
+		return "expired"
+	default:
+		return "unexpected event type"
+	}
+}
+
+// An Event is a change to an order.Order that is observed by a darknode. It
+// is signed by the darknode that observed it.
+type Event struct {
+	OrderID   order.ID
+	Type      EventType
+	Timestamp int64
+	Signature []byte
+
+	// Trader that opened the order.Order. It is only set for EventOpen.
+	Trader string
+}
+
+// NewEvent returns an unsigned Event, timestamped with the current time.
+func NewEvent(orderID order.ID, ty EventType) Event {
+	return Event{
+		OrderID:   orderID,
+		Type:      ty,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// NewEventFromNotification returns the Event for a Notification. It returns
+// false if the Notification does not produce an Event.
+func NewEventFromNotification(notification Notification) (Event, bool) {
+	switch n := notification.(type) {
+	case NotificationOpenOrder:
+		event := NewEvent(n.OrderID, EventOpen)
+		event.Trader = n.Trader
+		return event, true
+	case NotificationConfirmOrder:
+		return NewEvent(n.OrderID, EventConfirmed), true
+	case NotificationCancelOrder:
+		return NewEvent(n.OrderID, EventCanceled), true
+	case NotificationExpireOrder:
+		return NewEvent(n.OrderID, EventExpired), true
+	default:
+		return Event{}, false
+	}
+}
+
+// Hash returns the Keccak256 hash of the Event. This hash is signed by the
+// darknode that observed the Event.
+func (event Event) Hash() []byte {
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, uint64(event.Timestamp))
+	return crypto.Keccak256([]byte("Republic Protocol: order event: "), event.OrderID[:], []byte{byte(event.Type)}, []byte(event.Trader), timestamp)
+}
+
+// A Publisher publishes Events to all subscribers of the order.Order.
+type Publisher interface {
+	Publish(event Event)
+}
+
+// A Broadcaster signs the Events that are published to it, and broadcasts
+// them to the subscribers of the order.Order. Subscribers that are not
+// reading their Events fast enough will miss Events.
+type Broadcaster struct {
+	signer crypto.Signer
+
+	mu          *sync.RWMutex
+	subscribers map[order.ID]map[chan Event]struct{}
+}
+
+// NewBroadcaster returns a Broadcaster that uses a crypto.Signer to sign
+// Events.
+func NewBroadcaster(signer crypto.Signer) *Broadcaster {
+	return &Broadcaster{
+		signer: signer,
+
+		mu:          new(sync.RWMutex),
+		subscribers: map[order.ID]map[chan Event]struct{}{},
+	}
+}
+
+// Publish implements the Publisher interface.
+func (broadcaster *Broadcaster) Publish(event Event) {
+	broadcaster.mu.RLock()
+	defer broadcaster.mu.RUnlock()
+
+	subscribers := broadcaster.subscribers[event.OrderID]
+	if len(subscribers) == 0 {
+		return
+	}
+
+	signature, err := broadcaster.signer.Sign(event.Hash())
+	if err != nil {
+		logger.Stream(logger.LevelError, fmt.Sprintf("cannot sign event: %v", err))
+		return
+	}
+	event.Signature = signature
+
+	for subscriber := range subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			logger.Stream(logger.LevelWarn, fmt.Sprintf("dropped %v event for order = %v", event.Type, event.OrderID))
+		}
+	}
+}
+
+// Subscribers returns the number of subscribers to the Events of an
+// order.Order.
+func (broadcaster *Broadcaster) Subscribers(orderID order.ID) int {
+	broadcaster.mu.RLock()
+	defer broadcaster.mu.RUnlock()
+
+	return len(broadcaster.subscribers[orderID])
+}
+
+// Subscribe to the Events of an order.Order. The returned channel is closed
+// once the done channel is closed.
+func (broadcaster *Broadcaster) Subscribe(done <-chan struct{}, orderID order.ID) <-chan Event {
+	subscriber := make(chan Event, EventBufferLimit)
+
+	broadcaster.mu.Lock()
+	if _, ok := broadcaster.subscribers[orderID]; !ok {
+		broadcaster.subscribers[orderID] = map[chan Event]struct{}{}
+	}
+	broadcaster.subscribers[orderID][subscriber] = struct{}{}
+	broadcaster.mu.Unlock()
+
+	go func() {
+		<-done
+
+		broadcaster.mu.Lock()
+		defer broadcaster.mu.Unlock()
+
+		delete(broadcaster.subscribers[orderID], subscriber)
+		if len(broadcaster.subscribers[orderID]) == 0 {
+			delete(broadcaster.subscribers, orderID)
+		}
+		close(subscriber)
+	}()
+
+	return subscriber
+}
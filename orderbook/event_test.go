@@ -0,0 +1,94 @@
+package orderbook_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/orderbook"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Events", func() {
+
+	var key crypto.EcdsaKey
+	var broadcaster *Broadcaster
+	var orderID order.ID
+
+	BeforeEach(func() {
+		var err error
+		key, err = crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		broadcaster = NewBroadcaster(&key)
+		orderID = order.ID(testutils.Random32Bytes())
+	})
+
+	Context("when publishing events", func() {
+
+		It("should send signed events to all subscribers of the order", func() {
+			done := make(chan struct{})
+			defer close(done)
+
+			subscribers := []<-chan Event{
+				broadcaster.Subscribe(done, orderID),
+				broadcaster.Subscribe(done, orderID),
+			}
+			other := broadcaster.Subscribe(done, order.ID(testutils.Random32Bytes()))
+			broadcaster.Publish(NewEvent(orderID, EventMatched))
+
+			for _, subscriber := range subscribers {
+				event := <-subscriber
+				Expect(event.OrderID).Should(Equal(orderID))
+				Expect(event.Type).Should(Equal(EventMatched))
+				Expect(crypto.NewEcdsaVerifier(key.Address()).Verify(event.Hash(), event.Signature)).ShouldNot(HaveOccurred())
+			}
+			Consistently(other).ShouldNot(Receive())
+		})
+
+		It("should drop events for subscribers that are not reading", func() {
+			done := make(chan struct{})
+			defer close(done)
+
+			subscriber := broadcaster.Subscribe(done, orderID)
+			for i := 0; i < 2*EventBufferLimit; i++ {
+				broadcaster.Publish(NewEvent(orderID, EventReceived))
+			}
+			Expect(subscriber).Should(HaveLen(EventBufferLimit))
+		})
+
+		It("should close subscriptions once they are done", func() {
+			done := make(chan struct{})
+			subscriber := broadcaster.Subscribe(done, orderID)
+			Expect(broadcaster.Subscribers(orderID)).Should(Equal(1))
+			close(done)
+
+			Eventually(subscriber).Should(BeClosed())
+			Expect(broadcaster.Subscribers(orderID)).Should(Equal(0))
+			broadcaster.Publish(NewEvent(orderID, EventReceived))
+		})
+	})
+
+	Context("when converting notifications", func() {
+
+		It("should produce events for changes to orders", func() {
+			event, ok := NewEventFromNotification(NotificationOpenOrder{OrderID: orderID, Trader: "trader"})
+			Expect(ok).Should(BeTrue())
+			Expect(event.Type).Should(Equal(EventOpen))
+			Expect(event.Trader).Should(Equal("trader"))
+
+			event, ok = NewEventFromNotification(NotificationConfirmOrder{OrderID: orderID})
+			Expect(ok).Should(BeTrue())
+			Expect(event.Type).Should(Equal(EventConfirmed))
+
+			event, ok = NewEventFromNotification(NotificationCancelOrder{OrderID: orderID})
+			Expect(ok).Should(BeTrue())
+			Expect(event.Type).Should(Equal(EventCanceled))
+
+			event, ok = NewEventFromNotification(NotificationExpireOrder{OrderID: orderID})
+			Expect(ok).Should(BeTrue())
+			Expect(event.Type).Should(Equal(EventExpired))
+			Expect(event.Type.String()).Should(Equal("expired"))
+		})
+	})
+})
@@ -56,3 +56,13 @@ type NotificationCancelOrder struct {
 
 // IsNotification implements the Notification interface.
 func (notification NotificationCancelOrder) IsNotification() {}
+
+// NotificationExpireOrder is used to signal the expiry of an order.ID. This
+// happens when the expiry of an order.Order passes before it has been
+// confirmed.
+type NotificationExpireOrder struct {
+	OrderID order.ID
+}
+
+// IsNotification implements the Notification interface.
+func (notification NotificationExpireOrder) IsNotification() {}
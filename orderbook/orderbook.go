@@ -3,7 +3,7 @@ package orderbook
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"sync"
 	"time"
 
@@ -26,6 +26,22 @@ type Client interface {
 	// identity.MultiAddress. The order.EncryptedFragment will be stored by the
 	// Server.
 	OpenOrder(context.Context, identity.MultiAddress, order.EncryptedFragment) error
+
+	// OpenOrderBatch by sending many order.EncryptedFragments to an
+	// identity.MultiAddress in as few calls as possible. An error is returned
+	// for each order.EncryptedFragment, in the same order, and is nil if the
+	// order.EncryptedFragment was accepted by the Server. A non-nil error is
+	// returned if the batch could not be sent.
+	OpenOrderBatch(context.Context, identity.MultiAddress, []order.EncryptedFragment) ([]error, error)
+
+	// QueryOrder sends a signed Query to an identity.MultiAddress, and returns
+	// the OrderState that is known by the Querier.
+	QueryOrder(context.Context, identity.MultiAddress, Query) (OrderState, error)
+
+	// SubscribeOrder sends a signed Query to an identity.MultiAddress, and
+	// returns the Events of the order.Order until the context is done. Events
+	// that are not signed by the identity.MultiAddress are not returned.
+	SubscribeOrder(context.Context, identity.MultiAddress, Query) (<-chan Event, <-chan error)
 }
 
 // Server for opening order.EncryptedFragments. This RPC should only be called
@@ -42,6 +58,7 @@ type Server interface {
 // changes, a Notification is produced directly from the change.
 type Orderbook interface {
 	Server
+	Querier
 
 	// Sync status changes from Ethereum, receive order.EncryptedFragments from
 	// traders, and produce Notifications. Stop once the done channel is
@@ -101,6 +118,14 @@ func (orderbook *orderbook) OpenOrder(ctx context.Context, encryptedOrderFragmen
 	if encryptedOrderFragment.IsNil() {
 		return ErrOrderFragmentIsNil
 	}
+	if !encryptedOrderFragment.Version.IsSupported() {
+		return order.ErrUnsupportedFragmentVersion
+	}
+	// Reject order fragments that have expired before spending any effort on
+	// decrypting them
+	if encryptedOrderFragment.IsExpired(time.Now()) {
+		return order.ErrOrderExpired
+	}
 
 	orderFragment, err := encryptedOrderFragment.Decrypt(orderbook.rsaKey.PrivateKey)
 	if err != nil {
@@ -114,6 +139,52 @@ func (orderbook *orderbook) OpenOrder(ctx context.Context, encryptedOrderFragmen
 	return orderbook.routeOrderFragment(ctx.Done(), orderFragment)
 }
 
+// Query implements the Querier interface.
+func (orderbook *orderbook) Query(ctx context.Context, query Query) (OrderState, error) {
+	state := OrderState{OrderID: query.OrderID}
+
+	orderStatus, trader, _, err := orderbook.orderStore.Order(query.OrderID)
+	if err != nil {
+		if err != ErrOrderNotFound {
+			return state, err
+		}
+		// The order has not been synchronised, or is no longer open, so the
+		// status must be read from Ethereum
+		orderStatus, err = orderbook.contractBinder.Status(query.OrderID)
+		if err != nil && err != ErrOrderNotFound {
+			return state, err
+		}
+		if orderStatus != order.Nil {
+			if trader, err = orderbook.contractBinder.Trader(query.OrderID); err != nil {
+				return state, err
+			}
+		}
+	}
+	if orderStatus == order.Nil {
+		// There is no trader that can authenticate the Query, so nothing is
+		// returned about order fragments that have been received
+		if err := query.VerifyTimestamp(); err != nil {
+			return state, err
+		}
+		return state, nil
+	}
+	if err := query.Verify(trader); err != nil {
+		return state, err
+	}
+	state.Trader = trader
+	state.Status = orderStatus
+
+	orderFragment, err := orderbook.orderFragmentStore.OrderFragment(query.OrderID)
+	if err != nil {
+		if err != ErrOrderFragmentNotFound {
+			return state, err
+		}
+		return state, nil
+	}
+	state.EpochDepths = append(state.EpochDepths, orderFragment.EpochDepth)
+	return state, nil
+}
+
 // Sync implements the Orderbook interface.
 func (orderbook *orderbook) Sync(done <-chan struct{}) (<-chan Notification, <-chan error) {
 	notifications := make(chan Notification, 128)
@@ -251,7 +322,7 @@ func (orderbook *orderbook) routeOrderFragment(done <-chan struct{}, orderFragme
 			}
 			return orderbook.aggPrev.InsertOrderFragment(orderFragment)
 		default:
-			log.Printf("[error] (sync) unexpected depth = %v", orderFragment.EpochDepth)
+			logger.Sync(logger.LevelError, fmt.Sprintf("unexpected depth = %v", orderFragment.EpochDepth))
 			return nil, nil
 		}
 	}()
@@ -19,6 +19,7 @@ import (
 	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/order"
 	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -86,7 +87,7 @@ var _ = Describe("Orderbook", func() {
 				ord := testutils.RandomOrder()
 				err = storer.OrderbookOrderStore().PutOrder(ord.ID, order.Open, "", uint(i))
 				Expect(err).ShouldNot(HaveOccurred())
-				fragments, err := ord.Split(5, 4)
+				fragments, err := ord.Split(1, 1)
 				encryptedOrderFragments[i], err = fragments[0].Encrypt(rsaKey.PublicKey)
 				Expect(err).ShouldNot(HaveOccurred())
 			}
@@ -106,6 +107,112 @@ var _ = Describe("Orderbook", func() {
 			countMu.Unlock()
 		})
 
+		It("should reject order fragments that cannot be verified", func() {
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			storer, err := leveldb.NewStore("./data.out", time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer func() {
+				os.RemoveAll("./data.out")
+			}()
+			addr, epoch, err := testutils.RandomEpoch(0)
+			Expect(err).ShouldNot(HaveOccurred())
+			orderbook := NewOrderbook(addr, rsaKey, storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), testutils.NewMockContractBinder(), time.Hour, 100)
+			orderbook.OnChangeEpoch(epoch)
+
+			ord := testutils.RandomOrder()
+			fragments, err := ord.Split(1, 1)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Tamper with the share of the price
+			inconsistentFragment := fragments[0]
+			inconsistentFragment.Price.Co.Value = (inconsistentFragment.Price.Co.Value + 1) % shamir.Prime
+			encryptedOrderFragment, err := inconsistentFragment.Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orderbook.OpenOrder(context.Background(), encryptedOrderFragment)).Should(Equal(shamir.ErrInconsistentShare))
+
+			// Remove the verification
+			unverifiableFragment := fragments[0]
+			unverifiableFragment.Verification = order.FragmentVerification{}
+			encryptedOrderFragment, err = unverifiableFragment.Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orderbook.OpenOrder(context.Background(), encryptedOrderFragment)).Should(Equal(order.ErrUnverifiableFragment))
+
+			_, err = storer.OrderbookOrderFragmentStore().OrderFragment(ord.ID)
+			Expect(err).Should(Equal(ErrOrderFragmentNotFound))
+		})
+
+		It("should reject order fragments that were split with a different threshold than the pod", func() {
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			storer, err := leveldb.NewStore("./data.out", time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer func() {
+				os.RemoveAll("./data.out")
+			}()
+			addr, epoch, err := testutils.RandomEpoch(0)
+			Expect(err).ShouldNot(HaveOccurred())
+			orderbook := NewOrderbook(addr, rsaKey, storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), testutils.NewMockContractBinder(), time.Hour, 100)
+			orderbook.OnChangeEpoch(epoch)
+
+			// The pod has a threshold of 1, so the trader must share the order
+			// using polynomials of degree 0
+			ord := testutils.RandomOrder()
+			fragments, err := ord.Split(5, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(fragments[0].Verify(4)).ShouldNot(HaveOccurred())
+			encryptedOrderFragment, err := fragments[0].Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orderbook.OpenOrder(context.Background(), encryptedOrderFragment)).Should(Equal(order.ErrUnexpectedThreshold))
+
+			_, err = storer.OrderbookOrderFragmentStore().OrderFragment(ord.ID)
+			Expect(err).Should(Equal(ErrOrderFragmentNotFound))
+		})
+
+		It("should reject order fragments with unsupported versions", func() {
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			storer, err := leveldb.NewStore("./data.out", time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer func() {
+				os.RemoveAll("./data.out")
+			}()
+			addr, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			orderbook := NewOrderbook(addr, rsaKey, storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), testutils.NewMockContractBinder(), time.Hour, 100)
+
+			ord := testutils.RandomOrder()
+			fragments, err := ord.Split(5, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			encryptedOrderFragment, err := fragments[0].Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			encryptedOrderFragment.Version = order.FragmentVersion(2)
+			Expect(orderbook.OpenOrder(context.Background(), encryptedOrderFragment)).Should(Equal(order.ErrUnsupportedFragmentVersion))
+		})
+
+		It("should reject order fragments that have expired", func() {
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			storer, err := leveldb.NewStore("./data.out", time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			defer func() {
+				os.RemoveAll("./data.out")
+			}()
+			addr, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			orderbook := NewOrderbook(addr, rsaKey, storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), testutils.NewMockContractBinder(), time.Hour, 100)
+
+			ord := order.NewOrder(order.ParityBuy, order.TypeLimit, time.Now().Add(-time.Minute), order.SettlementRenEx, order.TokensETHREN, 1, 1, 1, 1)
+			fragments, err := ord.Split(5, 4)
+			Expect(err).ShouldNot(HaveOccurred())
+			encryptedOrderFragment, err := fragments[0].Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(orderbook.OpenOrder(context.Background(), encryptedOrderFragment)).Should(Equal(order.ErrOrderExpired))
+
+			_, err = storer.OrderbookOrderFragmentStore().OrderFragment(ord.ID)
+			Expect(err).Should(Equal(ErrOrderFragmentNotFound))
+		})
+
 		It("should be able to sync with the ledger by the syncer", func() {
 			// Generate new RSA key
 			rsaKey, err := crypto.RandomRsaKey()
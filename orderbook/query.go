@@ -0,0 +1,125 @@
+package orderbook
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/order"
+)
+
+// ErrUnauthorizedQuery is returned when a Query is not signed by the trader
+// that opened the order.Order.
+var ErrUnauthorizedQuery = errors.New("unauthorized query")
+
+// ErrQueryExpired is returned when a Query was signed more than MaxQueryAge
+// ago, or was signed in the future.
+var ErrQueryExpired = errors.New("query expired")
+
+// MaxQueryAge is the maximum duration between signing a Query and the Query
+// being answered. It prevents a Query from being replayed once it has been
+// observed.
+const MaxQueryAge = time.Minute
+
+// A Query for the OrderState of an order.Order. It must be signed by the
+// trader that opened the order.Order.
+type Query struct {
+	OrderID   order.ID
+	Timestamp int64
+	Signature []byte
+}
+
+// NewQuery returns an unsigned Query for the OrderState of an order.Order,
+// timestamped with the current time.
+func NewQuery(orderID order.ID) Query {
+	return Query{
+		OrderID:   orderID,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// Hash returns the Keccak256 hash of the Query. This hash is signed by the
+// trader.
+func (query Query) Hash() []byte {
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, uint64(query.Timestamp))
+	return crypto.Keccak256([]byte("Republic Protocol: query order: "), query.OrderID[:], timestamp)
+}
+
+// Sign the Query using a crypto.Signer.
+func (query *Query) Sign(signer crypto.Signer) error {
+	signature, err := signer.Sign(query.Hash())
+	if err != nil {
+		return err
+	}
+	query.Signature = signature
+	return nil
+}
+
+// Verify that the Query has not expired, and that it was signed by the
+// trader. The trader is the hex encoded Ethereum address that opened the
+// order.Order.
+func (query Query) Verify(trader string) error {
+	if err := query.VerifyTimestamp(); err != nil {
+		return err
+	}
+	return query.VerifyTrader(trader)
+}
+
+// VerifyTimestamp verifies that the Query was signed within the MaxQueryAge.
+// It is the only verification possible for an order.Order that has not been
+// opened, because there is no trader, so nothing is returned for such a
+// Query.
+func (query Query) VerifyTimestamp() error {
+	signedAt := time.Unix(query.Timestamp, 0)
+	if time.Since(signedAt) > MaxQueryAge || time.Until(signedAt) > MaxQueryAge {
+		return ErrQueryExpired
+	}
+	return nil
+}
+
+// VerifyTrader verifies that the Query was signed by the trader, regardless
+// of when it was signed.
+func (query Query) VerifyTrader(trader string) error {
+	if trader == "" {
+		return ErrUnauthorizedQuery
+	}
+	if err := crypto.NewEthereumVerifier(trader).Verify(query.Hash(), query.Signature); err != nil {
+		return ErrUnauthorizedQuery
+	}
+	return nil
+}
+
+// OrderState is everything that is known about an order.Order, and its
+// order.Fragments, by a darknode.
+type OrderState struct {
+	OrderID order.ID
+
+	// Trader that opened the order.Order on Ethereum. It is empty if the
+	// order.Order has not been opened.
+	Trader string
+
+	// Status of the order.Order on Ethereum. It is order.Nil if the
+	// order.Order has not been opened.
+	Status order.Status
+
+	// EpochDepths for which an order.Fragment has been received. It is empty
+	// if the order.Order has not been opened.
+	EpochDepths []order.FragmentEpochDepth
+
+	// Matched is true if a match has been found for the order.Order.
+	Matched bool
+
+	// Settled is true if a match for the order.Order has been settled.
+	Settled bool
+}
+
+// A Querier answers a Query for the OrderState of an order.Order. A Query
+// must be signed by the trader of the order.Order. If the order.Order has not
+// been opened on Ethereum, there is no trader to verify, so nothing but the
+// order.ID is returned.
+type Querier interface {
+	Query(ctx context.Context, query Query) (OrderState, error)
+}
@@ -0,0 +1,133 @@
+package orderbook_test
+
+import (
+	"context"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/orderbook"
+
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/order"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Queries", func() {
+
+	var traderKey crypto.EcdsaKey
+	var trader string
+	var orderID order.ID
+
+	BeforeEach(func() {
+		var err error
+		traderKey, err = crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+		trader = ethCrypto.PubkeyToAddress(traderKey.PublicKey).Hex()
+		orderID = order.ID(testutils.Random32Bytes())
+	})
+
+	Context("when verifying queries", func() {
+
+		It("should verify queries signed by the trader", func() {
+			query := NewQuery(orderID)
+			Expect(query.Sign(&traderKey)).ShouldNot(HaveOccurred())
+			Expect(query.Verify(trader)).ShouldNot(HaveOccurred())
+		})
+
+		It("should not verify queries signed by another trader", func() {
+			otherKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			query := NewQuery(orderID)
+			Expect(query.Sign(&otherKey)).ShouldNot(HaveOccurred())
+			Expect(query.Verify(trader)).Should(Equal(ErrUnauthorizedQuery))
+			Expect(query.Verify("")).Should(Equal(ErrUnauthorizedQuery))
+		})
+
+		It("should not verify queries for another order", func() {
+			query := NewQuery(orderID)
+			Expect(query.Sign(&traderKey)).ShouldNot(HaveOccurred())
+			query.OrderID = order.ID(testutils.Random32Bytes())
+			Expect(query.Verify(trader)).Should(Equal(ErrUnauthorizedQuery))
+		})
+
+		It("should not verify expired queries", func() {
+			query := NewQuery(orderID)
+			query.Timestamp = time.Now().Add(-2 * MaxQueryAge).Unix()
+			Expect(query.Sign(&traderKey)).ShouldNot(HaveOccurred())
+			Expect(query.Verify(trader)).Should(Equal(ErrQueryExpired))
+		})
+	})
+
+	Context("when querying an orderbook", func() {
+
+		var store *leveldb.Store
+		var orderbook Orderbook
+
+		BeforeEach(func() {
+			var err error
+			store, err = leveldb.NewStore("./data.out", time.Hour)
+			Expect(err).ShouldNot(HaveOccurred())
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			addr, _, err := testutils.RandomEpoch(0)
+			Expect(err).ShouldNot(HaveOccurred())
+			orderbook = NewOrderbook(addr, rsaKey, store.OrderbookPointerStore(), store.OrderbookOrderStore(), store.OrderbookOrderFragmentStore(), testutils.NewMockContractBinder(), time.Hour, 100)
+		})
+
+		AfterEach(func() {
+			store.Release()
+			os.RemoveAll("./data.out")
+		})
+
+		It("should not return pending order fragments", func() {
+			orderFragment := order.Fragment{OrderID: orderID, EpochDepth: 1}
+			Expect(store.OrderbookOrderFragmentStore().PutOrderFragment(orderFragment)).ShouldNot(HaveOccurred())
+
+			query := NewQuery(orderID)
+			Expect(query.Sign(&traderKey)).ShouldNot(HaveOccurred())
+			state, err := orderbook.Query(context.Background(), query)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(state).Should(Equal(OrderState{OrderID: orderID}))
+		})
+
+		It("should not return pending order fragments for expired queries", func() {
+			orderFragment := order.Fragment{OrderID: orderID, EpochDepth: 1}
+			Expect(store.OrderbookOrderFragmentStore().PutOrderFragment(orderFragment)).ShouldNot(HaveOccurred())
+
+			query := NewQuery(orderID)
+			query.Timestamp = time.Now().Add(-2 * MaxQueryAge).Unix()
+			state, err := orderbook.Query(context.Background(), query)
+			Expect(err).Should(Equal(ErrQueryExpired))
+			Expect(state.EpochDepths).Should(BeEmpty())
+		})
+
+		It("should return open orders to the trader", func() {
+			Expect(store.OrderbookOrderStore().PutOrder(orderID, order.Open, trader, 1)).ShouldNot(HaveOccurred())
+			orderFragment := order.Fragment{OrderID: orderID}
+			Expect(store.OrderbookOrderFragmentStore().PutOrderFragment(orderFragment)).ShouldNot(HaveOccurred())
+
+			query := NewQuery(orderID)
+			Expect(query.Sign(&traderKey)).ShouldNot(HaveOccurred())
+			state, err := orderbook.Query(context.Background(), query)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(state.Status).Should(Equal(order.Open))
+			Expect(state.Trader).Should(Equal(trader))
+			Expect(state.EpochDepths).Should(Equal([]order.FragmentEpochDepth{0}))
+		})
+
+		It("should not return open orders to other traders", func() {
+			Expect(store.OrderbookOrderStore().PutOrder(orderID, order.Open, trader, 1)).ShouldNot(HaveOccurred())
+
+			otherKey, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			query := NewQuery(orderID)
+			Expect(query.Sign(&otherKey)).ShouldNot(HaveOccurred())
+			_, err = orderbook.Query(context.Background(), query)
+			Expect(err).Should(Equal(ErrUnauthorizedQuery))
+		})
+	})
+})
@@ -2,8 +2,9 @@ package orderbook
 
 import (
 	"fmt"
-	"log"
+	"time"
 
+	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/order"
 )
 
@@ -63,12 +64,12 @@ func (syncer *syncer) sync(notifications *Notifications) error {
 		return fmt.Errorf("cannot load orders from contract binder: %v", err)
 	}
 	if len(orderIDs) > 0 {
-		log.Printf("[info] (sync) changed = %v", len(orderIDs))
+		logger.Sync(logger.LevelInfo, fmt.Sprintf("changed = %v", len(orderIDs)))
 	}
 
 	// Store the resulting pointer so that we do not re-sync orders next time
 	if err := syncer.pointerStore.PutPointer(pointer + Pointer(len(orderIDs))); err != nil {
-		log.Printf("[error] (sync) cannot store pointer: %v", err)
+		logger.Sync(logger.LevelError, fmt.Sprintf("cannot store pointer: %v", err))
 	}
 
 	// Logging data
@@ -78,16 +79,16 @@ func (syncer *syncer) sync(notifications *Notifications) error {
 	numUnknownOrders := 0
 	defer func() {
 		if numOpenOrders > 0 {
-			log.Printf("[info] (sync) opened = %v", numOpenOrders)
+			logger.Sync(logger.LevelInfo, fmt.Sprintf("opened = %v", numOpenOrders))
 		}
 		if numConfirmedOrders > 0 {
-			log.Printf("[info] (sync) confirmed = %v", numConfirmedOrders)
+			logger.Sync(logger.LevelInfo, fmt.Sprintf("confirmed = %v", numConfirmedOrders))
 		}
 		if numCanceledOrders > 0 {
-			log.Printf("[info] (sync) canceled = %v", numCanceledOrders)
+			logger.Sync(logger.LevelInfo, fmt.Sprintf("canceled = %v", numCanceledOrders))
 		}
 		if numUnknownOrders > 0 {
-			log.Printf("[info] (sync) unknown = %v", numUnknownOrders)
+			logger.Sync(logger.LevelInfo, fmt.Sprintf("unknown = %v", numUnknownOrders))
 		}
 	}()
 
@@ -121,7 +122,7 @@ func (syncer *syncer) resync(notifications *Notifications) error {
 
 	orders, _, traders, priorities, err := orderIter.Collect()
 	if err != nil {
-		log.Printf("[error] (resync) cannot collect orders: %v", err)
+		logger.Sync(logger.LevelError, fmt.Sprintf("resync: cannot collect orders: %v", err))
 	}
 	if len(orders) == 0 {
 		return nil
@@ -131,33 +132,24 @@ func (syncer *syncer) resync(notifications *Notifications) error {
 	numClosedOrders := 0
 	defer func() {
 		if numClosedOrders > 0 {
-			log.Printf("[info] (resync) closed = %v", numClosedOrders)
+			logger.Sync(logger.LevelInfo, fmt.Sprintf("resync: closed = %v", numClosedOrders))
 		}
 	}()
 
-	// Function for deleting order IDs from storage
-	deleteOrder := func(orderID order.ID, orderStatus order.Status) {
+	// Function for deleting order IDs from storage, and emitting the
+	// Notification that closed them
+	deleteOrder := func(orderID order.ID, notification Notification) {
 		numClosedOrders++
 		if _, err := syncer.orderFragmentStore.OrderFragment(orderID); err == nil {
 			if err := syncer.orderFragmentStore.DeleteOrderFragment(orderID); err != nil {
-				log.Printf("[error] (resync) cannot delete order fragment: %v", err)
+				logger.Sync(logger.LevelError, fmt.Sprintf("resync: cannot delete order fragment: %v", err))
 			}
 		}
 		if err := syncer.orderStore.DeleteOrder(orderID); err != nil {
-			log.Printf("[error] (resync) cannot delete order: %v", err)
-			return
-		}
-
-		switch orderStatus {
-		case order.Confirmed:
-			notification := NotificationConfirmOrder{OrderID: orderID}
-			*notifications = append(*notifications, notification)
-		case order.Canceled:
-			notification := NotificationCancelOrder{OrderID: orderID}
-			*notifications = append(*notifications, notification)
-		default:
+			logger.Sync(logger.LevelError, fmt.Sprintf("resync: cannot delete order: %v", err))
 			return
 		}
+		*notifications = append(*notifications, notification)
 	}
 
 	offset := syncer.resyncPointer
@@ -175,35 +167,46 @@ func (syncer *syncer) resync(notifications *Notifications) error {
 		orderID := orders[syncer.resyncPointer]
 		orderStatus, err := syncer.contractBinder.Status(orderID)
 		if err != nil {
-			log.Printf("[error] (resync) cannot load order status: %v", err)
+			logger.Sync(logger.LevelError, fmt.Sprintf("resync: cannot load order status: %v", err))
 			continue
 		}
 
 		switch orderStatus {
 		case order.Canceled:
-			deleteOrder(orderID, orderStatus)
+			deleteOrder(orderID, NotificationCancelOrder{OrderID: orderID})
 		case order.Confirmed:
 			settleStatus, err := syncer.contractBinder.SettlementStatus(orderID)
 			if err != nil {
-				log.Printf("[error] (resync) cannot load order settlement status: %v", err)
+				logger.Sync(logger.LevelError, fmt.Sprintf("resync: cannot load order settlement status: %v", err))
 				continue
 			}
 			if settleStatus > 1 {
-				deleteOrder(orderID, order.Confirmed)
+				deleteOrder(orderID, NotificationConfirmOrder{OrderID: orderID})
 			}
 		case order.Open:
+			fragment, err := syncer.orderFragmentStore.OrderFragment(orderID)
+			if err != nil {
+				continue
+			}
+
+			// Orders that have expired while they are still open can no
+			// longer be matched. Orders that have been confirmed must not be
+			// expired, because they are still waiting to be settled.
+			if fragment.IsExpired(time.Now()) {
+				deleteOrder(orderID, NotificationExpireOrder{OrderID: orderID})
+				continue
+			}
+
 			// If this is the first re-sync after a re-boot, open order
 			// notifications will be generated for all stored orders with order
 			// fragments
 			if syncer.firstSync {
-				if fragment, err := syncer.orderFragmentStore.OrderFragment(orderID); err == nil {
-					trader := traders[syncer.resyncPointer]
-					priority := priorities[syncer.resyncPointer]
-
-					log.Printf("[info] (resync) generating new notification %v, resync ptr = %v", orderID, syncer.resyncPointer)
-					notification := NotificationOpenOrder{OrderID: orderID, OrderFragment: fragment, Priority: priority, Trader: trader}
-					*notifications = append(*notifications, notification)
-				}
+				trader := traders[syncer.resyncPointer]
+				priority := priorities[syncer.resyncPointer]
+
+				logger.Sync(logger.LevelInfo, fmt.Sprintf("resync: generating new notification %v, resync ptr = %v", orderID, syncer.resyncPointer))
+				notification := NotificationOpenOrder{OrderID: orderID, OrderFragment: fragment, Priority: priority, Trader: trader}
+				*notifications = append(*notifications, notification)
 			}
 		}
 	}
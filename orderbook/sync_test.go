@@ -130,6 +130,36 @@ var _ = Describe("Syncer", func() {
 		})
 	})
 
+	Context("when resyncing expired orders", func() {
+
+		It("should only expire orders that are open", func() {
+			opened := contract.OpenMatchingOrders(1, order.Open)
+			confirmed := contract.OpenMatchingOrders(1, order.Confirmed)
+			for i, ord := range append(opened, confirmed...) {
+				fragments, err := ord.Split(5, 4)
+				Expect(err).ShouldNot(HaveOccurred())
+				fragments[0].OrderExpiry = time.Now().Add(-time.Minute)
+				Expect(storer.OrderbookOrderStore().PutOrder(ord.ID, order.Open, "", uint(i))).ShouldNot(HaveOccurred())
+				Expect(storer.OrderbookOrderFragmentStore().PutOrderFragment(fragments[0])).ShouldNot(HaveOccurred())
+			}
+
+			syncer := NewSyncer(storer.OrderbookPointerStore(), storer.OrderbookOrderStore(), storer.OrderbookOrderFragmentStore(), contract, 10)
+			notifications, err := syncer.Sync()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			expired := map[order.ID]bool{}
+			for _, notification := range notifications {
+				if notification, ok := notification.(NotificationExpireOrder); ok {
+					expired[notification.OrderID] = true
+				}
+			}
+			Expect(expired).Should(HaveLen(len(opened)))
+			for _, ord := range opened {
+				Expect(expired).Should(HaveKey(ord.ID))
+			}
+		})
+	})
+
 	Context("when syncing over multiple epochs", func() {
 
 		It("should not create any open order notifications for orders in a different epoch depth", func() {
@@ -252,7 +282,7 @@ var _ = Describe("Syncer", func() {
 func sendOrdersToOrderbook(orders []order.Order, key crypto.RsaKey, orderbook Orderbook, depth order.FragmentEpochDepth) error {
 
 	for _, ord := range orders {
-		fragments, err := ord.Split(5, 4)
+		fragments, err := ord.Split(1, 1)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,10 @@
+package shamir
+
+// Export the finite field arithmetic so that it can be tested against
+// stackint.
+var (
+	AddMod = addMod
+	SubMod = subMod
+	MulMod = mulMod
+	InvMod = invMod
+)
@@ -0,0 +1,75 @@
+package shamir
+
+import (
+	"math/bits"
+)
+
+// The finite field arithmetic operates on uint64 values directly, using
+// 128-bit intermediate products from math/bits. Values do not need to be
+// reduced before they are used, but the modulus must be greater than one.
+
+// addMod returns (x + y) % mod.
+func addMod(x uint64, y uint64, mod uint64) uint64 {
+	sum, carry := bits.Add64(x, y, 0)
+	return bits.Rem64(carry, sum, mod)
+}
+
+// subMod returns (x - y) % mod, where the result is never negative.
+func subMod(x uint64, y uint64, mod uint64) uint64 {
+	x, y = x%mod, y%mod
+	if x >= y {
+		return x - y
+	}
+	return mod - (y - x)
+}
+
+// mulMod returns (x * y) % mod.
+func mulMod(x uint64, y uint64, mod uint64) uint64 {
+	hi, lo := bits.Mul64(x, y)
+	return bits.Rem64(hi, lo, mod)
+}
+
+// invMod returns the multiplicative inverse of x modulo mod, using the
+// extended Euclidean algorithm. It panics if x and mod are not relatively
+// prime, so values received from other nodes must be verified before they are
+// inverted.
+func invMod(x uint64, mod uint64) uint64 {
+	t, nextT := uint64(0), uint64(1)
+	r, nextR := mod, x%mod
+	for nextR != 0 {
+		q := r / nextR
+		t, nextT = nextT, subMod(t, mulMod(q, nextT, mod), mod)
+		r, nextR = nextR, r-q*nextR
+	}
+	if r != 1 {
+		panic("not relatively prime")
+	}
+	return t
+}
+
+// batchInvMod returns the multiplicative inverse of each value modulo mod,
+// using one call to invMod and three multiplications for each value. It
+// panics if any value is not relatively prime to mod.
+func batchInvMod(xs []uint64, mod uint64) []uint64 {
+	if len(xs) == 0 {
+		return []uint64{}
+	}
+
+	// Compute the prefix products, and invert the product of all values
+	prefixes := make([]uint64, len(xs))
+	prefix := uint64(1)
+	for i, x := range xs {
+		prefix = mulMod(prefix, x, mod)
+		prefixes[i] = prefix
+	}
+	inv := invMod(prefix, mod)
+
+	// Walk backwards, peeling one value at a time off the inverted product
+	invs := make([]uint64, len(xs))
+	for i := len(xs) - 1; i > 0; i-- {
+		invs[i] = mulMod(inv, prefixes[i-1], mod)
+		inv = mulMod(inv, xs[i], mod)
+	}
+	invs[0] = inv
+	return invs
+}
@@ -0,0 +1,290 @@
+package shamir_test
+
+import (
+	"math/rand"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/shamir"
+
+	"github.com/republicprotocol/republic-go/stackint"
+)
+
+var _ = Describe("Finite field arithmetic", func() {
+
+	Context("when comparing against stackint", func() {
+
+		It("should return the same results for random values", func() {
+			for i := 0; i < 1000; i++ {
+				x, y := randomUint64(), randomUint64()
+				Expect(AddMod(x, y, Prime)).Should(Equal(stackintAddMod(x, y, Prime)))
+				Expect(SubMod(x, y, Prime)).Should(Equal(stackintSubMod(x, y, Prime)))
+				Expect(MulMod(x, y, Prime)).Should(Equal(stackintMulMod(x, y, Prime)))
+				if x%Prime != 0 {
+					Expect(InvMod(x, Prime)).Should(Equal(stackintInvMod(x, Prime)))
+				}
+			}
+		})
+
+		It("should return the same results at the edges of the field", func() {
+			values := []uint64{0, 1, 2, Prime - 2, Prime - 1, Prime, Prime + 1, ^uint64(0) - 1, ^uint64(0)}
+			for _, x := range values {
+				for _, y := range values {
+					Expect(AddMod(x, y, Prime)).Should(Equal(stackintAddMod(x, y, Prime)))
+					Expect(SubMod(x, y, Prime)).Should(Equal(stackintSubMod(x, y, Prime)))
+					Expect(MulMod(x, y, Prime)).Should(Equal(stackintMulMod(x, y, Prime)))
+				}
+				if x%Prime != 0 {
+					Expect(InvMod(x, Prime)).Should(Equal(stackintInvMod(x, Prime)))
+				}
+			}
+		})
+
+		It("should panic when inverting zero", func() {
+			Expect(func() { InvMod(0, Prime) }).Should(Panic())
+			Expect(func() { InvMod(Prime, Prime) }).Should(Panic())
+		})
+	})
+
+	Context("when computing lagrange coefficients", func() {
+
+		It("should join shares in the same way as stackint", func() {
+			for i := 0; i < 10; i++ {
+				shares, err := Split(24, 16, randomUint64()%Prime)
+				Expect(err).ShouldNot(HaveOccurred())
+				rand.Shuffle(len(shares), func(i, j int) { shares[i], shares[j] = shares[j], shares[i] })
+				Expect(Join(shares[:16])).Should(Equal(stackintJoin(shares[:16])))
+			}
+		})
+
+		It("should reuse coefficients to join secrets shared over the same indices", func() {
+			indices := []uint64{3, 7, 1, 12, 9, 4}
+			coefficients, err := LagrangeCoefficients(indices)
+			Expect(err).ShouldNot(HaveOccurred())
+			for i := 0; i < 10; i++ {
+				secret := randomUint64() % Prime
+				shares, err := Split(12, 6, secret)
+				Expect(err).ShouldNot(HaveOccurred())
+				subset := make(Shares, len(indices))
+				for j, index := range indices {
+					subset[j] = shares[index-1]
+				}
+				Expect(JoinWithLagrangeCoefficients(subset, coefficients)).Should(Equal(secret))
+			}
+		})
+
+		It("should join vectors of shares using one set of coefficients", func() {
+			secrets := make([]uint64, 16)
+			vectors := make([]Shares, 6)
+			for i := range vectors {
+				vectors[i] = make(Shares, len(secrets))
+			}
+			for i := range secrets {
+				secrets[i] = randomUint64() % Prime
+				shares, err := Split(12, 6, secrets[i])
+				Expect(err).ShouldNot(HaveOccurred())
+				for j := range vectors {
+					vectors[j][i] = shares[2*j]
+				}
+			}
+			indices := make([]uint64, len(vectors))
+			for j := range vectors {
+				indices[j] = vectors[j][0].Index
+			}
+			coefficients, err := LagrangeCoefficients(indices)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(JoinVectors(vectors, coefficients)).Should(Equal(secrets))
+		})
+
+		It("should panic when the number of coefficients is wrong", func() {
+			shares, err := Split(3, 2, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+			coefficients, err := LagrangeCoefficients([]uint64{1, 2})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(func() { JoinWithLagrangeCoefficients(shares, coefficients) }).Should(Panic())
+		})
+
+		It("should return an error when share indices are duplicated", func() {
+			_, err := LagrangeCoefficients([]uint64{1, 2, 2})
+			Expect(err).Should(Equal(ErrDuplicateIndex))
+			_, err = LagrangeCoefficients([]uint64{1, 2, Prime + 1})
+			Expect(err).Should(Equal(ErrDuplicateIndex))
+
+			shares, err := Split(6, 4, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+			shares[1].Index = shares[0].Index
+			_, err = Join(shares[:4])
+			Expect(err).Should(Equal(ErrDuplicateIndex))
+		})
+
+		It("should return an error when a share index is zero", func() {
+			_, err := LagrangeCoefficients([]uint64{0, 1, 2})
+			Expect(err).Should(Equal(ErrZeroIndex))
+			_, err = LagrangeCoefficients([]uint64{1, Prime})
+			Expect(err).Should(Equal(ErrZeroIndex))
+
+			shares, err := Split(6, 4, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+			shares[2].Index = 0
+			_, err = Join(shares[:4])
+			Expect(err).Should(Equal(ErrZeroIndex))
+		})
+	})
+})
+
+func FuzzAddMod(f *testing.F) {
+	f.Add(uint64(0), uint64(0))
+	f.Add(Prime-1, uint64(1))
+	f.Add(^uint64(0), ^uint64(0))
+	f.Fuzz(func(t *testing.T, x, y uint64) {
+		if got, expected := AddMod(x, y, Prime), stackintAddMod(x, y, Prime); got != expected {
+			t.Fatalf("addMod(%v, %v) = %v, expected %v", x, y, got, expected)
+		}
+	})
+}
+
+func FuzzSubMod(f *testing.F) {
+	f.Add(uint64(0), uint64(1))
+	f.Add(Prime, uint64(0))
+	f.Add(uint64(1), ^uint64(0))
+	f.Fuzz(func(t *testing.T, x, y uint64) {
+		if got, expected := SubMod(x, y, Prime), stackintSubMod(x, y, Prime); got != expected {
+			t.Fatalf("subMod(%v, %v) = %v, expected %v", x, y, got, expected)
+		}
+	})
+}
+
+func FuzzMulMod(f *testing.F) {
+	f.Add(uint64(0), uint64(1))
+	f.Add(Prime-1, Prime-1)
+	f.Add(^uint64(0), ^uint64(0))
+	f.Fuzz(func(t *testing.T, x, y uint64) {
+		if got, expected := MulMod(x, y, Prime), stackintMulMod(x, y, Prime); got != expected {
+			t.Fatalf("mulMod(%v, %v) = %v, expected %v", x, y, got, expected)
+		}
+	})
+}
+
+func FuzzInvMod(f *testing.F) {
+	f.Add(uint64(1))
+	f.Add(Prime - 1)
+	f.Add(^uint64(0))
+	f.Fuzz(func(t *testing.T, x uint64) {
+		if x%Prime == 0 {
+			t.Skip()
+		}
+		if got, expected := InvMod(x, Prime), stackintInvMod(x, Prime); got != expected {
+			t.Fatalf("invMod(%v) = %v, expected %v", x, got, expected)
+		}
+	})
+}
+
+func BenchmarkMulMod(b *testing.B) {
+	x, y := randomUint64(), randomUint64()
+	for i := 0; i < b.N; i++ {
+		x = MulMod(x, y, Prime)
+	}
+}
+
+func BenchmarkMulModStackint(b *testing.B) {
+	x, y := randomUint64(), randomUint64()
+	for i := 0; i < b.N; i++ {
+		x = stackintMulMod(x, y, Prime)
+	}
+}
+
+func BenchmarkJoin(b *testing.B) {
+	shares, err := Split(24, 16, 42)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Join(shares[:16])
+	}
+}
+
+func BenchmarkJoinWithLagrangeCoefficients(b *testing.B) {
+	shares, err := Split(24, 16, 42)
+	if err != nil {
+		b.Fatal(err)
+	}
+	indices := make([]uint64, 16)
+	for i := range indices {
+		indices[i] = shares[i].Index
+	}
+	coefficients, err := LagrangeCoefficients(indices)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		JoinWithLagrangeCoefficients(shares[:16], coefficients)
+	}
+}
+
+func BenchmarkJoinStackint(b *testing.B) {
+	shares, err := Split(24, 16, 42)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stackintJoin(shares[:16])
+	}
+}
+
+func randomUint64() uint64 {
+	return uint64(rand.Uint32())<<32 | uint64(rand.Uint32())
+}
+
+func stackintAddMod(x, y, mod uint64) uint64 {
+	stackX, stackY, stackM := stackint.FromUint(uint(x)), stackint.FromUint(uint(y)), stackint.FromUint(uint(mod))
+	return stackintToUint64(stackX.AddModulo(&stackY, &stackM))
+}
+
+func stackintSubMod(x, y, mod uint64) uint64 {
+	stackX, stackY, stackM := stackint.FromUint(uint(x)), stackint.FromUint(uint(y)), stackint.FromUint(uint(mod))
+	return stackintToUint64(stackX.SubModulo(&stackY, &stackM))
+}
+
+func stackintMulMod(x, y, mod uint64) uint64 {
+	stackX, stackY, stackM := stackint.FromUint(uint(x)), stackint.FromUint(uint(y)), stackint.FromUint(uint(mod))
+	return stackintToUint64(stackX.MulModulo(&stackY, &stackM))
+}
+
+func stackintInvMod(x, mod uint64) uint64 {
+	stackX, stackM := stackint.FromUint(uint(x)), stackint.FromUint(uint(mod))
+	return stackintToUint64(stackX.ModInverse(&stackM))
+}
+
+func stackintToUint64(x stackint.Int1024) uint64 {
+	r, err := x.ToUint()
+	if err != nil {
+		panic(err)
+	}
+	return uint64(r)
+}
+
+// stackintJoin is the implementation of Join that used stackint for all
+// finite field arithmetic.
+func stackintJoin(shares Shares) uint64 {
+	secret := uint64(0)
+	for i := 0; i < len(shares); i++ {
+		num := uint64(1)
+		den := uint64(1)
+		for j := 0; j < len(shares); j++ {
+			if i == j {
+				continue
+			}
+			num = Prime - stackintMulMod(num, shares[j].Index, Prime)
+			den = stackintMulMod(den, stackintSubMod(shares[i].Index, shares[j].Index, Prime), Prime)
+		}
+		den = stackintInvMod(den, Prime)
+		value := stackintMulMod(shares[i].Value, num, Prime)
+		value = stackintMulMod(value, den, Prime)
+		secret = stackintAddMod(secret, value, Prime)
+	}
+	return secret
+}
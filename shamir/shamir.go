@@ -2,15 +2,15 @@ package shamir
 
 import (
 	"bytes"
+	"crypto/rand"
 	"crypto/rsa"
 	"encoding/binary"
 	"encoding/json"
 	"errors"
+	"io"
 	"math/big"
-	"math/rand"
 
 	"github.com/republicprotocol/republic-go/crypto"
-	"github.com/republicprotocol/republic-go/stackint"
 )
 
 // ErrNKError is returned when the numbers of shared required to reconstruct a
@@ -24,6 +24,14 @@ var ErrFiniteField = errors.New("expected secret to be in the finite field")
 // byte slice.
 var ErrUnmarshalNilBytes = errors.New("unmarshal nil bytes")
 
+// ErrZeroIndex is returned when joining Shares, and one of the Shares has an
+// index that is zero in the finite field.
+var ErrZeroIndex = errors.New("expected share index to be non-zero")
+
+// ErrDuplicateIndex is returned when joining Shares, and two of the Shares have
+// the same index in the finite field.
+var ErrDuplicateIndex = errors.New("expected share indices to be distinct")
+
 // Prime is the prime number used to define the finite field.
 const Prime uint64 = 17012364981921935471
 
@@ -50,6 +58,24 @@ func (share *Share) Sub(arg *Share) Share {
 	}
 }
 
+// Add one share to another within the finite field and return the result.
+// The index of the result will always be set to the receiver index.
+func (share *Share) Add(arg *Share) Share {
+	return Share{
+		Index: share.Index,
+		Value: addMod(share.Value, arg.Value, Prime),
+	}
+}
+
+// Mul a share by a public scalar within the finite field and return the
+// result. The index of the result will always be set to the receiver index.
+func (share *Share) Mul(scalar uint64) Share {
+	return Share{
+		Index: share.Index,
+		Value: mulMod(share.Value, scalar, Prime),
+	}
+}
+
 // MarshalJSON implements the json.Marshaler interface.
 func (share Share) MarshalJSON() ([]byte, error) {
 	bytes, err := share.MarshalBinary()
@@ -125,10 +151,72 @@ func (share *Share) Decrypt(privKey *rsa.PrivateKey, cipherText []byte) error {
 // Shares are a slice of Share structs.
 type Shares []Share
 
+// MarshalBinary implements the encoding.BinaryMarshaler interface. Each Share
+// is marshaled, in order, using Share.MarshalBinary.
+func (shares Shares) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	for _, share := range shares {
+		data, err := share.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (shares *Shares) UnmarshalBinary(data []byte) error {
+	if data == nil || len(data) == 0 {
+		return ErrUnmarshalNilBytes
+	}
+	if len(data)%16 != 0 {
+		return io.ErrUnexpectedEOF
+	}
+	*shares = make(Shares, len(data)/16)
+	for i := range *shares {
+		if err := (*shares)[i].UnmarshalBinary(data[16*i : 16*(i+1)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Encrypt Shares using an rsa.PublicKey. All Shares are encrypted into one
+// cipher text, so the number of Shares is limited by the size of the
+// rsa.PublicKey.
+func (shares Shares) Encrypt(pubKey rsa.PublicKey) ([]byte, error) {
+	rsaKey := crypto.RsaKey{PrivateKey: &rsa.PrivateKey{PublicKey: pubKey}}
+	data, err := shares.MarshalBinary()
+	if err != nil {
+		return []byte{}, err
+	}
+	return rsaKey.Encrypt(data)
+}
+
+// Decrypt cipher text into Shares using an crypto.RsaKey.
+func (shares *Shares) Decrypt(privKey *rsa.PrivateKey, cipherText []byte) error {
+	rsaKey := crypto.RsaKey{PrivateKey: privKey}
+	plainText, err := rsaKey.Decrypt(cipherText)
+	if err != nil {
+		return err
+	}
+	return shares.UnmarshalBinary(plainText)
+}
+
 // Split a secret into Shares. N represents the number of Shares that the
 // secret will be split into, and K represents the number of Share required to
-// reconstruct the secret. A slice of Shares, or an error, is returned.
+// reconstruct the secret. The polynomial coefficients are sampled from
+// crypto/rand. A slice of Shares, or an error, is returned.
 func Split(n, k int64, secret uint64) (Shares, error) {
+	return SplitWithReader(n, k, secret, rand.Reader)
+}
+
+// SplitWithReader splits a secret into Shares in the same way as Split, but
+// samples the polynomial coefficients from an io.Reader. The io.Reader must be
+// a cryptographically secure source of randomness, unless the Shares are only
+// used for testing.
+func SplitWithReader(n, k int64, secret uint64, reader io.Reader) (Shares, error) {
 	// Validate the encoding by checking that N is greater than K, and that the
 	// secret is within the finite field.
 	if n < k {
@@ -138,130 +226,183 @@ func Split(n, k int64, secret uint64) (Shares, error) {
 		return nil, ErrFiniteField
 	}
 
-	// Generate K polynomial coefficients, where the first coefficient is the
-	// secret.
+	coefficients, err := randomPolynomial(k, secret, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create N shares.
+	shares := make(Shares, n)
+	for x := int64(1); x <= n; x++ {
+		shares[x-1] = Share{
+			Index: uint64(x),
+			Value: evaluatePolynomial(coefficients, uint64(x)),
+		}
+	}
+	return shares, nil
+}
+
+// randomPolynomial generates K polynomial coefficients, where the first
+// coefficient is the secret and the others are sampled from an io.Reader.
+func randomPolynomial(k int64, secret uint64, reader io.Reader) ([]uint64, error) {
 	coefficients := make([]uint64, k)
 	coefficients[0] = secret
-
 	for i := int64(1); i < k; i++ {
-		coefficients[i] = rand.Uint64()
-		for coefficients[i] >= Prime {
-			coefficients[i] = rand.Uint64()
+		coefficient, err := RandomValue(reader)
+		if err != nil {
+			return nil, err
 		}
+		coefficients[i] = coefficient
 	}
+	return coefficients, nil
+}
 
-	// Create N shares.
-	shares := make(Shares, n)
-	for x := int64(1); x <= n; x++ {
+// evaluatePolynomial evaluates the polynomial defined by its coefficients at
+// x, within the finite field.
+func evaluatePolynomial(coefficients []uint64, x uint64) uint64 {
+	accum := coefficients[0]
+	base := x % Prime
+	exp := base
 
-		accum := coefficients[0]
-		base := uint64(x)
-		exp := base % Prime
+	for _, coefficient := range coefficients[1:] {
 
-		// Evaluate the polynomial at x.
-		for j := range coefficients[1:] {
+		// co := (coefficients * expoMod) % prime
+		co := mulMod(coefficient, exp, Prime)
 
-			// co := (coefficients * expoMod) % prime
-			coefficient := coefficients[j]
-			co := mulMod(coefficient, exp, Prime)
+		accum = addMod(accum, co, Prime)
 
-			accum = addMod(accum, co, Prime)
+		// exp = (exp * base ) % prime
+		exp = mulMod(exp, base, Prime)
+	}
+	return accum
+}
 
-			// exp = (exp * base ) % prime
-			exp = mulMod(exp, base, Prime)
+// RandomValue samples a value uniformly from the finite field using an
+// io.Reader. Values greater than, or equal to, the Prime are rejected and
+// sampled again, so that no value is more likely than another.
+func RandomValue(reader io.Reader) (uint64, error) {
+	buf := [8]byte{}
+	for {
+		if _, err := io.ReadFull(reader, buf[:]); err != nil {
+			return 0, err
 		}
-		shares[x-1] = Share{
-			Index: uint64(x),
-			Value: accum,
+		if value := binary.BigEndian.Uint64(buf[:]); value < Prime {
+			return value, nil
 		}
 	}
-	return shares, nil
 }
 
 // Join Shares into a secret. Prime is used to define the finite field from
-// which the secret was selected. The reconstructed secret, or an error, is
-// returned.
-func Join(shares Shares) uint64 {
+// which the secret was selected. The reconstructed secret is returned. An
+// error is returned if the indices of the Shares are not distinct, and
+// non-zero, in the finite field.
+func Join(shares Shares) (uint64, error) {
+	indices := make([]uint64, len(shares))
+	for i := range shares {
+		indices[i] = shares[i].Index
+	}
+	coefficients, err := LagrangeCoefficients(indices)
+	if err != nil {
+		return 0, err
+	}
+	return JoinWithLagrangeCoefficients(shares, coefficients), nil
+}
+
+// JoinWithLagrangeCoefficients joins Shares into a secret using Lagrange
+// coefficients that were computed by LagrangeCoefficients for the indices of
+// the Shares, in the same order. It panics if the number of coefficients is
+// not the number of Shares.
+func JoinWithLagrangeCoefficients(shares Shares, coefficients []uint64) uint64 {
+	if len(shares) != len(coefficients) {
+		panic("expected a lagrange coefficient for each share")
+	}
 	secret := uint64(0)
+	for i := range shares {
+		secret = addMod(secret, mulMod(shares[i].Value, coefficients[i], Prime), Prime)
+	}
+	return secret
+}
+
+// JoinVectors joins many secrets at once. Each vector holds one Share of every
+// secret, and all Shares in a vector must have the same index. The Lagrange
+// coefficients must have been computed by LagrangeCoefficients for the indices
+// of the vectors, in the same order. The reconstructed secrets are returned in
+// the same order as the Shares in each vector. It panics if the number of
+// coefficients is not the number of vectors, or if the vectors have different
+// lengths.
+func JoinVectors(vectors []Shares, coefficients []uint64) []uint64 {
+	if len(vectors) != len(coefficients) {
+		panic("expected a lagrange coefficient for each vector")
+	}
+	if len(vectors) == 0 {
+		return []uint64{}
+	}
+	secrets := make([]uint64, len(vectors[0]))
+	for j, vector := range vectors {
+		if len(vector) != len(secrets) {
+			panic("expected vectors of equal length")
+		}
+		for i := range vector {
+			secrets[i] = addMod(secrets[i], mulMod(vector[i].Value, coefficients[j], Prime), Prime)
+		}
+	}
+	return secrets
+}
 
-	// Compute the Lagrange basic polynomial interpolation.
-	for i := 0; i < len(shares); i++ {
+// LagrangeCoefficients returns the Lagrange basis polynomials, evaluated at
+// zero, for a set of share indices. Joining Shares is a sum of their values
+// weighted by these coefficients, so the coefficients can be computed once and
+// reused to join every secret that is shared over the same indices. All
+// denominators are inverted together, using one modular inversion. The indices
+// are usually received from other nodes, so ErrZeroIndex, or
+// ErrDuplicateIndex, is returned if a denominator would not be invertible.
+func LagrangeCoefficients(indices []uint64) ([]uint64, error) {
+	if err := verifyIndices(indices); err != nil {
+		return nil, err
+	}
+
+	nums := make([]uint64, len(indices))
+	dens := make([]uint64, len(indices))
+	for i := range indices {
 		num := uint64(1)
 		den := uint64(1)
-
-		for j := 0; j < len(shares); j++ {
+		for j := range indices {
 			if i == j {
 				continue
 			}
-			// startposition = shares[formula][0];
-			start := shares[i].Index
-
-			// nextposition = shares[count][0];
-			next := shares[j].Index
-
 			// numerator = (numerator * -nextposition) % prime;
-			nextGen := mulMod(num, next, Prime)
-			num = Prime - nextGen
+			num = mulMod(num, subMod(0, indices[j], Prime), Prime)
 
 			// denominator = (denominator * (startposition - nextposition)) % prime;
-			nextDiff := subMod(start, next, Prime)
-			den = mulMod(den, nextDiff, Prime)
+			den = mulMod(den, subMod(indices[i], indices[j], Prime), Prime)
 		}
-
-		den = invMod(den, Prime)
-		value := mulMod(shares[i].Value, num, Prime)
-		value = mulMod(value, den, Prime)
-		secret = addMod(secret, value, Prime)
+		nums[i] = num
+		dens[i] = den
 	}
 
-	return secret
-}
-
-func addMod(x uint64, y uint64, mod uint64) uint64 {
-	stackX := stackint.FromUint(uint(x))
-	stackY := stackint.FromUint(uint(y))
-	stackM := stackint.FromUint(uint(mod))
-	stackR := stackX.AddModulo(&stackY, &stackM)
-	r, err := stackR.ToUint()
-	if err != nil {
-		panic(err)
-	}
-	return uint64(r)
-}
-
-func subMod(x uint64, y uint64, mod uint64) uint64 {
-	stackX := stackint.FromUint(uint(x))
-	stackY := stackint.FromUint(uint(y))
-	stackM := stackint.FromUint(uint(mod))
-	stackR := stackX.SubModulo(&stackY, &stackM)
-	r, err := stackR.ToUint()
-	if err != nil {
-		panic(err)
+	invDens := batchInvMod(dens, Prime)
+	coefficients := make([]uint64, len(indices))
+	for i := range coefficients {
+		coefficients[i] = mulMod(nums[i], invDens[i], Prime)
 	}
-	return uint64(r)
+	return coefficients, nil
 }
 
-func mulMod(x uint64, y uint64, mod uint64) uint64 {
-	stackX := stackint.FromUint(uint(x))
-	stackY := stackint.FromUint(uint(y))
-	stackM := stackint.FromUint(uint(mod))
-	stackR := stackX.MulModulo(&stackY, &stackM)
-	r, err := stackR.ToUint()
-	if err != nil {
-		panic(err)
-	}
-	return uint64(r)
-}
-
-func invMod(x uint64, mod uint64) uint64 {
-	stackX := stackint.FromUint(uint(x))
-	stackM := stackint.FromUint(uint(mod))
-	stackR := stackX.ModInverse(&stackM)
-	r, err := stackR.ToUint()
-	if err != nil {
-		panic(err)
+// verifyIndices returns an error if a set of share indices contains an index
+// that is zero, or two indices that are equal, in the finite field.
+func verifyIndices(indices []uint64) error {
+	seen := make(map[uint64]struct{}, len(indices))
+	for _, index := range indices {
+		index %= Prime
+		if index == 0 {
+			return ErrZeroIndex
+		}
+		if _, ok := seen[index]; ok {
+			return ErrDuplicateIndex
+		}
+		seen[index] = struct{}{}
 	}
-	return uint64(r)
+	return nil
 }
 
 type Blindings []Blinding
@@ -326,3 +467,24 @@ func NewCommitment(x Share, s Blinding) Commitment {
 	gˣhˢ := big.NewInt(0).Mul(gˣ, hˢ)
 	return Commitment{gˣhˢ.Mod(gˣhˢ, CommitP)}
 }
+
+// MarshalJSON implements the json.Marshaler interface.
+func (c Commitment) MarshalJSON() ([]byte, error) {
+	if c.Int == nil {
+		return json.Marshal([]byte{0})
+	}
+	return json.Marshal(c.Int.Bytes())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (c *Commitment) UnmarshalJSON(data []byte) error {
+	bs := []byte{}
+	if err := json.Unmarshal(data, &bs); err != nil {
+		return err
+	}
+	if c.Int == nil {
+		c.Int = big.NewInt(0)
+	}
+	c.Int.SetBytes(bs)
+	return nil
+}
@@ -1,6 +1,9 @@
 package shamir_test
 
 import (
+	"bytes"
+	cryptorand "crypto/rand"
+	"encoding/binary"
 	"encoding/json"
 	"math/big"
 	"math/rand"
@@ -66,11 +69,34 @@ var _ = Describe("Shamir's secret sharing", func() {
 					sharesResult[j] = shares[j].Sub(&sharesOther[j])
 				}
 
-				secretResult := Join(sharesResult)
+				secretResult, err := Join(sharesResult)
+				Expect(err).ShouldNot(HaveOccurred())
 				Expect(secretResult).Should(Equal(secret - secretOther))
 			}
 		})
 
+		It("should equal addition, and multiplication by a scalar, on the secrets when done on shares", func() {
+			for i := uint64(0); i < 100; i++ {
+
+				secret := (uint64(rand.Int63()) % Prime) / 8
+				secretOther := (uint64(rand.Int63()) % Prime) / 8
+
+				shares, err := Split(72, 48, secret)
+				Expect(err).ShouldNot(HaveOccurred())
+				sharesOther, err := Split(72, 48, secretOther)
+				Expect(err).ShouldNot(HaveOccurred())
+				sharesResult := make(Shares, 72)
+				for j := 0; j < 72; j++ {
+					sum := shares[j].Add(&sharesOther[j])
+					sharesResult[j] = sum.Mul(3)
+				}
+
+				secretResult, err := Join(sharesResult)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(secretResult).Should(Equal(3 * (secret + secretOther)))
+			}
+		})
+
 	})
 
 	Context("when marshaling and unmarshaling", func() {
@@ -184,6 +210,45 @@ var _ = Describe("Shamir's secret sharing", func() {
 		})
 	})
 
+	Context("when encrypting and decrypting many shares", func() {
+
+		It("should equal itself after an encryption then decryption", func() {
+			rsaKey, err := crypto.RandomRsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			shares := make(Shares, 8)
+			for i := range shares {
+				shares[i] = Share{
+					Index: uint64(rand.Int63()),
+					Value: uint64(rand.Int63()) % Prime,
+				}
+			}
+
+			cipherText, err := shares.Encrypt(rsaKey.PublicKey)
+			Expect(err).ShouldNot(HaveOccurred())
+			decryptedShares := Shares{}
+			err = decryptedShares.Decrypt(rsaKey.PrivateKey, cipherText)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(decryptedShares).Should(Equal(shares))
+		})
+
+		It("should return an error when unmarshaling a partial share", func() {
+			shares := Shares{}
+			Expect(shares.UnmarshalBinary(make([]byte, 20))).Should(HaveOccurred())
+		})
+	})
+
+	Context("when marshaling and unmarshaling commitments", func() {
+
+		It("should equal itself after marshaling them unmarshaling in JSON", func() {
+			commitment := NewCommitment(Share{Index: 1, Value: 42}, Blinding{big.NewInt(rand.Int63())})
+			data, err := json.Marshal(commitment)
+			Expect(err).ShouldNot(HaveOccurred())
+			unmarshaledCommitment := Commitment{}
+			Expect(json.Unmarshal(data, &unmarshaledCommitment)).ShouldNot(HaveOccurred())
+			Expect(unmarshaledCommitment.Cmp(commitment.Int)).Should(Equal(0))
+		})
+	})
+
 	Context("when splitting", func() {
 
 		It("should return the required number of shares", func() {
@@ -211,6 +276,88 @@ var _ = Describe("Shamir's secret sharing", func() {
 			Expect(err).Should(Equal(ErrFiniteField))
 		})
 
+		It("should return the same shares when using the same reader", func() {
+			shares, err := SplitWithReader(24, 16, 1234, rand.New(rand.NewSource(42)))
+			Expect(err).ShouldNot(HaveOccurred())
+			sharesOther, err := SplitWithReader(24, 16, 1234, rand.New(rand.NewSource(42)))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(shares).Should(Equal(sharesOther))
+			Expect(Join(shares[:16])).Should(Equal(uint64(1234)))
+
+			sharesOther, err = SplitWithReader(24, 16, 1234, rand.New(rand.NewSource(43)))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(shares).ShouldNot(Equal(sharesOther))
+		})
+
+		It("should return different shares when splitting the same secret twice", func() {
+			for k := int64(2); k < 8; k++ {
+				shares, err := Split(8, k, 1234)
+				Expect(err).ShouldNot(HaveOccurred())
+				sharesOther, err := Split(8, k, 1234)
+				Expect(err).ShouldNot(HaveOccurred())
+				for i := range shares {
+					Expect(shares[i].Value).ShouldNot(Equal(sharesOther[i].Value))
+				}
+			}
+		})
+
+		It("should return an error when the reader fails", func() {
+			_, err := SplitWithReader(24, 16, 1234, bytes.NewReader([]byte{1, 2, 3}))
+			Expect(err).Should(HaveOccurred())
+		})
+
+	})
+
+	Context("when sampling random values", func() {
+
+		It("should reject values that are not in the finite field", func() {
+			buf := make([]byte, 16)
+			binary.BigEndian.PutUint64(buf[:8], Prime)
+			binary.BigEndian.PutUint64(buf[8:], Prime-1)
+			value, err := RandomValue(bytes.NewReader(buf))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).Should(Equal(Prime - 1))
+		})
+
+		It("should sample values uniformly", func() {
+			values := make([]uint64, numberOfSamples)
+			for i := range values {
+				value, err := RandomValue(cryptorand.Reader)
+				Expect(err).ShouldNot(HaveOccurred())
+				values[i] = value
+			}
+			expectUniform(values)
+		})
+
+		It("should return shares that are uniformly distributed", func() {
+			// Shares of the same secret must be uniform, otherwise fewer
+			// than K shares would reveal information about the secret
+			for _, secret := range []uint64{0, 1234, Prime - 1} {
+				values := make([][]uint64, 3)
+				for i := 0; i < numberOfSamples; i++ {
+					shares, err := Split(3, 3, secret)
+					Expect(err).ShouldNot(HaveOccurred())
+					for j := range shares {
+						values[j] = append(values[j], shares[j].Value)
+					}
+				}
+				for j := range values {
+					expectUniform(values[j])
+				}
+			}
+		})
+
+		It("should return shares that are uniformly distributed using a deterministic reader", func() {
+			reader := rand.New(rand.NewSource(42))
+			values := make([]uint64, numberOfSamples)
+			for i := range values {
+				shares, err := SplitWithReader(2, 2, 1234, reader)
+				Expect(err).ShouldNot(HaveOccurred())
+				values[i] = shares[0].Value
+			}
+			expectUniform(values)
+		})
+
 	})
 
 	Context("when joining", func() {
@@ -248,7 +395,9 @@ var _ = Describe("Shamir's secret sharing", func() {
 			for index := range indices {
 				kShares[index] = shares[index]
 			}
-			decodedSecret := stackint.FromUint(uint(Join(kShares)))
+			joinedSecret, err := Join(kShares)
+			Expect(err).ShouldNot(HaveOccurred())
+			decodedSecret := stackint.FromUint(uint(joinedSecret))
 			Expect(decodedSecret.Cmp(&secretStackInt)).Should(Equal(0))
 		})
 
@@ -279,7 +428,9 @@ var _ = Describe("Shamir's secret sharing", func() {
 				for index := range indices {
 					kShares[index] = shares[index]
 				}
-				decodedSecret := stackint.FromUint(uint(Join(kShares)))
+				joinedSecret, err := Join(kShares)
+				Expect(err).ShouldNot(HaveOccurred())
+				decodedSecret := stackint.FromUint(uint(joinedSecret))
 				secretStackInt := stackint.FromUint(uint(secret))
 				Expect(decodedSecret.Cmp(&secretStackInt)).Should(Equal(0))
 			}
@@ -312,9 +463,42 @@ var _ = Describe("Shamir's secret sharing", func() {
 				for index := range indices {
 					kShares[index] = shares[index]
 				}
-				decodedSecret := Join(kShares)
+				decodedSecret, err := Join(kShares)
+				Expect(err).ShouldNot(HaveOccurred())
 				Expect(decodedSecret).Should(Not(Equal(&secret)))
 			}
 		})
 	})
 })
+
+// The number of samples, and buckets, used to check uniformity. The chi-squared
+// statistic of a uniform distribution with 15 degrees of freedom exceeds
+// chiSquaredLimit with a probability of approximately 1e-6.
+const numberOfSamples = 8000
+const numberOfBuckets = 16
+const chiSquaredLimit = 56.0
+
+// expectUniform checks that values are uniformly distributed over the finite
+// field, by bucketing the high bits and the low bits of the values, and
+// checking the chi-squared statistic of both.
+func expectUniform(values []uint64) {
+	high := make([]int, numberOfBuckets)
+	low := make([]int, numberOfBuckets)
+	for _, value := range values {
+		Expect(value).Should(BeNumerically("<", Prime))
+		high[value/(Prime/numberOfBuckets+1)]++
+		low[value%numberOfBuckets]++
+	}
+	Expect(chiSquared(high, len(values))).Should(BeNumerically("<", chiSquaredLimit))
+	Expect(chiSquared(low, len(values))).Should(BeNumerically("<", chiSquaredLimit))
+}
+
+func chiSquared(buckets []int, n int) float64 {
+	expected := float64(n) / float64(len(buckets))
+	statistic := 0.0
+	for _, observed := range buckets {
+		diff := float64(observed) - expected
+		statistic += diff * diff / expected
+	}
+	return statistic
+}
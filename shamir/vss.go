@@ -0,0 +1,181 @@
+package shamir
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// ErrInconsistentShare is returned when a Share, and its blinding Share, are
+// not consistent with the PolynomialCommitment that was published when the
+// secret was split.
+var ErrInconsistentShare = errors.New("inconsistent share")
+
+// ErrMalformedPolynomialCommitment is returned when a PolynomialCommitment is
+// empty, or contains values that are not in the subgroup used for verifiable
+// secret sharing.
+var ErrMalformedPolynomialCommitment = errors.New("malformed polynomial commitment")
+
+// Labels that are hashed to derive the generators used for verifiable secret
+// sharing.
+const (
+	VSSGLabel = "republic-go/shamir/vss/g"
+	VSSHLabel = "republic-go/shamir/vss/h"
+)
+
+// Constants used for verifiable secret sharing. VSSP is a prime such that the
+// Prime divides VSSP - 1, and VSSG and VSSH generate the subgroup of order
+// Prime. Both generators are derived by hashing a public label, so nobody
+// knows the discrete logarithm of VSSH with respect to VSSG.
+var (
+	VSSP, _ = big.NewInt(0).SetString("165370760022709819679267525529671785868828074672702149522044285340266402670501436257380860821214372184161204899855819084130623990392747654943258619004288221086293131818388587215891817248214223558821405123788231987190674183789335440144751498015880822465524457164764303476901370301431607062633278371847004217057", 10)
+	VSSG    = DeriveVSSGenerator(VSSGLabel)
+	VSSH    = DeriveVSSGenerator(VSSHLabel)
+)
+
+// DeriveVSSGenerator returns a generator of the subgroup of order Prime,
+// modulo VSSP, by hashing a label. The label is hashed with an incrementing
+// counter until the hash, reduced modulo VSSP and raised to the cofactor
+// (VSSP - 1) / Prime, is not the identity.
+func DeriveVSSGenerator(label string) *big.Int {
+	cofactor := big.NewInt(0).Sub(VSSP, big.NewInt(1))
+	cofactor.Div(cofactor, big.NewInt(0).SetUint64(Prime))
+
+	// Hash enough bytes to make the bias of the reduction modulo VSSP
+	// negligible
+	blocks := (VSSP.BitLen()+128)/(8*sha256.Size) + 1
+	for counter := uint32(0); ; counter++ {
+		digest := make([]byte, 0, blocks*sha256.Size)
+		for block := uint32(0); block < uint32(blocks); block++ {
+			preimage := make([]byte, len(label)+8)
+			copy(preimage, label)
+			binary.BigEndian.PutUint32(preimage[len(label):], counter)
+			binary.BigEndian.PutUint32(preimage[len(label)+4:], block)
+			hash := sha256.Sum256(preimage)
+			digest = append(digest, hash[:]...)
+		}
+		x := big.NewInt(0).SetBytes(digest)
+		x.Mod(x, VSSP)
+		generator := x.Exp(x, cofactor, VSSP)
+		if generator.Cmp(big.NewInt(1)) > 0 {
+			return generator
+		}
+	}
+}
+
+// A PolynomialCommitment is a Pedersen commitment to each coefficient of the
+// polynomial that was used to split a secret. It is published alongside the
+// Shares so that each holder can verify that its Share is consistent with the
+// Shares held by everyone else, without learning anything about the secret.
+//
+// Feldman commitments are not used because they would reveal VSSG raised to
+// the secret, and secrets with low entropy (such as prices) could be
+// recovered by brute force. Commitments are only binding while the discrete
+// logarithm problem in the subgroup of order Prime is hard, which is limited
+// by the size of the Prime.
+type PolynomialCommitment []Commitment
+
+// SplitVerifiable splits a secret into Shares in the same way as Split, but
+// also splits a random blinding secret using a second polynomial and returns
+// a PolynomialCommitment to both polynomials. The blinding Shares must be
+// kept as secret as the Shares.
+func SplitVerifiable(n, k int64, secret uint64) (Shares, Shares, PolynomialCommitment, error) {
+	return SplitVerifiableWithReader(n, k, secret, rand.Reader)
+}
+
+// SplitVerifiableWithReader splits a secret in the same way as
+// SplitVerifiable, but samples the polynomial coefficients from an io.Reader.
+// The io.Reader must be a cryptographically secure source of randomness,
+// unless the Shares are only used for testing.
+func SplitVerifiableWithReader(n, k int64, secret uint64, reader io.Reader) (Shares, Shares, PolynomialCommitment, error) {
+	if n < k {
+		return nil, nil, nil, ErrNKError
+	}
+	if Prime <= secret {
+		return nil, nil, nil, ErrFiniteField
+	}
+
+	coefficients, err := randomPolynomial(k, secret, reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	blinding, err := RandomValue(reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	blindingCoefficients, err := randomPolynomial(k, blinding, reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	commitment := make(PolynomialCommitment, k)
+	for j := range commitment {
+		commitment[j] = Commitment{vssCommit(coefficients[j], blindingCoefficients[j])}
+	}
+
+	shares := make(Shares, n)
+	blindings := make(Shares, n)
+	for x := int64(1); x <= n; x++ {
+		shares[x-1] = Share{
+			Index: uint64(x),
+			Value: evaluatePolynomial(coefficients, uint64(x)),
+		}
+		blindings[x-1] = Share{
+			Index: uint64(x),
+			Value: evaluatePolynomial(blindingCoefficients, uint64(x)),
+		}
+	}
+	return shares, blindings, commitment, nil
+}
+
+// Verify that a Share, and its blinding Share, were produced by the
+// polynomials committed to by the PolynomialCommitment. It returns
+// ErrInconsistentShare if they were not.
+func (commitment PolynomialCommitment) Verify(share, blinding Share) error {
+	if len(commitment) == 0 {
+		return ErrMalformedPolynomialCommitment
+	}
+	order := big.NewInt(0).SetUint64(Prime)
+	for _, c := range commitment {
+		if c.Int == nil || c.Int.Sign() <= 0 || c.Int.Cmp(VSSP) >= 0 {
+			return ErrMalformedPolynomialCommitment
+		}
+		// Values outside of the subgroup of order Prime could be used to
+		// make inconsistent Shares appear to be consistent
+		if big.NewInt(0).Exp(c.Int, order, VSSP).Cmp(big.NewInt(1)) != 0 {
+			return ErrMalformedPolynomialCommitment
+		}
+	}
+	if share.Index != blinding.Index || share.Value >= Prime || blinding.Value >= Prime {
+		return ErrInconsistentShare
+	}
+
+	// The commitment to the Share is the product of the commitments to the
+	// coefficients, each raised to the respective power of the index.
+	expected := big.NewInt(1)
+	base := share.Index % Prime
+	exp := uint64(1)
+	for _, c := range commitment {
+		term := big.NewInt(0).Exp(c.Int, big.NewInt(0).SetUint64(exp), VSSP)
+		expected.Mul(expected, term)
+		expected.Mod(expected, VSSP)
+		exp = mulMod(exp, base, Prime)
+	}
+
+	if vssCommit(share.Value, blinding.Value).Cmp(expected) != 0 {
+		return ErrInconsistentShare
+	}
+	return nil
+}
+
+// vssCommit returns VSSG raised to the value multiplied by VSSH raised to the
+// blinding, modulo VSSP.
+func vssCommit(value, blinding uint64) *big.Int {
+	gˣ := big.NewInt(0).Exp(VSSG, big.NewInt(0).SetUint64(value), VSSP)
+	hˢ := big.NewInt(0).Exp(VSSH, big.NewInt(0).SetUint64(blinding), VSSP)
+	gˣhˢ := gˣ.Mul(gˣ, hˢ)
+	return gˣhˢ.Mod(gˣhˢ, VSSP)
+}
@@ -0,0 +1,121 @@
+package shamir_test
+
+import (
+	"math/big"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/shamir"
+)
+
+var _ = Describe("Verifiable secret sharing", func() {
+
+	Context("when splitting a secret", func() {
+
+		It("should return shares that can be joined into the secret", func() {
+			secret := uint64(rand.Int63()) % Prime
+			shares, blindings, commitment, err := SplitVerifiable(24, 16, secret)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(shares).Should(HaveLen(24))
+			Expect(blindings).Should(HaveLen(24))
+			Expect(commitment).Should(HaveLen(16))
+			Expect(Join(shares[8:])).Should(Equal(secret))
+		})
+
+		It("should return an error when n is less than k", func() {
+			_, _, _, err := SplitVerifiable(16, 24, 0)
+			Expect(err).Should(Equal(ErrNKError))
+		})
+
+		It("should return an error when the secret is not in the finite field", func() {
+			_, _, _, err := SplitVerifiable(24, 16, Prime)
+			Expect(err).Should(Equal(ErrFiniteField))
+		})
+	})
+
+	Context("when verifying shares", func() {
+
+		var shares, blindings Shares
+		var commitment PolynomialCommitment
+
+		BeforeEach(func() {
+			var err error
+			shares, blindings, commitment, err = SplitVerifiable(12, 8, uint64(rand.Int63())%Prime)
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should verify consistent shares", func() {
+			for i := range shares {
+				Expect(commitment.Verify(shares[i], blindings[i])).ShouldNot(HaveOccurred())
+			}
+		})
+
+		It("should not verify shares that have been modified", func() {
+			for i := range shares {
+				share := shares[i]
+				share.Value = (share.Value + 1) % Prime
+				Expect(commitment.Verify(share, blindings[i])).Should(Equal(ErrInconsistentShare))
+			}
+		})
+
+		It("should not verify shares with the wrong blinding share", func() {
+			Expect(commitment.Verify(shares[0], blindings[1])).Should(Equal(ErrInconsistentShare))
+			blinding := blindings[0]
+			blinding.Value = (blinding.Value + 1) % Prime
+			Expect(commitment.Verify(shares[0], blinding)).Should(Equal(ErrInconsistentShare))
+		})
+
+		It("should not verify shares from a different polynomial", func() {
+			otherShares, otherBlindings, _, err := SplitVerifiable(12, 8, uint64(rand.Int63())%Prime)
+			Expect(err).ShouldNot(HaveOccurred())
+			for i := range otherShares {
+				Expect(commitment.Verify(otherShares[i], otherBlindings[i])).Should(Equal(ErrInconsistentShare))
+			}
+		})
+
+		It("should not verify shares against a modified commitment", func() {
+			modified := make(PolynomialCommitment, len(commitment))
+			copy(modified, commitment)
+			modified[1] = Commitment{Int: big.NewInt(0).Mul(commitment[1].Int, VSSG)}
+			modified[1].Mod(modified[1].Int, VSSP)
+			Expect(modified.Verify(shares[0], blindings[0])).Should(Equal(ErrInconsistentShare))
+		})
+
+		It("should return an error for malformed commitments", func() {
+			Expect(PolynomialCommitment{}.Verify(shares[0], blindings[0])).Should(Equal(ErrMalformedPolynomialCommitment))
+			Expect(PolynomialCommitment{Commitment{Int: big.NewInt(0)}}.Verify(shares[0], blindings[0])).Should(Equal(ErrMalformedPolynomialCommitment))
+			Expect(PolynomialCommitment{Commitment{Int: VSSP}}.Verify(shares[0], blindings[0])).Should(Equal(ErrMalformedPolynomialCommitment))
+		})
+
+		It("should return an error for commitments outside of the subgroup", func() {
+			// VSSP - 1 has order 2, so it is not in the subgroup of order Prime
+			outside := big.NewInt(0).Sub(VSSP, big.NewInt(1))
+			modified := make(PolynomialCommitment, len(commitment))
+			copy(modified, commitment)
+			modified[0] = Commitment{Int: big.NewInt(0).Mul(commitment[0].Int, outside)}
+			modified[0].Mod(modified[0].Int, VSSP)
+			Expect(modified.Verify(shares[0], blindings[0])).Should(Equal(ErrMalformedPolynomialCommitment))
+		})
+	})
+
+	Context("when checking the group parameters", func() {
+
+		It("should use generators of the subgroup with order equal to the prime", func() {
+			Expect(VSSP.ProbablyPrime(20)).Should(BeTrue())
+			order := big.NewInt(0).SetUint64(Prime)
+			Expect(big.NewInt(0).Mod(big.NewInt(0).Sub(VSSP, big.NewInt(1)), order).Sign()).Should(Equal(0))
+			for _, generator := range []*big.Int{VSSG, VSSH} {
+				Expect(generator.Cmp(big.NewInt(1))).ShouldNot(Equal(0))
+				Expect(big.NewInt(0).Exp(generator, order, VSSP).Cmp(big.NewInt(1))).Should(Equal(0))
+			}
+			Expect(VSSG.Cmp(VSSH)).ShouldNot(Equal(0))
+		})
+
+		It("should derive the generators from their labels", func() {
+			Expect(VSSG.Cmp(DeriveVSSGenerator(VSSGLabel))).Should(Equal(0))
+			Expect(VSSH.Cmp(DeriveVSSGenerator(VSSHLabel))).Should(Equal(0))
+			Expect(DeriveVSSGenerator("label").Cmp(DeriveVSSGenerator("label"))).Should(Equal(0))
+		})
+	})
+})
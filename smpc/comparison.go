@@ -0,0 +1,591 @@
+package smpc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+// ComparisonBits is the number of bits needed to represent any value in the
+// finite field defined by the shamir.Prime.
+const ComparisonBits = 64
+
+// ErrUnexpectedPredicate is returned when a Predicate is not one of the
+// explicitly enumerated values.
+var ErrUnexpectedPredicate = errors.New("unexpected predicate")
+
+// ErrComparisonLengthUnequal is returned when a Comparison does not have one
+// shamir.Share for each Predicate.
+var ErrComparisonLengthUnequal = errors.New("comparison length unequal")
+
+// ErrInsufficientComparisonValues is returned when there are not enough
+// ComparisonValues to compare a shamir.Share using a Predicate.
+var ErrInsufficientComparisonValues = errors.New("insufficient comparison values")
+
+// ErrUnexpectedShareIndex is returned when the shamir.Shares of a Comparison
+// do not have the same index as the shamir.Shares of the ComparisonValues.
+var ErrUnexpectedShareIndex = errors.New("unexpected share index")
+
+// ErrUnexpectedOpenedValues is returned when the number of opened values
+// passed to a ComparisonMachine is not the number of shamir.Shares that it
+// needed to open.
+var ErrUnexpectedOpenedValues = errors.New("unexpected opened values")
+
+// A Predicate is a relation between a shared value and zero. Shared values
+// are signed, so values greater than half of the shamir.Prime are negative.
+type Predicate byte
+
+// Values for Predicate.
+const (
+	PredicateLessThanZero    = Predicate(1)
+	PredicateGreaterThanZero = Predicate(2)
+	PredicateEqualToZero     = Predicate(3)
+)
+
+// String returns the human-readable representation of a Predicate.
+func (predicate Predicate) String() string {
+	switch predicate {
+	case PredicateLessThanZero:
+		return "lessThanZero"
+	case PredicateGreaterThanZero:
+		return "greaterThanZero"
+	case PredicateEqualToZero:
+		return "equalToZero"
+	}
+	return ""
+}
+
+// Evaluate the Predicate on a value that is not shared.
+func (predicate Predicate) Evaluate(value uint64) bool {
+	value %= shamir.Prime
+	switch predicate {
+	case PredicateLessThanZero:
+		return value > shamir.Prime/2
+	case PredicateGreaterThanZero:
+		return value > 0 && value <= shamir.Prime/2
+	case PredicateEqualToZero:
+		return value == 0
+	}
+	return false
+}
+
+// A Comparison of shared values with zero. It reveals whether or not each
+// value satisfies its Predicate, and nothing else about the values. All
+// shamir.Shares in a Comparison must have the same index value.
+type Comparison struct {
+	ID         JoinID
+	Index      JoinIndex
+	Shares     shamir.Shares
+	Predicates []Predicate
+}
+
+// A ComparisonCallback is called with the results of a Comparison, in the
+// same order as the Predicates of the Comparison.
+type ComparisonCallback func(JoinID, []bool)
+
+// A BeaverTriple is a share of the random values A and B, and a share of
+// their product C. Two shared values are multiplied by opening their
+// differences with A and B, which reveals nothing about the values.
+type BeaverTriple struct {
+	A shamir.Share
+	B shamir.Share
+	C shamir.Share
+}
+
+// ComparisonValues are shares of the random values that are consumed when
+// comparing a shared value using a Predicate. They must be generated before
+// the Comparison, must never be used for more than one Comparison, and must
+// not be known by any node that sees the values opened during the
+// Comparison.
+type ComparisonValues struct {
+
+	// Bits of a random value that is less than the shamir.Prime, starting
+	// with the least significant bit. Bits are used by PredicateLessThanZero
+	// and PredicateGreaterThanZero.
+	Bits shamir.Shares
+
+	// Random is a random value that is not zero. It is used by
+	// PredicateEqualToZero.
+	Random shamir.Share
+
+	// Triples are used to multiply shared values.
+	Triples []BeaverTriple
+}
+
+// DealComparisonValues generates the ComparisonValues needed to compare a
+// shared value using a Predicate, and splits them into n ComparisonValues, k
+// of which are needed to reconstruct the random values. The values are
+// sampled from crypto/rand.
+func DealComparisonValues(n, k int64, predicate Predicate) ([]ComparisonValues, error) {
+	return DealComparisonValuesWithReader(n, k, predicate, rand.Reader)
+}
+
+// DealComparisonValuesWithReader generates ComparisonValues in the same way
+// as DealComparisonValues, but samples the values from an io.Reader. The
+// io.Reader must be a cryptographically secure source of randomness, unless
+// the ComparisonValues are only used for testing.
+func DealComparisonValuesWithReader(n, k int64, predicate Predicate, reader io.Reader) ([]ComparisonValues, error) {
+	values := make([]ComparisonValues, n)
+
+	switch predicate {
+	case PredicateLessThanZero, PredicateGreaterThanZero:
+		mask, err := shamir.RandomValue(reader)
+		if err != nil {
+			return nil, err
+		}
+		for i := uint(0); i < ComparisonBits; i++ {
+			shares, err := shamir.SplitWithReader(n, k, (mask>>i)&1, reader)
+			if err != nil {
+				return nil, err
+			}
+			for j := range values {
+				values[j].Bits = append(values[j].Bits, shares[j])
+			}
+		}
+
+	case PredicateEqualToZero:
+		random := uint64(0)
+		for random == 0 {
+			var err error
+			if random, err = shamir.RandomValue(reader); err != nil {
+				return nil, err
+			}
+		}
+		shares, err := shamir.SplitWithReader(n, k, random, reader)
+		if err != nil {
+			return nil, err
+		}
+		for j := range values {
+			values[j].Random = shares[j]
+		}
+
+	default:
+		return nil, ErrUnexpectedPredicate
+	}
+
+	for t := 0; t < comparisonTriples(predicate); t++ {
+		a, err := shamir.RandomValue(reader)
+		if err != nil {
+			return nil, err
+		}
+		b, err := shamir.RandomValue(reader)
+		if err != nil {
+			return nil, err
+		}
+		c := constant(0, a)
+		c = c.Mul(b)
+
+		as, err := shamir.SplitWithReader(n, k, a, reader)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := shamir.SplitWithReader(n, k, b, reader)
+		if err != nil {
+			return nil, err
+		}
+		cs, err := shamir.SplitWithReader(n, k, c.Value, reader)
+		if err != nil {
+			return nil, err
+		}
+		for j := range values {
+			values[j].Triples = append(values[j].Triples, BeaverTriple{A: as[j], B: bs[j], C: cs[j]})
+		}
+	}
+
+	return values, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (values *ComparisonValues) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, int64(len(values.Bits))); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, values.Bits); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, values.Random); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(values.Triples))); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, values.Triples); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (values *ComparisonValues) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	return values.read(buf)
+}
+
+func (values *ComparisonValues) read(buf *bytes.Buffer) error {
+	numBits := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numBits); err != nil {
+		return err
+	}
+	if numBits < 0 || numBits > int64(buf.Len()/16) {
+		return io.ErrUnexpectedEOF
+	}
+	values.Bits = nil
+	if numBits > 0 {
+		values.Bits = make(shamir.Shares, numBits)
+		if err := binary.Read(buf, binary.BigEndian, values.Bits); err != nil {
+			return err
+		}
+	}
+	if err := binary.Read(buf, binary.BigEndian, &values.Random); err != nil {
+		return err
+	}
+	numTriples := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numTriples); err != nil {
+		return err
+	}
+	if numTriples < 0 || numTriples > int64(buf.Len()/48) {
+		return io.ErrUnexpectedEOF
+	}
+	values.Triples = make([]BeaverTriple, numTriples)
+	return binary.Read(buf, binary.BigEndian, values.Triples)
+}
+
+// validate that the ComparisonValues can be used to compare a shamir.Share,
+// with an index, using a Predicate.
+func (values *ComparisonValues) validate(predicate Predicate, index uint64) error {
+	shares := shamir.Shares{}
+	switch predicate {
+	case PredicateLessThanZero, PredicateGreaterThanZero:
+		if len(values.Bits) != ComparisonBits {
+			return ErrInsufficientComparisonValues
+		}
+		shares = append(shares, values.Bits...)
+	case PredicateEqualToZero:
+		shares = append(shares, values.Random)
+	default:
+		return ErrUnexpectedPredicate
+	}
+	if len(values.Triples) < comparisonTriples(predicate) {
+		return ErrInsufficientComparisonValues
+	}
+	for _, triple := range values.Triples {
+		shares = append(shares, triple.A, triple.B, triple.C)
+	}
+	for _, share := range shares {
+		if share.Index != index {
+			return ErrUnexpectedShareIndex
+		}
+	}
+	return nil
+}
+
+// comparisonTriples returns the number of BeaverTriples consumed when
+// comparing a shared value using a Predicate.
+func comparisonTriples(predicate Predicate) int {
+	switch predicate {
+	case PredicateLessThanZero, PredicateGreaterThanZero:
+		// Composing the function of each bit uses two multiplications, and
+		// one more is needed to compute the parity
+		return 2*(ComparisonBits-1) + 1
+	case PredicateEqualToZero:
+		return 1
+	}
+	return 0
+}
+
+// A comparisonStep takes the values that were opened by the network, and
+// returns the shamir.Shares that must be opened before the next
+// comparisonStep. When there is no next comparisonStep, the only shamir.Share
+// returned is a share of the result.
+type comparisonStep func(opened []uint64) (shamir.Shares, comparisonStep)
+
+// A ComparisonMachine computes shares of the results of a Comparison in
+// rounds. In each round, the shamir.Shares returned by Open must be opened by
+// the network, and the opened values are passed to Next. Once the
+// ComparisonMachine is done, the shares returned by Results can be opened to
+// reveal the results, where one means the Predicate is satisfied and zero
+// means it is not.
+type ComparisonMachine struct {
+	steps   []comparisonStep
+	opening []shamir.Shares
+}
+
+// NewComparisonMachine returns a ComparisonMachine for a Comparison that
+// consumes one ComparisonValues for each Predicate.
+func NewComparisonMachine(comparison Comparison, values []ComparisonValues) (*ComparisonMachine, error) {
+	if len(comparison.Shares) != len(comparison.Predicates) {
+		return nil, ErrComparisonLengthUnequal
+	}
+	if len(values) != len(comparison.Predicates) {
+		return nil, ErrInsufficientComparisonValues
+	}
+
+	machine := &ComparisonMachine{
+		steps:   make([]comparisonStep, len(comparison.Predicates)),
+		opening: make([]shamir.Shares, len(comparison.Predicates)),
+	}
+	for i, predicate := range comparison.Predicates {
+		share := comparison.Shares[i]
+		if err := values[i].validate(predicate, share.Index); err != nil {
+			return nil, err
+		}
+		switch predicate {
+		case PredicateLessThanZero:
+			machine.opening[i], machine.steps[i] = lessThanZero(share, values[i])
+		case PredicateGreaterThanZero:
+			// A value is greater than zero when its negation is less than
+			// zero
+			zero := constant(share.Index, 0)
+			machine.opening[i], machine.steps[i] = lessThanZero(zero.Sub(&share), values[i])
+		case PredicateEqualToZero:
+			machine.opening[i], machine.steps[i] = equalToZero(share, values[i])
+		}
+	}
+	return machine, nil
+}
+
+// Open returns the shamir.Shares that must be opened in this round.
+func (machine *ComparisonMachine) Open() shamir.Shares {
+	shares := shamir.Shares{}
+	for i, step := range machine.steps {
+		if step != nil {
+			shares = append(shares, machine.opening[i]...)
+		}
+	}
+	return shares
+}
+
+// Next round of the ComparisonMachine, using the values opened from the
+// shamir.Shares returned by Open. The values must be in the same order as the
+// shamir.Shares.
+func (machine *ComparisonMachine) Next(opened []uint64) error {
+	if len(opened) != len(machine.Open()) {
+		return ErrUnexpectedOpenedValues
+	}
+	for i, step := range machine.steps {
+		if step == nil {
+			continue
+		}
+		n := len(machine.opening[i])
+		machine.opening[i], machine.steps[i] = step(opened[:n])
+		opened = opened[n:]
+	}
+	return nil
+}
+
+// Done returns true when the ComparisonMachine has computed shares of all
+// results.
+func (machine *ComparisonMachine) Done() bool {
+	for _, step := range machine.steps {
+		if step != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Results returns shares of the results, in the same order as the Predicates
+// of the Comparison. It must only be called when the ComparisonMachine is
+// done.
+func (machine *ComparisonMachine) Results() shamir.Shares {
+	results := make(shamir.Shares, len(machine.opening))
+	for i := range results {
+		results[i] = machine.opening[i][0]
+	}
+	return results
+}
+
+// lessThanZero computes a share of whether or not a shared value is less than
+// zero. Doubling a negative value wraps around the odd shamir.Prime, so a
+// value is negative when its double is odd. The parity of the double is
+// computed by opening it masked with a random value, for which shares of the
+// bits are known. The parity is the parity of the opened value, flipped by
+// the parity of the random value, and flipped again when adding the random
+// value wrapped around the shamir.Prime.
+func lessThanZero(x shamir.Share, values ComparisonValues) (shamir.Shares, comparisonStep) {
+	mask := constant(x.Index, 0)
+	for i := len(values.Bits) - 1; i >= 0; i-- {
+		mask = mask.Mul(2)
+		mask = mask.Add(&values.Bits[i])
+	}
+	double := x.Mul(2)
+	masked := double.Add(&mask)
+
+	return shamir.Shares{masked}, func(opened []uint64) (shamir.Shares, comparisonStep) {
+		c := opened[0]
+		triples := values.Triples[:comparisonTriples(PredicateLessThanZero)]
+		return lessThanBits(c, values.Bits, triples[:len(triples)-1], func(wrapped shamir.Share) (shamir.Shares, comparisonStep) {
+			return multiply(shamir.Shares{values.Bits[0]}, shamir.Shares{wrapped}, triples[len(triples)-1:], func(products shamir.Shares) (shamir.Shares, comparisonStep) {
+				// The exclusive or of two bits is their sum minus twice
+				// their product
+				twice := products[0].Mul(2)
+				parity := values.Bits[0].Add(&wrapped)
+				parity = parity.Sub(&twice)
+				if c&1 == 1 {
+					one := constant(x.Index, 1)
+					parity = one.Sub(&parity)
+				}
+				return shamir.Shares{parity}, nil
+			})
+		})
+	}
+}
+
+// lessThanBits computes a share of whether or not a public value is less than
+// a shared value, using shares of the bits of the shared value. Each bit
+// defines an affine function that maps whether or not the lower bits of the
+// public value are less than the lower bits of the shared value, to whether
+// or not this is still true after including the bit. The functions are
+// composed as a tree, so the number of rounds is logarithmic in the number of
+// bits.
+func lessThanBits(c uint64, bits shamir.Shares, triples []BeaverTriple, then func(shamir.Share) (shamir.Shares, comparisonStep)) (shamir.Shares, comparisonStep) {
+	as := make(shamir.Shares, len(bits))
+	bs := make(shamir.Shares, len(bits))
+	for i := range bits {
+		if (c>>uint(i))&1 == 0 {
+			// The shared value is greater if its bit is one, otherwise the
+			// lower bits decide
+			one := constant(bits[i].Index, 1)
+			as[i] = bits[i]
+			bs[i] = one.Sub(&bits[i])
+			continue
+		}
+		// The shared value is greater if its bit is one and the lower bits
+		// decide that it is greater
+		as[i] = constant(bits[i].Index, 0)
+		bs[i] = bits[i]
+	}
+	// Before any bits are included, the public value is not less than the
+	// shared value, so the result is the constant of the composed function
+	return composeAffine(as, bs, triples, then)
+}
+
+// composeAffine functions, defined by their constants and their
+// coefficients, from the first function to the last function. Adjacent
+// functions are composed in each round.
+func composeAffine(as, bs shamir.Shares, triples []BeaverTriple, then func(shamir.Share) (shamir.Shares, comparisonStep)) (shamir.Shares, comparisonStep) {
+	if len(as) == 1 {
+		return then(as[0])
+	}
+
+	pairs := len(as) / 2
+	xs := make(shamir.Shares, 0, 2*pairs)
+	ys := make(shamir.Shares, 0, 2*pairs)
+	for i := 0; i < pairs; i++ {
+		lower, upper := 2*i, 2*i+1
+		xs = append(xs, bs[upper], bs[upper])
+		ys = append(ys, as[lower], bs[lower])
+	}
+
+	return multiply(xs, ys, triples[:2*pairs], func(products shamir.Shares) (shamir.Shares, comparisonStep) {
+		nextAs := make(shamir.Shares, 0, pairs+1)
+		nextBs := make(shamir.Shares, 0, pairs+1)
+		for i := 0; i < pairs; i++ {
+			upper := 2*i + 1
+			nextAs = append(nextAs, as[upper].Add(&products[2*i]))
+			nextBs = append(nextBs, products[2*i+1])
+		}
+		if len(as)%2 == 1 {
+			nextAs = append(nextAs, as[len(as)-1])
+			nextBs = append(nextBs, bs[len(bs)-1])
+		}
+		return composeAffine(nextAs, nextBs, triples[2*pairs:], then)
+	})
+}
+
+// equalToZero computes a share of whether or not a shared value is zero. The
+// value is multiplied by a random value that is not zero, and the product is
+// opened. The product is zero when the value is zero, and is otherwise
+// uniformly random.
+func equalToZero(x shamir.Share, values ComparisonValues) (shamir.Shares, comparisonStep) {
+	return multiply(shamir.Shares{values.Random}, shamir.Shares{x}, values.Triples[:1], func(products shamir.Shares) (shamir.Shares, comparisonStep) {
+		return products, func(opened []uint64) (shamir.Shares, comparisonStep) {
+			if opened[0] == 0 {
+				return shamir.Shares{constant(x.Index, 1)}, nil
+			}
+			return shamir.Shares{constant(x.Index, 0)}, nil
+		}
+	})
+}
+
+// multiply pairs of shared values using BeaverTriples. The differences
+// between the values and the random values of the BeaverTriples are opened,
+// and then shares of the products are passed to the continuation.
+func multiply(xs, ys shamir.Shares, triples []BeaverTriple, then func(shamir.Shares) (shamir.Shares, comparisonStep)) (shamir.Shares, comparisonStep) {
+	open := make(shamir.Shares, 0, 2*len(xs))
+	for i := range xs {
+		open = append(open, xs[i].Sub(&triples[i].A), ys[i].Sub(&triples[i].B))
+	}
+
+	return open, func(opened []uint64) (shamir.Shares, comparisonStep) {
+		products := make(shamir.Shares, len(xs))
+		for i := range products {
+			// Given d = x - a and e = y - b, the product xy is equal to
+			// c + db + ea + de
+			d, e := opened[2*i], opened[2*i+1]
+			db := triples[i].B.Mul(d)
+			ea := triples[i].A.Mul(e)
+			de := constant(triples[i].C.Index, d)
+			de = de.Mul(e)
+
+			product := triples[i].C.Add(&db)
+			product = product.Add(&ea)
+			products[i] = product.Add(&de)
+		}
+		return then(products)
+	}
+}
+
+// constant returns a share of a public value. The polynomial of a public
+// value has no other coefficients, so every share is the value itself.
+func constant(index, value uint64) shamir.Share {
+	return shamir.Share{
+		Index: index,
+		Value: value % shamir.Prime,
+	}
+}
+
+// comparisonRoundResults is the round in which the shares of the results of a
+// Comparison are opened.
+const comparisonRoundResults = 0xFF
+
+// comparisonJoinID returns the JoinID used to open a chunk of the shamir.Shares
+// in a round of a Comparison.
+func comparisonJoinID(id JoinID, round, chunk int) JoinID {
+	joinID := JoinID{}
+	copy(joinID[:], crypto.Keccak256(id[:], []byte{byte(round), byte(chunk)}))
+	joinID[32] = id[32]
+	return joinID
+}
+
+// comparisonDealer returns the address of the node that deals the
+// ComparisonValues for a Comparison.
+//
+// A single dealer is a weaker trust model than the threshold of the network.
+// The dealer knows the random values, so it is excluded from the Joins of the
+// Comparison, but a dealer that colludes with one other node learns the
+// values opened in the Joins and can unmask the compared values. Comparisons
+// are therefore only private if the dealer does not collude with any other
+// node. The dealer is chosen by the JoinID, so that no node can choose to
+// deal a Comparison, and every node deals an equal share of Comparisons.
+//
+// The ComparisonValues are not generated jointly, because they cannot be
+// summed over several dealers. The mask of PredicateEqualToZero could be
+// summed, but a sum of bits is not a bit and a sum of BeaverTriples is not a
+// BeaverTriple. Converting the sums needs secure multiplication, which either
+// consumes BeaverTriples that must be generated in the same way, or needs
+// shamir.Shares to be multiplied without them. Multiplying shamir.Shares
+// doubles their degree, and the product of two shamir.Shares is only
+// reconstructable by n nodes when the threshold is no more than half of the
+// network. The threshold of a network is two thirds of its nodes.
+func comparisonDealer(id JoinID, addrs identity.Addresses) identity.Address {
+	hash := crypto.Keccak256(id[:])
+	return addrs[binary.BigEndian.Uint64(hash[:8])%uint64(len(addrs))]
+}
@@ -0,0 +1,233 @@
+package smpc_test
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/shamir"
+)
+
+var _ = Describe("Comparisons", func() {
+
+	numNodes := int64(24)
+	threshold := 2 * (numNodes + 1) / 3
+	predicates := []Predicate{PredicateLessThanZero, PredicateGreaterThanZero, PredicateEqualToZero}
+
+	Context("when evaluating predicates", func() {
+
+		It("should interpret values greater than half of the prime as negative", func() {
+			Expect(PredicateLessThanZero.Evaluate(shamir.Prime - 1)).Should(BeTrue())
+			Expect(PredicateLessThanZero.Evaluate(shamir.Prime/2 + 1)).Should(BeTrue())
+			Expect(PredicateLessThanZero.Evaluate(shamir.Prime / 2)).Should(BeFalse())
+			Expect(PredicateLessThanZero.Evaluate(0)).Should(BeFalse())
+
+			Expect(PredicateGreaterThanZero.Evaluate(shamir.Prime / 2)).Should(BeTrue())
+			Expect(PredicateGreaterThanZero.Evaluate(1)).Should(BeTrue())
+			Expect(PredicateGreaterThanZero.Evaluate(0)).Should(BeFalse())
+			Expect(PredicateGreaterThanZero.Evaluate(shamir.Prime - 1)).Should(BeFalse())
+
+			Expect(PredicateEqualToZero.Evaluate(0)).Should(BeTrue())
+			Expect(PredicateEqualToZero.Evaluate(shamir.Prime)).Should(BeTrue())
+			Expect(PredicateEqualToZero.Evaluate(1)).Should(BeFalse())
+		})
+	})
+
+	Context("when comparing shared values", func() {
+
+		It("should reveal whether or not the values satisfy the predicates", func() {
+			values := []uint64{0, 1, 2, shamir.Prime - 1, shamir.Prime - 2, shamir.Prime / 2, shamir.Prime/2 + 1, shamir.Prime/2 - 1}
+			for i := 0; i < 8; i++ {
+				values = append(values, rand.Uint64()%shamir.Prime)
+			}
+			for _, value := range values {
+				results, err := simulateComparison(numNodes, threshold, predicates, []uint64{value, value, value})
+				Expect(err).ShouldNot(HaveOccurred())
+				for i, predicate := range predicates {
+					Expect(results[i]).Should(Equal(predicate.Evaluate(value)), "%v of %v", predicate, value)
+				}
+			}
+		})
+
+		It("should compare the differences between shared values", func() {
+			for i := 0; i < 8; i++ {
+				lhs, rhs := rand.Uint64()%(1<<62), rand.Uint64()%(1<<62)
+				lhsShares, err := shamir.Split(numNodes, threshold, lhs)
+				Expect(err).ShouldNot(HaveOccurred())
+				rhsShares, err := shamir.Split(numNodes, threshold, rhs)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				differences := make([]shamir.Shares, numNodes)
+				for j := range differences {
+					difference := lhsShares[j].Sub(&rhsShares[j])
+					differences[j] = shamir.Shares{difference, difference}
+				}
+				results, err := simulateComparisonOfShares(numNodes, threshold, []Predicate{PredicateLessThanZero, PredicateEqualToZero}, differences)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(results).Should(Equal([]bool{lhs < rhs, lhs == rhs}))
+			}
+		})
+
+		It("should not open the compared values", func() {
+			value := uint64(42)
+			shares, err := shamir.Split(numNodes, threshold, value)
+			Expect(err).ShouldNot(HaveOccurred())
+			values, err := DealComparisonValues(numNodes, threshold, PredicateLessThanZero)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			machines := make([]*ComparisonMachine, threshold)
+			for j := range machines {
+				machines[j], err = NewComparisonMachine(Comparison{Index: JoinIndex(j + 1), Shares: shamir.Shares{shares[j]}, Predicates: []Predicate{PredicateLessThanZero}}, []ComparisonValues{values[j]})
+				Expect(err).ShouldNot(HaveOccurred())
+			}
+			for !machines[0].Done() {
+				opened := openRound(machines)
+				Expect(opened).ShouldNot(ContainElement(value))
+				Expect(opened).ShouldNot(ContainElement(2 * value))
+				for j := range machines {
+					Expect(machines[j].Next(opened)).ShouldNot(HaveOccurred())
+				}
+			}
+		})
+	})
+
+	Context("when using comparison values", func() {
+
+		It("should return an error when there are not enough values", func() {
+			values, err := DealComparisonValues(numNodes, threshold, PredicateEqualToZero)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			share := shamir.Share{Index: 1, Value: 1}
+			_, err = NewComparisonMachine(Comparison{Index: 1, Shares: shamir.Shares{share}, Predicates: []Predicate{PredicateLessThanZero}}, []ComparisonValues{values[0]})
+			Expect(err).Should(Equal(ErrInsufficientComparisonValues))
+			_, err = NewComparisonMachine(Comparison{Index: 1, Shares: shamir.Shares{share, share}, Predicates: []Predicate{PredicateEqualToZero, PredicateEqualToZero}}, []ComparisonValues{values[0]})
+			Expect(err).Should(Equal(ErrInsufficientComparisonValues))
+			_, err = NewComparisonMachine(Comparison{Index: 1, Shares: shamir.Shares{share}, Predicates: []Predicate{PredicateEqualToZero, PredicateEqualToZero}}, values[:2])
+			Expect(err).Should(Equal(ErrComparisonLengthUnequal))
+		})
+
+		It("should return an error when the values are for a different index", func() {
+			values, err := DealComparisonValues(numNodes, threshold, PredicateEqualToZero)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			share := shamir.Share{Index: 2, Value: 1}
+			_, err = NewComparisonMachine(Comparison{Index: 2, Shares: shamir.Shares{share}, Predicates: []Predicate{PredicateEqualToZero}}, []ComparisonValues{values[0]})
+			Expect(err).Should(Equal(ErrUnexpectedShareIndex))
+		})
+
+		It("should return an error for unexpected predicates", func() {
+			_, err := DealComparisonValues(numNodes, threshold, Predicate(0))
+			Expect(err).Should(Equal(ErrUnexpectedPredicate))
+		})
+
+		It("should equal itself after marshaling and unmarshaling to binary", func() {
+			for _, predicate := range predicates {
+				values, err := DealComparisonValues(numNodes, threshold, predicate)
+				Expect(err).ShouldNot(HaveOccurred())
+
+				data, err := values[0].MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+				unmarshaledValues := ComparisonValues{}
+				Expect(unmarshaledValues.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+				Expect(unmarshaledValues).Should(Equal(values[0]))
+			}
+		})
+
+		It("should return an error when unmarshaling partial values", func() {
+			values, err := DealComparisonValues(numNodes, threshold, PredicateLessThanZero)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			data, err := values[0].MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+			unmarshaledValues := ComparisonValues{}
+			Expect(unmarshaledValues.UnmarshalBinary(data[:len(data)-1])).Should(HaveOccurred())
+		})
+	})
+})
+
+func simulateComparison(n, k int64, predicates []Predicate, secrets []uint64) ([]bool, error) {
+	shares := make([]shamir.Shares, n)
+	for _, secret := range secrets {
+		secretShares, err := shamir.Split(n, k, secret)
+		if err != nil {
+			return nil, err
+		}
+		for j := range shares {
+			shares[j] = append(shares[j], secretShares[j])
+		}
+	}
+	return simulateComparisonOfShares(n, k, predicates, shares)
+}
+
+// simulateComparisonOfShares runs a ComparisonMachine for each node, except
+// for the first node which is the dealer, and opens the shamir.Shares of each
+// round using the first k of them.
+func simulateComparisonOfShares(n, k int64, predicates []Predicate, shares []shamir.Shares) ([]bool, error) {
+	dealt := make([][]ComparisonValues, len(predicates))
+	for i, predicate := range predicates {
+		var err error
+		if dealt[i], err = DealComparisonValues(n, k, predicate); err != nil {
+			return nil, err
+		}
+	}
+
+	machines := make([]*ComparisonMachine, n-1)
+	for j := range machines {
+		values := make([]ComparisonValues, len(predicates))
+		for i := range predicates {
+			values[i] = dealt[i][j+1]
+		}
+		comparison := Comparison{
+			Index:      JoinIndex(shares[j+1][0].Index),
+			Shares:     shares[j+1],
+			Predicates: predicates,
+		}
+		var err error
+		if machines[j], err = NewComparisonMachine(comparison, values); err != nil {
+			return nil, err
+		}
+	}
+
+	for !machines[0].Done() {
+		opened := openRound(machines[:k])
+		for j := range machines {
+			if err := machines[j].Next(opened); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	results := make([]bool, len(predicates))
+	for i := range results {
+		resultShares := make(shamir.Shares, k)
+		for j := range resultShares {
+			resultShares[j] = machines[j].Results()[i]
+		}
+		result, err := shamir.Join(resultShares)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result == 1
+	}
+	return results, nil
+}
+
+func openRound(machines []*ComparisonMachine) []uint64 {
+	opening := make([]shamir.Shares, len(machines))
+	for j := range machines {
+		opening[j] = machines[j].Open()
+	}
+	opened := make([]uint64, len(opening[0]))
+	for i := range opened {
+		shares := make(shamir.Shares, len(machines))
+		for j := range shares {
+			shares[j] = opening[j][i]
+		}
+		value, err := shamir.Join(shares)
+		Expect(err).ShouldNot(HaveOccurred())
+		opened[i] = value
+	}
+	return opened
+}
@@ -0,0 +1,38 @@
+package smpc
+
+import "time"
+
+// Export the identifiers used by Comparisons so that tests can send the
+// messages of a malicious node.
+var (
+	ComparisonJoinID = comparisonJoinID
+	ComparisonDealer = comparisonDealer
+)
+
+// ComparisonRoundResults is the round in which the results of a Comparison
+// are opened.
+const ComparisonRoundResults = comparisonRoundResults
+
+// PruneBefore removes the state of Joins, and Comparisons, that was stored by
+// an Smpcer before a time.
+func PruneBefore(node Smpcer, before time.Time) {
+	node.(*smpcer).pruneBefore(before)
+}
+
+// StateLen returns the number of Joins sent by an Smpcer that are stored, and
+// the number of Comparisons that are waiting to start.
+func StateLen(node Smpcer) (int, int) {
+	smpc := node.(*smpcer)
+
+	smpc.selfJoinsMu.RLock()
+	selfJoins := len(smpc.selfJoins)
+	smpc.selfJoinsMu.RUnlock()
+
+	smpc.comparisonsMu.Lock()
+	defer smpc.comparisonsMu.Unlock()
+	comparisons := 0
+	for _, states := range smpc.comparisons {
+		comparisons += len(states)
+	}
+	return selfJoins, comparisons
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/shamir"
@@ -16,6 +18,11 @@ import (
 // reconstructed by a JoinSet.
 const MaxJoinLength = 16
 
+// MaxLagrangeCoefficientsCacheLength restricts the number of index sets for
+// which a Joiner caches Lagrange coefficients. The cache is cleared when it is
+// full.
+const MaxLagrangeCoefficientsCacheLength = 256
+
 // ErrJoinLengthUnequal is returned when two Joins with the same JoinID have a
 // different number of shamir.Shares.
 var ErrJoinLengthUnequal = errors.New("join length unequal")
@@ -24,6 +31,11 @@ var ErrJoinLengthUnequal = errors.New("join length unequal")
 // compared to the MaxJoinLength.
 var ErrJoinLengthExceedsMax = errors.New("join length exceeds max")
 
+// ErrUnexpectedJoinIndex is returned when a Join has a JoinIndex that is zero,
+// or not in the finite field, or when the shamir.Shares in a Join do not all
+// have the JoinIndex as their index.
+var ErrUnexpectedJoinIndex = errors.New("unexpected join index")
+
 // ErrUnverifiedJoin is returned when the computations in a Join cannot be
 // verified using JoinCommitments.
 var ErrUnverifiedJoin = errors.New("unverified join")
@@ -35,7 +47,7 @@ type JoinID [33]byte
 
 // A Join is used to join a set of shamir.Shares. The shamir.Shares within a
 // Join are all associated with different shared values. All shamir.Shares must
-// have the same index value.
+// have the JoinIndex as their index.
 type Join struct {
 	ID        JoinID
 	Index     JoinIndex
@@ -150,28 +162,36 @@ type JoinSet struct {
 
 	// Callback for when the reconstruction happens.
 	Callback Callback
+
+	// Timestamp at which the JoinSet was created, used to prune JoinSets that
+	// are never reconstructed.
+	Timestamp time.Time
 }
 
 // A Joiner received Joins and groups them together based on their JoinID. Once
 // a sufficient number of Joins have been collected, the shamir.Shares are
 // zipped across all Joins, and each zip is reconstructed into a value.
 type Joiner struct {
-	k     int64
-	cache shamir.Shares
+	k int64
 
 	joinSetsMu *sync.Mutex
 	joinSets   map[JoinID]JoinSet
+
+	// Lagrange coefficients for the sorted sets of shamir.Share indices that
+	// have been used to reconstruct values, protected by the joinSetsMu
+	coefficients map[string][]uint64
 }
 
 // NewJoiner returns an empty Joiner that needs k shamir.Shares before it can
 // reconstruct a value.
 func NewJoiner(k int64) *Joiner {
 	return &Joiner{
-		k:     k,
-		cache: make(shamir.Shares, k),
+		k: k,
 
 		joinSetsMu: new(sync.Mutex),
 		joinSets:   map[JoinID]JoinSet{},
+
+		coefficients: map[string][]uint64{},
 	}
 }
 
@@ -181,19 +201,58 @@ func NewJoiner(k int64) *Joiner {
 // after it is called. Passing a nil Callback will remove any existing
 // Callback.
 func (joiner *Joiner) InsertJoinAndSetCallback(join Join, callback Callback) error {
-	return joiner.insertJoin(join, callback, true)
+	return joiner.insertJoin(join.ID, &join, callback, true)
 }
 
 // InsertJoin for a JoinID. If a Callback has been set for this JoinID it will
 // be called if the insertion results in a successful reconstruction of values.
 func (joiner *Joiner) InsertJoin(join Join) error {
-	return joiner.insertJoin(join, nil, false)
+	return joiner.insertJoin(join.ID, &join, nil, false)
+}
+
+// SetCallback for a JoinID without inserting a Join. This is used when the
+// values reconstructed from the Joins of other nodes are needed, but there is
+// no Join to insert. The Callback is called in the same way as Callbacks set
+// by Joiner.InsertJoinAndSetCallback.
+func (joiner *Joiner) SetCallback(joinID JoinID, callback Callback) {
+	// Without a Join there is nothing that can fail
+	joiner.insertJoin(joinID, nil, callback, true)
 }
 
-func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback bool) error {
-	if len(join.Shares) > MaxJoinLength {
+// Delete the JoinSet for a JoinID. Joins that are inserted after the JoinSet
+// has been deleted are stored in a new JoinSet.
+func (joiner *Joiner) Delete(joinID JoinID) {
+	joiner.joinSetsMu.Lock()
+	defer joiner.joinSetsMu.Unlock()
+
+	delete(joiner.joinSets, joinID)
+}
+
+// Prune all JoinSets that were created before a time, and return the number
+// of JoinSets that were pruned.
+func (joiner *Joiner) Prune(before time.Time) int {
+	joiner.joinSetsMu.Lock()
+	defer joiner.joinSetsMu.Unlock()
+
+	n := 0
+	for joinID, joinSet := range joiner.joinSets {
+		if joinSet.Timestamp.Before(before) {
+			delete(joiner.joinSets, joinID)
+			n++
+		}
+	}
+	return n
+}
+
+func (joiner *Joiner) insertJoin(joinID JoinID, join *Join, callback Callback, overrideCallback bool) error {
+	if join != nil && len(join.Shares) > MaxJoinLength {
 		return ErrJoinLengthExceedsMax
 	}
+	if join != nil {
+		if err := verifyJoinIndex(join); err != nil {
+			return err
+		}
+	}
 
 	maybeCallback := Callback(nil)
 	maybeValues := [MaxJoinLength]uint64{}
@@ -204,9 +263,9 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 		defer joiner.joinSetsMu.Unlock()
 
 		// Load the JoinSet and store any mutations when this function returns
-		joinSet, ok := joiner.joinSets[join.ID]
+		joinSet, ok := joiner.joinSets[joinID]
 		defer func() {
-			joiner.joinSets[join.ID] = joinSet
+			joiner.joinSets[joinID] = joinSet
 		}()
 
 		// Initialize the JoinSet for this JoinID if it has not been initialized
@@ -214,17 +273,23 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 			joinSet = JoinSet{
 				Set:       map[JoinIndex]Join{},
 				Values:    [MaxJoinLength]uint64{},
-				ValuesLen: len(join.Shares),
+				Timestamp: time.Now(),
 			}
 		}
 
-		// Insert this join, if it is needed, and set the callback
-		if len(join.Shares) != joinSet.ValuesLen {
-			logger.Error(fmt.Sprintf("%v: expected %v, got %v", ErrJoinLengthUnequal, joinSet.ValuesLen, len(join.Shares)))
-			return ErrJoinLengthUnequal
-		}
-		if !joinSet.ValuesOk {
-			joinSet.Set[join.Index] = join
+		// Insert this join, if it is needed, and set the callback. The number
+		// of values is defined by the first Join that is inserted.
+		if join != nil {
+			if len(joinSet.Set) == 0 {
+				joinSet.ValuesLen = len(join.Shares)
+			}
+			if len(join.Shares) != joinSet.ValuesLen {
+				logger.Error(fmt.Sprintf("%v: expected %v, got %v", ErrJoinLengthUnequal, joinSet.ValuesLen, len(join.Shares)))
+				return ErrJoinLengthUnequal
+			}
+			if !joinSet.ValuesOk {
+				joinSet.Set[join.Index] = *join
+			}
 		}
 		if overrideCallback {
 			joinSet.Callback = callback
@@ -238,16 +303,8 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 
 		// If the reconstruction has not happened, perform the reconstruction
 		if !joinSet.ValuesOk {
-			for i := 0; i < joinSet.ValuesLen; i++ {
-				k := int64(0)
-				for _, join := range joinSet.Set {
-					joiner.cache[k] = join.Shares[i]
-					k++
-					if k >= joiner.k {
-						break
-					}
-				}
-				joinSet.Values[i] = shamir.Join(joiner.cache)
+			if err := joiner.reconstruct(&joinSet); err != nil {
+				return err
 			}
 			joinSet.ValuesOk = true
 		}
@@ -269,8 +326,84 @@ func (joiner *Joiner) insertJoin(join Join, callback Callback, overrideCallback
 	}
 
 	if maybeCallback != nil {
-		maybeCallback(join.ID, maybeValues[:maybeValuesLen])
+		maybeCallback(joinID, maybeValues[:maybeValuesLen])
+	}
+
+	return nil
+}
+
+// verifyJoinIndex returns ErrUnexpectedJoinIndex if a Join cannot be used to
+// reconstruct values. Joins are stored by their JoinIndex, so requiring all
+// shamir.Shares to have the JoinIndex as their index guarantees that the
+// indices used to reconstruct values are distinct.
+func verifyJoinIndex(join *Join) error {
+	if join.Index == 0 || uint64(join.Index) >= shamir.Prime {
+		return ErrUnexpectedJoinIndex
+	}
+	for _, share := range join.Shares {
+		if share.Index != uint64(join.Index) {
+			return ErrUnexpectedJoinIndex
+		}
+	}
+	return nil
+}
+
+// reconstruct the values of a JoinSet from the k Joins with the lowest
+// JoinIndices. All values are reconstructed using the same Lagrange
+// coefficients, which are cached for the indices of the Joins. An error is
+// returned if the indices of the shamir.Shares cannot be used to reconstruct
+// values. The joinSetsMu must be locked.
+func (joiner *Joiner) reconstruct(joinSet *JoinSet) error {
+	if joinSet.ValuesLen == 0 {
+		return nil
+	}
+
+	joinIndices := make([]JoinIndex, 0, len(joinSet.Set))
+	for joinIndex := range joinSet.Set {
+		joinIndices = append(joinIndices, joinIndex)
+	}
+	sort.Slice(joinIndices, func(i, j int) bool {
+		return joinIndices[i] < joinIndices[j]
+	})
+	joinIndices = joinIndices[:joiner.k]
+
+	// All shamir.Shares in a Join have been verified to have the JoinIndex
+	// as their index
+	vectors := make([]shamir.Shares, joiner.k)
+	indices := make([]uint64, joiner.k)
+	for j, joinIndex := range joinIndices {
+		vectors[j] = joinSet.Set[joinIndex].Shares
+		indices[j] = uint64(joinIndex)
 	}
 
+	coefficients, err := joiner.lagrangeCoefficients(indices)
+	if err != nil {
+		return err
+	}
+	values := shamir.JoinVectors(vectors, coefficients)
+	copy(joinSet.Values[:], values)
 	return nil
 }
+
+// lagrangeCoefficients returns the Lagrange coefficients for a set of sorted
+// shamir.Share indices, computing and caching them if they have not been used
+// before. The joinSetsMu must be locked.
+func (joiner *Joiner) lagrangeCoefficients(indices []uint64) ([]uint64, error) {
+	key := make([]byte, 8*len(indices))
+	for i, index := range indices {
+		binary.BigEndian.PutUint64(key[8*i:], index)
+	}
+	if coefficients, ok := joiner.coefficients[string(key)]; ok {
+		return coefficients, nil
+	}
+
+	coefficients, err := shamir.LagrangeCoefficients(indices)
+	if err != nil {
+		return nil, err
+	}
+	if len(joiner.coefficients) >= MaxLagrangeCoefficientsCacheLength {
+		joiner.coefficients = map[string][]uint64{}
+	}
+	joiner.coefficients[string(key)] = coefficients
+	return coefficients, nil
+}
@@ -2,7 +2,11 @@ package smpc_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"math/rand"
 	"sync/atomic"
+	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -91,6 +95,21 @@ var _ = Describe("Joiner", func() {
 			})
 		})
 
+		Context("when setting the callback without inserting a join", func() {
+			It("should call the callback after inserting n joins", func() {
+				ord, joins := generateJoins(n, k)
+				called := int64(0)
+				callback := generateCallback(&called, ord)
+
+				joiner.SetCallback(joins[0].ID, callback)
+				for i := int64(0); i < n; i++ {
+					Expect(atomic.LoadInt64(&called)).Should(Equal(int64(0)))
+					Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+				}
+				Expect(atomic.LoadInt64(&called)).Should(Equal(int64(1)))
+			})
+		})
+
 		Context("when inserting computed joins", func() {
 			It("should pass the computed values to the callback", func() {
 				joins := generateMatchedJoins(n, k)
@@ -120,6 +139,76 @@ var _ = Describe("Joiner", func() {
 		})
 	})
 
+	Context("when reconstructing values", func() {
+
+		It("should reconstruct values from the joins with the lowest indices", func() {
+			joiner := NewJoiner(k)
+			for iter := 0; iter < 3; iter++ {
+				secrets, joins := generateSecretJoins(n, k)
+				called := int64(0)
+				for i := n - 1; i >= 0; i-- {
+					if i == n-1 {
+						Expect(joiner.InsertJoinAndSetCallback(joins[i], func(id JoinID, values []uint64) {
+							atomic.AddInt64(&called, 1)
+							Expect(values).Should(Equal(secrets))
+						})).ShouldNot(HaveOccurred())
+					} else {
+						Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+					}
+				}
+				Expect(atomic.LoadInt64(&called)).Should(Equal(int64(1)))
+			}
+		})
+
+		It("should reject joins when the shares in a join have different indices", func() {
+			joiner := NewJoiner(k)
+			secrets, joins := generateSecretJoins(n, k)
+			// Rotate the shares of the last value so that they no longer have
+			// the same index as the other shares in their join
+			last := joins[0].Shares[len(secrets)-1]
+			for i := int64(0); i < n-1; i++ {
+				joins[i].Shares[len(secrets)-1] = joins[i+1].Shares[len(secrets)-1]
+			}
+			joins[n-1].Shares[len(secrets)-1] = last
+
+			called := int64(0)
+			callback := func(id JoinID, values []uint64) {
+				atomic.AddInt64(&called, 1)
+			}
+			for i := int64(0); i < k; i++ {
+				Expect(joiner.InsertJoinAndSetCallback(joins[i], callback)).Should(Equal(ErrUnexpectedJoinIndex))
+			}
+			Expect(atomic.LoadInt64(&called)).Should(Equal(int64(0)))
+		})
+
+		It("should reject joins with duplicate indices without panicking", func() {
+			joiner := NewJoiner(k)
+			secrets, joins := generateSecretJoins(n, k)
+
+			// A join that claims its own index, but carries the shares of
+			// another join, would duplicate an index when reconstructing
+			duplicate := joins[1]
+			duplicate.Shares = append(shamir.Shares{}, joins[0].Shares...)
+			Expect(joiner.InsertJoin(joins[0])).ShouldNot(HaveOccurred())
+			Expect(joiner.InsertJoin(duplicate)).Should(Equal(ErrUnexpectedJoinIndex))
+
+			// Joins with a zero index are rejected
+			zero := Join{ID: joins[0].ID, Index: 0, Shares: make(shamir.Shares, len(secrets))}
+			Expect(joiner.InsertJoin(zero)).Should(Equal(ErrUnexpectedJoinIndex))
+
+			called := int64(0)
+			callback := func(id JoinID, values []uint64) {
+				atomic.AddInt64(&called, 1)
+				Expect(values).Should(Equal(secrets))
+			}
+			Expect(joiner.InsertJoinAndSetCallback(joins[1], callback)).ShouldNot(HaveOccurred())
+			for i := int64(2); i < k; i++ {
+				Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+			}
+			Expect(atomic.LoadInt64(&called)).Should(Equal(int64(1)))
+		})
+	})
+
 	Context("when marshaling and unmarshaling joins", func() {
 		It("should get the same join after marshal and unmarshal", func() {
 			_, joins := generateJoins(n, k)
@@ -186,6 +275,34 @@ var _ = Describe("Joiner", func() {
 			Expect(atomic.LoadInt64(&called)).Should(Equal(int64(1)))
 		})
 	})
+
+	Context("when removing join sets", func() {
+
+		It("should not call the callback of a deleted join set", func() {
+			ord, joins := generateJoins(n, k)
+			called := int64(0)
+			Expect(joiner.InsertJoinAndSetCallback(joins[0], generateCallback(&called, ord))).ShouldNot(HaveOccurred())
+
+			joiner.Delete(joins[0].ID)
+			for i := int64(1); i < n; i++ {
+				Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+			}
+			Expect(atomic.LoadInt64(&called)).Should(Equal(int64(0)))
+		})
+
+		It("should only prune join sets created before a time", func() {
+			ord, joins := generateJoins(n, k)
+			called := int64(0)
+			Expect(joiner.InsertJoinAndSetCallback(joins[0], generateCallback(&called, ord))).ShouldNot(HaveOccurred())
+
+			Expect(joiner.Prune(time.Now().Add(-time.Minute))).Should(Equal(0))
+			for i := int64(1); i < n; i++ {
+				Expect(joiner.InsertJoin(joins[i])).ShouldNot(HaveOccurred())
+			}
+			Expect(atomic.LoadInt64(&called)).Should(Equal(int64(1)))
+			Expect(joiner.Prune(time.Now().Add(time.Minute))).Should(Equal(1))
+		})
+	})
 })
 
 func generateJoins(n, k int64) (order.Order, []Join) {
@@ -213,6 +330,28 @@ func generateJoins(n, k int64) (order.Order, []Join) {
 	return ord, joins
 }
 
+func generateSecretJoins(n, k int64) ([]uint64, []Join) {
+	secrets := make([]uint64, MaxJoinLength)
+	joins := make([]Join, n)
+	id := testutils.Random32Bytes()
+	for i := range joins {
+		joins[i] = Join{
+			Index:  JoinIndex(i + 1),
+			Shares: make(shamir.Shares, len(secrets)),
+		}
+		copy(joins[i].ID[:], id[:])
+	}
+	for j := range secrets {
+		secrets[j] = uint64(rand.Int63()) % shamir.Prime
+		shares, err := shamir.Split(n, k, secrets[j])
+		Expect(err).ShouldNot(HaveOccurred())
+		for i := range joins {
+			joins[i].Shares[j] = shares[i]
+		}
+	}
+	return secrets, joins
+}
+
 func generateMatchedJoins(n, k int64) []Join {
 	buy, sell := testutils.RandomOrderMatch()
 	buyFragments, err := buy.Split(n, k)
@@ -232,7 +371,7 @@ func generateMatchedJoins(n, k int64) []Join {
 			buyFragments[i].Tokens.Sub(&sellFragments[i].Tokens),
 		}
 		joins[i] = Join{
-			Index:  JoinIndex(i),
+			Index:  JoinIndex(i + 1),
 			Shares: shares,
 		}
 		copy(joins[i].ID[:], crypto.Keccak256(buy.ID[:], sell.ID[:]))
@@ -254,3 +393,24 @@ func generateCallback(called *int64, ord order.Order) func(id JoinID, values []u
 		// Expect(values[6]).Should(Equal(uint64(ord.Tokens)))
 	}
 }
+
+func BenchmarkJoinerInsertJoin(b *testing.B) {
+	RegisterTestingT(b)
+	n, k := int64(24), int64(16)
+	_, joins := generateSecretJoins(n, k)
+	joiner := NewJoiner(k)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Use a new JoinID so that the values are reconstructed for every
+		// iteration, and a new Joiner so that the JoinSets do not accumulate
+		if i%1024 == 0 {
+			joiner = NewJoiner(k)
+		}
+		id := JoinID{}
+		binary.BigEndian.PutUint64(id[:], uint64(i))
+		for _, join := range joins[:k] {
+			join.ID = id
+			joiner.InsertJoin(join)
+		}
+	}
+}
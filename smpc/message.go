@@ -6,6 +6,8 @@ import (
 	"errors"
 	"io/ioutil"
 	"math/big"
+
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 // ErrUnexpectedMessageType is returned when a message has an unexpected
@@ -24,24 +26,53 @@ type MessageType byte
 
 // MessageType values for messages passed between SMPC nodes.
 const (
-	MessageTypeJoin         = MessageType(1)
-	MessageTypeJoinResponse = MessageType(2)
+	MessageTypeJoin             = MessageType(1)
+	MessageTypeJoinResponse     = MessageType(2)
+	MessageTypeComparisonValues = MessageType(3)
+	MessageTypeBatch            = MessageType(4)
+	MessageTypeComparisonSlots  = MessageType(5)
 )
 
+// messageFlagTrace is set in the MessageType byte of a binary encoded
+// Message when it is followed by a trace.Context. Messages without a
+// trace.Context are encoded without the flag, so that they can still be
+// unmarshaled by nodes that do not propagate traces.
+const messageFlagTrace = byte(0x80)
+
 // A Message is sent internally between nodes. It is not intended for direct
-// use when interacting with an Smpcer.
+// use when interacting with an Smpcer. The trace.Context of the sender is
+// propagated, when it is set, so that receivers can record events in the
+// same trace.
 type Message struct {
 	MessageType
+	Trace trace.Context
 
-	MessageJoin         *MessageJoin
-	MessageJoinResponse *MessageJoinResponse
+	MessageJoin             *MessageJoin
+	MessageJoinResponse     *MessageJoinResponse
+	MessageComparisonValues *MessageComparisonValues
+	MessageBatch            *MessageBatch
+	MessageComparisonSlots  *MessageComparisonSlots
 }
 
-// MarshalBinary implements the stream.Message interface.
+// MarshalBinary implements the stream.Message interface. It returns
+// ErrUnexpectedMessageType when the MessageType does not match the message
+// that is set.
 func (message *Message) MarshalBinary() ([]byte, error) {
+	if message.IsNil() {
+		return nil, ErrUnexpectedMessageType
+	}
 	buf := new(bytes.Buffer)
-	if err := binary.Write(buf, binary.BigEndian, message.MessageType); err != nil {
-		return nil, err
+	if message.Trace == (trace.Context{}) {
+		if err := binary.Write(buf, binary.BigEndian, message.MessageType); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := binary.Write(buf, binary.BigEndian, byte(message.MessageType)|messageFlagTrace); err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, message.Trace); err != nil {
+			return nil, err
+		}
 	}
 
 	switch message.MessageType {
@@ -61,6 +92,30 @@ func (message *Message) MarshalBinary() ([]byte, error) {
 		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
 			return nil, err
 		}
+	case MessageTypeComparisonValues:
+		bytes, err := message.MessageComparisonValues.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
+			return nil, err
+		}
+	case MessageTypeBatch:
+		bytes, err := message.MessageBatch.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
+			return nil, err
+		}
+	case MessageTypeComparisonSlots:
+		bytes, err := message.MessageComparisonSlots.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, bytes); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, ErrUnexpectedMessageType
 	}
@@ -71,9 +126,17 @@ func (message *Message) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary implements the stream.Message interface.
 func (message *Message) UnmarshalBinary(data []byte) error {
 	buf := bytes.NewBuffer(data)
-	if err := binary.Read(buf, binary.BigEndian, &message.MessageType); err != nil {
+	var messageType byte
+	if err := binary.Read(buf, binary.BigEndian, &messageType); err != nil {
 		return err
 	}
+	message.MessageType = MessageType(messageType &^ messageFlagTrace)
+	message.Trace = trace.Context{}
+	if messageType&messageFlagTrace != 0 {
+		if err := binary.Read(buf, binary.BigEndian, &message.Trace); err != nil {
+			return err
+		}
+	}
 
 	switch message.MessageType {
 	case MessageTypeJoin:
@@ -90,6 +153,27 @@ func (message *Message) UnmarshalBinary(data []byte) error {
 		}
 		message.MessageJoinResponse = new(MessageJoinResponse)
 		return message.MessageJoinResponse.UnmarshalBinary(bytes)
+	case MessageTypeComparisonValues:
+		bytes, err := ioutil.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		message.MessageComparisonValues = new(MessageComparisonValues)
+		return message.MessageComparisonValues.UnmarshalBinary(bytes)
+	case MessageTypeBatch:
+		bytes, err := ioutil.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		message.MessageBatch = new(MessageBatch)
+		return message.MessageBatch.UnmarshalBinary(bytes)
+	case MessageTypeComparisonSlots:
+		bytes, err := ioutil.ReadAll(buf)
+		if err != nil {
+			return err
+		}
+		message.MessageComparisonSlots = new(MessageComparisonSlots)
+		return message.MessageComparisonSlots.UnmarshalBinary(bytes)
 	default:
 		return ErrUnexpectedMessageType
 	}
@@ -124,10 +208,19 @@ func (message *Message) IsNil() bool {
 	if message == nil {
 		return true
 	}
-	if message.MessageType != MessageTypeJoin && message.MessageType != MessageTypeJoinResponse {
-		return true
+	switch message.MessageType {
+	case MessageTypeJoin:
+		return message.MessageJoin == nil
+	case MessageTypeJoinResponse:
+		return message.MessageJoinResponse == nil
+	case MessageTypeComparisonValues:
+		return message.MessageComparisonValues == nil
+	case MessageTypeBatch:
+		return message.MessageBatch == nil
+	case MessageTypeComparisonSlots:
+		return message.MessageComparisonSlots == nil
 	}
-	return message.MessageJoin == nil && message.MessageJoinResponse == nil
+	return true
 }
 
 // A MessageJoin is used to broadcast a Join between nodes in the same network.
@@ -201,3 +294,155 @@ func (message *MessageJoinResponse) UnmarshalBinary(data []byte) error {
 	}
 	return message.Join.UnmarshalBinary(joinData)
 }
+
+// A MessageComparisonValues is sent by the dealer of a Comparison to each node
+// in the network. It contains the ComparisonValues of the receiver, one for
+// each Predicate of the Comparison.
+type MessageComparisonValues struct {
+	NetworkID
+
+	ID     JoinID
+	Values []ComparisonValues
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (message *MessageComparisonValues) MarshalBinary() ([]byte, error) {
+	if len(message.Values) > MaxJoinLength {
+		return nil, ErrJoinLengthExceedsMax
+	}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, message.NetworkID); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, message.ID); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(message.Values))); err != nil {
+		return nil, err
+	}
+	for _, values := range message.Values {
+		valuesData, err := values.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, valuesData); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (message *MessageComparisonValues) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	if err := binary.Read(buf, binary.BigEndian, &message.NetworkID); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &message.ID); err != nil {
+		return err
+	}
+	numValues := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numValues); err != nil {
+		return err
+	}
+	if numValues < 0 || numValues > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
+	message.Values = make([]ComparisonValues, numValues)
+	for i := range message.Values {
+		if err := message.Values[i].read(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A MessageBatch is sent by a node to each node in the network, ahead of any
+// Comparison, to deal a Batch of ComparisonValues. The Batch contains the
+// ComparisonValues of the receiver.
+type MessageBatch struct {
+	NetworkID
+
+	Batch Batch
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (message *MessageBatch) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, message.NetworkID); err != nil {
+		return nil, err
+	}
+	batchData, err := message.Batch.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, batchData); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (message *MessageBatch) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	if err := binary.Read(buf, binary.BigEndian, &message.NetworkID); err != nil {
+		return err
+	}
+	batchData, err := ioutil.ReadAll(buf)
+	if err != nil {
+		return err
+	}
+	return message.Batch.UnmarshalBinary(batchData)
+}
+
+// A MessageComparisonSlots is sent by the dealer of a Comparison, instead of
+// a MessageComparisonValues, when the dealer has already dealt a Batch of
+// ComparisonValues. It contains one PoolSlot for each Predicate of the
+// Comparison.
+type MessageComparisonSlots struct {
+	NetworkID
+
+	ID    JoinID
+	Slots []PoolSlot
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (message *MessageComparisonSlots) MarshalBinary() ([]byte, error) {
+	if len(message.Slots) > MaxJoinLength {
+		return nil, ErrJoinLengthExceedsMax
+	}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, message.NetworkID); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, message.ID); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(message.Slots))); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, message.Slots); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (message *MessageComparisonSlots) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	if err := binary.Read(buf, binary.BigEndian, &message.NetworkID); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &message.ID); err != nil {
+		return err
+	}
+	numSlots := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numSlots); err != nil {
+		return err
+	}
+	if numSlots < 0 || numSlots > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
+	message.Slots = make([]PoolSlot, numSlots)
+	return binary.Read(buf, binary.BigEndian, message.Slots)
+}
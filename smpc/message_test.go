@@ -9,6 +9,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 var (
@@ -43,6 +44,7 @@ var _ = Describe("Messages", func() {
 			for i := range messages {
 				messages[i] = Message{
 					MessageType:         MessageTypeJoin,
+					Trace:               trace.NewContext(trace.ID(messageJoins[i].NetworkID), trace.SpanID(messageJoins[i].Join.ID)),
 					MessageJoin:         &messageJoins[i],
 					MessageJoinResponse: nil,
 				}
@@ -61,6 +63,7 @@ var _ = Describe("Messages", func() {
 				var message Message
 				Expect(message.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
 				Expect(message.MessageType).Should(Equal(MessageTypeJoin))
+				Expect(message.Trace).Should(Equal(messages[i].Trace))
 				Expect(message.MessageJoinResponse).Should(BeNil())
 				Expect(bytes.Compare(messages[i].MessageJoin.NetworkID[:], message.MessageJoin.NetworkID[:])).Should(Equal(0))
 				Expect(bytes.Compare(messages[i].MessageJoin.Join.ID[:], message.MessageJoin.Join.ID[:])).Should(Equal(0))
@@ -84,6 +87,24 @@ var _ = Describe("Messages", func() {
 
 		})
 
+		It("should not encode a trace context that is not set", func() {
+			for i := range messages {
+				messages[i].Trace = trace.Context{}
+				data, err := messages[i].MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(data[0]).Should(Equal(byte(MessageTypeJoin)))
+
+				joinData, err := messages[i].MessageJoin.MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(data[1:]).Should(Equal(joinData))
+
+				var message Message
+				Expect(message.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+				Expect(message.MessageType).Should(Equal(MessageTypeJoin))
+				Expect(message.Trace).Should(Equal(trace.Context{}))
+			}
+		})
+
 		It("should implements the stream.Message interface", func() {
 			for i := range messages {
 				messages[i].IsMessage()
@@ -145,6 +166,91 @@ var _ = Describe("Messages", func() {
 			}
 		})
 	})
+
+	Context("when marshaling and unmarshaling message of type MessageComparisonValues", func() {
+		var messageComparisonValues []MessageComparisonValues
+		var messages []Message
+
+		BeforeEach(func() {
+			messageComparisonValues = generateMessageComparisonValues(n, k)
+			messages = make([]Message, len(messageComparisonValues))
+			for i := range messages {
+				messages[i] = Message{
+					MessageType:             MessageTypeComparisonValues,
+					MessageComparisonValues: &messageComparisonValues[i],
+				}
+			}
+
+			for _, message := range messages {
+				Expect(message.IsNil()).Should(BeFalse())
+			}
+		})
+
+		It("should equal itself after marshaling and unmarshaling to binary", func() {
+			for i := range messages {
+				data, err := messages[i].MarshalBinary()
+				Expect(err).ShouldNot(HaveOccurred())
+
+				var message Message
+				Expect(message.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+				Expect(message.MessageType).Should(Equal(MessageTypeComparisonValues))
+				Expect(message.MessageJoin).Should(BeNil())
+				Expect(message.MessageJoinResponse).Should(BeNil())
+				Expect(*message.MessageComparisonValues).Should(Equal(messageComparisonValues[i]))
+			}
+		})
+
+		It("should error if there are too many values", func() {
+			for i := range messageComparisonValues {
+				values := messageComparisonValues[i].Values[0]
+				for len(messageComparisonValues[i].Values) <= MaxJoinLength {
+					messageComparisonValues[i].Values = append(messageComparisonValues[i].Values, values)
+				}
+				_, err := messages[i].MarshalBinary()
+				Expect(err).Should(HaveOccurred())
+			}
+		})
+	})
+
+	Context("when marshaling and unmarshaling preprocessing messages", func() {
+
+		It("should equal itself after marshaling and unmarshaling a MessageBatch to binary", func() {
+			batch := generateBatch(BatchID{1}, n, k, PredicateEqualToZero, 0)
+			message := Message{
+				MessageType:  MessageTypeBatch,
+				MessageBatch: &MessageBatch{NetworkID: NetworkID{1}, Batch: batch},
+			}
+			data, err := message.MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			unmarshaledMessage := Message{}
+			Expect(unmarshaledMessage.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+			Expect(unmarshaledMessage.MessageType).Should(Equal(MessageTypeBatch))
+			Expect(*unmarshaledMessage.MessageBatch).Should(Equal(*message.MessageBatch))
+		})
+
+		It("should equal itself after marshaling and unmarshaling a MessageComparisonSlots to binary", func() {
+			message := Message{
+				MessageType: MessageTypeComparisonSlots,
+				MessageComparisonSlots: &MessageComparisonSlots{
+					NetworkID: NetworkID{1},
+					ID:        JoinID{2},
+					Slots:     []PoolSlot{{Batch: BatchID{3}, Index: 4}, {Batch: BatchID{5}, Index: 6}},
+				},
+			}
+			data, err := message.MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			unmarshaledMessage := Message{}
+			Expect(unmarshaledMessage.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+			Expect(unmarshaledMessage.MessageType).Should(Equal(MessageTypeComparisonSlots))
+			Expect(*unmarshaledMessage.MessageComparisonSlots).Should(Equal(*message.MessageComparisonSlots))
+
+			message.MessageComparisonSlots = nil
+			_, err = message.MarshalBinary()
+			Expect(err).Should(Equal(ErrUnexpectedMessageType))
+		})
+	})
 })
 
 func generateMessageJoin(n, k int64) []MessageJoin {
@@ -176,3 +282,27 @@ func generateMessageJoinResponse(n, k int64) []MessageJoinResponse {
 
 	return messages
 }
+
+func generateMessageComparisonValues(n, k int64) []MessageComparisonValues {
+	messages := make([]MessageComparisonValues, n)
+	_, joins := generateJoins(n, k)
+	var networkID [32]byte
+	copy(networkID[:], crypto.Keccak256([]byte{uint8(math.MaxUint8)}))
+	values := make([][]ComparisonValues, 0, 2)
+	for _, predicate := range []Predicate{PredicateLessThanZero, PredicateEqualToZero} {
+		dealt, err := DealComparisonValues(n, k, predicate)
+		if err != nil {
+			panic(err)
+		}
+		values = append(values, dealt)
+	}
+	for i := range messages {
+		messages[i] = MessageComparisonValues{
+			NetworkID: networkID,
+			ID:        joins[i].ID,
+			Values:    []ComparisonValues{values[0][i], values[1][i]},
+		}
+	}
+
+	return messages
+}
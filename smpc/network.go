@@ -3,13 +3,13 @@ package smpc
 import (
 	"encoding/base64"
 	"fmt"
-	"log"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/swarm"
 	"golang.org/x/net/context"
 )
@@ -69,6 +69,7 @@ type network struct {
 	conn     ConnectorListener
 	receiver Receiver
 	swarmer  swarm.Swarmer
+	tracker  swarm.PeerTracker
 
 	networkMu      *sync.RWMutex
 	networkPos     map[NetworkID]map[identity.Address]uint64
@@ -76,11 +77,15 @@ type network struct {
 	networkCancels map[NetworkID]map[identity.Address]context.CancelFunc
 }
 
-func NewNetwork(conn ConnectorListener, receiver Receiver, swarmer swarm.Swarmer) Network {
+// NewNetwork returns a Network that uses the ConnectorListener to connect to
+// peers. Peers that are unhealthy, according to the swarm.PeerTracker, are
+// connected after all healthy peers.
+func NewNetwork(conn ConnectorListener, receiver Receiver, swarmer swarm.Swarmer, tracker swarm.PeerTracker) Network {
 	return &network{
 		conn:     conn,
 		receiver: receiver,
 		swarmer:  swarmer,
+		tracker:  tracker,
 
 		networkMu:      new(sync.RWMutex),
 		networkPos:     map[NetworkID]map[identity.Address]uint64{},
@@ -93,7 +98,7 @@ func NewNetwork(conn ConnectorListener, receiver Receiver, swarmer swarm.Swarmer
 func (network *network) Connect(networkID NetworkID, addrs identity.Addresses) {
 
 	k := int64(2 * (len(addrs) + 1) / 3)
-	log.Printf("[info] connecting to network %v with thresold = (%v, %v)", networkID, len(addrs), k)
+	logger.Smpc(logger.LevelInfo, fmt.Sprintf("connecting to network %v with thresold = (%v, %v)", networkID, len(addrs), k))
 
 	func() {
 		network.networkMu.Lock()
@@ -103,9 +108,22 @@ func (network *network) Connect(networkID NetworkID, addrs identity.Addresses) {
 		network.networkCancels[networkID] = map[identity.Address]context.CancelFunc{}
 	}()
 
-	go dispatch.CoForAll(addrs, func(i int) {
+	// Connections to unhealthy peers are deferred until connections to
+	// healthy peers have been attempted, so that flaky peers do not slow
+	// down the rest of the network
+	self := network.swarmer.MultiAddress().Address()
+	healthy, unhealthy := []int{}, []int{}
+	for i, addr := range addrs {
+		if addr < self && !network.tracker.Health(addr).IsHealthy() {
+			unhealthy = append(unhealthy, i)
+			continue
+		}
+		healthy = append(healthy, i)
+	}
+
+	connect := func(i int) {
 		addr := addrs[i]
-		if addr == network.swarmer.MultiAddress().Address() {
+		if addr == self {
 			// Skip trying to connect to ourself
 			return
 		}
@@ -138,12 +156,21 @@ func (network *network) Connect(networkID NetworkID, addrs identity.Addresses) {
 				network.networkSenders[networkID][addr] = sender
 			}
 		}()
-	})
+	}
+
+	go func() {
+		dispatch.CoForAll(healthy, func(i int) {
+			connect(healthy[i])
+		})
+		dispatch.CoForAll(unhealthy, func(i int) {
+			connect(unhealthy[i])
+		})
+	}()
 }
 
 // Disconnect implements the Network interface.
 func (network *network) Disconnect(networkID NetworkID) {
-	log.Printf("[info] disconnecting from network %v", networkID)
+	logger.Smpc(logger.LevelInfo, fmt.Sprintf("disconnecting from network %v", networkID))
 
 	go func() {
 		time.Sleep(10 * time.Minute)
@@ -170,7 +197,7 @@ func (network *network) Send(networkID NetworkID, message Message) {
 
 	senders, ok := network.networkSenders[networkID]
 	if !ok {
-		log.Printf("[error] cannot send message to unknown network %v", networkID)
+		logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to unknown network %v", networkID))
 		return
 	}
 
@@ -178,7 +205,7 @@ func (network *network) Send(networkID NetworkID, message Message) {
 		sender := senders[addr]
 		if err := sender.Send(message); err != nil {
 			// These logs are disabled to prevent verbose output
-			log.Printf("[error] cannot send message to %v on network %v: %v", addr, networkID, err)
+			logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to %v on network %v: %v", addr, networkID, err))
 		}
 	})
 }
@@ -189,12 +216,12 @@ func (network *network) SendWithDelay(networkID NetworkID, message Message) {
 
 	positions, ok := network.networkPos[networkID]
 	if !ok {
-		log.Printf("[error] cannot send message to displaced network %v", networkID)
+		logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to displaced network %v", networkID))
 		return
 	}
 	senders, ok := network.networkSenders[networkID]
 	if !ok {
-		log.Printf("[error] cannot send message to unknown network %v", networkID)
+		logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to unknown network %v", networkID))
 		return
 	}
 
@@ -215,12 +242,12 @@ func (network *network) SendWithDelay(networkID NetworkID, message Message) {
 			go func(addr identity.Address) {
 				sender, ok := senders[addr]
 				if !ok {
-					log.Printf("[error] cannot send message to node at position %v", addr)
+					logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to node at position %v", addr))
 					return
 				}
 				if err := sender.Send(message); err != nil {
 					// These logs are disabled to prevent verbose output
-					// log.Printf("[error] cannot send message to %v on network %v: %v", addr, networkID, err)
+					// logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to %v on network %v: %v", addr, networkID, err))
 				}
 			}(addr)
 
@@ -236,19 +263,19 @@ func (network *network) SendTo(networkID NetworkID, to identity.Address, message
 
 	senders, ok := network.networkSenders[networkID]
 	if !ok {
-		log.Printf("[error] cannot send message to unknown network %v", networkID)
+		logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to unknown network %v", networkID))
 		return
 	}
 	sender, ok := senders[to]
 	if !ok {
-		log.Printf("[error] cannot send message to unknown peer %v", to)
+		logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to unknown peer %v", to))
 		return
 	}
 
 	go func() {
 		if err := sender.Send(message); err != nil {
 			// These logs are disabled to prevent verbose output
-			// log.Printf("[error] cannot send message to %v on network %v: %v", addr, networkID, err)
+			// logger.Smpc(logger.LevelError, fmt.Sprintf("cannot send message to %v on network %v: %v", addr, networkID, err))
 		}
 	}()
 }
@@ -268,33 +295,38 @@ func (network *network) connectOrListen(ctx context.Context, networkID NetworkID
 	if addr < network.swarmer.MultiAddress().Address() {
 
 		// Query for the multi-address
-		log.Printf("[debug] querying peer %v on network %v", addr, networkID)
+		logger.Smpc(logger.LevelDebug, fmt.Sprintf("querying peer %v on network %v", addr, networkID))
 		multiAddr, err := network.query(addr)
 		if err != nil {
-			log.Printf("[error] cannot connect to peer %v on network %v: %v", addr, networkID, err)
+			network.tracker.Failure(addr)
+			logger.Smpc(logger.LevelError, fmt.Sprintf("cannot connect to peer %v on network %v: %v", addr, networkID, err))
 			if addr < network.swarmer.MultiAddress().Address() {
 				return nil
 			}
 		}
 
 		// Connect to the remote server
-		log.Printf("[debug] connecting to peer %v on network %v", addr, networkID)
+		logger.Smpc(logger.LevelDebug, fmt.Sprintf("connecting to peer %v on network %v", addr, networkID))
+		begin := time.Now()
 		sender, err := network.conn.Connect(ctx, networkID, multiAddr, network.receiver)
 		if err != nil {
-			log.Printf("[error] cannot connect to peer %v on network %v: %v", addr, networkID, err)
+			network.tracker.Failure(addr)
+			logger.Smpc(logger.LevelError, fmt.Sprintf("cannot connect to peer %v on network %v: %v", addr, networkID, err))
 			return nil
 		}
-		log.Printf("[debug] 🔗 connected to peer %v on network %v", addr, networkID)
+		network.tracker.Success(addr, time.Since(begin))
+		logger.Smpc(logger.LevelDebug, fmt.Sprintf("🔗 connected to peer %v on network %v", addr, networkID))
 		return sender
 	}
 
 	// Wait for the client to connect to us
-	log.Printf("[debug] listening for peer %v on network %v", addr, networkID)
+	logger.Smpc(logger.LevelDebug, fmt.Sprintf("listening for peer %v on network %v", addr, networkID))
 	sender, err := network.conn.Listen(ctx, networkID, addr, network.receiver)
 	if err != nil {
-		log.Printf("[error] cannot listen for peer %v on network %v: %v", addr, networkID, err)
+		logger.Smpc(logger.LevelError, fmt.Sprintf("cannot listen for peer %v on network %v: %v", addr, networkID, err))
 		return nil
 	}
-	log.Printf("[debug] 🔗 accepted peer %v on network %v", addr, networkID)
+	network.tracker.Success(addr, 0)
+	logger.Smpc(logger.LevelDebug, fmt.Sprintf("🔗 accepted peer %v on network %v", addr, networkID))
 	return sender
 }
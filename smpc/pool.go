@@ -0,0 +1,391 @@
+package smpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// ErrPoolFull is returned when inserting a Batch, dealt by this node, into a
+// Pool that already stores the maximum number of ComparisonValues dealt by
+// this node.
+var ErrPoolFull = errors.New("pool full")
+
+// ErrPoolSlotNotFound is returned when consuming ComparisonValues from a
+// PoolSlot that has already been consumed, or that references a Batch that
+// has not been inserted.
+var ErrPoolSlotNotFound = errors.New("pool slot not found")
+
+// ErrBatchLengthExceedsMax is returned when a Batch contains more than the
+// MaxBatchLength number of ComparisonValues.
+var ErrBatchLengthExceedsMax = errors.New("batch length exceeds max")
+
+// MaxBatchLength is the maximum number of ComparisonValues that can be dealt
+// in one Batch.
+const MaxBatchLength = 16
+
+// BatchID uniquely identifies a Batch dealt by a node.
+type BatchID [32]byte
+
+// String returns a human-readable representation of a BatchID.
+func (id BatchID) String() string {
+	return base64.StdEncoding.EncodeToString(id[:8])
+}
+
+// A Batch of ComparisonValues that is dealt before it is needed, so that the
+// ComparisonValues do not need to be dealt while comparing. All
+// ComparisonValues in a Batch are for the same Predicate. A Batch is dealt by
+// one node, and is only used by the Comparisons that the same node deals, so
+// preprocessing has the same trust model as dealing ComparisonValues while
+// comparing. Contributions from several dealers are not combined into one
+// PoolSlot, for the reasons given by comparisonDealer.
+type Batch struct {
+	ID        BatchID
+	Predicate Predicate
+	Values    []ComparisonValues
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (batch *Batch) MarshalBinary() ([]byte, error) {
+	if len(batch.Values) > MaxBatchLength {
+		return nil, ErrBatchLengthExceedsMax
+	}
+	buf := new(bytes.Buffer)
+	if err := binary.Write(buf, binary.BigEndian, batch.ID); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, batch.Predicate); err != nil {
+		return nil, err
+	}
+	if err := binary.Write(buf, binary.BigEndian, int64(len(batch.Values))); err != nil {
+		return nil, err
+	}
+	for _, values := range batch.Values {
+		valuesData, err := values.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		if err := binary.Write(buf, binary.BigEndian, valuesData); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (batch *Batch) UnmarshalBinary(data []byte) error {
+	buf := bytes.NewBuffer(data)
+	if err := binary.Read(buf, binary.BigEndian, &batch.ID); err != nil {
+		return err
+	}
+	if err := binary.Read(buf, binary.BigEndian, &batch.Predicate); err != nil {
+		return err
+	}
+	numValues := int64(0)
+	if err := binary.Read(buf, binary.BigEndian, &numValues); err != nil {
+		return err
+	}
+	if numValues < 0 || numValues > MaxBatchLength {
+		return ErrBatchLengthExceedsMax
+	}
+	batch.Values = make([]ComparisonValues, numValues)
+	for i := range batch.Values {
+		if err := batch.Values[i].read(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// A PoolSlot references the ComparisonValues at an index in a Batch.
+type PoolSlot struct {
+	Batch BatchID
+	Index uint32
+}
+
+// PoolDepth is the number of ComparisonValues stored in a Pool for a
+// Predicate.
+type PoolDepth struct {
+
+	// Dealt is the number of ComparisonValues dealt by this node that have
+	// not been assigned to a Comparison. Comparisons for which this node is
+	// the dealer can only avoid dealing ComparisonValues while this is not
+	// zero.
+	Dealt int
+
+	// Received is the number of ComparisonValues dealt by other nodes that
+	// have not been consumed.
+	Received int
+}
+
+// A Pool stores the Batches dealt by the nodes in each network. The dealer of
+// a Comparison takes PoolSlots from the Batches that it dealt, and sends them
+// to the other nodes, which consume the ComparisonValues in those PoolSlots
+// from their own Pool. A Pool is safe for concurrent use.
+type Pool struct {
+	self     identity.Address
+	maxDepth int
+
+	mu       *sync.Mutex
+	networks map[NetworkID]*networkPool
+}
+
+type networkPool struct {
+	batches   map[identity.Address]map[BatchID]*poolBatch
+	available map[Predicate][]PoolSlot
+	inserted  uint64
+}
+
+type poolBatch struct {
+	inserted  uint64
+	predicate Predicate
+	values    []ComparisonValues
+	consumed  []bool
+	remaining int
+}
+
+// NewPool returns an empty Pool for a node. The Pool will store at most
+// maxDepth ComparisonValues, dealt by each node, for each Predicate. When
+// more are dealt by another node, the oldest Batches dealt by that node are
+// dropped.
+func NewPool(self identity.Address, maxDepth int) *Pool {
+	return &Pool{
+		self:     self,
+		maxDepth: maxDepth,
+
+		mu:       new(sync.Mutex),
+		networks: map[NetworkID]*networkPool{},
+	}
+}
+
+// Connect the Pool to a network so that Batches can be inserted for it.
+func (pool *Pool) Connect(networkID NetworkID) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if _, ok := pool.networks[networkID]; ok {
+		return
+	}
+	pool.networks[networkID] = &networkPool{
+		batches:   map[identity.Address]map[BatchID]*poolBatch{},
+		available: map[Predicate][]PoolSlot{},
+	}
+}
+
+// Disconnect the Pool from a network and drop all Batches stored for it.
+func (pool *Pool) Disconnect(networkID NetworkID) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	delete(pool.networks, networkID)
+}
+
+// Insert a Batch dealt by a node in a network. When the dealer is this node,
+// the PoolSlots of the Batch become available to be taken. Otherwise, the
+// oldest Batches dealt by the same node are dropped until there is space for
+// the Batch. A dealer takes its PoolSlots in the order they were dealt, so
+// the oldest Batches are those most likely to belong to Comparisons that
+// this node will never see.
+func (pool *Pool) Insert(networkID NetworkID, dealer identity.Address, batch Batch) error {
+	if len(batch.Values) > MaxBatchLength {
+		return ErrBatchLengthExceedsMax
+	}
+	predicate := poolPredicate(batch.Predicate)
+	if predicate != batch.Predicate {
+		return ErrUnexpectedPredicate
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	network, ok := pool.networks[networkID]
+	if !ok {
+		return ErrCompareOnDisconnectedNetwork
+	}
+	batches, ok := network.batches[dealer]
+	if !ok {
+		batches = map[BatchID]*poolBatch{}
+		network.batches[dealer] = batches
+	}
+	if _, ok := batches[batch.ID]; ok {
+		return nil
+	}
+	if len(batch.Values) > pool.maxDepth {
+		return ErrPoolFull
+	}
+	if dealer == pool.self {
+		if len(network.available[predicate])+len(batch.Values) > pool.maxDepth {
+			return ErrPoolFull
+		}
+	} else {
+		for depth(batches, predicate)+len(batch.Values) > pool.maxDepth {
+			evictOldest(batches, predicate)
+		}
+	}
+
+	network.inserted++
+	batches[batch.ID] = &poolBatch{
+		inserted:  network.inserted,
+		predicate: predicate,
+		values:    append([]ComparisonValues{}, batch.Values...),
+		consumed:  make([]bool, len(batch.Values)),
+		remaining: len(batch.Values),
+	}
+	if dealer == pool.self {
+		for i := range batch.Values {
+			network.available[predicate] = append(network.available[predicate], PoolSlot{Batch: batch.ID, Index: uint32(i)})
+		}
+	}
+	return nil
+}
+
+// Take one PoolSlot, dealt by this node, for each Predicate, and consume the
+// ComparisonValues of this node in those PoolSlots. The PoolSlots will not be
+// taken again. It returns false, and takes nothing, when there are not enough
+// PoolSlots available.
+func (pool *Pool) Take(networkID NetworkID, predicates []Predicate) ([]PoolSlot, []ComparisonValues, bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	network, ok := pool.networks[networkID]
+	if !ok {
+		return nil, nil, false
+	}
+	needed := map[Predicate]int{}
+	for _, predicate := range predicates {
+		needed[poolPredicate(predicate)]++
+	}
+	for predicate, n := range needed {
+		if len(network.available[predicate]) < n {
+			return nil, nil, false
+		}
+	}
+
+	slots := make([]PoolSlot, len(predicates))
+	for i, predicate := range predicates {
+		predicate = poolPredicate(predicate)
+		slots[i] = network.available[predicate][0]
+		network.available[predicate] = network.available[predicate][1:]
+	}
+	return slots, consume(network.batches[pool.self], slots), true
+}
+
+// Consume the ComparisonValues in PoolSlots of Batches dealt by a node, one
+// for each Predicate. Consumed ComparisonValues are removed from the Pool. It
+// returns ErrPoolSlotNotFound, and consumes nothing, when any of the
+// PoolSlots cannot be found.
+func (pool *Pool) Consume(networkID NetworkID, dealer identity.Address, predicates []Predicate, slots []PoolSlot) ([]ComparisonValues, error) {
+	if len(predicates) != len(slots) {
+		return nil, ErrComparisonLengthUnequal
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	network, ok := pool.networks[networkID]
+	if !ok {
+		return nil, ErrCompareOnDisconnectedNetwork
+	}
+	batches := network.batches[dealer]
+	for i, slot := range slots {
+		batch, ok := batches[slot.Batch]
+		if !ok || slot.Index >= uint32(len(batch.values)) || batch.consumed[slot.Index] {
+			return nil, ErrPoolSlotNotFound
+		}
+		if batch.predicate != poolPredicate(predicates[i]) {
+			return nil, ErrUnexpectedPredicate
+		}
+		for _, other := range slots[:i] {
+			if other == slot {
+				return nil, ErrPoolSlotNotFound
+			}
+		}
+	}
+
+	return consume(batches, slots), nil
+}
+
+// Depth returns the PoolDepth of a network for each Predicate that is
+// preprocessed.
+func (pool *Pool) Depth(networkID NetworkID) map[Predicate]PoolDepth {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	depths := map[Predicate]PoolDepth{}
+	network, ok := pool.networks[networkID]
+	if !ok {
+		return depths
+	}
+	for _, predicate := range PoolPredicates {
+		poolDepth := PoolDepth{Dealt: len(network.available[predicate])}
+		for dealer, batches := range network.batches {
+			if dealer != pool.self {
+				poolDepth.Received += depth(batches, predicate)
+			}
+		}
+		depths[predicate] = poolDepth
+	}
+	return depths
+}
+
+// PoolPredicates are the Predicates for which ComparisonValues are
+// preprocessed. The ComparisonValues for PredicateLessThanZero are also used
+// for PredicateGreaterThanZero.
+var PoolPredicates = []Predicate{PredicateLessThanZero, PredicateEqualToZero}
+
+// poolPredicate returns the Predicate of the Batches from which
+// ComparisonValues for a Predicate are consumed.
+func poolPredicate(predicate Predicate) Predicate {
+	if predicate == PredicateGreaterThanZero {
+		return PredicateLessThanZero
+	}
+	return predicate
+}
+
+// depth returns the number of ComparisonValues for a Predicate that have not
+// been consumed.
+func depth(batches map[BatchID]*poolBatch, predicate Predicate) int {
+	n := 0
+	for _, batch := range batches {
+		if batch.predicate == predicate {
+			n += batch.remaining
+		}
+	}
+	return n
+}
+
+// consume the ComparisonValues in PoolSlots that are known to exist, and drop
+// Batches once all of their ComparisonValues have been consumed.
+func consume(batches map[BatchID]*poolBatch, slots []PoolSlot) []ComparisonValues {
+	values := make([]ComparisonValues, len(slots))
+	for i, slot := range slots {
+		batch := batches[slot.Batch]
+		values[i] = batch.values[slot.Index]
+		batch.values[slot.Index] = ComparisonValues{}
+		batch.consumed[slot.Index] = true
+		batch.remaining--
+		if batch.remaining == 0 {
+			delete(batches, slot.Batch)
+		}
+	}
+	return values
+}
+
+// evictOldest drops the Batch for a Predicate that was inserted first.
+func evictOldest(batches map[BatchID]*poolBatch, predicate Predicate) {
+	oldest := (*poolBatch)(nil)
+	oldestID := BatchID{}
+	for id, batch := range batches {
+		if batch.predicate != predicate {
+			continue
+		}
+		if oldest == nil || batch.inserted < oldest.inserted {
+			oldest, oldestID = batch, id
+		}
+	}
+	delete(batches, oldestID)
+}
@@ -0,0 +1,176 @@
+package smpc_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/smpc"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Pools", func() {
+
+	numNodes := int64(6)
+	threshold := 2 * (numNodes + 1) / 3
+
+	var networkID NetworkID
+	var self, dealer identity.Address
+	var pool *Pool
+
+	BeforeEach(func() {
+		var err error
+		self, err = testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		dealer, err = testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		networkID = NetworkID{1}
+		pool = NewPool(self, 2*MaxBatchLength)
+		pool.Connect(networkID)
+	})
+
+	Context("when taking slots dealt by this node", func() {
+
+		It("should take each slot once", func() {
+			Expect(pool.Insert(networkID, self, generateBatch(BatchID{1}, numNodes, threshold, PredicateLessThanZero, 0))).ShouldNot(HaveOccurred())
+			Expect(pool.Depth(networkID)[PredicateLessThanZero].Dealt).Should(Equal(MaxBatchLength))
+
+			taken := map[PoolSlot]bool{}
+			for i := 0; i < MaxBatchLength/2; i++ {
+				slots, values, ok := pool.Take(networkID, []Predicate{PredicateLessThanZero, PredicateGreaterThanZero})
+				Expect(ok).Should(BeTrue())
+				Expect(values).Should(HaveLen(2))
+				for _, slot := range slots {
+					Expect(taken[slot]).Should(BeFalse())
+					taken[slot] = true
+				}
+			}
+			_, _, ok := pool.Take(networkID, []Predicate{PredicateLessThanZero})
+			Expect(ok).Should(BeFalse())
+			Expect(pool.Depth(networkID)[PredicateLessThanZero].Dealt).Should(Equal(0))
+		})
+
+		It("should not take anything when there are not enough slots", func() {
+			Expect(pool.Insert(networkID, self, generateBatch(BatchID{1}, numNodes, threshold, PredicateLessThanZero, 0))).ShouldNot(HaveOccurred())
+
+			_, _, ok := pool.Take(networkID, []Predicate{PredicateLessThanZero, PredicateEqualToZero})
+			Expect(ok).Should(BeFalse())
+			Expect(pool.Depth(networkID)[PredicateLessThanZero].Dealt).Should(Equal(MaxBatchLength))
+		})
+
+		It("should return an error when inserting too many batches", func() {
+			Expect(pool.Insert(networkID, self, generateBatch(BatchID{1}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			Expect(pool.Insert(networkID, self, generateBatch(BatchID{2}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			Expect(pool.Insert(networkID, self, generateBatch(BatchID{3}, numNodes, threshold, PredicateEqualToZero, 0))).Should(Equal(ErrPoolFull))
+		})
+	})
+
+	Context("when consuming slots dealt by another node", func() {
+
+		It("should consume the values dealt for the same slot", func() {
+			dealerBatch := generateBatches(BatchID{1}, numNodes, threshold, PredicateEqualToZero)
+			dealerPool := NewPool(dealer, MaxBatchLength)
+			dealerPool.Connect(networkID)
+			Expect(dealerPool.Insert(networkID, dealer, dealerBatch[0])).ShouldNot(HaveOccurred())
+			Expect(pool.Insert(networkID, dealer, dealerBatch[1])).ShouldNot(HaveOccurred())
+			Expect(pool.Depth(networkID)[PredicateEqualToZero].Received).Should(Equal(MaxBatchLength))
+
+			slots, _, ok := dealerPool.Take(networkID, []Predicate{PredicateEqualToZero})
+			Expect(ok).Should(BeTrue())
+			values, err := pool.Consume(networkID, dealer, []Predicate{PredicateEqualToZero}, slots)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(values).Should(Equal([]ComparisonValues{dealerBatch[1].Values[slots[0].Index]}))
+			Expect(pool.Depth(networkID)[PredicateEqualToZero].Received).Should(Equal(MaxBatchLength - 1))
+
+			_, err = pool.Consume(networkID, dealer, []Predicate{PredicateEqualToZero}, slots)
+			Expect(err).Should(Equal(ErrPoolSlotNotFound))
+		})
+
+		It("should return an error when the batch has not been inserted", func() {
+			_, err := pool.Consume(networkID, dealer, []Predicate{PredicateEqualToZero}, []PoolSlot{{Batch: BatchID{1}}})
+			Expect(err).Should(Equal(ErrPoolSlotNotFound))
+
+			Expect(pool.Insert(networkID, dealer, generateBatch(BatchID{1}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			_, err = pool.Consume(networkID, dealer, []Predicate{PredicateEqualToZero}, []PoolSlot{{Batch: BatchID{1}}})
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+
+		It("should return an error when the slot is for a different predicate", func() {
+			Expect(pool.Insert(networkID, dealer, generateBatch(BatchID{1}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			_, err := pool.Consume(networkID, dealer, []Predicate{PredicateGreaterThanZero}, []PoolSlot{{Batch: BatchID{1}}})
+			Expect(err).Should(Equal(ErrUnexpectedPredicate))
+		})
+
+		It("should not consume slots from batches dealt by other nodes", func() {
+			Expect(pool.Insert(networkID, dealer, generateBatch(BatchID{1}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			_, err := pool.Consume(networkID, self, []Predicate{PredicateEqualToZero}, []PoolSlot{{Batch: BatchID{1}}})
+			Expect(err).Should(Equal(ErrPoolSlotNotFound))
+		})
+
+		It("should drop the oldest batches when inserting too many batches", func() {
+			Expect(pool.Insert(networkID, dealer, generateBatch(BatchID{1}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			Expect(pool.Insert(networkID, dealer, generateBatch(BatchID{2}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			Expect(pool.Insert(networkID, dealer, generateBatch(BatchID{3}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			Expect(pool.Depth(networkID)[PredicateEqualToZero].Received).Should(Equal(2 * MaxBatchLength))
+
+			_, err := pool.Consume(networkID, dealer, []Predicate{PredicateEqualToZero}, []PoolSlot{{Batch: BatchID{1}}})
+			Expect(err).Should(Equal(ErrPoolSlotNotFound))
+			_, err = pool.Consume(networkID, dealer, []Predicate{PredicateEqualToZero}, []PoolSlot{{Batch: BatchID{3}}})
+			Expect(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("when disconnecting", func() {
+
+		It("should drop all batches", func() {
+			Expect(pool.Insert(networkID, self, generateBatch(BatchID{1}, numNodes, threshold, PredicateEqualToZero, 0))).ShouldNot(HaveOccurred())
+			pool.Disconnect(networkID)
+
+			_, _, ok := pool.Take(networkID, []Predicate{PredicateEqualToZero})
+			Expect(ok).Should(BeFalse())
+			Expect(pool.Insert(networkID, self, generateBatch(BatchID{2}, numNodes, threshold, PredicateEqualToZero, 0))).Should(Equal(ErrCompareOnDisconnectedNetwork))
+		})
+	})
+
+	Context("when marshaling and unmarshaling batches", func() {
+
+		It("should equal itself after marshaling and unmarshaling to binary", func() {
+			batch := generateBatch(BatchID{1}, numNodes, threshold, PredicateLessThanZero, 0)
+			data, err := batch.MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			unmarshaledBatch := Batch{}
+			Expect(unmarshaledBatch.UnmarshalBinary(data)).ShouldNot(HaveOccurred())
+			Expect(unmarshaledBatch).Should(Equal(batch))
+		})
+
+		It("should return an error when there are too many values", func() {
+			batch := generateBatch(BatchID{1}, numNodes, threshold, PredicateEqualToZero, 0)
+			batch.Values = append(batch.Values, batch.Values[0])
+			_, err := batch.MarshalBinary()
+			Expect(err).Should(Equal(ErrBatchLengthExceedsMax))
+			Expect(pool.Insert(networkID, dealer, batch)).Should(Equal(ErrBatchLengthExceedsMax))
+		})
+	})
+})
+
+func generateBatch(id BatchID, n, k int64, predicate Predicate, j int) Batch {
+	return generateBatches(id, n, k, predicate)[j]
+}
+
+func generateBatches(id BatchID, n, k int64, predicate Predicate) []Batch {
+	batches := make([]Batch, n)
+	for j := range batches {
+		batches[j] = Batch{ID: id, Predicate: predicate}
+	}
+	for i := 0; i < MaxBatchLength; i++ {
+		values, err := DealComparisonValues(n, k, predicate)
+		if err != nil {
+			panic(err)
+		}
+		for j := range batches {
+			batches[j].Values = append(batches[j].Values, values[j])
+		}
+	}
+	return batches
+}
@@ -1,22 +1,52 @@
 package smpc
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"log"
 	"math/big"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/swarm"
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 // ErrJoinOnDisconnectedNetwork is returned when an Smpcer attempts to access a
 // Joiner for a NetworkID that has not been connected to.
 var ErrJoinOnDisconnectedNetwork = errors.New("join on disconnected network")
 
+// ErrCompareOnDisconnectedNetwork is returned when an Smpcer attempts to
+// compare values for a NetworkID that has not been connected to.
+var ErrCompareOnDisconnectedNetwork = errors.New("compare on disconnected network")
+
+// ErrUnexpectedDealer is returned when ComparisonValues, or PoolSlots, are
+// received from a node that is not the dealer of the Comparison, or when a
+// Batch is received from a node that is not in the network.
+var ErrUnexpectedDealer = errors.New("unexpected dealer")
+
+// ErrUnexpectedSender is returned when a Join is received from a node that is
+// not in the network, or that is excluded from the Join.
+var ErrUnexpectedSender = errors.New("unexpected sender")
+
+// MaxPoolDepth is the maximum number of preprocessed ComparisonValues, dealt
+// by each node, that are stored for each Predicate.
+const MaxPoolDepth = 256
+
+// MaxStateAge is the maximum duration for which the state of a Join, or a
+// Comparison, is stored. State that is older than the MaxStateAge belongs to
+// Joins and Comparisons that will never complete, and is pruned.
+const MaxStateAge = 10 * time.Minute
+
+// PruneInterval is the minimum duration between two prunings of state that is
+// older than the MaxStateAge.
+const PruneInterval = time.Minute
+
 // Smpcer is an interface for a secure multi-party computer. It asynchronously
 // consumes computation instructions and produces computation results.
 type Smpcer interface {
@@ -31,47 +61,118 @@ type Smpcer interface {
 
 	// Join a set of shamir.Shares for distinct values. This involves broadcast
 	// communication with the nodes in the network. On a success, the Callback
-	// is called.
-	Join(networkID NetworkID, join Join, callback Callback, useDelay bool) error
+	// is called. The trace.Context is propagated to the nodes in the network
+	// so that the Join can be traced across all of them.
+	Join(ctx trace.Context, networkID NetworkID, join Join, callback Callback, useDelay bool) error
 
 	// InsertCommitments for the shamir.Shares inside a Join. These commitments
 	// are used to blind shamir.Shares while being able to verify that the
 	// computations performed have been done correctly.
 	InsertCommitments(networkID NetworkID, joinID JoinID, joinCommitments JoinCommitments)
+
+	// Compare a set of shamir.Shares with zero. This involves several rounds
+	// of broadcast communication with the nodes in the network, and reveals
+	// only whether or not each value satisfies its Predicate, as long as the
+	// node that deals the random values of the Comparison does not collude
+	// with another node. On a success, the ComparisonCallback is called.
+	// Delays are only used when sending the results.
+	Compare(ctx trace.Context, networkID NetworkID, comparison Comparison, callback ComparisonCallback, useDelay bool) error
+
+	// Preprocess ComparisonValues for all connected networks until the done
+	// channel is closed. Every interval in which no Comparison was started,
+	// a Batch is dealt for each Predicate whose depth of ComparisonValues,
+	// dealt by this node, is less than the target depth. Comparisons for
+	// which this node is the dealer use preprocessed ComparisonValues
+	// whenever they are available. Preprocessed ComparisonValues are only
+	// used by the node that dealt them, so they are as private as the
+	// ComparisonValues dealt by Compare.
+	Preprocess(done <-chan struct{}, depth int, interval time.Duration)
+
+	// PoolDepths returns the PoolDepth of preprocessed ComparisonValues for
+	// each connected network and Predicate.
+	PoolDepths() map[NetworkID]map[Predicate]PoolDepth
 }
 
 type smpcer struct {
 	network Network
+	swarmer swarm.Swarmer
+
+	networksMu *sync.RWMutex
+	networks   map[NetworkID]identity.Addresses
 
 	joinersMu *sync.RWMutex
 	joiners   map[NetworkID]*Joiner
 
 	selfJoinsMu *sync.RWMutex
-	selfJoins   map[JoinID]Join
+	selfJoins   map[JoinID]selfJoin
 
 	commitmentsMu *sync.RWMutex
 	commitments   map[NetworkID]map[JoinID]JoinCommitments
+
+	comparisonsMu *sync.Mutex
+	comparisons   map[NetworkID]map[JoinID]*comparisonState
+	compared      int64
+	pruned        int64
+
+	pool *Pool
+}
+
+// A selfJoin is a Join sent by this node. It is stored so that it can be sent
+// in response to the Joins of other nodes in the network, except for the node
+// that was excluded from the Join.
+type selfJoin struct {
+	networkID NetworkID
+	join      Join
+	exclude   identity.Address
+	timestamp time.Time
+}
+
+// A comparisonState stores a Comparison until the ComparisonValues dealt for
+// it have been received, or stores the ComparisonValues until the Comparison
+// has started. When the dealer uses preprocessed ComparisonValues, the
+// PoolSlots are stored until they can be consumed from the Pool.
+type comparisonState struct {
+	ctx        trace.Context
+	comparison *Comparison
+	useDelay   bool
+	values     []ComparisonValues
+	dealer     identity.Address
+	slots      []PoolSlot
+	timestamp  time.Time
 }
 
-// NewSmpcer returns an Smpcer node that is not connected to a network.
-func NewSmpcer(conn ConnectorListener, swarmer swarm.Swarmer) Smpcer {
+// NewSmpcer returns an Smpcer node that is not connected to a network. The
+// swarm.PeerTracker is used to prefer healthy peers when connecting to a
+// network.
+func NewSmpcer(conn ConnectorListener, swarmer swarm.Swarmer, tracker swarm.PeerTracker) Smpcer {
 	smpc := &smpcer{
+		swarmer: swarmer,
+
+		networksMu: new(sync.RWMutex),
+		networks:   map[NetworkID]identity.Addresses{},
+
 		joinersMu: new(sync.RWMutex),
 		joiners:   map[NetworkID]*Joiner{},
 
 		selfJoinsMu: new(sync.RWMutex),
-		selfJoins:   map[JoinID]Join{},
+		selfJoins:   map[JoinID]selfJoin{},
 
 		commitmentsMu: new(sync.RWMutex),
 		commitments:   map[NetworkID]map[JoinID]JoinCommitments{},
+
+		comparisonsMu: new(sync.Mutex),
+		comparisons:   map[NetworkID]map[JoinID]*comparisonState{},
+
+		pool:   NewPool(swarmer.MultiAddress().Address(), MaxPoolDepth),
+		pruned: time.Now().UnixNano(),
 	}
-	smpc.network = NewNetwork(conn, smpc, swarmer)
+	smpc.network = NewNetwork(conn, smpc, swarmer, tracker)
 	return smpc
 }
 
 // Connect implements the Smpcer interface.
 func (smpc *smpcer) Connect(networkID NetworkID, addrs identity.Addresses) {
-	k := int64(2 * (len(addrs) + 1) / 3)
+	k := threshold(len(addrs))
 
 	smpc.joinersMu.Lock()
 	smpc.joiners[networkID] = NewJoiner(k)
@@ -81,6 +182,15 @@ func (smpc *smpcer) Connect(networkID NetworkID, addrs identity.Addresses) {
 	smpc.commitments[networkID] = map[JoinID]JoinCommitments{}
 	smpc.commitmentsMu.Unlock()
 
+	smpc.comparisonsMu.Lock()
+	smpc.comparisons[networkID] = map[JoinID]*comparisonState{}
+	smpc.comparisonsMu.Unlock()
+	smpc.pool.Connect(networkID)
+
+	smpc.networksMu.Lock()
+	smpc.networks[networkID] = addrs
+	smpc.networksMu.Unlock()
+
 	smpc.network.Connect(networkID, addrs)
 }
 
@@ -95,12 +205,23 @@ func (smpc *smpcer) Disconnect(networkID NetworkID) {
 	smpc.commitmentsMu.Lock()
 	delete(smpc.commitments, networkID)
 	smpc.commitmentsMu.Unlock()
+
+	smpc.comparisonsMu.Lock()
+	delete(smpc.comparisons, networkID)
+	smpc.comparisonsMu.Unlock()
+	smpc.pool.Disconnect(networkID)
+
+	smpc.networksMu.Lock()
+	delete(smpc.networks, networkID)
+	smpc.networksMu.Unlock()
 }
 
 // Join implements the Smpcer interface.
-func (smpc *smpcer) Join(networkID NetworkID, join Join, callback Callback, useDelay bool) error {
+func (smpc *smpcer) Join(ctx trace.Context, networkID NetworkID, join Join, callback Callback, useDelay bool) error {
+	smpc.prune()
+
 	smpc.selfJoinsMu.Lock()
-	smpc.selfJoins[join.ID] = join
+	smpc.selfJoins[join.ID] = selfJoin{networkID: networkID, join: join, timestamp: time.Now()}
 	smpc.selfJoinsMu.Unlock()
 
 	smpc.joinersMu.RLock()
@@ -109,17 +230,23 @@ func (smpc *smpcer) Join(networkID NetworkID, join Join, callback Callback, useD
 	if !joinerOk {
 		return ErrJoinOnDisconnectedNetwork
 	}
-	if err := joiner.InsertJoinAndSetCallback(join, callback); err != nil {
+	err := joiner.InsertJoinAndSetCallback(join, func(joinID JoinID, values []uint64) {
+		trace.Record(ctx, "smpc.join.reconstruct", trace.Attributes{"values": fmt.Sprintf("%v", len(values))})
+		callback(joinID, values)
+	})
+	if err != nil {
 		return err
 	}
 
 	message := Message{
 		MessageType: MessageTypeJoin,
+		Trace:       ctx,
 		MessageJoin: &MessageJoin{
 			NetworkID: networkID,
 			Join:      join,
 		},
 	}
+	trace.Record(ctx, "smpc.join.send", trace.Attributes{"index": fmt.Sprintf("%v", join.Index), "delay": fmt.Sprintf("%v", useDelay)})
 	if useDelay {
 		smpc.network.SendWithDelay(networkID, message)
 	} else {
@@ -139,23 +266,515 @@ func (smpc *smpcer) InsertCommitments(networkID NetworkID, joinID JoinID, joinCo
 	smpc.commitments[networkID][joinID] = joinCommitements
 }
 
+// Compare implements the Smpcer interface. The ComparisonValues for each
+// Comparison are dealt by one node in the network, chosen using the
+// ComparisonID. The dealer knows the random values, so it is not sent any of
+// the values opened while comparing, unless the network is too small to
+// reconstruct values without it. Comparisons are only private while the
+// dealer does not collude with another node, see comparisonDealer.
+func (smpc *smpcer) Compare(ctx trace.Context, networkID NetworkID, comparison Comparison, callback ComparisonCallback, useDelay bool) error {
+	if len(comparison.Shares) != len(comparison.Predicates) {
+		return ErrComparisonLengthUnequal
+	}
+	if len(comparison.Shares) > MaxJoinLength {
+		return ErrJoinLengthExceedsMax
+	}
+
+	smpc.networksMu.RLock()
+	addrs, networkOk := smpc.networks[networkID]
+	smpc.networksMu.RUnlock()
+	smpc.joinersMu.RLock()
+	joiner, joinerOk := smpc.joiners[networkID]
+	smpc.joinersMu.RUnlock()
+	if !networkOk || !joinerOk {
+		return ErrCompareOnDisconnectedNetwork
+	}
+	smpc.prune()
+
+	// Set the callback for the results before anything is sent, so that
+	// results cannot be missed. The Comparison is finished once the results
+	// are known, so its last Join is no longer needed.
+	joiner.SetCallback(comparisonJoinID(comparison.ID, comparisonRoundResults, 0), func(joinID JoinID, values []uint64) {
+		smpc.deleteJoins(joiner, joinID)
+		results := make([]bool, len(values))
+		for i := range values {
+			results[i] = values[i] == 1
+		}
+		trace.Record(ctx, "smpc.compare.results", trace.Attributes{"results": fmt.Sprintf("%v", results)})
+		callback(comparison.ID, results)
+	})
+
+	atomic.StoreInt64(&smpc.compared, time.Now().UnixNano())
+
+	self := smpc.swarmer.MultiAddress().Address()
+	dealer := comparisonDealer(comparison.ID, addrs)
+	if self == dealer {
+		values, err := smpc.assign(ctx, networkID, comparison, addrs)
+		if err != nil {
+			return err
+		}
+		if smpc.excludeDealer(addrs) {
+			return nil
+		}
+		return smpc.insertComparison(networkID, comparison.ID, &comparisonState{ctx: ctx, comparison: &comparison, useDelay: useDelay, values: values})
+	}
+	return smpc.insertComparison(networkID, comparison.ID, &comparisonState{ctx: ctx, comparison: &comparison, useDelay: useDelay})
+}
+
+// assign ComparisonValues to a Comparison, and return the ComparisonValues of
+// this node. Preprocessed ComparisonValues are used when they are available,
+// otherwise ComparisonValues are dealt.
+func (smpc *smpcer) assign(ctx trace.Context, networkID NetworkID, comparison Comparison, addrs identity.Addresses) ([]ComparisonValues, error) {
+	slots, values, ok := smpc.pool.Take(networkID, comparison.Predicates)
+	if !ok {
+		return smpc.deal(ctx, networkID, comparison, addrs)
+	}
+
+	trace.Record(ctx, "smpc.compare.assign", trace.Attributes{"predicates": fmt.Sprintf("%v", len(comparison.Predicates))})
+	smpc.network.Send(networkID, Message{
+		MessageType: MessageTypeComparisonSlots,
+		Trace:       ctx,
+		MessageComparisonSlots: &MessageComparisonSlots{
+			NetworkID: networkID,
+			ID:        comparison.ID,
+			Slots:     slots,
+		},
+	})
+	return values, nil
+}
+
+// deal the ComparisonValues for a Comparison to all nodes in the network, and
+// return the ComparisonValues of this node.
+func (smpc *smpcer) deal(ctx trace.Context, networkID NetworkID, comparison Comparison, addrs identity.Addresses) ([]ComparisonValues, error) {
+	n := int64(len(addrs))
+	dealt := make([][]ComparisonValues, len(comparison.Predicates))
+	for i, predicate := range comparison.Predicates {
+		var err error
+		if dealt[i], err = DealComparisonValues(n, threshold(len(addrs)), predicate); err != nil {
+			return nil, err
+		}
+	}
+
+	trace.Record(ctx, "smpc.compare.deal", trace.Attributes{"predicates": fmt.Sprintf("%v", len(comparison.Predicates))})
+	self := smpc.swarmer.MultiAddress().Address()
+	selfValues := []ComparisonValues{}
+	for j, addr := range addrs {
+		values := make([]ComparisonValues, len(dealt))
+		for i := range dealt {
+			values[i] = dealt[i][j]
+		}
+		if addr == self {
+			selfValues = values
+			continue
+		}
+		smpc.network.SendTo(networkID, addr, Message{
+			MessageType: MessageTypeComparisonValues,
+			Trace:       ctx,
+			MessageComparisonValues: &MessageComparisonValues{
+				NetworkID: networkID,
+				ID:        comparison.ID,
+				Values:    values,
+			},
+		})
+	}
+	return selfValues, nil
+}
+
+// insertComparison merges a comparisonState with the comparisonState already
+// stored for a ComparisonID. Once the Comparison and its ComparisonValues are
+// both known, the Comparison is started. PoolSlots are only consumed once the
+// Comparison is known, and remain stored if their Batch has not been
+// received.
+func (smpc *smpcer) insertComparison(networkID NetworkID, id JoinID, state *comparisonState) error {
+	smpc.comparisonsMu.Lock()
+	states, ok := smpc.comparisons[networkID]
+	if !ok {
+		smpc.comparisonsMu.Unlock()
+		return ErrCompareOnDisconnectedNetwork
+	}
+	state.timestamp = time.Now()
+	if stored, ok := states[id]; ok {
+		state.timestamp = stored.timestamp
+		if state.comparison == nil {
+			state.ctx = stored.ctx
+			state.comparison = stored.comparison
+			state.useDelay = stored.useDelay
+		}
+		if state.values == nil {
+			state.values = stored.values
+		}
+		if state.slots == nil {
+			state.dealer = stored.dealer
+			state.slots = stored.slots
+		}
+	}
+	if state.comparison != nil && state.values == nil && state.slots != nil {
+		values, err := smpc.pool.Consume(networkID, state.dealer, state.comparison.Predicates, state.slots)
+		if err != nil && err != ErrPoolSlotNotFound {
+			delete(states, id)
+			smpc.comparisonsMu.Unlock()
+			return err
+		}
+		state.values = values
+	}
+	if state.comparison == nil || state.values == nil {
+		states[id] = state
+		smpc.comparisonsMu.Unlock()
+		return nil
+	}
+	delete(states, id)
+	smpc.comparisonsMu.Unlock()
+
+	machine, err := NewComparisonMachine(*state.comparison, state.values)
+	if err != nil {
+		return err
+	}
+	return smpc.compareRound(state.ctx, networkID, *state.comparison, machine, 0, state.useDelay)
+}
+
+// compareRound opens the shamir.Shares of a round of a ComparisonMachine, and
+// starts the next round once all values have been opened. The shamir.Shares
+// are split into chunks so that each Join respects the MaxJoinLength.
+func (smpc *smpcer) compareRound(ctx trace.Context, networkID NetworkID, comparison Comparison, machine *ComparisonMachine, round int, useDelay bool) error {
+	smpc.networksMu.RLock()
+	addrs, networkOk := smpc.networks[networkID]
+	smpc.networksMu.RUnlock()
+	smpc.joinersMu.RLock()
+	joiner, joinerOk := smpc.joiners[networkID]
+	smpc.joinersMu.RUnlock()
+	if !networkOk || !joinerOk {
+		return ErrCompareOnDisconnectedNetwork
+	}
+
+	if machine.Done() {
+		// The results are sent to all nodes, including the dealer
+		join := Join{
+			ID:     comparisonJoinID(comparison.ID, comparisonRoundResults, 0),
+			Index:  comparison.Index,
+			Shares: machine.Results(),
+		}
+		smpc.sendJoin(ctx, networkID, join, identity.Address(""), useDelay)
+		return joiner.InsertJoin(join)
+	}
+
+	shares := machine.Open()
+	joins := make([]Join, 0, (len(shares)+MaxJoinLength-1)/MaxJoinLength)
+	for chunk := 0; chunk*MaxJoinLength < len(shares); chunk++ {
+		end := (chunk + 1) * MaxJoinLength
+		if end > len(shares) {
+			end = len(shares)
+		}
+		joins = append(joins, Join{
+			ID:     comparisonJoinID(comparison.ID, round, chunk),
+			Index:  comparison.Index,
+			Shares: shares[chunk*MaxJoinLength : end],
+		})
+	}
+
+	// Send all Joins before inserting them, because inserting a Join can
+	// complete the round
+	exclude := identity.Address("")
+	if smpc.excludeDealer(addrs) {
+		exclude = comparisonDealer(comparison.ID, addrs)
+	}
+	for _, join := range joins {
+		smpc.sendJoin(ctx, networkID, join, exclude, false)
+	}
+
+	openedMu := new(sync.Mutex)
+	opened := make([]uint64, len(shares))
+	remaining := len(joins)
+	for chunk, join := range joins {
+		offset := chunk * MaxJoinLength
+		err := joiner.InsertJoinAndSetCallback(join, func(joinID JoinID, values []uint64) {
+			openedMu.Lock()
+			copy(opened[offset:], values)
+			remaining--
+			done := remaining == 0
+			openedMu.Unlock()
+			if !done {
+				return
+			}
+
+			// The Joins of this round are no longer needed once the next
+			// round starts
+			joinIDs := make([]JoinID, len(joins))
+			for i := range joins {
+				joinIDs[i] = joins[i].ID
+			}
+			smpc.deleteJoins(joiner, joinIDs...)
+
+			if err := machine.Next(opened); err != nil {
+				logger.Smpc(logger.LevelError, fmt.Sprintf("cannot compare %v: %v", base64.StdEncoding.EncodeToString(comparison.ID[:8]), err))
+				return
+			}
+			if err := smpc.compareRound(ctx, networkID, comparison, machine, round+1, useDelay); err != nil {
+				logger.Smpc(logger.LevelError, fmt.Sprintf("cannot compare %v: %v", base64.StdEncoding.EncodeToString(comparison.ID[:8]), err))
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendJoin to all nodes in a network, except for one address. The Join is
+// stored so that it can be sent in response to the Joins of other nodes, but
+// never to the excluded address. Delays can only be used when no address is
+// excluded.
+func (smpc *smpcer) sendJoin(ctx trace.Context, networkID NetworkID, join Join, exclude identity.Address, useDelay bool) {
+	smpc.selfJoinsMu.Lock()
+	smpc.selfJoins[join.ID] = selfJoin{networkID: networkID, join: join, exclude: exclude, timestamp: time.Now()}
+	smpc.selfJoinsMu.Unlock()
+
+	message := Message{
+		MessageType: MessageTypeJoin,
+		Trace:       ctx,
+		MessageJoin: &MessageJoin{
+			NetworkID: networkID,
+			Join:      join,
+		},
+	}
+	if exclude == "" {
+		if useDelay {
+			smpc.network.SendWithDelay(networkID, message)
+		} else {
+			smpc.network.Send(networkID, message)
+		}
+		return
+	}
+
+	smpc.networksMu.RLock()
+	addrs := smpc.networks[networkID]
+	smpc.networksMu.RUnlock()
+	self := smpc.swarmer.MultiAddress().Address()
+	for _, addr := range addrs {
+		if addr != self && addr != exclude {
+			smpc.network.SendTo(networkID, addr, message)
+		}
+	}
+}
+
+// deleteJoins from a Joiner, and from the Joins sent by this node, once they
+// are no longer needed.
+func (smpc *smpcer) deleteJoins(joiner *Joiner, joinIDs ...JoinID) {
+	for _, joinID := range joinIDs {
+		joiner.Delete(joinID)
+	}
+	smpc.selfJoinsMu.Lock()
+	for _, joinID := range joinIDs {
+		delete(smpc.selfJoins, joinID)
+	}
+	smpc.selfJoinsMu.Unlock()
+}
+
+// prune the state of Joins, and Comparisons, that is older than the
+// MaxStateAge. State is pruned at most once per PruneInterval, so it is safe
+// to call prune whenever this node receives a message.
+func (smpc *smpcer) prune() {
+	now := time.Now()
+	pruned := atomic.LoadInt64(&smpc.pruned)
+	if now.Sub(time.Unix(0, pruned)) < PruneInterval {
+		return
+	}
+	if !atomic.CompareAndSwapInt64(&smpc.pruned, pruned, now.UnixNano()) {
+		return
+	}
+	smpc.pruneBefore(now.Add(-MaxStateAge))
+}
+
+// pruneBefore removes the state of Joins, and Comparisons, that was stored
+// before a time.
+func (smpc *smpcer) pruneBefore(before time.Time) {
+	smpc.joinersMu.RLock()
+	for _, joiner := range smpc.joiners {
+		joiner.Prune(before)
+	}
+	smpc.joinersMu.RUnlock()
+
+	smpc.selfJoinsMu.Lock()
+	for joinID, self := range smpc.selfJoins {
+		if self.timestamp.Before(before) {
+			delete(smpc.selfJoins, joinID)
+		}
+	}
+	smpc.selfJoinsMu.Unlock()
+
+	smpc.comparisonsMu.Lock()
+	for _, states := range smpc.comparisons {
+		for id, state := range states {
+			if state.timestamp.Before(before) {
+				delete(states, id)
+			}
+		}
+	}
+	smpc.comparisonsMu.Unlock()
+}
+
+// Preprocess implements the Smpcer interface.
+func (smpc *smpcer) Preprocess(done <-chan struct{}, depth int, interval time.Duration) {
+	if depth > MaxPoolDepth-MaxBatchLength {
+		depth = MaxPoolDepth - MaxBatchLength
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		// Preprocessing competes with comparisons for bandwidth, so it only
+		// happens while this node is idle
+		compared := time.Unix(0, atomic.LoadInt64(&smpc.compared))
+		if time.Since(compared) < interval {
+			continue
+		}
+
+		networks := map[NetworkID]identity.Addresses{}
+		smpc.networksMu.RLock()
+		for networkID, addrs := range smpc.networks {
+			networks[networkID] = addrs
+		}
+		smpc.networksMu.RUnlock()
+
+		for networkID, addrs := range networks {
+			depths := smpc.pool.Depth(networkID)
+			for _, predicate := range PoolPredicates {
+				if depths[predicate].Dealt >= depth {
+					continue
+				}
+				if err := smpc.dealBatch(networkID, addrs, predicate); err != nil {
+					logger.Smpc(logger.LevelError, fmt.Sprintf("cannot preprocess %v for network %v: %v", predicate, networkID, err))
+				}
+			}
+		}
+	}
+}
+
+// PoolDepths implements the Smpcer interface.
+func (smpc *smpcer) PoolDepths() map[NetworkID]map[Predicate]PoolDepth {
+	smpc.networksMu.RLock()
+	defer smpc.networksMu.RUnlock()
+
+	depths := map[NetworkID]map[Predicate]PoolDepth{}
+	for networkID := range smpc.networks {
+		depths[networkID] = smpc.pool.Depth(networkID)
+	}
+	return depths
+}
+
+// dealBatch of ComparisonValues for a Predicate to all nodes in the network.
+// The Batch is inserted into the Pool of this node before it is sent, so that
+// it can be used as soon as the other nodes have received it. Other nodes
+// only consume the Batch when this node assigns it to a Comparison that this
+// node deals.
+func (smpc *smpcer) dealBatch(networkID NetworkID, addrs identity.Addresses, predicate Predicate) error {
+	id := BatchID{}
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	batches := make([]Batch, len(addrs))
+	for j := range batches {
+		batches[j] = Batch{ID: id, Predicate: predicate, Values: make([]ComparisonValues, 0, MaxBatchLength)}
+	}
+	for i := 0; i < MaxBatchLength; i++ {
+		values, err := DealComparisonValues(int64(len(addrs)), threshold(len(addrs)), predicate)
+		if err != nil {
+			return err
+		}
+		for j := range batches {
+			batches[j].Values = append(batches[j].Values, values[j])
+		}
+	}
+
+	self := smpc.swarmer.MultiAddress().Address()
+	for j, addr := range addrs {
+		if addr == self {
+			if err := smpc.pool.Insert(networkID, self, batches[j]); err != nil {
+				return err
+			}
+		}
+	}
+	for j, addr := range addrs {
+		if addr == self {
+			continue
+		}
+		smpc.network.SendTo(networkID, addr, Message{
+			MessageType: MessageTypeBatch,
+			MessageBatch: &MessageBatch{
+				NetworkID: networkID,
+				Batch:     batches[j],
+			},
+		})
+	}
+	return nil
+}
+
+// excludeDealer returns true when there are enough nodes in a network to
+// reconstruct values without the dealer of a Comparison.
+func (smpc *smpcer) excludeDealer(addrs identity.Addresses) bool {
+	return threshold(len(addrs)) <= int64(len(addrs)-1)
+}
+
+// threshold returns the number of nodes that are needed to reconstruct values
+// in a network of n nodes.
+func threshold(n int) int64 {
+	return int64(2 * (n + 1) / 3)
+}
+
 // Receive implements the Receiver interface.
 func (smpc *smpcer) Receive(from identity.Address, message Message) {
+	smpc.prune()
+
 	switch message.MessageType {
 	case MessageTypeJoin:
-		if err := smpc.handleMessageJoin(from, message.MessageJoin); err != nil {
+		trace.Record(message.Trace, "smpc.join.receive", trace.Attributes{"from": from.String()})
+		if err := smpc.handleMessageJoin(from, message.Trace, message.MessageJoin); err != nil {
 			logger.Network(logger.LevelError, fmt.Sprintf("error handling join message from smpc node %v: %v", from, err))
 		}
 	case MessageTypeJoinResponse:
-		if err := smpc.handleMessageJoinResponse(message.MessageJoinResponse); err != nil {
+		trace.Record(message.Trace, "smpc.join.receiveResponse", trace.Attributes{"from": from.String()})
+		if err := smpc.handleMessageJoinResponse(from, message.MessageJoinResponse); err != nil {
 			logger.Network(logger.LevelError, fmt.Sprintf("error handling joinResponse message from smpc node %v: %v", from, err))
 		}
+	case MessageTypeComparisonValues:
+		trace.Record(message.Trace, "smpc.compare.receiveValues", trace.Attributes{"from": from.String()})
+		if err := smpc.handleMessageComparisonValues(from, message.Trace, message.MessageComparisonValues); err != nil {
+			logger.Network(logger.LevelError, fmt.Sprintf("error handling comparisonValues message from smpc node %v: %v", from, err))
+		}
+	case MessageTypeBatch:
+		if err := smpc.handleMessageBatch(from, message.MessageBatch); err != nil {
+			logger.Network(logger.LevelError, fmt.Sprintf("error handling batch message from smpc node %v: %v", from, err))
+		}
+	case MessageTypeComparisonSlots:
+		trace.Record(message.Trace, "smpc.compare.receiveSlots", trace.Attributes{"from": from.String()})
+		if err := smpc.handleMessageComparisonSlots(from, message.Trace, message.MessageComparisonSlots); err != nil {
+			logger.Network(logger.LevelError, fmt.Sprintf("error handling comparisonSlots message from smpc node %v: %v", from, err))
+		}
 	default:
 		logger.Network(logger.LevelError, fmt.Sprintf("error receiving message from smpc node %v: %v", from, ErrUnexpectedMessageType))
 	}
 }
 
-func (smpc *smpcer) handleMessageJoin(from identity.Address, message *MessageJoin) error {
+func (smpc *smpcer) handleMessageJoin(from identity.Address, ctx trace.Context, message *MessageJoin) error {
+	if err := smpc.verifySender(message.NetworkID, from); err != nil {
+		return err
+	}
+
+	// Nodes that are excluded from a Join, such as the dealer of a
+	// Comparison, must not be able to request it
+	smpc.selfJoinsMu.RLock()
+	self, selfOk := smpc.selfJoins[message.Join.ID]
+	smpc.selfJoinsMu.RUnlock()
+	if selfOk && self.exclude == from {
+		return ErrUnexpectedSender
+	}
+
 	if !smpc.verifyJoin(message.NetworkID, message.Join) {
 		return ErrUnverifiedJoin
 	}
@@ -169,20 +788,17 @@ func (smpc *smpcer) handleMessageJoin(from identity.Address, message *MessageJoi
 	if err != nil {
 		return err
 	}
+	if !selfOk || self.networkID != message.NetworkID {
+		return nil
+	}
 
 	go func() {
-		smpc.selfJoinsMu.RLock()
-		join, ok := smpc.selfJoins[message.Join.ID]
-		smpc.selfJoinsMu.RUnlock()
-		if !ok {
-			return
-		}
-
 		response := Message{
 			MessageType: MessageTypeJoinResponse,
+			Trace:       ctx,
 			MessageJoinResponse: &MessageJoinResponse{
 				NetworkID: message.NetworkID,
-				Join:      join,
+				Join:      self.join,
 			},
 		}
 		smpc.network.SendTo(message.NetworkID, from, response)
@@ -191,7 +807,23 @@ func (smpc *smpcer) handleMessageJoin(from identity.Address, message *MessageJoi
 	return nil
 }
 
-func (smpc *smpcer) handleMessageJoinResponse(message *MessageJoinResponse) error {
+func (smpc *smpcer) handleMessageJoinResponse(from identity.Address, message *MessageJoinResponse) error {
+	if err := smpc.verifySender(message.NetworkID, from); err != nil {
+		return err
+	}
+
+	// Responses are only accepted for Joins that this node has sent to the
+	// sender, and are ignored once the Join is no longer stored
+	smpc.selfJoinsMu.RLock()
+	self, ok := smpc.selfJoins[message.Join.ID]
+	smpc.selfJoinsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	if self.networkID != message.NetworkID || self.exclude == from {
+		return ErrUnexpectedSender
+	}
+
 	if !smpc.verifyJoin(message.NetworkID, message.Join) {
 		return ErrUnverifiedJoin
 	}
@@ -206,6 +838,91 @@ func (smpc *smpcer) handleMessageJoinResponse(message *MessageJoinResponse) erro
 	return err
 }
 
+func (smpc *smpcer) handleMessageComparisonValues(from identity.Address, ctx trace.Context, message *MessageComparisonValues) error {
+	smpc.networksMu.RLock()
+	addrs, ok := smpc.networks[message.NetworkID]
+	smpc.networksMu.RUnlock()
+	if !ok {
+		return ErrCompareOnDisconnectedNetwork
+	}
+	if from != comparisonDealer(message.ID, addrs) {
+		return ErrUnexpectedDealer
+	}
+	return smpc.insertComparison(message.NetworkID, message.ID, &comparisonState{ctx: ctx, values: message.Values})
+}
+
+func (smpc *smpcer) handleMessageBatch(from identity.Address, message *MessageBatch) error {
+	if from == smpc.swarmer.MultiAddress().Address() {
+		return ErrUnexpectedDealer
+	}
+	smpc.networksMu.RLock()
+	addrs, ok := smpc.networks[message.NetworkID]
+	smpc.networksMu.RUnlock()
+	if !ok {
+		return ErrCompareOnDisconnectedNetwork
+	}
+	member := false
+	for _, addr := range addrs {
+		if addr == from {
+			member = true
+			break
+		}
+	}
+	if !member {
+		return ErrUnexpectedDealer
+	}
+	if err := smpc.pool.Insert(message.NetworkID, from, message.Batch); err != nil {
+		return err
+	}
+
+	// Comparisons that are waiting for the Batch can now consume their
+	// PoolSlots
+	ids := []JoinID{}
+	smpc.comparisonsMu.Lock()
+	for id, state := range smpc.comparisons[message.NetworkID] {
+		if state.dealer == from && state.comparison != nil && state.values == nil {
+			ids = append(ids, id)
+		}
+	}
+	smpc.comparisonsMu.Unlock()
+
+	for _, id := range ids {
+		if err := smpc.insertComparison(message.NetworkID, id, &comparisonState{}); err != nil {
+			logger.Smpc(logger.LevelError, fmt.Sprintf("cannot compare %v: %v", base64.StdEncoding.EncodeToString(id[:8]), err))
+		}
+	}
+	return nil
+}
+
+func (smpc *smpcer) handleMessageComparisonSlots(from identity.Address, ctx trace.Context, message *MessageComparisonSlots) error {
+	smpc.networksMu.RLock()
+	addrs, ok := smpc.networks[message.NetworkID]
+	smpc.networksMu.RUnlock()
+	if !ok {
+		return ErrCompareOnDisconnectedNetwork
+	}
+	if from != comparisonDealer(message.ID, addrs) {
+		return ErrUnexpectedDealer
+	}
+	return smpc.insertComparison(message.NetworkID, message.ID, &comparisonState{ctx: ctx, dealer: from, slots: message.Slots})
+}
+
+// verifySender returns an error if a node is not in a network.
+func (smpc *smpcer) verifySender(networkID NetworkID, from identity.Address) error {
+	smpc.networksMu.RLock()
+	addrs, ok := smpc.networks[networkID]
+	smpc.networksMu.RUnlock()
+	if !ok {
+		return ErrJoinOnDisconnectedNetwork
+	}
+	for _, addr := range addrs {
+		if addr == from {
+			return nil
+		}
+	}
+	return ErrUnexpectedSender
+}
+
 func (smpc *smpcer) verifyJoin(networkID NetworkID, join Join) bool {
 	// FIXME: Pedersen commitment verification has been disabled. This needs
 	// to be re-enabled.
@@ -267,7 +984,7 @@ func (smpc *smpcer) verifyJoin(networkID NetworkID, join Join) bool {
 
 		if expected.Cmp(got.Int) != 0 {
 			// Reject the join
-			log.Printf("reject the join due to %vth share, expected=%v , got=%v", i, expected.Int64(), got.Int64())
+			logger.Smpc(logger.LevelWarn, fmt.Sprintf("reject the join due to %vth share, expected=%v , got=%v", i, expected.Int64(), got.Int64()))
 			return false
 		}
 	}
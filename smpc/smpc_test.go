@@ -6,6 +6,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,8 +20,10 @@ import (
 	"github.com/republicprotocol/republic-go/grpc"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/swarm"
 	"github.com/republicprotocol/republic-go/testutils"
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 var (
@@ -88,7 +91,7 @@ var _ = Describe("Smpcer", func() {
 				callback := generateCallback(&called, ord)
 
 				dispatch.CoForAll(nodes, func(i int) {
-					err := nodes[i].Smpcer.Join(networkID, joins[i], callback, false)
+					err := nodes[i].Smpcer.Join(trace.Context{}, networkID, joins[i], callback, false)
 					Expect(err).ShouldNot(HaveOccurred())
 				})
 				for atomic.LoadInt64(&called) < int64(numDarknodes) {
@@ -112,6 +115,238 @@ var _ = Describe("Smpcer", func() {
 		// })
 
 	})
+	Context("when exchanging messages in memory", func() {
+
+		networkID := NetworkID{1}
+		n := int64(6)
+		k := 2 * (n + 1) / 3
+
+		var hub *messageHub
+		var smpcers []Smpcer
+		var addrs identity.Addresses
+
+		BeforeEach(func() {
+			var err error
+			hub = newMessageHub()
+			smpcers, addrs, err = generateHubSmpcers(hub, int(n))
+			Expect(err).ShouldNot(HaveOccurred())
+			for i := range smpcers {
+				smpcers[i].Connect(networkID, addrs)
+			}
+			// Wait for the connections to be established
+			time.Sleep(100 * time.Millisecond)
+		})
+
+		AfterEach(func() {
+			for i := range smpcers {
+				smpcers[i].Disconnect(networkID)
+			}
+		})
+
+		// stall the first round of a Comparison by only starting it on the
+		// dealer and k - 1 other nodes, which cannot open values without the
+		// dealer. It returns the indices of the nodes that started the
+		// Comparison, starting with the dealer, and of one other node.
+		stall := func(id JoinID) ([]int, int) {
+			shares, err := shamir.Split(n, k, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+			dealer := ComparisonDealer(id, addrs)
+
+			started, other := []int{}, -1
+			for i := range addrs {
+				if addrs[i] == dealer {
+					started = append([]int{i}, started...)
+				}
+			}
+			for i := range addrs {
+				if addrs[i] == dealer {
+					continue
+				}
+				if int64(len(started)) < k {
+					started = append(started, i)
+				} else if other < 0 {
+					other = i
+				}
+			}
+			Expect(addrs[started[0]]).Should(Equal(dealer))
+			Expect(int64(len(started))).Should(Equal(k))
+
+			for _, i := range started {
+				comparison := Comparison{
+					ID:         id,
+					Index:      JoinIndex(shares[i].Index),
+					Shares:     shamir.Shares{shares[i]},
+					Predicates: []Predicate{PredicateEqualToZero},
+				}
+				Expect(smpcers[i].Compare(trace.Context{}, networkID, comparison, func(JoinID, []bool) {}, false)).ShouldNot(HaveOccurred())
+			}
+			for _, i := range started[1:] {
+				Eventually(func() int {
+					return hub.joins(addrs[i], "", MessageTypeJoin, ComparisonJoinID(id, 0, 0))
+				}).Should(BeNumerically(">", 0))
+			}
+			return started, other
+		}
+
+		// openingJoin returns a Join for the first round of a Comparison
+		// that is stalled, as it would be sent by a node.
+		openingJoin := func(id JoinID, index int) Join {
+			hub.mu.Lock()
+			defer hub.mu.Unlock()
+			for _, sent := range hub.messages {
+				if sent.message.MessageType != MessageTypeJoin || sent.message.MessageJoin.Join.ID != ComparisonJoinID(id, 0, 0) {
+					continue
+				}
+				join := Join{ID: ComparisonJoinID(id, 0, 0), Index: JoinIndex(index + 1)}
+				for range sent.message.MessageJoin.Join.Shares {
+					join.Shares = append(join.Shares, shamir.Share{Index: uint64(index + 1), Value: 1})
+				}
+				return join
+			}
+			Fail("the comparison has not started")
+			return Join{}
+		}
+
+		It("should compare shared values without sending the opened values to the dealer", func() {
+			predicates := []Predicate{PredicateLessThanZero, PredicateGreaterThanZero, PredicateEqualToZero}
+			value := shamir.Prime - 5
+			shares, err := shamir.Split(n, k, value)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			id := JoinID{1}
+			results := make(chan []bool, n)
+			for i := range smpcers {
+				comparison := Comparison{
+					ID:         id,
+					Index:      JoinIndex(shares[i].Index),
+					Shares:     shamir.Shares{shares[i], shares[i], shares[i]},
+					Predicates: predicates,
+				}
+				Expect(smpcers[i].Compare(trace.Context{}, networkID, comparison, func(_ JoinID, values []bool) {
+					results <- values
+				}, false)).ShouldNot(HaveOccurred())
+			}
+			for i := int64(0); i < n; i++ {
+				Eventually(results, 10*time.Second).Should(Receive(Equal([]bool{true, false, false})))
+			}
+
+			dealer := ComparisonDealer(id, addrs)
+			resultsID := ComparisonJoinID(id, ComparisonRoundResults, 0)
+			Expect(hub.joins("", dealer, MessageTypeJoin, JoinID{})).Should(Equal(hub.joins("", dealer, MessageTypeJoin, resultsID)))
+			Expect(hub.joins("", dealer, MessageTypeJoinResponse, JoinID{})).Should(Equal(hub.joins("", dealer, MessageTypeJoinResponse, resultsID)))
+		})
+
+		It("should delete the state of a comparison once it has finished", func() {
+			shares, err := shamir.Split(n, k, 42)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			id := JoinID{4}
+			results := make(chan []bool, n)
+			for i := range smpcers {
+				comparison := Comparison{
+					ID:         id,
+					Index:      JoinIndex(shares[i].Index),
+					Shares:     shamir.Shares{shares[i]},
+					Predicates: []Predicate{PredicateGreaterThanZero},
+				}
+				Expect(smpcers[i].Compare(trace.Context{}, networkID, comparison, func(_ JoinID, values []bool) {
+					results <- values
+				}, false)).ShouldNot(HaveOccurred())
+			}
+			for i := int64(0); i < n; i++ {
+				Eventually(results, 10*time.Second).Should(Receive(Equal([]bool{true})))
+			}
+			for i := range smpcers {
+				Eventually(func() []int {
+					selfJoins, comparisons := StateLen(smpcers[i])
+					return []int{selfJoins, comparisons}
+				}).Should(Equal([]int{0, 0}))
+			}
+		})
+
+		It("should prune the state of comparisons that never start", func() {
+			id := JoinID{5}
+			started, _ := stall(id)
+			node := started[1]
+
+			selfJoins, _ := StateLen(smpcers[node])
+			Expect(selfJoins).Should(BeNumerically(">", 0))
+			PruneBefore(smpcers[node], time.Now().Add(-time.Minute))
+			Expect(StateLen(smpcers[node])).Should(Equal(selfJoins))
+
+			PruneBefore(smpcers[node], time.Now().Add(time.Minute))
+			selfJoins, comparisons := StateLen(smpcers[node])
+			Expect(selfJoins).Should(Equal(0))
+			Expect(comparisons).Should(Equal(0))
+		})
+
+		It("should not respond to comparison joins from the dealer", func() {
+			id := JoinID{2}
+			started, other := stall(id)
+			dealer, node := started[0], started[1]
+
+			message := Message{
+				MessageType: MessageTypeJoin,
+				MessageJoin: &MessageJoin{NetworkID: networkID, Join: openingJoin(id, dealer)},
+			}
+			Expect(hub.send(addrs[dealer], addrs[node], message)).ShouldNot(HaveOccurred())
+			Consistently(func() int {
+				return hub.joins(addrs[node], addrs[dealer], MessageTypeJoinResponse, JoinID{})
+			}).Should(Equal(0))
+
+			message.MessageJoin.Join = openingJoin(id, other)
+			Expect(hub.send(addrs[other], addrs[node], message)).ShouldNot(HaveOccurred())
+			Eventually(func() int {
+				return hub.joins(addrs[node], addrs[other], MessageTypeJoinResponse, ComparisonJoinID(id, 0, 0))
+			}).Should(Equal(1))
+		})
+
+		It("should only accept join responses from nodes that were sent the join", func() {
+			id := JoinID{3}
+			started, other := stall(id)
+			dealer, node := started[0], started[1]
+			outsider, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			// Responses from the dealer, or from nodes that are not in the
+			// network, do not open the values of the round
+			for _, from := range []identity.Address{addrs[dealer], outsider} {
+				message := Message{
+					MessageType:         MessageTypeJoinResponse,
+					MessageJoinResponse: &MessageJoinResponse{NetworkID: networkID, Join: openingJoin(id, dealer)},
+				}
+				hub.send(from, addrs[node], message)
+			}
+			Consistently(func() int {
+				return hub.joins(addrs[node], "", MessageTypeJoin, ComparisonJoinID(id, 1, 0))
+			}).Should(Equal(0))
+
+			message := Message{
+				MessageType:         MessageTypeJoinResponse,
+				MessageJoinResponse: &MessageJoinResponse{NetworkID: networkID, Join: openingJoin(id, other)},
+			}
+			Expect(hub.send(addrs[other], addrs[node], message)).ShouldNot(HaveOccurred())
+			Eventually(func() int {
+				return hub.joins(addrs[node], "", MessageTypeJoin, ComparisonJoinID(id, 1, 0))
+			}).Should(BeNumerically(">", 0))
+		})
+
+		It("should only insert batches dealt by nodes in the network", func() {
+			outsider, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			batch := generateBatch(BatchID{1}, n, k, PredicateLessThanZero, 0)
+			message := Message{
+				MessageType:  MessageTypeBatch,
+				MessageBatch: &MessageBatch{NetworkID: networkID, Batch: batch},
+			}
+
+			Expect(hub.send(outsider, addrs[0], message)).ShouldNot(HaveOccurred())
+			Expect(smpcers[0].PoolDepths()[networkID][PredicateLessThanZero].Received).Should(Equal(0))
+
+			Expect(hub.send(addrs[1], addrs[0], message)).ShouldNot(HaveOccurred())
+			Expect(smpcers[0].PoolDepths()[networkID][PredicateLessThanZero].Received).Should(Equal(MaxBatchLength))
+		})
+	})
 })
 
 type mockNode struct {
@@ -175,14 +410,16 @@ func generateMocknodes(n, α int) ([]*mockNode, []identity.Address, []swarm.Mult
 		}
 		verifier := registry.NewCrypter(key, testutils.NewMockSwarmBinder(), 2, time.Hour)
 
-		swarmer := swarm.NewSwarmer(swarmClient, stores[i], α, &verifier)
+		table := swarm.NewRoutingTable(multiAddr.Address(), swarm.BucketSize)
+		tracker := swarm.NewPeerTracker()
+		swarmer := swarm.NewSwarmer(swarmClient, stores[i], table, tracker, α, &verifier)
 
-		swarmService := grpc.NewSwarmService(swarm.NewServer(swarmer, stores[i], α, &verifier))
+		swarmService := grpc.NewSwarmService(swarm.NewServer(swarmer, stores[i], table, tracker, swarm.NewGuard(swarm.DefaultNonceWindow, swarm.DefaultRelayLimit, swarm.DefaultRelayBurst), α, &verifier))
 
-		streamer := grpc.NewConnectorListener(addr, testutils.NewCrypter(), testutils.NewCrypter())
-		streamerService := grpc.NewStreamerService(addr, testutils.NewCrypter(), testutils.NewCrypter(), streamer.Listener)
+		streamer := grpc.NewConnectorListener(addr, &keystore.EcdsaKey, testutils.NewCrypter())
+		streamerService := grpc.NewStreamerService(addr, &keystore.EcdsaKey, testutils.NewCrypter(), streamer.Listener)
 
-		smpcer := NewSmpcer(streamer, swarmer)
+		smpcer := NewSmpcer(streamer, swarmer, tracker)
 
 		addresses[i] = addr
 		nodes[i] = new(mockNode)
@@ -203,3 +440,145 @@ func generateMocknodes(n, α int) ([]*mockNode, []identity.Address, []swarm.Mult
 
 	return nodes, addresses, stores, nil
 }
+
+// A messageHub delivers the messages sent between Smpcers in memory, and
+// records them.
+type messageHub struct {
+	mu        *sync.Mutex
+	receivers map[identity.Address]Receiver
+	messages  []hubMessage
+}
+
+type hubMessage struct {
+	from    identity.Address
+	to      identity.Address
+	message Message
+}
+
+func newMessageHub() *messageHub {
+	return &messageHub{
+		mu:        new(sync.Mutex),
+		receivers: map[identity.Address]Receiver{},
+	}
+}
+
+// send a message, encoding and decoding it as if it was sent over a network.
+func (hub *messageHub) send(from, to identity.Address, message Message) error {
+	data, err := message.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	received := Message{}
+	if err := received.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	hub.mu.Lock()
+	hub.messages = append(hub.messages, hubMessage{from: from, to: to, message: received})
+	receiver, ok := hub.receivers[to]
+	hub.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("cannot send message to unknown address %v", to)
+	}
+	receiver.Receive(from, received)
+	return nil
+}
+
+// joins returns the number of messages of a MessageType, for a JoinID, that
+// have been sent from one address to another. Empty addresses, and the empty
+// JoinID, match any value.
+func (hub *messageHub) joins(from, to identity.Address, ty MessageType, id JoinID) int {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	n := 0
+	for _, sent := range hub.messages {
+		if (from != "" && sent.from != from) || (to != "" && sent.to != to) || sent.message.MessageType != ty {
+			continue
+		}
+		join := Join{}
+		switch ty {
+		case MessageTypeJoin:
+			join = sent.message.MessageJoin.Join
+		case MessageTypeJoinResponse:
+			join = sent.message.MessageJoinResponse.Join
+		}
+		if id == (JoinID{}) || join.ID == id {
+			n++
+		}
+	}
+	return n
+}
+
+type hubConnectorListener struct {
+	hub  *messageHub
+	self identity.Address
+}
+
+func (conn hubConnectorListener) Connect(ctx context.Context, networkID NetworkID, to identity.MultiAddress, receiver Receiver) (Sender, error) {
+	return hubSender{hub: conn.hub, from: conn.self, to: to.Address()}, nil
+}
+
+func (conn hubConnectorListener) Listen(ctx context.Context, networkID NetworkID, to identity.Address, receiver Receiver) (Sender, error) {
+	return hubSender{hub: conn.hub, from: conn.self, to: to}, nil
+}
+
+type hubSender struct {
+	hub  *messageHub
+	from identity.Address
+	to   identity.Address
+}
+
+func (sender hubSender) Send(message Message) error {
+	return sender.hub.send(sender.from, sender.to, message)
+}
+
+type hubSwarmer struct {
+	multiAddr identity.MultiAddress
+}
+
+func (swarmer hubSwarmer) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (swarmer hubSwarmer) Pong(ctx context.Context, to identity.MultiAddress) error {
+	return nil
+}
+
+func (swarmer hubSwarmer) BroadcastMultiAddress(ctx context.Context, multiAddr identity.MultiAddress) error {
+	return nil
+}
+
+func (swarmer hubSwarmer) Query(ctx context.Context, query identity.Address) (identity.MultiAddress, error) {
+	return query.MultiAddress()
+}
+
+func (swarmer hubSwarmer) MultiAddress() identity.MultiAddress {
+	return swarmer.multiAddr
+}
+
+func (swarmer hubSwarmer) Peers() (identity.MultiAddresses, error) {
+	return identity.MultiAddresses{}, nil
+}
+
+func generateHubSmpcers(hub *messageHub, n int) ([]Smpcer, identity.Addresses, error) {
+	smpcers := make([]Smpcer, n)
+	addrs := make(identity.Addresses, n)
+	for i := range smpcers {
+		addr, err := testutils.RandomAddress()
+		if err != nil {
+			return nil, nil, err
+		}
+		multiAddr, err := addr.MultiAddress()
+		if err != nil {
+			return nil, nil, err
+		}
+		smpcers[i] = NewSmpcer(hubConnectorListener{hub: hub, self: addr}, hubSwarmer{multiAddr: multiAddr}, swarm.NewPeerTracker())
+		addrs[i] = addr
+
+		hub.mu.Lock()
+		hub.receivers[addr] = smpcers[i].(Receiver)
+		hub.mu.Unlock()
+	}
+	return smpcers, addrs, nil
+}
@@ -19,6 +19,11 @@ type Writer interface {
 	WriteRewardVaultAddress(address string) error
 	WriteInfuraURL(url string) error
 	WriteTokens(tokens map[string]string) error
+	WriteTokenPairs(pairs []string) error
+	WriteFragmentVersions(versions []uint32) error
+	WriteMatchingPolicy(policy string) error
+	WritePoolDepths(depths []PoolDepth) error
+	WriteQueueDepths(depths []QueueDepth) error
 }
 
 // Reader the address
@@ -26,7 +31,7 @@ type Reader interface {
 	Network() (string, error)
 	MultiAddress() (identity.MultiAddress, error)
 	PublicKey() ([]byte, error)
-	Peers() (int, error)
+	Peers() ([]swarm.PeerHealth, error)
 
 	EthereumNetwork() (string, error)
 	EthereumAddress() (string, error)
@@ -34,6 +39,31 @@ type Reader interface {
 	RewardVaultAddress() (string, error)
 	InfuraURL() (string, error)
 	Tokens() (map[string]string, error)
+	TokenPairs() ([]string, error)
+	FragmentVersions() ([]uint32, error)
+	MatchingPolicy() (string, error)
+	PoolDepths() ([]PoolDepth, error)
+	QueueDepths() ([]QueueDepth, error)
+}
+
+// PoolDepth is the number of preprocessed random values, used for secure
+// comparisons, that are stored for a network and predicate.
+type PoolDepth struct {
+	Network   string
+	Predicate string
+	Dealt     int
+	Received  int
+}
+
+// QueueDepth is the number of computations that are waiting to be resolved
+// for an epoch, and the number that have been evicted from, and generated
+// again for, the queue of that epoch.
+type QueueDepth struct {
+	Epoch       string
+	Depth       int
+	Capacity    int
+	Evicted     uint64
+	Regenerated uint64
 }
 
 /*
@@ -58,6 +88,7 @@ type provider struct {
 	mu                      *sync.Mutex
 	network                 string
 	swarmer                 swarm.Swarmer
+	tracker                 swarm.PeerTracker
 	multiAddress            identity.MultiAddress
 	ethereumNetwork         string
 	ethereumAddress         string
@@ -66,13 +97,20 @@ type provider struct {
 	publicKey               []byte
 	infuraURL               string
 	tokens                  map[string]string
+	tokenPairs              []string
+	fragmentVersions        []uint32
+	matchingPolicy          string
+	poolDepths              []PoolDepth
+	queueDepths             []QueueDepth
 }
 
-// NewProvider returns a new provider
-func NewProvider(swarmer swarm.Swarmer) Provider {
+// NewProvider returns a new provider that reports the health of the peers
+// known by the swarmer using the tracker
+func NewProvider(swarmer swarm.Swarmer, tracker swarm.PeerTracker) Provider {
 	return &provider{
 		mu:      new(sync.Mutex),
 		swarmer: swarmer,
+		tracker: tracker,
 	}
 }
 
@@ -191,11 +229,95 @@ func (sp *provider) Tokens() (map[string]string, error) {
 	return sp.tokens, nil
 }
 
-// Peers returns the number of peers the darknode is connected to
-func (sp *provider) Peers() (int, error) {
+// WriteTokenPairs writes the token pairs supported by the dark node to the
+// provider
+func (sp *provider) WriteTokenPairs(pairs []string) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.tokenPairs = pairs
+	return nil
+}
+
+// TokenPairs gets the token pairs supported by the dark node
+func (sp *provider) TokenPairs() ([]string, error) {
+	return sp.tokenPairs, nil
+}
+
+// WriteFragmentVersions writes the order fragment versions supported by the
+// dark node to the provider
+func (sp *provider) WriteFragmentVersions(versions []uint32) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.fragmentVersions = versions
+	return nil
+}
+
+// FragmentVersions gets the order fragment versions supported by the dark
+// node
+func (sp *provider) FragmentVersions() ([]uint32, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.fragmentVersions, nil
+}
+
+// WriteMatchingPolicy writes the policy used by the dark node to order its
+// computations to the provider
+func (sp *provider) WriteMatchingPolicy(policy string) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.matchingPolicy = policy
+	return nil
+}
+
+// MatchingPolicy gets the policy used by the dark node to order its
+// computations
+func (sp *provider) MatchingPolicy() (string, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.matchingPolicy, nil
+}
+
+// WritePoolDepths writes the depths of the preprocessing pools of the dark
+// node to the provider
+func (sp *provider) WritePoolDepths(depths []PoolDepth) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.poolDepths = depths
+	return nil
+}
+
+// PoolDepths gets the depths of the preprocessing pools of the dark node
+func (sp *provider) PoolDepths() ([]PoolDepth, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.poolDepths, nil
+}
+
+// WriteQueueDepths writes the depths of the computation queues of the dark
+// node to the provider
+func (sp *provider) WriteQueueDepths(depths []QueueDepth) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	sp.queueDepths = depths
+	return nil
+}
+
+// QueueDepths gets the depths of the computation queues of the dark node
+func (sp *provider) QueueDepths() ([]QueueDepth, error) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.queueDepths, nil
+}
+
+// Peers returns the health of the peers the darknode is connected to
+func (sp *provider) Peers() ([]swarm.PeerHealth, error) {
 	peers, err := sp.swarmer.Peers()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+	healths := make([]swarm.PeerHealth, len(peers))
+	for i, peer := range peers {
+		healths[i] = sp.tracker.Health(peer.Address())
 	}
-	return len(peers), nil
+	return healths, nil
 }
@@ -3,6 +3,7 @@ package status_test
 import (
 	"fmt"
 	"log"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/swarm"
 	"github.com/republicprotocol/republic-go/testutils"
 )
 
@@ -20,13 +22,15 @@ var _ = Describe("Status", func() {
 		var prov Provider
 		var confAddr identity.Address
 		var swarmer testutils.Swarmer
+		var tracker swarm.PeerTracker
 
 		BeforeEach(func() {
 			var err error
 			confAddr, err = testutils.RandomAddress()
 			Expect(err).ShouldNot(HaveOccurred())
 			swarmer = testutils.NewMockSwarmer()
-			prov = NewProvider(&swarmer)
+			tracker = swarm.NewPeerTracker()
+			prov = NewProvider(&swarmer, tracker)
 		})
 
 		It("should store network information correctly", func() {
@@ -37,6 +41,50 @@ var _ = Describe("Status", func() {
 			Expect(network).Should(Equal(testStr))
 		})
 
+		It("should store token pairs correctly", func() {
+			pairs := []string{"BTC-ETH", "ETH-REN"}
+			err := prov.WriteTokenPairs(pairs)
+			Expect(err).ShouldNot(HaveOccurred())
+			readPairs, err := prov.TokenPairs()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(readPairs).Should(Equal(pairs))
+		})
+
+		It("should store fragment versions correctly", func() {
+			versions := []uint32{0, 1}
+			err := prov.WriteFragmentVersions(versions)
+			Expect(err).ShouldNot(HaveOccurred())
+			readVersions, err := prov.FragmentVersions()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(readVersions).Should(Equal(versions))
+		})
+
+		It("should store the matching policy correctly", func() {
+			err := prov.WriteMatchingPolicy("fifo")
+			Expect(err).ShouldNot(HaveOccurred())
+			policy, err := prov.MatchingPolicy()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(policy).Should(Equal("fifo"))
+		})
+
+		It("should store pool depths correctly", func() {
+			depths := []PoolDepth{{Network: testStr, Predicate: "lessThanZero", Dealt: 1, Received: 2}}
+			err := prov.WritePoolDepths(depths)
+			Expect(err).ShouldNot(HaveOccurred())
+			readDepths, err := prov.PoolDepths()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(readDepths).Should(Equal(depths))
+		})
+
+		It("should store queue depths correctly", func() {
+			depths := []QueueDepth{{Epoch: testStr, Depth: 1, Capacity: 2, Evicted: 3, Regenerated: 4}}
+			err := prov.WriteQueueDepths(depths)
+			Expect(err).ShouldNot(HaveOccurred())
+			readDepths, err := prov.QueueDepths()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(readDepths).Should(Equal(depths))
+		})
+
 		It("should store ethereum address correctly", func() {
 			err := prov.WriteEthereumAddress(testStr)
 			Expect(err).ShouldNot(HaveOccurred())
@@ -75,27 +123,57 @@ var _ = Describe("Status", func() {
 			// shoud have zero by default
 			peers, err := prov.Peers()
 			Expect(err).ShouldNot(HaveOccurred())
-			Expect(peers).Should(Equal(0))
+			Expect(peers).Should(HaveLen(0))
 
 			// should return 1 after adding a peer
 			multiAddr, err := testutils.RandomMultiAddress()
 			swarmer.InsertMultiAddress(multiAddr)
 			Expect(err).ShouldNot(HaveOccurred())
 			peers, err = prov.Peers()
-			Expect(peers).Should(Equal(1))
+			Expect(peers).Should(HaveLen(1))
 
 			// should return 2 after adding another peer
 			multiAddr, err = testutils.RandomMultiAddress()
 			swarmer.InsertMultiAddress(multiAddr)
 			Expect(err).ShouldNot(HaveOccurred())
 			peers, err = prov.Peers()
-			Expect(peers).Should(Equal(2))
+			Expect(peers).Should(HaveLen(2))
 
 			// should return 1 after removing a peer
 			swarmer.RemoveMultiAddress(multiAddr)
 			Expect(err).ShouldNot(HaveOccurred())
 			peers, err = prov.Peers()
-			Expect(peers).Should(Equal(1))
+			Expect(peers).Should(HaveLen(1))
+		})
+
+		It("should return the health of peers", func() {
+			healthy, err := testutils.RandomMultiAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			swarmer.InsertMultiAddress(healthy)
+			tracker.Success(healthy.Address(), 10*time.Millisecond)
+
+			unhealthy, err := testutils.RandomMultiAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			swarmer.InsertMultiAddress(unhealthy)
+			tracker.Failure(unhealthy.Address())
+			tracker.Failure(unhealthy.Address())
+
+			peers, err := prov.Peers()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(peers).Should(HaveLen(2))
+			for _, peer := range peers {
+				switch peer.Address {
+				case healthy.Address():
+					Expect(peer.Successes).Should(Equal(1))
+					Expect(peer.RTT).Should(Equal(10 * time.Millisecond))
+					Expect(peer.IsHealthy()).Should(BeTrue())
+				case unhealthy.Address():
+					Expect(peer.Failures).Should(Equal(2))
+					Expect(peer.IsHealthy()).Should(BeFalse())
+				default:
+					Fail("unexpected peer")
+				}
+			}
 		})
 	})
 
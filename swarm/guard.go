@@ -0,0 +1,248 @@
+package swarm
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/registry"
+	"golang.org/x/time/rate"
+)
+
+// DefaultNonceWindow is the default maximum amount by which the nonce of a
+// multi-address can increase between two pings that are seen by a Guard.
+const DefaultNonceWindow = 128
+
+// DefaultRelayLimit is the default rate at which a Guard will relay the
+// multi-address of a darknode.
+const DefaultRelayLimit = rate.Limit(1.0 / 600.0)
+
+// DefaultRelayBurst is the default number of times a Guard will relay the
+// multi-address of a darknode in quick succession.
+const DefaultRelayBurst = 4
+
+// SeenCacheSize is the number of pings that a Guard remembers, to recognise
+// duplicate pings.
+const SeenCacheSize = 4096
+
+// GuardCacheSize is the number of darknodes that a Guard remembers. When it
+// is exceeded, the least recently seen darknode is forgotten.
+const GuardCacheSize = 4096
+
+// GuardExpiry is the duration after which a Guard forgets the nonce of a
+// darknode that has not been seen.
+const GuardExpiry = 24 * time.Hour
+
+// GuardResetTimeout is the duration after which a Guard admits a nonce that
+// is below the highest nonce seen for a darknode. A darknode that restarts
+// without its database starts again from a low nonce, and must not be
+// ignored until its nonce expires.
+const GuardResetTimeout = 10 * time.Minute
+
+// A Guard protects the network from the amplification of Ping gossip. It
+// ignores duplicate pings and pings that replay old nonces, limits the number
+// of times the multi-address of each darknode is relayed, and only relays
+// the multi-addresses of darknodes that are registered in the current
+// registry.Epoch.
+type Guard interface {
+
+	// Seen returns true if the ping has been admitted recently. It can be
+	// used to drop duplicate pings before verifying signatures.
+	Seen(multiAddr identity.MultiAddress) bool
+
+	// Admit returns true if the ping has not been seen and its nonce is
+	// within the nonce window of the highest nonce seen for the darknode. A
+	// nonce below the highest nonce seen is admitted once the darknode has
+	// not been seen for the GuardResetTimeout. Admitted pings are
+	// remembered. The signature of the ping must be verified before it is
+	// admitted.
+	Admit(multiAddr identity.MultiAddress) bool
+
+	// Latest returns true if the ping is the latest ping admitted for the
+	// darknode, with the same nonce and signature. A retransmission of the
+	// latest ping can be ponged again, because the pong may have been lost,
+	// but it must not be relayed again.
+	Latest(multiAddr identity.MultiAddress) bool
+
+	// Relay returns true if the multi-address should be relayed to the
+	// network. The darknode must be registered in the current
+	// registry.Epoch, and must not have exhausted its relay budget. If no
+	// registry.Epoch is known, registration is not checked.
+	Relay(multiAddr identity.MultiAddress) bool
+
+	// OnChangeEpoch updates the darknodes that are registered in the current
+	// registry.Epoch.
+	OnChangeEpoch(epoch registry.Epoch)
+}
+
+type guardEntry struct {
+	addr     identity.Address
+	elem     *list.Element
+	nonce    uint64
+	hash     [32]byte
+	seenAt   time.Time
+	limiter  *rate.Limiter
+	hasNonce bool
+}
+
+type guard struct {
+	nonceWindow uint64
+	relayLimit  rate.Limit
+	relayBurst  int
+
+	mu         *sync.Mutex
+	entries    map[identity.Address]*guardEntry
+	entryQueue *list.List
+	seen       map[[32]byte]struct{}
+	seenQueue  [][32]byte
+	registered map[identity.Address]struct{}
+}
+
+// NewGuard returns a Guard that rejects nonces that are more than the
+// nonceWindow above the highest nonce seen, and that relays the
+// multi-address of each darknode at the relayLimit, with bursts of at most
+// relayBurst.
+func NewGuard(nonceWindow uint64, relayLimit rate.Limit, relayBurst int) Guard {
+	return &guard{
+		nonceWindow: nonceWindow,
+		relayLimit:  relayLimit,
+		relayBurst:  relayBurst,
+
+		mu:         new(sync.Mutex),
+		entries:    map[identity.Address]*guardEntry{},
+		entryQueue: list.New(),
+		seen:       map[[32]byte]struct{}{},
+		seenQueue:  make([][32]byte, 0, SeenCacheSize),
+	}
+}
+
+// Seen implements the Guard interface.
+func (guard *guard) Seen(multiAddr identity.MultiAddress) bool {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	_, ok := guard.seen[pingHash(multiAddr)]
+	return ok
+}
+
+// Admit implements the Guard interface.
+func (guard *guard) Admit(multiAddr identity.MultiAddress) bool {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	hash := pingHash(multiAddr)
+	if _, ok := guard.seen[hash]; ok {
+		return false
+	}
+
+	now := time.Now()
+	entry := guard.entry(multiAddr.Address(), now)
+	if entry.hasNonce {
+		if multiAddr.Nonce <= entry.nonce && now.Sub(entry.seenAt) < GuardResetTimeout {
+			// The nonce has been replayed
+			return false
+		}
+		if multiAddr.Nonce > entry.nonce && multiAddr.Nonce-entry.nonce > guard.nonceWindow {
+			// The nonce is outside of the window
+			return false
+		}
+	}
+	entry.nonce = multiAddr.Nonce
+	entry.hash = hash
+	entry.seenAt = now
+	entry.hasNonce = true
+
+	guard.remember(hash)
+	return true
+}
+
+// Latest implements the Guard interface.
+func (guard *guard) Latest(multiAddr identity.MultiAddress) bool {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	entry, ok := guard.entries[multiAddr.Address()]
+	if !ok || !entry.hasNonce {
+		return false
+	}
+	return entry.nonce == multiAddr.Nonce && entry.hash == pingHash(multiAddr)
+}
+
+// Relay implements the Guard interface.
+func (guard *guard) Relay(multiAddr identity.MultiAddress) bool {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	if guard.registered != nil {
+		if _, ok := guard.registered[multiAddr.Address()]; !ok {
+			return false
+		}
+	}
+	return guard.entry(multiAddr.Address(), time.Now()).limiter.Allow()
+}
+
+// OnChangeEpoch implements the Guard interface.
+func (guard *guard) OnChangeEpoch(epoch registry.Epoch) {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+
+	guard.registered = make(map[identity.Address]struct{}, len(epoch.Darknodes))
+	for _, addr := range epoch.Darknodes {
+		guard.registered[addr] = struct{}{}
+	}
+
+	// Forget darknodes that are no longer registered
+	for addr, entry := range guard.entries {
+		if _, ok := guard.registered[addr]; !ok {
+			guard.entryQueue.Remove(entry.elem)
+			delete(guard.entries, addr)
+		}
+	}
+}
+
+// entry returns the guardEntry for an identity.Address, creating it if it
+// does not exist or has expired, and marks it as the most recently seen
+// entry. The least recently seen entry is forgotten when there are more than
+// GuardCacheSize entries. The mutex must be locked by the caller.
+func (guard *guard) entry(addr identity.Address, now time.Time) *guardEntry {
+	entry, ok := guard.entries[addr]
+	if ok && !(entry.hasNonce && now.Sub(entry.seenAt) > GuardExpiry) {
+		guard.entryQueue.MoveToBack(entry.elem)
+		return entry
+	}
+
+	limiter := rate.NewLimiter(guard.relayLimit, guard.relayBurst)
+	if ok {
+		// Expiring the nonce must not refill the relay budget
+		limiter = entry.limiter
+		guard.entryQueue.Remove(entry.elem)
+	}
+	entry = &guardEntry{addr: addr, limiter: limiter}
+	entry.elem = guard.entryQueue.PushBack(entry)
+	guard.entries[addr] = entry
+
+	if guard.entryQueue.Len() > GuardCacheSize {
+		oldest := guard.entryQueue.Remove(guard.entryQueue.Front()).(*guardEntry)
+		delete(guard.entries, oldest.addr)
+	}
+	return entry
+}
+
+// remember a ping hash, forgetting the oldest ping hash when the cache is
+// full. The mutex must be locked by the caller.
+func (guard *guard) remember(hash [32]byte) {
+	if len(guard.seenQueue) >= SeenCacheSize {
+		delete(guard.seen, guard.seenQueue[0])
+		guard.seenQueue = guard.seenQueue[1:]
+	}
+	guard.seen[hash] = struct{}{}
+	guard.seenQueue = append(guard.seenQueue, hash)
+}
+
+// pingHash returns a hash that uniquely identifies a ping, including its
+// signature.
+func pingHash(multiAddr identity.MultiAddress) [32]byte {
+	return sha256.Sum256(append(multiAddr.Hash(), multiAddr.Signature...))
+}
@@ -0,0 +1,327 @@
+package swarm_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/swarm"
+
+	"github.com/republicprotocol/republic-go/crypto"
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/leveldb"
+	"github.com/republicprotocol/republic-go/registry"
+	"github.com/republicprotocol/republic-go/testutils"
+	"golang.org/x/time/rate"
+)
+
+var _ = Describe("Guard", func() {
+
+	newSignedMultiAddress := func(key crypto.EcdsaKey, nonce uint64) identity.MultiAddress {
+		multiAddr, err := identity.Address(key.Address()).MultiAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		multiAddr.Nonce = nonce
+		multiAddr.Signature, err = key.Sign(multiAddr.Hash())
+		Expect(err).ShouldNot(HaveOccurred())
+		return multiAddr
+	}
+
+	var key crypto.EcdsaKey
+
+	BeforeEach(func() {
+		var err error
+		key, err = crypto.RandomEcdsaKey()
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when admitting pings", func() {
+
+		It("should not admit duplicate pings", func() {
+			guard := NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst)
+			multiAddr := newSignedMultiAddress(key, 1)
+
+			Expect(guard.Seen(multiAddr)).Should(BeFalse())
+			Expect(guard.Admit(multiAddr)).Should(BeTrue())
+			Expect(guard.Seen(multiAddr)).Should(BeTrue())
+			Expect(guard.Admit(multiAddr)).Should(BeFalse())
+		})
+
+		It("should recognise retransmissions of the latest ping", func() {
+			guard := NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst)
+			multiAddr := newSignedMultiAddress(key, 1)
+			Expect(guard.Latest(multiAddr)).Should(BeFalse())
+			Expect(guard.Admit(multiAddr)).Should(BeTrue())
+			Expect(guard.Latest(multiAddr)).Should(BeTrue())
+
+			// The same nonce with a different signature is not a
+			// retransmission
+			forged := multiAddr
+			forged.Signature = append([]byte{}, multiAddr.Signature...)
+			forged.Signature[0] ^= 0xFF
+			Expect(guard.Latest(forged)).Should(BeFalse())
+
+			Expect(guard.Admit(newSignedMultiAddress(key, 2))).Should(BeTrue())
+			Expect(guard.Latest(multiAddr)).Should(BeFalse())
+		})
+
+		It("should not admit replayed nonces", func() {
+			guard := NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst)
+
+			Expect(guard.Admit(newSignedMultiAddress(key, 2))).Should(BeTrue())
+			Expect(guard.Admit(newSignedMultiAddress(key, 1))).Should(BeFalse())
+			Expect(guard.Admit(newSignedMultiAddress(key, 3))).Should(BeTrue())
+		})
+
+		It("should forget the least recently seen darknodes when the cache is full", func() {
+			guard := NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst)
+			Expect(guard.Admit(newSignedMultiAddress(key, 5))).Should(BeTrue())
+			for i := 0; i < GuardCacheSize; i++ {
+				multiAddr, err := testutils.RandomMultiAddress()
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(guard.Admit(multiAddr)).Should(BeTrue())
+			}
+			Expect(guard.Admit(newSignedMultiAddress(key, 1))).Should(BeTrue())
+		})
+
+		It("should not admit nonces outside of the window", func() {
+			guard := NewGuard(4, DefaultRelayLimit, DefaultRelayBurst)
+
+			Expect(guard.Admit(newSignedMultiAddress(key, 10))).Should(BeTrue())
+			Expect(guard.Admit(newSignedMultiAddress(key, 15))).Should(BeFalse())
+			Expect(guard.Admit(newSignedMultiAddress(key, 14))).Should(BeTrue())
+		})
+	})
+
+	Context("when relaying pings", func() {
+
+		It("should relay at most the burst before the budget is exhausted", func() {
+			guard := NewGuard(DefaultNonceWindow, rate.Every(time.Hour), 3)
+
+			relayed := 0
+			for nonce := uint64(1); nonce <= 10; nonce++ {
+				if guard.Relay(newSignedMultiAddress(key, nonce)) {
+					relayed++
+				}
+			}
+			Expect(relayed).Should(Equal(3))
+		})
+
+		It("should only relay darknodes registered in the current epoch", func() {
+			guard := NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst)
+			other, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			guard.OnChangeEpoch(registry.Epoch{Darknodes: identity.Addresses{identity.Address(other.Address())}})
+			Expect(guard.Relay(newSignedMultiAddress(key, 1))).Should(BeFalse())
+			Expect(guard.Relay(newSignedMultiAddress(other, 1))).Should(BeTrue())
+
+			guard.OnChangeEpoch(registry.Epoch{Darknodes: identity.Addresses{identity.Address(key.Address())}})
+			Expect(guard.Relay(newSignedMultiAddress(key, 2))).Should(BeTrue())
+			Expect(guard.Relay(newSignedMultiAddress(other, 2))).Should(BeFalse())
+		})
+	})
+
+	Context("when gossiping across a network", func() {
+
+		const numberOfNodes = 8
+		const α = 3
+
+		var hub *gossipHub
+		var keys []crypto.EcdsaKey
+		var attacker crypto.EcdsaKey
+
+		BeforeEach(func() {
+			var err error
+			hub = newGossipHub()
+
+			// The attacker is registered with the darknode registry, but may
+			// not be registered in the current epoch
+			attacker, err = crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			keys = make([]crypto.EcdsaKey, numberOfNodes)
+			for i := range keys {
+				keys[i], err = crypto.RandomEcdsaKey()
+				Expect(err).ShouldNot(HaveOccurred())
+				hub.registered[identity.Address(keys[i].Address())] = true
+			}
+			hub.registered[identity.Address(attacker.Address())] = true
+
+			epoch := registry.Epoch{Darknodes: identity.Addresses{}}
+			for i := range keys {
+				epoch.Darknodes = append(epoch.Darknodes, identity.Address(keys[i].Address()))
+			}
+
+			for i, key := range append(keys, attacker) {
+				db, err := leveldb.NewStore(fmt.Sprintf("./tmp/guard-%v.out", i), time.Hour)
+				Expect(err).ShouldNot(HaveOccurred())
+				store := db.SwarmMultiAddressStore()
+				for _, other := range append(keys, attacker) {
+					Expect(store.InsertMultiAddress(newSignedMultiAddress(other, 1))).ShouldNot(HaveOccurred())
+				}
+
+				multiAddr := newSignedMultiAddress(key, 1)
+				verifier := registry.NewCrypter(crypto.Keystore{EcdsaKey: key}, hub, numberOfNodes+1, time.Hour)
+				client := &gossipClient{hub: hub, multiAddr: multiAddr}
+				table := NewRoutingTable(multiAddr.Address(), BucketSize)
+				tracker := NewPeerTracker()
+				guard := NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst)
+				guard.OnChangeEpoch(epoch)
+
+				swarmer := NewSwarmer(client, store, table, tracker, α, &verifier)
+				hub.servers[multiAddr.Address()] = NewServer(swarmer, store, table, tracker, guard, α, &verifier)
+			}
+		})
+
+		AfterEach(func() {
+			os.RemoveAll("./tmp")
+		})
+
+		It("should relay pings from honest darknodes to the network", func() {
+			multiAddr := newSignedMultiAddress(keys[0], 2)
+			Expect(hub.ping(identity.Address(keys[1].Address()), multiAddr)).ShouldNot(HaveOccurred())
+			Expect(hub.pings()).Should(BeNumerically(">", α))
+		})
+
+		It("should pong retransmitted pings without relaying them again", func() {
+			multiAddr := newSignedMultiAddress(keys[0], 2)
+			Expect(hub.ping(identity.Address(keys[1].Address()), multiAddr)).ShouldNot(HaveOccurred())
+			pings := hub.pings()
+			pongs := hub.pongs()
+			Expect(hub.ping(identity.Address(keys[1].Address()), multiAddr)).ShouldNot(HaveOccurred())
+			Expect(hub.pings()).Should(Equal(pings + 1))
+			Expect(hub.pongs()).Should(Equal(pongs + 1))
+		})
+
+		It("should not admit pings that are signed by a different darknode", func() {
+			multiAddr := newSignedMultiAddress(keys[0], 2)
+			forged, err := identity.Address(keys[2].Address()).MultiAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			forged.Nonce = multiAddr.Nonce
+			forged.Signature, err = keys[0].Sign(forged.Hash())
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(hub.ping(identity.Address(keys[1].Address()), forged)).Should(Equal(ErrMultiAddressSignatoryMismatch))
+			Expect(hub.pongs()).Should(BeZero())
+		})
+
+		It("should not relay pings signed by an unregistered darknode", func() {
+			unregistered, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			multiAddr := newSignedMultiAddress(unregistered, 2)
+			Expect(hub.ping(identity.Address(keys[0].Address()), multiAddr)).Should(HaveOccurred())
+			Expect(hub.pings()).Should(Equal(int64(1)))
+		})
+
+		It("should not relay pings from darknodes that are not registered in the current epoch", func() {
+			for nonce := uint64(2); nonce < 100; nonce++ {
+				multiAddr := newSignedMultiAddress(attacker, nonce)
+				Expect(hub.ping(identity.Address(keys[0].Address()), multiAddr)).ShouldNot(HaveOccurred())
+			}
+			Expect(hub.pings()).Should(Equal(int64(98)))
+		})
+
+		It("should not amplify a flood of rising nonces", func() {
+			numberOfPings := int64(100)
+			for nonce := uint64(2); nonce < uint64(numberOfPings)+2; nonce++ {
+				multiAddr := newSignedMultiAddress(keys[0], nonce)
+				Expect(hub.ping(identity.Address(keys[1].Address()), multiAddr)).ShouldNot(HaveOccurred())
+			}
+			// Every darknode relays the flooding darknode at most
+			// DefaultRelayBurst times to α peers
+			Expect(hub.pings()).Should(BeNumerically("<=", numberOfPings+numberOfNodes*DefaultRelayBurst*α))
+		})
+
+		It("should not amplify duplicate pings", func() {
+			numberOfPings := int64(100)
+			multiAddr := newSignedMultiAddress(keys[0], 2)
+			for i := int64(0); i < numberOfPings; i++ {
+				Expect(hub.ping(identity.Address(keys[1].Address()), multiAddr)).ShouldNot(HaveOccurred())
+			}
+			// Every darknode relays the ping at most once to α peers
+			Expect(hub.pings()).Should(BeNumerically("<=", numberOfPings+numberOfNodes*α))
+		})
+	})
+})
+
+// gossipHub connects servers in memory and counts the number of pings
+// delivered between them. It also acts as the darknode registry.
+type gossipHub struct {
+	mu         *sync.Mutex
+	servers    map[identity.Address]Server
+	registered map[identity.Address]bool
+	numPings   int64
+	numPongs   int64
+}
+
+func newGossipHub() *gossipHub {
+	return &gossipHub{
+		mu:         new(sync.Mutex),
+		servers:    map[identity.Address]Server{},
+		registered: map[identity.Address]bool{},
+	}
+}
+
+func (hub *gossipHub) ping(to identity.Address, multiAddr identity.MultiAddress) error {
+	atomic.AddInt64(&hub.numPings, 1)
+	server, ok := hub.servers[to]
+	if !ok {
+		return errors.New("server not found")
+	}
+	return server.Ping(context.Background(), multiAddr)
+}
+
+func (hub *gossipHub) pings() int64 {
+	return atomic.LoadInt64(&hub.numPings)
+}
+
+func (hub *gossipHub) pongs() int64 {
+	return atomic.LoadInt64(&hub.numPongs)
+}
+
+func (hub *gossipHub) IsRegistered(addr identity.Address) (bool, error) {
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+	return hub.registered[addr], nil
+}
+
+func (hub *gossipHub) PublicKey(addr identity.Address) (rsa.PublicKey, error) {
+	return rsa.PublicKey{}, nil
+}
+
+type gossipClient struct {
+	hub       *gossipHub
+	multiAddr identity.MultiAddress
+}
+
+func (client *gossipClient) Ping(ctx context.Context, to, multiAddr identity.MultiAddress) error {
+	return client.hub.ping(to.Address(), multiAddr)
+}
+
+func (client *gossipClient) Pong(ctx context.Context, to identity.MultiAddress) error {
+	atomic.AddInt64(&client.hub.numPongs, 1)
+	server, ok := client.hub.servers[to.Address()]
+	if !ok {
+		return errors.New("server not found")
+	}
+	return server.Pong(ctx, client.multiAddr)
+}
+
+func (client *gossipClient) Query(ctx context.Context, to identity.MultiAddress, query identity.Address) (identity.MultiAddresses, error) {
+	server, ok := client.hub.servers[to.Address()]
+	if !ok {
+		return nil, errors.New("server not found")
+	}
+	return server.Query(ctx, query)
+}
+
+func (client *gossipClient) MultiAddress() identity.MultiAddress {
+	return client.multiAddr
+}
@@ -0,0 +1,274 @@
+package swarm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// BucketSize is the default maximum number of identity.MultiAddresses stored
+// in each bucket of a RoutingTable.
+const BucketSize = 20
+
+// NumBuckets is the number of buckets in a RoutingTable. There is one bucket
+// for each bit of an identity.ID.
+const NumBuckets = identity.IDLength * 8
+
+// A RoutingTable stores identity.MultiAddresses in buckets, organised by the
+// XOR distance between their identity.Address and the identity.Address of
+// the RoutingTable. The bucket at index i stores identity.MultiAddresses that
+// share a prefix of exactly i bits with the identity.Address of the
+// RoutingTable. Each bucket is ordered from the least recently seen
+// identity.MultiAddress to the most recently seen.
+type RoutingTable interface {
+
+	// Update marks an identity.MultiAddress as recently seen. If its bucket
+	// is full, the identity.MultiAddress is stored in a replacement cache and
+	// the least recently seen identity.MultiAddress in the bucket is returned,
+	// alongside true. The caller is expected to check the liveness of the
+	// returned identity.MultiAddress and call Update if it is alive, or
+	// Remove if it is not.
+	Update(multiAddr identity.MultiAddress) (identity.MultiAddress, bool)
+
+	// Remove an identity.Address from the RoutingTable. The most recently
+	// seen identity.MultiAddress in the replacement cache of the bucket is
+	// moved into the bucket.
+	Remove(addr identity.Address)
+
+	// Closest returns at most n identity.MultiAddresses, sorted by their XOR
+	// distance to the target. The bucket that contains the target is marked
+	// as recently used.
+	Closest(target identity.Address, n int) identity.MultiAddresses
+
+	// MultiAddresses returns all identity.MultiAddresses in the RoutingTable.
+	MultiAddresses() identity.MultiAddresses
+
+	// Refresh returns a random identity.Address in the range of each bucket
+	// that has not been used within the interval, and marks these buckets as
+	// recently used. Looking up these identity.Addresses will refresh the
+	// buckets. Only buckets up to, and including, the deepest non-empty
+	// bucket are considered.
+	Refresh(interval time.Duration) identity.Addresses
+}
+
+type bucket struct {
+	entries      identity.MultiAddresses
+	replacements identity.MultiAddresses
+	lastUsed     time.Time
+}
+
+type routingTable struct {
+	self identity.Address
+	k    int
+
+	mu      *sync.Mutex
+	buckets [NumBuckets]bucket
+}
+
+// NewRoutingTable returns a RoutingTable for the identity.Address that stores
+// at most k identity.MultiAddresses in each bucket.
+func NewRoutingTable(self identity.Address, k int) RoutingTable {
+	if k <= 0 {
+		k = BucketSize
+	}
+	table := &routingTable{
+		self: self,
+		k:    k,
+		mu:   new(sync.Mutex),
+	}
+	now := time.Now()
+	for i := range table.buckets {
+		table.buckets[i].lastUsed = now
+	}
+	return table
+}
+
+// Update implements the RoutingTable interface.
+func (table *routingTable) Update(multiAddr identity.MultiAddress) (identity.MultiAddress, bool) {
+	i, ok := table.bucketIndex(multiAddr.Address())
+	if !ok {
+		return identity.MultiAddress{}, false
+	}
+
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	b := &table.buckets[i]
+	if j := indexOf(b.entries, multiAddr.Address()); j >= 0 {
+		// Move the identity.MultiAddress to the end of the bucket
+		b.entries = append(append(b.entries[:j:j], b.entries[j+1:]...), multiAddr)
+		return identity.MultiAddress{}, false
+	}
+	if len(b.entries) < table.k {
+		b.entries = append(b.entries, multiAddr)
+		return identity.MultiAddress{}, false
+	}
+
+	// The bucket is full so the identity.MultiAddress is cached until the
+	// least recently seen identity.MultiAddress is removed
+	if j := indexOf(b.replacements, multiAddr.Address()); j >= 0 {
+		b.replacements = append(b.replacements[:j:j], b.replacements[j+1:]...)
+	}
+	b.replacements = append(b.replacements, multiAddr)
+	if len(b.replacements) > table.k {
+		b.replacements = b.replacements[1:]
+	}
+	return b.entries[0], true
+}
+
+// Remove implements the RoutingTable interface.
+func (table *routingTable) Remove(addr identity.Address) {
+	i, ok := table.bucketIndex(addr)
+	if !ok {
+		return
+	}
+
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	b := &table.buckets[i]
+	if j := indexOf(b.replacements, addr); j >= 0 {
+		b.replacements = append(b.replacements[:j:j], b.replacements[j+1:]...)
+	}
+	j := indexOf(b.entries, addr)
+	if j < 0 {
+		return
+	}
+	b.entries = append(b.entries[:j:j], b.entries[j+1:]...)
+	if n := len(b.replacements); n > 0 {
+		b.entries = append(b.entries, b.replacements[n-1])
+		b.replacements = b.replacements[:n-1]
+	}
+}
+
+// Closest implements the RoutingTable interface.
+func (table *routingTable) Closest(target identity.Address, n int) identity.MultiAddresses {
+	multiAddrs := table.MultiAddresses()
+
+	if i, ok := table.bucketIndex(target); ok {
+		table.mu.Lock()
+		table.buckets[i].lastUsed = time.Now()
+		table.mu.Unlock()
+	}
+
+	SortByDistance(multiAddrs, target)
+	if len(multiAddrs) > n {
+		multiAddrs = multiAddrs[:n]
+	}
+	return multiAddrs
+}
+
+// MultiAddresses implements the RoutingTable interface.
+func (table *routingTable) MultiAddresses() identity.MultiAddresses {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	multiAddrs := identity.MultiAddresses{}
+	for i := range table.buckets {
+		multiAddrs = append(multiAddrs, table.buckets[i].entries...)
+	}
+	return multiAddrs
+}
+
+// Refresh implements the RoutingTable interface.
+func (table *routingTable) Refresh(interval time.Duration) identity.Addresses {
+	table.mu.Lock()
+	defer table.mu.Unlock()
+
+	deepest := -1
+	for i := range table.buckets {
+		if len(table.buckets[i].entries) > 0 {
+			deepest = i
+		}
+	}
+
+	now := time.Now()
+	targets := identity.Addresses{}
+	for i := 0; i <= deepest; i++ {
+		if now.Sub(table.buckets[i].lastUsed) < interval {
+			continue
+		}
+		target, err := RandomAddressInBucket(table.self, i)
+		if err != nil {
+			continue
+		}
+		table.buckets[i].lastUsed = now
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+func (table *routingTable) bucketIndex(addr identity.Address) (int, bool) {
+	if addr == table.self {
+		return 0, false
+	}
+	i, err := table.self.SamePrefixLength(addr)
+	if err != nil || i >= NumBuckets {
+		return 0, false
+	}
+	return i, true
+}
+
+// RandomAddressInBucket returns a random identity.Address that shares a
+// prefix of exactly i bits with the identity.Address of a RoutingTable.
+func RandomAddressInBucket(self identity.Address, i int) (identity.Address, error) {
+	if len(self) != identity.AddressLength {
+		return "", identity.ErrWrongAddressLength
+	}
+	id := make([]byte, identity.IDLength)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	selfID := self.ID()
+	for j := 0; j < identity.IDLength; j++ {
+		// Copy the first i bits, flip the bit at index i, and keep the
+		// remaining random bits
+		var mask byte
+		switch {
+		case (j+1)*8 <= i:
+			mask = 0xFF
+		case j*8 < i:
+			mask = byte(0xFF) << uint(8-(i-j*8))
+		}
+		id[j] = (selfID[j] & mask) | (id[j] &^ mask)
+		if i/8 == j {
+			bit := byte(0x80) >> uint(i%8)
+			id[j] = (id[j] &^ bit) | (^selfID[j] & bit)
+		}
+	}
+	return identity.ID(id).Address(), nil
+}
+
+// SortByDistance sorts identity.MultiAddresses by the XOR distance of their
+// identity.Address to the target, from closest to furthest.
+// identity.MultiAddresses with an invalid identity.Address are sorted last.
+func SortByDistance(multiAddrs identity.MultiAddresses, target identity.Address) {
+	distances := make(map[identity.Address][]byte, len(multiAddrs))
+	for _, multiAddr := range multiAddrs {
+		distance, err := multiAddr.Address().Distance(target)
+		if err != nil {
+			distance = nil
+		}
+		distances[multiAddr.Address()] = distance
+	}
+	sort.SliceStable(multiAddrs, func(i, j int) bool {
+		left, right := distances[multiAddrs[i].Address()], distances[multiAddrs[j].Address()]
+		if left == nil || right == nil {
+			return right == nil && left != nil
+		}
+		return bytes.Compare(left, right) < 0
+	})
+}
+
+func indexOf(multiAddrs identity.MultiAddresses, addr identity.Address) int {
+	for i, multiAddr := range multiAddrs {
+		if multiAddr.Address() == addr {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,154 @@
+package swarm_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/swarm"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Routing table", func() {
+
+	var self identity.Address
+
+	randomMultiAddressInBucket := func(i int) identity.MultiAddress {
+		addr, err := RandomAddressInBucket(self, i)
+		Expect(err).ShouldNot(HaveOccurred())
+		multiAddr, err := addr.MultiAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+		return multiAddr
+	}
+
+	BeforeEach(func() {
+		var err error
+		self, err = testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when generating addresses in a bucket", func() {
+
+		It("should share a prefix of exactly i bits", func() {
+			for i := 0; i < NumBuckets; i++ {
+				addr, err := RandomAddressInBucket(self, i)
+				Expect(err).ShouldNot(HaveOccurred())
+				prefixLength, err := self.SamePrefixLength(addr)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(prefixLength).Should(Equal(i))
+			}
+		})
+
+		It("should error for invalid addresses", func() {
+			_, err := RandomAddressInBucket("invalid", 0)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when updating the routing table", func() {
+
+		It("should ignore its own address", func() {
+			table := NewRoutingTable(self, 2)
+			multiAddr, err := self.MultiAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, full := table.Update(multiAddr)
+			Expect(full).Should(BeFalse())
+			Expect(table.MultiAddresses()).Should(BeEmpty())
+		})
+
+		It("should order buckets from least to most recently seen", func() {
+			table := NewRoutingTable(self, 3)
+			multiAddrs := identity.MultiAddresses{}
+			for i := 0; i < 3; i++ {
+				multiAddrs = append(multiAddrs, randomMultiAddressInBucket(0))
+				table.Update(multiAddrs[i])
+			}
+			table.Update(multiAddrs[0])
+
+			Expect(table.MultiAddresses()).Should(Equal(identity.MultiAddresses{multiAddrs[1], multiAddrs[2], multiAddrs[0]}))
+		})
+
+		It("should return the least recently seen address when a bucket is full", func() {
+			table := NewRoutingTable(self, 2)
+			first, second, third := randomMultiAddressInBucket(1), randomMultiAddressInBucket(1), randomMultiAddressInBucket(1)
+			table.Update(first)
+			table.Update(second)
+
+			stale, full := table.Update(third)
+			Expect(full).Should(BeTrue())
+			Expect(stale).Should(Equal(first))
+			Expect(table.MultiAddresses()).Should(Equal(identity.MultiAddresses{first, second}))
+
+			// Other buckets are not affected
+			_, full = table.Update(randomMultiAddressInBucket(2))
+			Expect(full).Should(BeFalse())
+		})
+
+		It("should replace removed addresses with the most recently seen replacement", func() {
+			table := NewRoutingTable(self, 2)
+			first, second, third := randomMultiAddressInBucket(1), randomMultiAddressInBucket(1), randomMultiAddressInBucket(1)
+			table.Update(first)
+			table.Update(second)
+			table.Update(third)
+
+			table.Remove(first.Address())
+			Expect(table.MultiAddresses()).Should(Equal(identity.MultiAddresses{second, third}))
+
+			table.Remove(second.Address())
+			Expect(table.MultiAddresses()).Should(Equal(identity.MultiAddresses{third}))
+		})
+	})
+
+	Context("when finding the closest addresses", func() {
+
+		It("should return addresses sorted by distance to the target", func() {
+			table := NewRoutingTable(self, BucketSize)
+			for i := 0; i < 50; i++ {
+				multiAddr, err := testutils.RandomMultiAddress()
+				Expect(err).ShouldNot(HaveOccurred())
+				table.Update(multiAddr)
+			}
+			target, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			closest := table.Closest(target, 10)
+			Expect(closest).Should(HaveLen(10))
+			for i := 1; i < len(closest); i++ {
+				prev, err := closest[i-1].Address().Distance(target)
+				Expect(err).ShouldNot(HaveOccurred())
+				next, err := closest[i].Address().Distance(target)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(string(prev) < string(next)).Should(BeTrue())
+			}
+
+			all := table.MultiAddresses()
+			SortByDistance(all, target)
+			Expect(closest).Should(Equal(all[:10]))
+		})
+	})
+
+	Context("when refreshing the routing table", func() {
+
+		It("should return an address for each unused bucket up to the deepest non-empty bucket", func() {
+			table := NewRoutingTable(self, BucketSize)
+			table.Update(randomMultiAddressInBucket(3))
+			Expect(table.Refresh(time.Hour)).Should(BeEmpty())
+
+			time.Sleep(10 * time.Millisecond)
+			table.Closest(randomMultiAddressInBucket(1).Address(), 1)
+			targets := table.Refresh(5 * time.Millisecond)
+			Expect(targets).Should(HaveLen(3))
+			for i, j := range []int{0, 2, 3} {
+				prefixLength, err := self.SamePrefixLength(targets[i])
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(prefixLength).Should(Equal(j))
+			}
+
+			// Refreshed buckets are marked as used
+			Expect(table.Refresh(5 * time.Millisecond)).Should(BeEmpty())
+		})
+	})
+})
@@ -3,10 +3,12 @@ package swarm
 import (
 	"context"
 	"errors"
-	"log"
+	"fmt"
 	"math/rand"
 	"sync"
+	"time"
 
+	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/logger"
@@ -21,6 +23,10 @@ var ErrMultiAddressIsNil = errors.New("multi-address is nil")
 // has nil fields.
 var ErrAddressIsNil = errors.New("query address is nil")
 
+// ErrMultiAddressSignatoryMismatch is returned when a multi-address is not
+// signed by the darknode that it identifies.
+var ErrMultiAddressSignatoryMismatch = errors.New("multi-address signatory mismatch")
+
 // A Client exposes methods for invoking RPCs on a remote server.
 type Client interface {
 
@@ -54,9 +60,10 @@ type Swarmer interface {
 	// BroadcastMultiAddress to a maximum of α randomly selected nodes.
 	BroadcastMultiAddress(ctx context.Context, multiAddress identity.MultiAddress) error
 
-	// Query a node for the identity.MultiAddress of an identity.Address.
-	// Returns a list of random identity.MultiAddresses from the node that
-	// was queried if not the target is not found.
+	// Query the network for the identity.MultiAddress of an
+	// identity.Address. Nodes are queried iteratively, α at a time, starting
+	// from the nodes in the RoutingTable that are closest to the
+	// identity.Address.
 	Query(ctx context.Context, query identity.Address) (identity.MultiAddress, error)
 
 	// MultiAddress used when pinging and ponging.
@@ -67,19 +74,36 @@ type Swarmer interface {
 	Peers() (identity.MultiAddresses, error)
 }
 
+// RefreshInterval is the interval after which a bucket of the RoutingTable
+// that has not been used should be refreshed.
+const RefreshInterval = time.Hour
+
+// LivenessTimeout is the maximum time that a node has to respond to a
+// liveness check before it is removed from the RoutingTable.
+const LivenessTimeout = 5 * time.Second
+
 type swarmer struct {
 	client   Client
 	verifier *registry.Crypter
 	storer   MultiAddressStorer
+	table    RoutingTable
+	tracker  PeerTracker
 	α        int
 }
 
-// NewSwarmer will return an object that implements the Swarmer interface.
-func NewSwarmer(client Client, storer MultiAddressStorer, α int, verifier *registry.Crypter) Swarmer {
+// NewSwarmer will return an object that implements the Swarmer interface. The
+// RoutingTable is updated with the identity.MultiAddresses in the storer when
+// the Swarmer pings the network, and with the identity.MultiAddresses that
+// are discovered when querying the network. The PeerTracker records the
+// liveness of every peer that is pinged or queried, and healthy peers are
+// preferred over unhealthy peers.
+func NewSwarmer(client Client, storer MultiAddressStorer, table RoutingTable, tracker PeerTracker, α int, verifier *registry.Crypter) Swarmer {
 	return &swarmer{
 		client:   client,
 		verifier: verifier,
 		storer:   storer,
+		table:    table,
+		tracker:  tracker,
 		α:        α,
 	}
 }
@@ -100,6 +124,9 @@ func (swarmer *swarmer) Ping(ctx context.Context) error {
 	if err := swarmer.storer.InsertMultiAddress(multi); err != nil {
 		return err
 	}
+	if err := swarmer.updateRoutingTable(); err != nil {
+		return err
+	}
 
 	return swarmer.pingNodes(ctx, multi)
 }
@@ -145,8 +172,10 @@ func (swarmer *swarmer) Peers() (identity.MultiAddresses, error) {
 }
 
 func (swarmer *swarmer) query(ctx context.Context, query identity.Address) (identity.MultiAddress, error) {
+	self := swarmer.MultiAddress().Address()
+
 	// Is the multi-address same as the swarmer's multi-address?
-	if swarmer.MultiAddress().Address() == query {
+	if self == query {
 		return swarmer.MultiAddress(), nil
 	}
 	// Is the multi-address present in the storer?
@@ -158,56 +187,62 @@ func (swarmer *swarmer) query(ctx context.Context, query identity.Address) (iden
 		return identity.MultiAddress{}, err
 	}
 
-	// If multi-address is not present in the store, query for a maximum of α random nodes.
-	randomMultiAddrs, err := RandomMultiAddrs(swarmer.storer, swarmer.MultiAddress().Address(), swarmer.α)
-	if err != nil {
-		return identity.MultiAddress{}, err
+	// Start with the closest known multi-addresses
+	k := len(swarmer.table.Closest(query, BucketSize))
+	if k == 0 {
+		if err := swarmer.updateRoutingTable(); err != nil {
+			return identity.MultiAddress{}, err
+		}
+	}
+	shortlist := swarmer.table.Closest(query, BucketSize)
+	if k = len(shortlist); k < swarmer.α {
+		k = swarmer.α
 	}
 
-	// Create two maps to records the addrs we have seen and queried
+	// Record the multi-addresses that have been seen and queried
 	seenMu := new(sync.Mutex)
-	seenAddrs := map[identity.Address]struct{}{
-		swarmer.MultiAddress().Address(): {},
+	seen := map[identity.Address]struct{}{self: {}}
+	queried := map[identity.Address]struct{}{}
+	for _, multiAddr := range shortlist {
+		seen[multiAddr.Address()] = struct{}{}
 	}
 
-	// Query α multiAddresses until the node is reached or there are no
-	// more newer multi-addresses can be queried.
+	// Iteratively query the α closest multi-addresses that have not been
+	// queried, until the target is found or all of the k closest
+	// multi-addresses have been queried. Healthy multi-addresses are queried
+	// before unhealthy multi-addresses.
 	for {
-		if len(randomMultiAddrs) == 0 {
-			break
-		}
-		if _, ok := seenAddrs[query]; ok {
-			target, err := swarmer.storer.MultiAddress(query)
-			if err != nil {
-				logger.Error("cannot get multiAddress from the storer")
-				return identity.MultiAddress{}, err
+		candidates := identity.MultiAddresses{}
+		for _, multiAddr := range shortlist {
+			if _, ok := queried[multiAddr.Address()]; !ok {
+				candidates = append(candidates, multiAddr)
 			}
-			return target, nil
 		}
-
-		// Pick at most α multiAddresses
-		length := swarmer.α
-		if len(randomMultiAddrs) < swarmer.α {
-			length = len(randomMultiAddrs)
+		SortByHealth(candidates, swarmer.tracker)
+		if len(candidates) > swarmer.α {
+			candidates = candidates[:swarmer.α]
+		}
+		peersThisRound := candidates
+		for _, multiAddr := range peersThisRound {
+			queried[multiAddr.Address()] = struct{}{}
+		}
+		if len(peersThisRound) == 0 {
+			break
 		}
-		peersThisRound := randomMultiAddrs[:length]
-		randomMultiAddrs = randomMultiAddrs[length:]
 
 		// Query the α multiAddresses simultaneously
-		dispatch.ForAll(peersThisRound, func(i int) {
+		dispatch.CoForAll(peersThisRound, func(i int) {
 			multiAddr := peersThisRound[i]
-			seenMu.Lock()
-			_, ok := seenAddrs[multiAddr.Address()]
-			seenAddrs[multiAddr.Address()] = struct{}{}
-			seenMu.Unlock()
-			if ok {
-				return
-			}
+			begin := time.Now()
 			multiAddrs, err := swarmer.client.Query(ctx, multiAddr, query)
 			if err != nil {
-				log.Printf("cannot query %v: %v", multiAddr.Address(), err)
+				logger.Swarm(logger.LevelWarn, fmt.Sprintf("cannot query %v: %v", multiAddr.Address(), err))
+				swarmer.tracker.Failure(multiAddr.Address())
+				swarmer.table.Remove(multiAddr.Address())
 				return
 			}
+			swarmer.tracker.Success(multiAddr.Address(), time.Since(begin))
+			swarmer.table.Update(multiAddr)
 
 			// Process only the first α multi-addresses returned.
 			if len(multiAddrs) > swarmer.α {
@@ -215,42 +250,128 @@ func (swarmer *swarmer) query(ctx context.Context, query identity.Address) (iden
 			}
 
 			for _, multi := range multiAddrs {
-				if err := swarmer.verifier.Verify(multi.Hash(), multi.Signature); err != nil {
-					log.Println("cannot verify the multiAddress", err)
-					continue
-				}
-
-				// Mark the new multi as seen and add to the query backlog.
+				// Ignore the multi if it has been seen
 				seenMu.Lock()
-				if _, ok := seenAddrs[multi.Address()]; ok {
+				if _, ok := seen[multi.Address()]; ok {
 					seenMu.Unlock()
 					continue
 				}
-				seenAddrs[multi.Address()] = struct{}{}
-				randomMultiAddrs = append(randomMultiAddrs, multi)
 				seenMu.Unlock()
 
-				// Put the new multi in our storer if it has a higher nonce
-				oldMulti, err := swarmer.storer.MultiAddress(multi.Address())
-				if err != nil && err != ErrMultiAddressNotFound {
-					log.Printf("cannot get nonce of %v : %v", multi.Address(), err)
+				if err := verifyMultiAddress(swarmer.verifier, multi); err != nil {
+					logger.Swarm(logger.LevelWarn, fmt.Sprintf("cannot verify the multiaddress: %v", err))
 					continue
 				}
-				if err == ErrMultiAddressNotFound || oldMulti.Nonce < multi.Nonce {
-					if err = swarmer.storer.InsertMultiAddress(multi); err != nil {
-						log.Printf("cannot store %v: %v", multi.Address(), err)
-						continue
-					}
+
+				// Mark the new multi as seen and add it to the shortlist
+				seenMu.Lock()
+				seen[multi.Address()] = struct{}{}
+				shortlist = append(shortlist, multi)
+				seenMu.Unlock()
+
+				if err := swarmer.insertMultiAddress(multi); err != nil {
+					logger.Swarm(logger.LevelWarn, fmt.Sprintf("cannot store %v: %v", multi.Address(), err))
 				}
 			}
 		})
+
+		// Return the target if it has been found
+		if _, ok := seen[query]; ok {
+			target, err := swarmer.storer.MultiAddress(query)
+			if err != nil {
+				logger.Error("cannot get multiAddress from the storer")
+				return identity.MultiAddress{}, err
+			}
+			return target, nil
+		}
+
+		// Keep the k closest multi-addresses
+		SortByDistance(shortlist, query)
+		if len(shortlist) > k {
+			shortlist = shortlist[:k]
+		}
 	}
 
 	return identity.MultiAddress{}, ErrMultiAddressNotFound
 }
 
+// insertMultiAddress will store the multi-address if it has a higher nonce
+// than the stored multi-address and update the RoutingTable. If the bucket
+// of the multi-address is full, the least recently seen multi-address in the
+// bucket is checked for liveness in the background.
+func (swarmer *swarmer) insertMultiAddress(multiAddr identity.MultiAddress) error {
+	oldMulti, err := swarmer.storer.MultiAddress(multiAddr.Address())
+	if err != nil && err != ErrMultiAddressNotFound {
+		return err
+	}
+	if err == ErrMultiAddressNotFound || oldMulti.Nonce < multiAddr.Nonce {
+		if err := swarmer.storer.InsertMultiAddress(multiAddr); err != nil {
+			return err
+		}
+	}
+
+	if stale, full := swarmer.table.Update(multiAddr); full {
+		go swarmer.checkLiveness(stale)
+	}
+	return nil
+}
+
+// checkLiveness pings the multi-address and removes it from the RoutingTable
+// if it does not respond within the LivenessTimeout.
+func (swarmer *swarmer) checkLiveness(multiAddr identity.MultiAddress) {
+	ctx, cancel := context.WithTimeout(context.Background(), LivenessTimeout)
+	defer cancel()
+
+	if err := swarmer.ping(ctx, multiAddr, swarmer.MultiAddress()); err != nil {
+		swarmer.table.Remove(multiAddr.Address())
+		return
+	}
+	swarmer.table.Update(multiAddr)
+}
+
+// updateRoutingTable updates the RoutingTable with all multi-addresses in the
+// storer.
+func (swarmer *swarmer) updateRoutingTable() error {
+	multiAddrs, err := swarmer.Peers()
+	if err != nil {
+		return err
+	}
+	self := swarmer.MultiAddress().Address()
+	for _, multiAddr := range multiAddrs {
+		if multiAddr.Address() == self {
+			continue
+		}
+		swarmer.table.Update(multiAddr)
+	}
+	return nil
+}
+
+// Refresh the buckets of the RoutingTable that have not been used within the
+// interval by querying the Swarmer for a random identity.Address in the range
+// of each bucket.
+func Refresh(ctx context.Context, swarmer Swarmer, table RoutingTable, interval time.Duration) {
+	targets := table.Refresh(interval)
+	dispatch.CoForAll(targets, func(i int) {
+		if _, err := swarmer.Query(ctx, targets[i]); err != nil && err != ErrMultiAddressNotFound {
+			logger.Swarm(logger.LevelWarn, fmt.Sprintf("cannot refresh bucket using %v: %v", targets[i], err))
+		}
+	})
+}
+
+// ping a node using the client and record the liveness of the node.
+func (swarmer *swarmer) ping(ctx context.Context, to, multiAddr identity.MultiAddress) error {
+	begin := time.Now()
+	if err := swarmer.client.Ping(ctx, to, multiAddr); err != nil {
+		swarmer.tracker.Failure(to.Address())
+		return err
+	}
+	swarmer.tracker.Success(to.Address(), time.Since(begin))
+	return nil
+}
+
 // pingNodes will ping α random nodes in the storer using the client to gossip
-// about the multiAddress and nonce seen.
+// about the multiAddress and nonce seen. Healthy nodes are selected before
+// unhealthy nodes.
 func (swarmer *swarmer) pingNodes(ctx context.Context, multiAddr identity.MultiAddress) error {
 	multiAddrs, err := swarmer.Peers()
 	if err != nil {
@@ -261,31 +382,18 @@ func (swarmer *swarmer) pingNodes(ctx context.Context, multiAddr identity.MultiA
 		if to.Address() == multiAddr.Address() || to.Address() == swarmer.MultiAddress().Address() {
 			return nil
 		}
-		return swarmer.client.Ping(ctx, to, multiAddr)
-	}
-
-	if len(multiAddrs) <= swarmer.α {
-		dispatch.CoForAll(multiAddrs, func(i int) {
-			if err := pingNode(multiAddrs[i]); err != nil {
-				log.Printf("cannot ping node with address %v: %v", multiAddrs[i].Address(), err)
-			}
-		})
-		return nil
+		return swarmer.ping(ctx, to, multiAddr)
 	}
 
-	seenAddrs := map[identity.Address]identity.MultiAddress{}
-	for len(multiAddrs) > 0 && len(seenAddrs) < swarmer.α {
-		i := rand.Intn(len(multiAddrs))
-		multi := multiAddrs[i]
-		seenAddrs[multi.Address()] = multi
-
-		multiAddrs[i] = multiAddrs[len(multiAddrs)-1]
-		multiAddrs = multiAddrs[:len(multiAddrs)-1]
+	if len(multiAddrs) > swarmer.α {
+		shuffle(multiAddrs)
+		SortByHealth(multiAddrs, swarmer.tracker)
+		multiAddrs = multiAddrs[:swarmer.α]
 	}
 
-	dispatch.CoForAll(seenAddrs, func(addr identity.Address) {
-		if err := pingNode(seenAddrs[addr]); err != nil {
-			log.Printf("cannot ping node with address %v: %v", addr, err)
+	dispatch.CoForAll(multiAddrs, func(i int) {
+		if err := pingNode(multiAddrs[i]); err != nil {
+			logger.Swarm(logger.LevelWarn, fmt.Sprintf("cannot ping node with address %v: %v", multiAddrs[i].Address(), err))
 		}
 	})
 
@@ -295,7 +403,9 @@ func (swarmer *swarmer) pingNodes(ctx context.Context, multiAddr identity.MultiA
 type Server interface {
 
 	// Ping will register the multi-address and nonce into a storer and
-	// broadcast this information to the network.
+	// broadcast this information to the network. Pings that are rejected by
+	// the Guard are ignored, and retransmitted pings are ponged but not
+	// broadcast again.
 	Ping(ctx context.Context, from identity.MultiAddress) error
 
 	// Pong will handle responses from unseen nodes and register their
@@ -303,8 +413,8 @@ type Server interface {
 	Pong(ctx context.Context, from identity.MultiAddress) error
 
 	// Query will return the multi-address of the query, if available in
-	// the storer. Otherwise, it will return α random multi-addresses from
-	// the storer.
+	// the storer. Otherwise, it will return the α multi-addresses in the
+	// RoutingTable that are closest to the query.
 	Query(ctx context.Context, query identity.Address) (identity.MultiAddresses, error)
 }
 
@@ -312,15 +422,24 @@ type server struct {
 	swarmer        Swarmer
 	verifier       *registry.Crypter
 	multiAddrStore MultiAddressStorer
+	table          RoutingTable
+	tracker        PeerTracker
+	guard          Guard
 	α              int
 }
 
 // NewServer returns a new server that adheres to the swarm.Server interface.
-func NewServer(swarmer Swarmer, multiAddrStore MultiAddressStorer, α int, verifier *registry.Crypter) Server {
+// The RoutingTable and PeerTracker should be the same RoutingTable and
+// PeerTracker used by the Swarmer. The Guard decides which pings are
+// processed and relayed to the network.
+func NewServer(swarmer Swarmer, multiAddrStore MultiAddressStorer, table RoutingTable, tracker PeerTracker, guard Guard, α int, verifier *registry.Crypter) Server {
 	return &server{
 		swarmer:        swarmer,
 		verifier:       verifier,
 		multiAddrStore: multiAddrStore,
+		table:          table,
+		tracker:        tracker,
+		guard:          guard,
 		α:              α,
 	}
 }
@@ -330,13 +449,26 @@ func (server *server) Ping(ctx context.Context, multiAddr identity.MultiAddress)
 	if multiAddr.IsNil() {
 		return ErrMultiAddressIsNil
 	}
+	// Duplicate pings have already been verified. A retransmission of the
+	// latest ping is ponged again, in case the pong was lost, but it is not
+	// relayed again.
+	if server.guard.Seen(multiAddr) {
+		if !server.guard.Latest(multiAddr) {
+			return nil
+		}
+		return server.pong(ctx, multiAddr)
+	}
 	// Verify the signature
-	if err := server.verifier.Verify(multiAddr.Hash(), multiAddr.Signature); err != nil {
+	if err := verifyMultiAddress(server.verifier, multiAddr); err != nil {
 		return err
 	}
+	// Drop pings that replay an old nonce, or jump too far ahead
+	if !server.guard.Admit(multiAddr) {
+		return nil
+	}
 
 	// Pong back
-	if err := server.swarmer.Pong(ctx, multiAddr); err != nil {
+	if err := server.pong(ctx, multiAddr); err != nil {
 		return err
 	}
 
@@ -349,6 +481,9 @@ func (server *server) Ping(ctx context.Context, multiAddr identity.MultiAddress)
 		if err := server.multiAddrStore.InsertMultiAddress(multiAddr); err != nil {
 			return err
 		}
+		if !server.guard.Relay(multiAddr) {
+			return nil
+		}
 		return server.swarmer.BroadcastMultiAddress(ctx, multiAddr)
 	}
 
@@ -361,10 +496,13 @@ func (server *server) Pong(ctx context.Context, from identity.MultiAddress) erro
 		return ErrMultiAddressIsNil
 	}
 	// Verify the signature
-	if err := server.verifier.Verify(from.Hash(), from.Signature); err != nil {
+	if err := verifyMultiAddress(server.verifier, from); err != nil {
 		return err
 	}
 
+	server.tracker.Success(from.Address(), 0)
+	server.updateRoutingTable(from)
+
 	// Compare the nonce and see if we need to store the from multiAddress.
 	oldMulti, err := server.multiAddrStore.MultiAddress(from.Address())
 	if err == ErrMultiAddressNotFound || oldMulti.Nonce < from.Nonce {
@@ -382,7 +520,59 @@ func (server *server) Query(ctx context.Context, query identity.Address) (identi
 	if err == nil {
 		return []identity.MultiAddress{multiAddr}, nil
 	}
-	return RandomMultiAddrs(server.multiAddrStore, server.swarmer.MultiAddress().Address(), server.α)
+	return server.table.Closest(query, server.α), nil
+}
+
+// pong the multi-address and record its liveness.
+func (server *server) pong(ctx context.Context, multiAddr identity.MultiAddress) error {
+	begin := time.Now()
+	if err := server.swarmer.Pong(ctx, multiAddr); err != nil {
+		server.tracker.Failure(multiAddr.Address())
+		return err
+	}
+	server.tracker.Success(multiAddr.Address(), time.Since(begin))
+	server.updateRoutingTable(multiAddr)
+	return nil
+}
+
+// updateRoutingTable marks the multi-address as recently seen in the
+// RoutingTable. If the bucket of the multi-address is full, the least
+// recently seen multi-address in the bucket is checked for liveness in the
+// background, in the same way that the Swarmer checks it.
+func (server *server) updateRoutingTable(multiAddr identity.MultiAddress) {
+	if stale, full := server.table.Update(multiAddr); full {
+		go server.checkLiveness(stale)
+	}
+}
+
+// checkLiveness pongs the multi-address and removes it from the RoutingTable
+// if it does not respond within the LivenessTimeout.
+func (server *server) checkLiveness(multiAddr identity.MultiAddress) {
+	ctx, cancel := context.WithTimeout(context.Background(), LivenessTimeout)
+	defer cancel()
+
+	begin := time.Now()
+	if err := server.swarmer.Pong(ctx, multiAddr); err != nil {
+		server.tracker.Failure(multiAddr.Address())
+		server.table.Remove(multiAddr.Address())
+		return
+	}
+	server.tracker.Success(multiAddr.Address(), time.Since(begin))
+	server.table.Update(multiAddr)
+}
+
+// verifyMultiAddress verifies that the multi-address is signed by a darknode
+// that is registered, and that this darknode is the one identified by the
+// multi-address.
+func verifyMultiAddress(verifier *registry.Crypter, multiAddr identity.MultiAddress) error {
+	signatory, err := crypto.RecoverAddress(multiAddr.Hash(), multiAddr.Signature)
+	if err != nil {
+		return err
+	}
+	if identity.Address(signatory) != multiAddr.Address() {
+		return ErrMultiAddressSignatoryMismatch
+	}
+	return verifier.Verify(multiAddr.Hash(), multiAddr.Signature)
 }
 
 // RandomMultiAddrs returns maximum α random multi-addresses from the storer.
@@ -390,14 +580,14 @@ func RandomMultiAddrs(storer MultiAddressStorer, self identity.Address, α int)
 	// Get all known multi-addresses from the storer.
 	multiAddrsIter, err := storer.MultiAddresses()
 	if err != nil {
-		log.Printf("error getting multiaddresses: %v", err)
+		logger.Swarm(logger.LevelWarn, fmt.Sprintf("error getting multiaddresses: %v", err))
 		return identity.MultiAddresses{}, err
 	}
 	defer multiAddrsIter.Release()
 
 	multiAddrs, err := multiAddrsIter.Collect()
 	if err != nil {
-		log.Printf("error collecting multiaddresses: %v", err)
+		logger.Swarm(logger.LevelWarn, fmt.Sprintf("error collecting multiaddresses: %v", err))
 		return identity.MultiAddresses{}, err
 	}
 
@@ -2,6 +2,7 @@ package swarm_test
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"math/rand"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"github.com/republicprotocol/republic-go/crypto"
 	"github.com/republicprotocol/republic-go/dispatch"
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/leveldb"
 	"github.com/republicprotocol/republic-go/registry"
 	"github.com/republicprotocol/republic-go/testutils"
 )
@@ -59,9 +61,11 @@ var _ = Describe("Swarm", func() {
 			clients[i] = &client
 			stores[i] = store
 			alpha := rand.Intn(α-2) + 2
-			swarmers[i] = NewSwarmer(clients[i], stores[i], alpha, &verifiers[i])
+			table := NewRoutingTable(clients[i].MultiAddress().Address(), BucketSize)
+			tracker := NewPeerTracker()
+			swarmers[i] = NewSwarmer(clients[i], stores[i], table, tracker, alpha, &verifiers[i])
 
-			server := NewServer(swarmers[i], stores[i], alpha, &verifiers[i])
+			server := NewServer(swarmers[i], stores[i], table, tracker, NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst), alpha, &verifiers[i])
 			serverHub.Register(clients[i].MultiAddress().Address(), server)
 		}
 
@@ -162,4 +166,69 @@ var _ = Describe("Swarm", func() {
 			})
 		})
 	})
+
+	Context("when a server receives a ping and the bucket is full", func() {
+
+		AfterEach(func() {
+			os.RemoveAll("./tmp")
+		})
+
+		It("should evict the least recently seen peer if it is not alive", func() {
+			hub := newGossipHub()
+			signedMultiAddress := func(key crypto.EcdsaKey, nonce uint64) identity.MultiAddress {
+				multiAddr, err := identity.Address(key.Address()).MultiAddress()
+				Expect(err).ShouldNot(HaveOccurred())
+				multiAddr.Nonce = nonce
+				multiAddr.Signature, err = key.Sign(multiAddr.Hash())
+				Expect(err).ShouldNot(HaveOccurred())
+				return multiAddr
+			}
+
+			// Generate keys that are in the same bucket of the routing table
+			// of the server
+			key, err := crypto.RandomEcdsaKey()
+			Expect(err).ShouldNot(HaveOccurred())
+			self := identity.Address(key.Address())
+			randomKeyInFirstBucket := func() crypto.EcdsaKey {
+				for {
+					other, err := crypto.RandomEcdsaKey()
+					Expect(err).ShouldNot(HaveOccurred())
+					if i, err := self.SamePrefixLength(identity.Address(other.Address())); err == nil && i == 0 {
+						return other
+					}
+				}
+			}
+			staleKey := randomKeyInFirstBucket()
+			peerKey := randomKeyInFirstBucket()
+			hub.registered[self] = true
+			hub.registered[identity.Address(peerKey.Address())] = true
+
+			// Only the server and the peer are connected to the hub, so the
+			// stale peer will not respond to the liveness check
+			table := NewRoutingTable(self, 1)
+			for _, k := range []crypto.EcdsaKey{key, peerKey} {
+				db, err := leveldb.NewStore(fmt.Sprintf("./tmp/server-%v.out", k.Address()), time.Hour)
+				Expect(err).ShouldNot(HaveOccurred())
+				multiAddr := signedMultiAddress(k, 1)
+				verifier := registry.NewCrypter(crypto.Keystore{EcdsaKey: k}, hub, 2, time.Hour)
+				client := &gossipClient{hub: hub, multiAddr: multiAddr}
+				t := table
+				if k.Address() != key.Address() {
+					t = NewRoutingTable(multiAddr.Address(), BucketSize)
+				}
+				tracker := NewPeerTracker()
+				swarmer := NewSwarmer(client, db.SwarmMultiAddressStore(), t, tracker, α, &verifier)
+				hub.servers[multiAddr.Address()] = NewServer(swarmer, db.SwarmMultiAddressStore(), t, tracker, NewGuard(DefaultNonceWindow, DefaultRelayLimit, DefaultRelayBurst), α, &verifier)
+			}
+			stale := signedMultiAddress(staleKey, 1)
+			_, full := table.Update(stale)
+			Expect(full).Should(BeFalse())
+
+			peer := signedMultiAddress(peerKey, 2)
+			Expect(hub.ping(self, peer)).ShouldNot(HaveOccurred())
+			Eventually(func() identity.MultiAddresses {
+				return table.MultiAddresses()
+			}).Should(Equal(identity.MultiAddresses{peer}))
+		})
+	})
 })
@@ -0,0 +1,196 @@
+package swarm
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/identity"
+)
+
+// HealthyScore is the minimum score of a healthy peer. Peers that have never
+// been contacted have a score of 0.5 and are considered healthy.
+const HealthyScore = 0.25
+
+// PriorObservations is the number of successes, and the number of failures,
+// that are assumed for every peer before it is contacted. It stops a single
+// failure from dominating the score of a peer that has just been discovered.
+const PriorObservations = 2
+
+// MaxConsecutiveFailures is the number of consecutive failures after which
+// the score of a peer stops decreasing.
+const MaxConsecutiveFailures = 8
+
+// PeerHealth is the liveness of a peer, as observed by a PeerTracker.
+type PeerHealth struct {
+	Address             identity.Address
+	LastSeen            time.Time
+	RTT                 time.Duration
+	Successes           int
+	Failures            int
+	ConsecutiveFailures int
+	Score               float64
+}
+
+// IsHealthy returns true if the score of the peer is at least the
+// HealthyScore, otherwise it returns false.
+func (health PeerHealth) IsHealthy() bool {
+	return health.Score >= HealthyScore
+}
+
+// A PeerTracker tracks the liveness of peers and derives a score for each
+// peer. The score is between 0 and 1, and a higher score means that the peer
+// is more reliable and responds faster. Scores are used to prefer healthy
+// peers when pinging, querying, and connecting to the network.
+type PeerTracker interface {
+
+	// Success records a successful interaction with a peer. The round trip
+	// time is ignored if it is not positive.
+	Success(addr identity.Address, rtt time.Duration)
+
+	// Failure records a failed interaction with a peer.
+	Failure(addr identity.Address)
+
+	// Health returns the PeerHealth of a peer. Peers that have not been
+	// tracked have a neutral score.
+	Health(addr identity.Address) PeerHealth
+
+	// Prune forgets the PeerHealth of all peers that are not in the
+	// identity.MultiAddresses, usually the peers in the RoutingTable.
+	Prune(multiAddrs identity.MultiAddresses)
+}
+
+type peerTracker struct {
+	mu    *sync.RWMutex
+	peers map[identity.Address]PeerHealth
+}
+
+// NewPeerTracker returns a PeerTracker that stores the PeerHealth of all
+// peers in memory.
+func NewPeerTracker() PeerTracker {
+	return &peerTracker{
+		mu:    new(sync.RWMutex),
+		peers: map[identity.Address]PeerHealth{},
+	}
+}
+
+// Success implements the PeerTracker interface.
+func (tracker *peerTracker) Success(addr identity.Address, rtt time.Duration) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	health := tracker.peers[addr]
+	health.Address = addr
+	health.LastSeen = time.Now()
+	health.Successes++
+	health.ConsecutiveFailures = 0
+	if rtt > 0 {
+		// Smooth the round trip time in the same way as TCP
+		if health.RTT == 0 {
+			health.RTT = rtt
+		} else {
+			health.RTT = (7*health.RTT + rtt) / 8
+		}
+	}
+	health.Score = score(health)
+	tracker.peers[addr] = health
+}
+
+// Failure implements the PeerTracker interface.
+func (tracker *peerTracker) Failure(addr identity.Address) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	health := tracker.peers[addr]
+	health.Address = addr
+	health.Failures++
+	health.ConsecutiveFailures++
+	health.Score = score(health)
+	tracker.peers[addr] = health
+}
+
+// Health implements the PeerTracker interface.
+func (tracker *peerTracker) Health(addr identity.Address) PeerHealth {
+	tracker.mu.RLock()
+	defer tracker.mu.RUnlock()
+
+	health, ok := tracker.peers[addr]
+	if !ok {
+		health = PeerHealth{Address: addr}
+		health.Score = score(health)
+	}
+	return health
+}
+
+// Prune implements the PeerTracker interface.
+func (tracker *peerTracker) Prune(multiAddrs identity.MultiAddresses) {
+	keep := make(map[identity.Address]struct{}, len(multiAddrs))
+	for _, multiAddr := range multiAddrs {
+		keep[multiAddr.Address()] = struct{}{}
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	for addr := range tracker.peers {
+		if _, ok := keep[addr]; !ok {
+			delete(tracker.peers, addr)
+		}
+	}
+}
+
+// score is the product of the success rate of the peer, a penalty that
+// halves for each consecutive failure after the first, and a penalty for
+// slow round trip times. The success rate starts from PriorObservations
+// successes and failures, and the first consecutive failure is not
+// penalised, so that one dropped message does not make a new peer unhealthy.
+func score(health PeerHealth) float64 {
+	reliability := float64(health.Successes+PriorObservations) / float64(health.Successes+health.Failures+2*PriorObservations)
+
+	consecutiveFailures := health.ConsecutiveFailures
+	if consecutiveFailures > MaxConsecutiveFailures {
+		consecutiveFailures = MaxConsecutiveFailures
+	}
+	penalty := 1.0
+	if consecutiveFailures > 1 {
+		penalty = 1.0 / float64(uint(1)<<uint(consecutiveFailures-1))
+	}
+
+	latency := 1.0 / (1.0 + health.RTT.Seconds())
+
+	return reliability * penalty * latency
+}
+
+// SortByHealth sorts identity.MultiAddresses so that healthy peers appear
+// before unhealthy peers. The order of peers with the same health is
+// preserved.
+func SortByHealth(multiAddrs identity.MultiAddresses, tracker PeerTracker) {
+	healthy := make(map[identity.Address]bool, len(multiAddrs))
+	for _, multiAddr := range multiAddrs {
+		healthy[multiAddr.Address()] = tracker.Health(multiAddr.Address()).IsHealthy()
+	}
+	sort.SliceStable(multiAddrs, func(i, j int) bool {
+		return healthy[multiAddrs[i].Address()] && !healthy[multiAddrs[j].Address()]
+	})
+}
+
+// SortByScore sorts identity.Addresses from the highest score to the lowest
+// score.
+func SortByScore(addrs identity.Addresses, tracker PeerTracker) {
+	scores := make(map[identity.Address]float64, len(addrs))
+	for _, addr := range addrs {
+		scores[addr] = tracker.Health(addr).Score
+	}
+	sort.SliceStable(addrs, func(i, j int) bool {
+		return scores[addrs[i]] > scores[addrs[j]]
+	})
+}
+
+// shuffle the identity.MultiAddresses in place.
+func shuffle(multiAddrs identity.MultiAddresses) {
+	for i := len(multiAddrs) - 1; i > 0; i-- {
+		j := rand.Intn(i + 1)
+		multiAddrs[i], multiAddrs[j] = multiAddrs[j], multiAddrs[i]
+	}
+}
@@ -0,0 +1,137 @@
+package swarm_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/republicprotocol/republic-go/swarm"
+
+	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/testutils"
+)
+
+var _ = Describe("Peer tracker", func() {
+
+	var tracker PeerTracker
+	var addr identity.Address
+
+	BeforeEach(func() {
+		var err error
+		tracker = NewPeerTracker()
+		addr, err = testutils.RandomAddress()
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when a peer has not been tracked", func() {
+
+		It("should return a healthy peer with a neutral score", func() {
+			health := tracker.Health(addr)
+			Expect(health.Address).Should(Equal(addr))
+			Expect(health.LastSeen.IsZero()).Should(BeTrue())
+			Expect(health.Score).Should(Equal(0.5))
+			Expect(health.IsHealthy()).Should(BeTrue())
+		})
+	})
+
+	Context("when recording successes", func() {
+
+		It("should update the last seen time and smooth the round trip time", func() {
+			tracker.Success(addr, 80*time.Millisecond)
+			health := tracker.Health(addr)
+			Expect(health.Successes).Should(Equal(1))
+			Expect(health.RTT).Should(Equal(80 * time.Millisecond))
+			Expect(health.LastSeen.IsZero()).Should(BeFalse())
+
+			tracker.Success(addr, 160*time.Millisecond)
+			Expect(tracker.Health(addr).RTT).Should(Equal(90 * time.Millisecond))
+
+			// Successes without a round trip time do not change it
+			tracker.Success(addr, 0)
+			Expect(tracker.Health(addr).RTT).Should(Equal(90 * time.Millisecond))
+			Expect(tracker.Health(addr).Successes).Should(Equal(3))
+		})
+
+		It("should score faster peers higher", func() {
+			other, err := testutils.RandomAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			tracker.Success(addr, 10*time.Millisecond)
+			tracker.Success(other, time.Second)
+			Expect(tracker.Health(addr).Score).Should(BeNumerically(">", tracker.Health(other).Score))
+		})
+	})
+
+	Context("when recording failures", func() {
+
+		It("should become unhealthy after consecutive failures and recover after a success", func() {
+			tracker.Success(addr, 10*time.Millisecond)
+			tracker.Failure(addr)
+			tracker.Failure(addr)
+			health := tracker.Health(addr)
+			Expect(health.Failures).Should(Equal(2))
+			Expect(health.ConsecutiveFailures).Should(Equal(2))
+			Expect(health.IsHealthy()).Should(BeFalse())
+
+			tracker.Success(addr, 10*time.Millisecond)
+			health = tracker.Health(addr)
+			Expect(health.ConsecutiveFailures).Should(Equal(0))
+			Expect(health.IsHealthy()).Should(BeTrue())
+		})
+
+		It("should not become unhealthy after a single failure on first contact", func() {
+			tracker.Failure(addr)
+			health := tracker.Health(addr)
+			Expect(health.Score).Should(BeNumerically("<", 0.5))
+			Expect(health.IsHealthy()).Should(BeTrue())
+		})
+	})
+
+	Context("when pruning peers", func() {
+
+		It("should forget peers that are not kept", func() {
+			multiAddr, err := testutils.RandomMultiAddress()
+			Expect(err).ShouldNot(HaveOccurred())
+			tracker.Failure(addr)
+			tracker.Success(multiAddr.Address(), time.Millisecond)
+
+			tracker.Prune(identity.MultiAddresses{multiAddr})
+			Expect(tracker.Health(addr)).Should(Equal(PeerHealth{Address: addr, Score: 0.5}))
+			Expect(tracker.Health(multiAddr.Address()).Successes).Should(Equal(1))
+		})
+	})
+
+	Context("when sorting peers", func() {
+
+		It("should sort healthy peers before unhealthy peers", func() {
+			multiAddrs := identity.MultiAddresses{}
+			for i := 0; i < 6; i++ {
+				multiAddr, err := testutils.RandomMultiAddress()
+				Expect(err).ShouldNot(HaveOccurred())
+				multiAddrs = append(multiAddrs, multiAddr)
+				if i%2 == 0 {
+					tracker.Failure(multiAddr.Address())
+					tracker.Failure(multiAddr.Address())
+				}
+			}
+			expected := identity.MultiAddresses{multiAddrs[1], multiAddrs[3], multiAddrs[5], multiAddrs[0], multiAddrs[2], multiAddrs[4]}
+
+			SortByHealth(multiAddrs, tracker)
+			Expect(multiAddrs).Should(Equal(expected))
+		})
+
+		It("should sort addresses by score", func() {
+			addrs := identity.Addresses{}
+			for i := 0; i < 3; i++ {
+				addr, err := testutils.RandomAddress()
+				Expect(err).ShouldNot(HaveOccurred())
+				addrs = append(addrs, addr)
+			}
+			tracker.Failure(addrs[0])
+			tracker.Success(addrs[2], time.Millisecond)
+			expected := identity.Addresses{addrs[2], addrs[1], addrs[0]}
+
+			SortByScore(addrs, tracker)
+			Expect(addrs).Should(Equal(expected))
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package testutils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/republicprotocol/republic-go/logger"
+)
+
+// MockCollector is a local stand-in for a log collector. It receives batches
+// of logger.Logs sent by a logger.HTTPPlugin and stores them in memory.
+type MockCollector struct {
+	server *httptest.Server
+
+	mu       *sync.Mutex
+	logs     []logger.Log
+	batches  int
+	failures int
+}
+
+// NewMockCollector starts a MockCollector listening on a local address.
+func NewMockCollector() *MockCollector {
+	collector := &MockCollector{
+		mu:   new(sync.Mutex),
+		logs: []logger.Log{},
+	}
+	collector.server = httptest.NewServer(http.HandlerFunc(collector.handle))
+	return collector
+}
+
+// URL of the MockCollector.
+func (collector *MockCollector) URL() string {
+	return collector.server.URL
+}
+
+// Logs returns all logger.Logs that have been received.
+func (collector *MockCollector) Logs() []logger.Log {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	logs := make([]logger.Log, len(collector.logs))
+	copy(logs, collector.logs)
+	return logs
+}
+
+// Batches returns the number of batches that have been received.
+func (collector *MockCollector) Batches() int {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	return collector.batches
+}
+
+// Fail the next n batches with an internal server error.
+func (collector *MockCollector) Fail(n int) {
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	collector.failures = n
+}
+
+// Close the MockCollector.
+func (collector *MockCollector) Close() {
+	collector.server.Close()
+}
+
+func (collector *MockCollector) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	logs := []logger.Log{}
+	if err := json.NewDecoder(r.Body).Decode(&logs); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	if collector.failures > 0 {
+		collector.failures--
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	collector.logs = append(collector.logs, logs...)
+	collector.batches++
+	w.WriteHeader(http.StatusOK)
+}
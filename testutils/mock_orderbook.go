@@ -29,6 +29,11 @@ func (mock *EmptyOrderbook) OpenOrder(ctx context.Context, encryptedOrderFragmen
 	return nil
 }
 
+// Query implements the orderbook.Orderbook interface.
+func (mock *EmptyOrderbook) Query(ctx context.Context, query orderbook.Query) (orderbook.OrderState, error) {
+	return orderbook.OrderState{OrderID: query.OrderID}, nil
+}
+
 // Sync implements the orderbook.Orderbook interface.
 func (mock *EmptyOrderbook) Sync(done <-chan struct{}) (<-chan orderbook.Notification, <-chan error) {
 	notifications := make(chan orderbook.Notification)
@@ -79,6 +84,11 @@ func (mock *RandOrderbook) OpenOrder(ctx context.Context, encryptedOrderFragment
 	return nil
 }
 
+// Query implements the orderbook.Orderbook interface.
+func (mock *RandOrderbook) Query(ctx context.Context, query orderbook.Query) (orderbook.OrderState, error) {
+	return orderbook.OrderState{OrderID: query.OrderID}, nil
+}
+
 // Sync implements the orderbook.Orderbook interface.
 func (mock *RandOrderbook) Sync(done <-chan struct{}) (<-chan orderbook.Notification, <-chan error) {
 	notifications := make(chan orderbook.Notification)
@@ -214,6 +224,16 @@ func (binder *MockContractBinder) Status(orderID order.ID) (order.Status, error)
 	return order.Nil, orderbook.ErrOrderNotFound
 }
 
+func (binder *MockContractBinder) Trader(orderID order.ID) (string, error) {
+	binder.ordersMu.RLock()
+	defer binder.ordersMu.RUnlock()
+
+	if trader, ok := binder.traders[orderID]; ok {
+		return trader, nil
+	}
+	return "", orderbook.ErrOrderNotFound
+}
+
 func (binder *MockContractBinder) MinimumEpochInterval() (*big.Int, error) {
 	return big.NewInt(2), nil
 }
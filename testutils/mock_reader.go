@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/republicprotocol/republic-go/identity"
+	"github.com/republicprotocol/republic-go/status"
+	"github.com/republicprotocol/republic-go/swarm"
 )
 
 var alwaysFailError = errors.New("Error")
@@ -35,8 +37,8 @@ func (reader *Reader) PublicKey() ([]byte, error) {
 	return []byte{}, reader.err
 }
 
-func (reader *Reader) Peers() (int, error) {
-	return 0, reader.err
+func (reader *Reader) Peers() ([]swarm.PeerHealth, error) {
+	return []swarm.PeerHealth{}, reader.err
 }
 
 func (reader *Reader) EthereumNetwork() (string, error) {
@@ -62,3 +64,23 @@ func (reader *Reader) InfuraURL() (string, error) {
 func (reader *Reader) Tokens() (map[string]string, error) {
 	return map[string]string{}, reader.err
 }
+
+func (reader *Reader) TokenPairs() ([]string, error) {
+	return []string{}, reader.err
+}
+
+func (reader *Reader) FragmentVersions() ([]uint32, error) {
+	return []uint32{}, reader.err
+}
+
+func (reader *Reader) MatchingPolicy() (string, error) {
+	return "", reader.err
+}
+
+func (reader *Reader) PoolDepths() ([]status.PoolDepth, error) {
+	return []status.PoolDepth{}, reader.err
+}
+
+func (reader *Reader) QueueDepths() ([]status.QueueDepth, error) {
+	return []status.QueueDepth{}, reader.err
+}
@@ -2,10 +2,12 @@ package testutils
 
 import (
 	"math/rand"
+	"time"
 
 	"github.com/republicprotocol/republic-go/identity"
 	"github.com/republicprotocol/republic-go/shamir"
 	"github.com/republicprotocol/republic-go/smpc"
+	"github.com/republicprotocol/republic-go/trace"
 )
 
 // Smpc is a mock implementation of the smpc.Smpcer interface.
@@ -53,7 +55,7 @@ func (smpc *Smpc) Disconnect(networkID smpc.NetworkID) {
 }
 
 // Join implements smpc.Smpcer.
-func (smpc *Smpc) Join(networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, useDelay bool) error {
+func (smpc *Smpc) Join(ctx trace.Context, networkID smpc.NetworkID, join smpc.Join, callback smpc.Callback, useDelay bool) error {
 	values := make([]uint64, len(join.Shares))
 	for i := range values {
 		if smpc.useRandomValue {
@@ -70,6 +72,34 @@ func (smpc *Smpc) Join(networkID smpc.NetworkID, join smpc.Join, callback smpc.C
 	return nil
 }
 
+// Compare implements smpc.Smpcer.
+func (smpc *Smpc) Compare(ctx trace.Context, networkID smpc.NetworkID, comparison smpc.Comparison, callback smpc.ComparisonCallback, useDelay bool) error {
+	value := smpc.value
+	if smpc.useRandomValue {
+		if rand.Uint64()%2 == 0 {
+			value = 0
+		} else {
+			value = shamir.Prime - 1
+		}
+	}
+	results := make([]bool, len(comparison.Predicates))
+	for i, predicate := range comparison.Predicates {
+		results[i] = predicate.Evaluate(value)
+	}
+	callback(comparison.ID, results)
+	return nil
+}
+
+// Preprocess implements smpc.Smpcer.
+func (smpc *Smpc) Preprocess(done <-chan struct{}, depth int, interval time.Duration) {
+	<-done
+}
+
+// PoolDepths implements smpc.Smpcer.
+func (*Smpc) PoolDepths() map[smpc.NetworkID]map[smpc.Predicate]smpc.PoolDepth {
+	return map[smpc.NetworkID]map[smpc.Predicate]smpc.PoolDepth{}
+}
+
 // InsertCommitments implements smpc.Smpcer.
 func (smpc *Smpc) InsertCommitments(networkID smpc.NetworkID, join smpc.JoinID, joinCommitments smpc.JoinCommitments) {
 	// Do nothing
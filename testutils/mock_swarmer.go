@@ -46,7 +46,13 @@ func (swarmer *Swarmer) MultiAddress() identity.MultiAddress {
 }
 
 func (swarmer *Swarmer) Peers() (identity.MultiAddresses, error) {
-	return make([]identity.MultiAddress, len(swarmer.multiAddrs)), nil
+	swarmer.multiAddrsMu.Lock()
+	defer swarmer.multiAddrsMu.Unlock()
+	multiAddrs := make(identity.MultiAddresses, 0, len(swarmer.multiAddrs))
+	for _, multiAddr := range swarmer.multiAddrs {
+		multiAddrs = append(multiAddrs, multiAddr)
+	}
+	return multiAddrs, nil
 }
 
 func (swarmer *Swarmer) Pong(ctx context.Context, to identity.MultiAddress) error {
@@ -129,7 +135,7 @@ type MockSwarmClient struct {
 }
 
 func NewMockSwarmClient(MockServerHub *MockServerHub, clientType ClientType, verifier *registry.Crypter) (MockSwarmClient, swarm.MultiAddressStorer, error) {
-	multiAddr, err := RandomMultiAddress()
+	multiAddr, err := identity.Address(verifier.Keystore().EcdsaKey.Address()).MultiAddress()
 	if err != nil {
 		return MockSwarmClient{}, nil, err
 	}
@@ -5,13 +5,16 @@ import (
 	"time"
 
 	"github.com/republicprotocol/republic-go/oracle"
+	"github.com/republicprotocol/republic-go/order"
 )
 
-// RandMidpointPrice returns a random MidpointPrice.
+// RandMidpointPrice returns a random MidpointPrice for all pairs in the
+// order.DefaultTokenRegistry.
 func RandMidpointPrice() oracle.MidpointPrice {
-	prices := make(map[uint64]uint64, 10)
-	for i := range prices {
-		prices[i] = rand.Uint64()
+	pairs := order.DefaultTokenRegistry().Pairs()
+	prices := make(map[uint64]uint64, len(pairs))
+	for _, tokens := range pairs {
+		prices[uint64(tokens)] = rand.Uint64()
 	}
 
 	return oracle.MidpointPrice{
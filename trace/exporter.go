@@ -0,0 +1,95 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// A FileExporter implements the Exporter interface by appending Events to a
+// local trace file. Each Event is written as one line of JSON.
+type FileExporter struct {
+	mu      *sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewFileExporter opens, or creates, the trace file at the path and returns a
+// FileExporter that appends Events to it.
+func NewFileExporter(path string) (*FileExporter, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, err
+	}
+	return &FileExporter{
+		mu:      new(sync.Mutex),
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// Export implements the Exporter interface.
+func (exporter *FileExporter) Export(event Event) error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	return exporter.encoder.Encode(event)
+}
+
+// Close the trace file.
+func (exporter *FileExporter) Close() error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	return exporter.file.Close()
+}
+
+// ReadEvents reads all Events written by a FileExporter.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	events := []Event{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		event := Event{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return events, err
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// ReadFile reads all Events from a trace file written by a FileExporter.
+func ReadFile(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadEvents(file)
+}
+
+// Reconstruct the life of a trace by merging the Events recorded by each
+// node. Only Events for the ID are returned, ordered by their timestamp.
+// Passing the Events from the trace files of all nodes in a pod reconstructs
+// the trace across the pod.
+func Reconstruct(id ID, events ...[]Event) []Event {
+	trace := []Event{}
+	for i := range events {
+		for _, event := range events[i] {
+			if event.ID == id {
+				trace = append(trace, event)
+			}
+		}
+	}
+	sort.SliceStable(trace, func(i, j int) bool {
+		return trace[i].Timestamp.Before(trace[j].Timestamp)
+	})
+	return trace
+}
@@ -0,0 +1,159 @@
+package trace
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/republicprotocol/republic-go/logger"
+)
+
+// ErrMalformedID is returned when an ID, or SpanID, cannot be decoded
+// because it has an unexpected length.
+var ErrMalformedID = errors.New("malformed id")
+
+// An ID identifies a trace. All nodes in a pod use the same ID for the same
+// computation, so that the events recorded by different nodes can be merged.
+// The ome uses the ComputationID as the ID.
+type ID [32]byte
+
+// String returns the base64 encoding of the ID.
+func (id ID) String() string {
+	return base64.StdEncoding.EncodeToString(id[:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (id *ID) UnmarshalText(text []byte) error {
+	return unmarshalText(id[:], text)
+}
+
+// A SpanID identifies a span within a trace. The ome uses the smpc.JoinID of
+// each resolve stage as the SpanID, so that all nodes in a pod record the
+// same SpanID for the same stage. Events that are not part of a span use the
+// zero SpanID.
+type SpanID [33]byte
+
+// String returns the base64 encoding of the SpanID.
+func (id SpanID) String() string {
+	return base64.StdEncoding.EncodeToString(id[:])
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (id SpanID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (id *SpanID) UnmarshalText(text []byte) error {
+	return unmarshalText(id[:], text)
+}
+
+func unmarshalText(id []byte, text []byte) error {
+	data, err := base64.StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	if len(data) != len(id) {
+		return ErrMalformedID
+	}
+	copy(id, data)
+	return nil
+}
+
+// A Context is propagated between nodes so that events recorded while
+// handling a message can be associated with the trace, and span, of the
+// sender.
+type Context struct {
+	ID     ID
+	SpanID SpanID
+}
+
+// NewContext returns a Context for a span within a trace.
+func NewContext(id ID, spanID SpanID) Context {
+	return Context{
+		ID:     id,
+		SpanID: spanID,
+	}
+}
+
+// Attributes are key-value pairs that describe an Event.
+type Attributes map[string]string
+
+// An Event is recorded by a node when something happens within a trace.
+type Event struct {
+	Timestamp  time.Time  `json:"timestamp"`
+	Node       string     `json:"node"`
+	ID         ID         `json:"id"`
+	SpanID     SpanID     `json:"spanId"`
+	Name       string     `json:"name"`
+	Attributes Attributes `json:"attributes,omitempty"`
+}
+
+// An Exporter writes Events to a destination from which they can be read
+// once the trace is complete.
+type Exporter interface {
+	Export(event Event) error
+}
+
+// A Tracer records Events on behalf of a node and writes them to an Exporter.
+type Tracer struct {
+	node     string
+	exporter Exporter
+}
+
+// NewTracer returns a Tracer that records Events for a node. If the Exporter
+// is nil, Events are discarded.
+func NewTracer(node string, exporter Exporter) *Tracer {
+	return &Tracer{
+		node:     node,
+		exporter: exporter,
+	}
+}
+
+// Record an Event within the trace, and span, of a Context.
+func (tracer *Tracer) Record(ctx Context, name string, attrs Attributes) {
+	if tracer.exporter == nil {
+		return
+	}
+	event := Event{
+		Timestamp:  time.Now(),
+		Node:       tracer.node,
+		ID:         ctx.ID,
+		SpanID:     ctx.SpanID,
+		Name:       name,
+		Attributes: attrs,
+	}
+	if err := tracer.exporter.Export(event); err != nil {
+		logger.Error(fmt.Sprintf("cannot export trace event %v: %v", name, err))
+	}
+}
+
+var defaultTracerMu = new(sync.RWMutex)
+var defaultTracer = NewTracer("", nil)
+
+// SetDefaultTracer sets the Tracer used to record all Events. By default,
+// Events are discarded.
+func SetDefaultTracer(tracer *Tracer) {
+	defaultTracerMu.Lock()
+	defer defaultTracerMu.Unlock()
+	defaultTracer = tracer
+}
+
+// DefaultTracer returns the Tracer used to record all Events.
+func DefaultTracer() *Tracer {
+	defaultTracerMu.RLock()
+	defer defaultTracerMu.RUnlock()
+	return defaultTracer
+}
+
+// Record an Event using the DefaultTracer.
+func Record(ctx Context, name string, attrs Attributes) {
+	DefaultTracer().Record(ctx, name, attrs)
+}
@@ -0,0 +1,125 @@
+package trace_test
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/republicprotocol/republic-go/testutils"
+	"github.com/republicprotocol/republic-go/trace"
+)
+
+var _ = Describe("Tracing", func() {
+
+	var id trace.ID
+	var spanID trace.SpanID
+
+	BeforeEach(func() {
+		id = trace.ID(testutils.Random32Bytes())
+		copy(spanID[:], id[:])
+		spanID[32] = 1
+	})
+
+	Context("when marshaling events", func() {
+
+		It("should encode identifiers as base64", func() {
+			event := trace.Event{
+				Timestamp: time.Now(),
+				Node:      "node",
+				ID:        id,
+				SpanID:    spanID,
+				Name:      "name",
+			}
+			data, err := json.Marshal(event)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(data)).Should(ContainSubstring(id.String()))
+
+			other := trace.Event{}
+			Expect(json.Unmarshal(data, &other)).ShouldNot(HaveOccurred())
+			Expect(other.ID).Should(Equal(id))
+			Expect(other.SpanID).Should(Equal(spanID))
+		})
+
+		It("should error for malformed identifiers", func() {
+			other := trace.Event{}
+			Expect(json.Unmarshal([]byte(`{"id":"AAAA"}`), &other)).Should(Equal(trace.ErrMalformedID))
+		})
+	})
+
+	Context("when exporting events to trace files", func() {
+
+		AfterEach(func() {
+			os.Remove("./first.trace")
+			os.Remove("./second.trace")
+		})
+
+		It("should reconstruct traces across nodes", func() {
+			first, err := trace.NewFileExporter("./first.trace")
+			Expect(err).ShouldNot(HaveOccurred())
+			second, err := trace.NewFileExporter("./second.trace")
+			Expect(err).ShouldNot(HaveOccurred())
+			firstTracer := trace.NewTracer("first", first)
+			secondTracer := trace.NewTracer("second", second)
+
+			ctx := trace.NewContext(id, spanID)
+			other := trace.NewContext(trace.ID(testutils.Random32Bytes()), trace.SpanID{})
+			firstTracer.Record(ctx, "send", nil)
+			secondTracer.Record(other, "unrelated", nil)
+			secondTracer.Record(ctx, "receive", trace.Attributes{"from": "first"})
+			firstTracer.Record(ctx, "reconstruct", nil)
+			Expect(first.Close()).ShouldNot(HaveOccurred())
+			Expect(second.Close()).ShouldNot(HaveOccurred())
+
+			firstEvents, err := trace.ReadFile("./first.trace")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(firstEvents).Should(HaveLen(2))
+			secondEvents, err := trace.ReadFile("./second.trace")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(secondEvents).Should(HaveLen(2))
+
+			events := trace.Reconstruct(id, firstEvents, secondEvents)
+			Expect(events).Should(HaveLen(3))
+			Expect(events[0].Name).Should(Equal("send"))
+			Expect(events[0].Node).Should(Equal("first"))
+			Expect(events[1].Name).Should(Equal("receive"))
+			Expect(events[1].Node).Should(Equal("second"))
+			Expect(events[1].Attributes["from"]).Should(Equal("first"))
+			Expect(events[2].Name).Should(Equal("reconstruct"))
+			for _, event := range events {
+				Expect(event.ID).Should(Equal(id))
+				Expect(event.SpanID).Should(Equal(spanID))
+			}
+		})
+	})
+
+	Context("when using the default tracer", func() {
+
+		AfterEach(func() {
+			trace.SetDefaultTracer(trace.NewTracer("", nil))
+		})
+
+		It("should discard events by default", func() {
+			trace.Record(trace.NewContext(id, spanID), "name", nil)
+		})
+
+		It("should record events using the default tracer", func() {
+			exporter := &mockExporter{}
+			trace.SetDefaultTracer(trace.NewTracer("node", exporter))
+			trace.Record(trace.NewContext(id, spanID), "name", nil)
+			Expect(exporter.events).Should(HaveLen(1))
+			Expect(exporter.events[0].Node).Should(Equal("node"))
+		})
+	})
+})
+
+type mockExporter struct {
+	events []trace.Event
+}
+
+func (exporter *mockExporter) Export(event trace.Event) error {
+	exporter.events = append(exporter.events, event)
+	return nil
+}